@@ -18,6 +18,7 @@ import (
 
 	"github.com/joho/godotenv"
 	configaccess "github.com/router-for-me/CLIProxyAPI/v7/internal/access/config_access"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/authcrypt"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/buildinfo"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/cmd"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
@@ -89,6 +90,9 @@ func main() {
 	var tuiMode bool
 	var standalone bool
 	var localModel bool
+	var dryRunConfig string
+	var dryRunRequest string
+	var encryptAuthFiles bool
 
 	// Define command-line flags for different operation modes.
 	flag.BoolVar(&login, "login", false, "Login Google Account")
@@ -110,6 +114,9 @@ func main() {
 	flag.BoolVar(&tuiMode, "tui", false, "Start with terminal management UI")
 	flag.BoolVar(&standalone, "standalone", false, "In TUI mode, start an embedded local server")
 	flag.BoolVar(&localModel, "local-model", false, "Use embedded model catalog only, skip remote model fetching")
+	flag.StringVar(&dryRunConfig, "dry-run", "", "Validate a candidate config file and preview its auths, models and payload rules without applying it")
+	flag.StringVar(&dryRunRequest, "dry-run-request", "", "Sample request JSON evaluated against payload rules (use with -dry-run)")
+	flag.BoolVar(&encryptAuthFiles, "encrypt-auth-files", false, "Encrypt auth files with AUTH_ENCRYPTION_KEY, re-encrypting files sealed with AUTH_ENCRYPTION_PREVIOUS_KEYS")
 
 	flag.CommandLine.Usage = func() {
 		out := flag.CommandLine.Output()
@@ -206,6 +213,16 @@ func main() {
 		}
 	}
 
+	authEncryptionKey, _ := lookupEnv("AUTH_ENCRYPTION_KEY", "auth_encryption_key")
+	authEncryptionKeyFile, _ := lookupEnv("AUTH_ENCRYPTION_KEY_FILE", "auth_encryption_key_file")
+	authEncryptionPreviousKeys, _ := lookupEnv("AUTH_ENCRYPTION_PREVIOUS_KEYS", "auth_encryption_previous_keys")
+	authKeyring, errKeyring := authcrypt.LoadKeyring(authEncryptionKey, authEncryptionKeyFile, authEncryptionPreviousKeys)
+	if errKeyring != nil {
+		log.Errorf("failed to load auth encryption key: %v", errKeyring)
+		return
+	}
+	authcrypt.SetKeyring(authKeyring)
+
 	if value, ok := lookupEnv("PGSTORE_DSN", "pgstore_dsn"); ok {
 		usePostgresStore = true
 		pgStoreDSN = value
@@ -530,7 +547,7 @@ func main() {
 		CallbackPort: oauthCallbackPort,
 	}
 
-	commandMode := vertexImport != "" || login || antigravityLogin || codexLogin || codexDeviceLogin || claudeLogin || kimiLogin || xaiLogin
+	commandMode := dryRunConfig != "" || encryptAuthFiles || vertexImport != "" || login || antigravityLogin || codexLogin || codexDeviceLogin || claudeLogin || kimiLogin || xaiLogin
 	cloudConfigMissing := isCloudDeploy && !configFileExists
 	homeMode := configLoadedFromHome || (cfg != nil && cfg.Home.Enabled)
 	if shouldStartExampleAPIKeyWarningServer(cfg, commandMode, tuiMode, standalone, cloudConfigMissing, homeMode) {
@@ -565,7 +582,17 @@ func main() {
 
 	// Handle different command modes based on the provided flags.
 
-	if vertexImport != "" {
+	if dryRunConfig != "" {
+		// Validate a candidate config against the loaded one
+		if !cmd.DoConfigDryRun(cfg, dryRunConfig, dryRunRequest) {
+			os.Exit(1)
+		}
+	} else if encryptAuthFiles {
+		// Seal existing auth files with the current encryption key
+		if !cmd.DoEncryptAuthFiles(cfg) {
+			os.Exit(1)
+		}
+	} else if vertexImport != "" {
 		// Handle Vertex service account import
 		cmd.DoVertexImport(cfg, vertexImport, vertexImportPrefix)
 	} else if login {
@@ -597,6 +624,10 @@ func main() {
 		if localModel && (!tuiMode || standalone) {
 			log.Info("Local model mode: using embedded model catalog, remote model updates disabled")
 		}
+		if usePostgresStore && (!tuiMode || standalone) {
+			// Mirror config and auth changes made by other replicas sharing the database.
+			pgStoreInst.StartChangeListener(context.Background())
+		}
 		if tuiMode {
 			if standalone {
 				// Standalone mode: start an embedded local server and connect TUI client to it.
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"strings"
+	"time"
 
 	sdkaccess "github.com/router-for-me/CLIProxyAPI/v7/sdk/access"
 	sdkconfig "github.com/router-for-me/CLIProxyAPI/v7/sdk/config"
@@ -17,23 +18,26 @@ func Register(cfg *sdkconfig.SDKConfig) {
 	}
 
 	keys := normalizeKeys(cfg.APIKeys)
-	if len(keys) == 0 {
+	clientKeys := normalizeClientKeys(cfg.ClientAPIKeys)
+	if len(keys) == 0 && len(clientKeys) == 0 {
 		sdkaccess.UnregisterProvider(sdkaccess.AccessProviderTypeConfigAPIKey)
 		return
 	}
 
 	sdkaccess.RegisterProvider(
 		sdkaccess.AccessProviderTypeConfigAPIKey,
-		newProvider(sdkaccess.DefaultAccessProviderName, keys),
+		newProvider(sdkaccess.DefaultAccessProviderName, keys, clientKeys...),
 	)
 }
 
 type provider struct {
-	name string
-	keys map[string]struct{}
+	name       string
+	keys       map[string]struct{}
+	clientKeys map[string]sdkconfig.ClientAPIKey
+	now        func() time.Time
 }
 
-func newProvider(name string, keys []string) *provider {
+func newProvider(name string, keys []string, clientKeys ...sdkconfig.ClientAPIKey) *provider {
 	providerName := strings.TrimSpace(name)
 	if providerName == "" {
 		providerName = sdkaccess.DefaultAccessProviderName
@@ -42,7 +46,14 @@ func newProvider(name string, keys []string) *provider {
 	for _, key := range keys {
 		keySet[key] = struct{}{}
 	}
-	return &provider{name: providerName, keys: keySet}
+	clientKeySet := make(map[string]sdkconfig.ClientAPIKey, len(clientKeys))
+	for _, entry := range clientKeys {
+		if _, plain := keySet[entry.APIKey]; plain {
+			continue
+		}
+		clientKeySet[entry.APIKey] = entry
+	}
+	return &provider{name: providerName, keys: keySet, clientKeys: clientKeySet, now: time.Now}
 }
 
 func (p *provider) Identifier() string {
@@ -56,7 +67,7 @@ func (p *provider) Authenticate(_ context.Context, r *http.Request) (*sdkaccess.
 	if p == nil {
 		return nil, sdkaccess.NewNotHandledError()
 	}
-	if len(p.keys) == 0 {
+	if len(p.keys) == 0 && len(p.clientKeys) == 0 {
 		return nil, sdkaccess.NewNotHandledError()
 	}
 	authHeader := r.Header.Get("Authorization")
@@ -94,15 +105,54 @@ func (p *provider) Authenticate(_ context.Context, r *http.Request) (*sdkaccess.
 				Provider:  p.Identifier(),
 				Principal: candidate.value,
 				Metadata: map[string]string{
-					"source": candidate.source,
+					sdkaccess.MetadataKeySource: candidate.source,
 				},
 			}, nil
 		}
+		if entry, ok := p.clientKeys[candidate.value]; ok {
+			return p.authenticateClientKey(r, entry, candidate.source)
+		}
 	}
 
 	return nil, sdkaccess.NewInvalidCredentialError()
 }
 
+// authenticateClientKey applies the restrictions of a structured client key.
+// Model allowlists are carried in the result metadata and enforced once the
+// request model is known.
+func (p *provider) authenticateClientKey(r *http.Request, entry sdkconfig.ClientAPIKey, source string) (*sdkaccess.Result, *sdkaccess.AuthError) {
+	if entry.Disabled {
+		return nil, sdkaccess.NewForbiddenError("API key is disabled")
+	}
+	now := time.Now
+	if p.now != nil {
+		now = p.now
+	}
+	if entry.IsExpired(now()) {
+		return nil, sdkaccess.NewForbiddenError("API key has expired")
+	}
+	path := ""
+	if r != nil && r.URL != nil {
+		path = r.URL.Path
+	}
+	if !entry.AllowsEndpoint(path) {
+		return nil, sdkaccess.NewForbiddenError("API key is not allowed to access this endpoint")
+	}
+
+	metadata := map[string]string{
+		sdkaccess.MetadataKeySource: source,
+		sdkaccess.MetadataKeyName:   entry.Label(),
+	}
+	if len(entry.Models) > 0 {
+		metadata[sdkaccess.MetadataKeyModels] = strings.Join(entry.Models, ",")
+	}
+	return &sdkaccess.Result{
+		Provider:  p.Identifier(),
+		Principal: entry.APIKey,
+		Metadata:  metadata,
+	}, nil
+}
+
 func extractBearerToken(header string) string {
 	if header == "" {
 		return ""
@@ -139,3 +189,27 @@ func normalizeKeys(keys []string) []string {
 	}
 	return normalized
 }
+
+func normalizeClientKeys(entries []sdkconfig.ClientAPIKey) []sdkconfig.ClientAPIKey {
+	if len(entries) == 0 {
+		return nil
+	}
+	normalized := make([]sdkconfig.ClientAPIKey, 0, len(entries))
+	seen := make(map[string]struct{}, len(entries))
+	for i := range entries {
+		entry := entries[i]
+		sdkconfig.NormalizeClientAPIKey(&entry)
+		if entry.APIKey == "" {
+			continue
+		}
+		if _, exists := seen[entry.APIKey]; exists {
+			continue
+		}
+		seen[entry.APIKey] = struct{}{}
+		normalized = append(normalized, entry)
+	}
+	if len(normalized) == 0 {
+		return nil
+	}
+	return normalized
+}
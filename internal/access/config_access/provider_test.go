@@ -0,0 +1,100 @@
+package configaccess
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdkaccess "github.com/router-for-me/CLIProxyAPI/v7/sdk/access"
+	sdkconfig "github.com/router-for-me/CLIProxyAPI/v7/sdk/config"
+)
+
+func newClientKeyRequest(path, key string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.Header.Set("Authorization", "Bearer "+key)
+	return req
+}
+
+func TestAuthenticatePlainKey(t *testing.T) {
+	p := newProvider("", []string{"plain-key"})
+
+	result, authErr := p.Authenticate(context.Background(), newClientKeyRequest("/v1/chat/completions", "plain-key"))
+	if authErr != nil {
+		t.Fatalf("Authenticate error: %v", authErr)
+	}
+	if result.Principal != "plain-key" {
+		t.Fatalf("principal = %q", result.Principal)
+	}
+	if got := result.Metadata[sdkaccess.MetadataKeySource]; got != "authorization" {
+		t.Fatalf("source = %q", got)
+	}
+	if _, ok := result.Metadata[sdkaccess.MetadataKeyName]; ok {
+		t.Fatalf("plain key should not carry a name: %#v", result.Metadata)
+	}
+}
+
+func TestAuthenticateClientKeyMetadata(t *testing.T) {
+	p := newProvider("", nil, sdkconfig.ClientAPIKey{
+		APIKey: "team-key",
+		Name:   "team-a",
+		Models: []string{"gemini-*", "claude-*"},
+	})
+
+	result, authErr := p.Authenticate(context.Background(), newClientKeyRequest("/v1/chat/completions", "team-key"))
+	if authErr != nil {
+		t.Fatalf("Authenticate error: %v", authErr)
+	}
+	if result.Principal != "team-key" {
+		t.Fatalf("principal = %q", result.Principal)
+	}
+	if got := result.Metadata[sdkaccess.MetadataKeyName]; got != "team-a" {
+		t.Fatalf("name = %q", got)
+	}
+	if got := result.Metadata[sdkaccess.MetadataKeyModels]; got != "gemini-*,claude-*" {
+		t.Fatalf("models = %q", got)
+	}
+}
+
+func TestAuthenticateClientKeyRestrictions(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	p := newProvider("", nil,
+		sdkconfig.ClientAPIKey{APIKey: "disabled-key", Disabled: true},
+		sdkconfig.ClientAPIKey{APIKey: "expired-key", ExpiresAt: "2026-05-31T23:59:59Z"},
+		sdkconfig.ClientAPIKey{APIKey: "future-key", ExpiresAt: "2026-12-31"},
+		sdkconfig.ClientAPIKey{APIKey: "chat-only-key", Endpoints: []string{"/v1/chat/*"}},
+	)
+	p.now = func() time.Time { return now }
+
+	cases := []struct {
+		name   string
+		path   string
+		key    string
+		status int
+	}{
+		{name: "disabled", path: "/v1/chat/completions", key: "disabled-key", status: http.StatusForbidden},
+		{name: "expired", path: "/v1/chat/completions", key: "expired-key", status: http.StatusForbidden},
+		{name: "not expired", path: "/v1/chat/completions", key: "future-key"},
+		{name: "endpoint allowed", path: "/v1/chat/completions", key: "chat-only-key"},
+		{name: "endpoint denied", path: "/v1/embeddings", key: "chat-only-key", status: http.StatusForbidden},
+		{name: "unknown", path: "/v1/chat/completions", key: "other-key", status: http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, authErr := p.Authenticate(context.Background(), newClientKeyRequest(tc.path, tc.key))
+			if tc.status == 0 {
+				if authErr != nil {
+					t.Fatalf("unexpected error: %v", authErr)
+				}
+				return
+			}
+			if authErr == nil {
+				t.Fatal("expected error")
+			}
+			if got := authErr.HTTPStatusCode(); got != tc.status {
+				t.Fatalf("status = %d, want %d", got, tc.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package management
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/audit"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/util"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/watcher/diff"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultAuditLogFile = "management-audit.jsonl"
+	defaultAuditLimit   = 50
+	maxAuditLimit       = 500
+)
+
+// auditAuthState holds the operator-controlled auth fields compared by the audit log.
+type auditAuthState struct {
+	disabled bool
+	prefix   string
+	label    string
+	proxyURL string
+}
+
+// auditLogger returns the audit log for the current config, or nil when disabled.
+func (h *Handler) auditLogger() *audit.Log {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.cfg == nil || h.cfg.RemoteManagement.DisableAuditLog {
+		return nil
+	}
+	path := strings.TrimSpace(h.cfg.RemoteManagement.AuditLogFile)
+	if path == "" {
+		base := util.WritablePath()
+		if base == "" && h.configFilePath != "" {
+			base = filepath.Dir(h.configFilePath)
+		}
+		if base == "" {
+			base = "."
+		}
+		path = filepath.Join(base, defaultAuditLogFile)
+	}
+	if h.auditLog == nil || h.auditLog.Path() != path {
+		h.auditLog = audit.NewLog(path)
+	}
+	return h.auditLog
+}
+
+func (h *Handler) auditConfigSnapshot() *config.Config {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.cfg.CloneForRuntime()
+}
+
+func (h *Handler) auditAuthSnapshot() map[string]auditAuthState {
+	h.mu.Lock()
+	manager := h.authManager
+	h.mu.Unlock()
+	if manager == nil {
+		return nil
+	}
+	out := make(map[string]auditAuthState)
+	for _, auth := range manager.List() {
+		out[auth.ID] = auditAuthState{disabled: auth.Disabled, prefix: auth.Prefix, label: auth.Label, proxyURL: auth.ProxyURL}
+	}
+	return out
+}
+
+// AuditMutations returns middleware that records mutating management calls.
+// It must run after Middleware.
+func (h *Handler) AuditMutations() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h.AuditMutation(c, func() bool {
+			c.Next()
+			return true
+		})
+	}
+}
+
+// AuditMutation runs serve and, for POST, PUT, PATCH and DELETE requests that
+// serve reports as handled, appends an audit entry with the config and auth
+// changes made while serving. It returns the result of serve.
+func (h *Handler) AuditMutation(c *gin.Context, serve func() bool) bool {
+	if c == nil || c.Request == nil || !isMutatingMethod(c.Request.Method) {
+		return serve()
+	}
+	auditLog := h.auditLogger()
+	if auditLog == nil {
+		return serve()
+	}
+
+	beforeCfg := h.auditConfigSnapshot()
+	beforeAuths := h.auditAuthSnapshot()
+	handled := serve()
+	if !handled {
+		return false
+	}
+	changes := diff.BuildConfigChangeDetails(beforeCfg, h.auditConfigSnapshot())
+	changes = append(changes, diffAuditAuthStates(beforeAuths, h.auditAuthSnapshot())...)
+
+	entry := audit.Entry{
+		Timestamp: time.Now(),
+		ClientIP:  c.ClientIP(),
+		Method:    c.Request.Method,
+		Route:     c.FullPath(),
+		Path:      c.Request.URL.Path,
+		Status:    c.Writer.Status(),
+		Changes:   changes,
+	}
+	if entry.Route == "" {
+		entry.Route = entry.Path
+	}
+	if principal := principalFromContext(c); principal != nil {
+		entry.Key = principal.Name
+	}
+	if _, errAppend := auditLog.Append(entry); errAppend != nil {
+		log.WithError(errAppend).Warn("management: failed to write audit log entry")
+	}
+	return true
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// diffAuditAuthStates describes auth records added, removed or changed between
+// two snapshots. Proxy URLs may embed credentials and are never printed.
+func diffAuditAuthStates(before, after map[string]auditAuthState) []string {
+	ids := make([]string, 0, len(before)+len(after))
+	for id := range before {
+		ids = append(ids, id)
+	}
+	for id := range after {
+		if _, ok := before[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var changes []string
+	for _, id := range ids {
+		oldState, hadOld := before[id]
+		newState, hasNew := after[id]
+		switch {
+		case !hadOld:
+			changes = append(changes, fmt.Sprintf("auth %s: added", id))
+		case !hasNew:
+			changes = append(changes, fmt.Sprintf("auth %s: removed", id))
+		default:
+			if oldState.disabled != newState.disabled {
+				changes = append(changes, fmt.Sprintf("auth %s: disabled %t -> %t", id, oldState.disabled, newState.disabled))
+			}
+			if oldState.prefix != newState.prefix {
+				changes = append(changes, fmt.Sprintf("auth %s: prefix %s -> %s", id, oldState.prefix, newState.prefix))
+			}
+			if oldState.label != newState.label {
+				changes = append(changes, fmt.Sprintf("auth %s: label %s -> %s", id, oldState.label, newState.label))
+			}
+			if oldState.proxyURL != newState.proxyURL {
+				changes = append(changes, fmt.Sprintf("auth %s: proxy-url updated", id))
+			}
+		}
+	}
+	return changes
+}
+
+// GetAudit returns recorded management mutations, newest first.
+//
+// Query parameters:
+//   - offset: number of newest entries to skip (default 0)
+//   - limit: page size (default 50, max 500)
+func (h *Handler) GetAudit(c *gin.Context) {
+	offset := 0
+	if raw := strings.TrimSpace(c.Query("offset")); raw != "" {
+		value, errOffset := strconv.Atoi(raw)
+		if errOffset != nil || value < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset: must be a non-negative integer"})
+			return
+		}
+		offset = value
+	}
+	limit, errLimit := parseLimit(c.Query("limit"))
+	if errLimit != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid limit: %v", errLimit)})
+		return
+	}
+	if limit == 0 {
+		limit = defaultAuditLimit
+	}
+	limit = min(limit, maxAuditLimit)
+
+	auditLog := h.auditLogger()
+	if auditLog == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "management audit log is disabled"})
+		return
+	}
+	entries, total, errList := auditLog.List(offset, limit)
+	if errList != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errList.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"total":   total,
+		"offset":  offset,
+		"limit":   limit,
+	})
+}
@@ -0,0 +1,88 @@
+package management
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/audit"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+)
+
+func TestAuditMutations_RecordsConfigDiff(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.RemoteManagement.AuditLogFile = filepath.Join(t.TempDir(), "audit.jsonl")
+	h := &Handler{
+		cfg:            cfg,
+		failedAttempts: make(map[string]*attemptInfo),
+		envSecret:      "admin-secret",
+	}
+
+	engine := gin.New()
+	mgmt := engine.Group("/v0/management", h.Middleware(), h.AuditMutations())
+	mgmt.GET("/audit", h.GetAudit)
+	mgmt.GET("/debug", func(c *gin.Context) { c.Status(http.StatusOK) })
+	mgmt.PUT("/debug", func(c *gin.Context) {
+		h.mu.Lock()
+		h.cfg.Debug = true
+		h.mu.Unlock()
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = "127.0.0.1:12345"
+		req.Header.Set("X-Management-Key", "admin-secret")
+		engine.ServeHTTP(rec, req)
+		return rec
+	}
+	do(http.MethodGet, "/v0/management/debug")
+	do(http.MethodPut, "/v0/management/debug?value=secret")
+
+	rec := do(http.MethodGet, "/v0/management/audit")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /audit status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Entries []audit.Entry `json:"entries"`
+		Total   int           `json:"total"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode audit response: %v", err)
+	}
+	if body.Total != 1 || len(body.Entries) != 1 {
+		t.Fatalf("audit = %+v, want only the PUT recorded", body)
+	}
+	entry := body.Entries[0]
+	if entry.Key != "admin" || entry.Route != "/v0/management/debug" || entry.Path != "/v0/management/debug" || entry.ClientIP != "127.0.0.1" || entry.Status != http.StatusOK {
+		t.Fatalf("entry = %+v", entry)
+	}
+	if len(entry.Changes) != 1 || entry.Changes[0] != "debug: false -> true" {
+		t.Fatalf("changes = %q, want the debug toggle", entry.Changes)
+	}
+}
+
+func TestDiffAuditAuthStates(t *testing.T) {
+	before := map[string]auditAuthState{
+		"a.json": {disabled: false, proxyURL: "http://u:p@old"},
+		"b.json": {},
+	}
+	after := map[string]auditAuthState{
+		"a.json": {disabled: true, proxyURL: "http://u:p@new"},
+		"c.json": {},
+	}
+	got := diffAuditAuthStates(before, after)
+	want := []string{"auth a.json: disabled false -> true", "auth a.json: proxy-url updated", "auth b.json: removed", "auth c.json: added"}
+	if len(got) != len(want) {
+		t.Fatalf("changes = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("changes = %q, want %q", got, want)
+		}
+	}
+}
@@ -28,6 +28,8 @@ import (
 	geminiAuth "github.com/router-for-me/CLIProxyAPI/v7/internal/auth/gemini"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/auth/kimi"
 	xaiauth "github.com/router-for-me/CLIProxyAPI/v7/internal/auth/xai"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/authcrypt"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/interfaces"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/misc"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/registry"
@@ -275,6 +277,9 @@ func (h *Handler) ServePluginAuthURL(c *gin.Context) bool {
 	if !ok || !host.HasAuthProvider(provider) {
 		return false
 	}
+	if !h.AuthorizeScope(c, config.ManagementScopeAuthFiles) {
+		return true
+	}
 
 	ctx := PopulateAuthContext(context.Background(), c)
 	baseURL, errBaseURL := h.managementCallbackURL("/v0/management/oauth-callback")
@@ -378,6 +383,9 @@ func (h *Handler) GetAuthFileModels(c *gin.Context) {
 		if m.OwnedBy != "" {
 			entry["owned_by"] = m.OwnedBy
 		}
+		if !m.Pricing.IsZero() {
+			entry["pricing"] = m.Pricing
+		}
 		result = append(result, entry)
 	}
 
@@ -405,7 +413,7 @@ func (h *Handler) listAuthFilesFromDisk(c *gin.Context) {
 
 			// Read file to get type field
 			full := filepath.Join(h.cfg.AuthDir, name)
-			if data, errRead := os.ReadFile(full); errRead == nil {
+			if data, errRead := authcrypt.ReadFile(full); errRead == nil {
 				typeValue := gjson.GetBytes(data, "type").String()
 				emailValue := gjson.GetBytes(data, "email").String()
 				fileData["type"] = typeValue
@@ -448,6 +456,21 @@ func (h *Handler) listAuthFilesFromDisk(c *gin.Context) {
 	c.JSON(200, gin.H{"files": files})
 }
 
+// authHealthStatus summarizes probe state for display. Credentials no probe applies to are
+// reported as not probeable rather than healthy.
+func authHealthStatus(health *coreauth.HealthState) string {
+	switch {
+	case health.Quarantined:
+		return "quarantined"
+	case health.NotProbeable:
+		return "not_probeable"
+	case health.ConsecutiveFailures > 0:
+		return "failing"
+	default:
+		return "healthy"
+	}
+}
+
 func (h *Handler) buildAuthFileEntry(auth *coreauth.Auth) gin.H {
 	if auth == nil {
 		return nil
@@ -510,6 +533,13 @@ func (h *Handler) buildAuthFileEntry(auth *coreauth.Auth) gin.H {
 	if !auth.NextRetryAfter.IsZero() {
 		entry["next_retry_after"] = auth.NextRetryAfter
 	}
+	if auth.QuotaSnapshot != nil {
+		entry["quota"] = auth.QuotaSnapshot
+	}
+	if auth.Health != nil {
+		entry["health"] = auth.Health
+		entry["health_status"] = authHealthStatus(auth.Health)
+	}
 	if path != "" {
 		entry["path"] = path
 		entry["source"] = "file"
@@ -706,7 +736,8 @@ func isUnsafeAuthFileName(name string) bool {
 	return false
 }
 
-// Download single auth file by name
+// Download single auth file by name. Encrypted files are returned decrypted
+// unless ?raw=true asks for the stored envelope.
 func (h *Handler) DownloadAuthFile(c *gin.Context) {
 	name := strings.TrimSpace(c.Query("name"))
 	if isUnsafeAuthFileName(name) {
@@ -727,6 +758,14 @@ func (h *Handler) DownloadAuthFile(c *gin.Context) {
 		}
 		return
 	}
+	if raw, _ := strconv.ParseBool(c.Query("raw")); !raw {
+		plain, errOpen := authcrypt.Open(data)
+		if errOpen != nil {
+			c.JSON(500, gin.H{"error": fmt.Sprintf("failed to decrypt file: %v", errOpen)})
+			return
+		}
+		data = plain
+	}
 	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", name))
 	c.Data(200, "application/json", data)
 }
@@ -953,7 +992,7 @@ func (h *Handler) writeAuthFile(ctx context.Context, name string, data []byte) e
 	if err != nil {
 		return err
 	}
-	if errWrite := os.WriteFile(dst, data, 0o600); errWrite != nil {
+	if errWrite := authcrypt.WriteFile(dst, data, 0o600); errWrite != nil {
 		return fmt.Errorf("failed to write file: %w", errWrite)
 	}
 	if err := h.upsertAuthRecord(ctx, auth); err != nil {
@@ -1143,6 +1182,10 @@ func (h *Handler) buildAuthFromFileData(path string, data []byte) (*coreauth.Aut
 			return nil, fmt.Errorf("failed to read auth file: %w", err)
 		}
 	}
+	data, errOpen := authcrypt.Open(data)
+	if errOpen != nil {
+		return nil, fmt.Errorf("invalid auth file: %w", errOpen)
+	}
 	metadata := make(map[string]any)
 	if err := json.Unmarshal(data, &metadata); err != nil {
 		return nil, fmt.Errorf("invalid auth file: %w", err)
@@ -148,7 +148,10 @@ func (h *Handler) PutConfigYAML(c *gin.Context) {
 	}
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	if WriteConfig(h.configFilePath, body) != nil {
+	errWrite := h.writeConfigWithHistoryLocked(c, configHistorySource(c), func() error {
+		return WriteConfig(h.configFilePath, body)
+	})
+	if errWrite != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "write_failed", "message": "failed to write config"})
 		return
 	}
@@ -286,6 +289,12 @@ func normalizeRoutingStrategy(strategy string) (string, bool) {
 		return "round-robin", true
 	case "fill-first", "fillfirst", "ff":
 		return "fill-first", true
+	case "least-in-flight", "leastinflight", "lif":
+		return "least-in-flight", true
+	case "latency", "ewma-latency", "ewma":
+		return "latency", true
+	case "weighted", "priority-share":
+		return "weighted", true
 	default:
 		return "", false
 	}
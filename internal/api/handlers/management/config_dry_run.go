@@ -0,0 +1,70 @@
+package management
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/configcheck"
+)
+
+// configDryRunRequest is the JSON form of a dry-run request.
+type configDryRunRequest struct {
+	YAML   string                     `json:"yaml"`
+	Sample *configcheck.SampleRequest `json:"sample"`
+}
+
+// SetConfigModelPreview updates the callback used to resolve client-visible
+// models during config dry runs.
+func (h *Handler) SetConfigModelPreview(preview configcheck.ModelPreviewFunc) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	h.configModelPreview = preview
+	h.mu.Unlock()
+}
+
+// DryRunConfig validates a candidate config.yaml and previews the auths,
+// models and payload rules it would produce, without saving or applying it.
+//
+// The body is either raw YAML, or JSON with "yaml" and an optional "sample"
+// request evaluated against the candidate payload rules.
+func (h *Handler) DryRunConfig(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_body", "message": "cannot read request body"})
+		return
+	}
+	data := body
+	var sample *configcheck.SampleRequest
+	if strings.HasPrefix(c.ContentType(), "application/json") {
+		var req configDryRunRequest
+		if errDecode := json.Unmarshal(body, &req); errDecode != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_body", "message": errDecode.Error()})
+			return
+		}
+		data = []byte(req.YAML)
+		sample = req.Sample
+	}
+
+	h.mu.Lock()
+	opts := configcheck.Options{
+		Current:       h.cfg.CloneForRuntime(),
+		Sample:        sample,
+		PreviewModels: h.configModelPreview,
+	}
+	if h.pluginHost != nil {
+		opts.PluginAuthParser = h.pluginHost
+	}
+	h.mu.Unlock()
+
+	report := configcheck.Run(c.Request.Context(), data, opts)
+	status := http.StatusOK
+	if !report.Valid {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, report)
+}
@@ -0,0 +1,73 @@
+package management
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/configcheck"
+)
+
+func TestDryRunConfig_DoesNotApplyCandidate(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	original := []byte("port: 8317\nauth-dir: " + dir + "\n")
+	if err := os.WriteFile(configPath, original, 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	h := NewHandlerWithoutConfigFilePath(cfg, nil)
+	h.configFilePath = configPath
+
+	engine := gin.New()
+	engine.POST("/config.yaml/dry-run", h.DryRunConfig)
+	do := func(contentType, body string) (*httptest.ResponseRecorder, configcheck.Report) {
+		req := httptest.NewRequest(http.MethodPost, "/config.yaml/dry-run", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		var report configcheck.Report
+		if errDecode := json.Unmarshal(rec.Body.Bytes(), &report); errDecode != nil {
+			t.Fatalf("decode report: %v (%s)", errDecode, rec.Body.String())
+		}
+		return rec, report
+	}
+
+	rec, report := do("application/yaml", "port: [\n")
+	if rec.Code != http.StatusUnprocessableEntity || report.Valid || len(report.Errors) == 0 {
+		t.Fatalf("invalid yaml: status %d, report %+v", rec.Code, report)
+	}
+
+	candidate := "port: 9000\nauth-dir: " + dir + "\n"
+	body, _ := json.Marshal(map[string]any{
+		"yaml":   candidate,
+		"sample": map[string]any{"model": "gpt-5", "protocol": "openai", "body": map[string]any{}},
+	})
+	rec, report = do("application/json", string(body))
+	if rec.Code != http.StatusOK || !report.Valid {
+		t.Fatalf("valid candidate: status %d, report %+v", rec.Code, report)
+	}
+	if len(report.Changes) != 1 || report.Changes[0] != "port: 8317 -> 9000" {
+		t.Fatalf("changes = %v", report.Changes)
+	}
+	if report.Payload == nil {
+		t.Fatal("expected a payload preview for the sample request")
+	}
+
+	data, errRead := os.ReadFile(configPath)
+	if errRead != nil {
+		t.Fatalf("read config: %v", errRead)
+	}
+	if string(data) != string(original) || h.cfg.Port != 8317 {
+		t.Fatal("dry run modified the config")
+	}
+}
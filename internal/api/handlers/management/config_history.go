@@ -0,0 +1,298 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/confighistory"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/watcher/diff"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	configHistoryDirName        = "config-history"
+	configHistorySourceExternal = "external"
+	configHistoryCurrent        = "current"
+)
+
+// configHistoryStore is implemented by token stores that keep the config history
+// themselves so every replica sharing the store sees the same revisions.
+type configHistoryStore interface {
+	ConfigHistory() confighistory.Backend
+}
+
+// configHistoryLocked returns the config history for the current config, or
+// nil when history is disabled. Revisions are kept by the active token store
+// when it supports it, otherwise next to config.yaml. Callers must hold h.mu.
+func (h *Handler) configHistoryLocked() *confighistory.History {
+	if h == nil || h.cfg == nil || h.configFilePath == "" || h.cfg.RemoteManagement.DisableConfigHistory {
+		return nil
+	}
+	var backend confighistory.Backend
+	if store, ok := h.tokenStore.(configHistoryStore); ok {
+		backend = store.ConfigHistory()
+	}
+	if backend == nil {
+		backend = confighistory.NewFileBackend(filepath.Join(filepath.Dir(h.configFilePath), configHistoryDirName))
+	}
+	if h.configHistory == nil || h.configHistory.Backend() != backend {
+		h.configHistory = confighistory.NewWithBackend(backend, h.cfg.RemoteManagement.ConfigHistoryMaxRevisions)
+	} else {
+		h.configHistory.SetMaxRevisions(h.cfg.RemoteManagement.ConfigHistoryMaxRevisions)
+	}
+	return h.configHistory
+}
+
+// saveConfigLocked saves h.cfg to config.yaml and records the result in the
+// config history. Callers must hold h.mu.
+func (h *Handler) saveConfigLocked(c *gin.Context) error {
+	return h.writeConfigWithHistoryLocked(c, configHistorySource(c), func() error {
+		return config.SaveConfigPreserveComments(h.configFilePath, h.cfg)
+	})
+}
+
+// writeConfigWithHistoryLocked runs write and records config.yaml before and
+// after it. The first record captures edits made outside the management API
+// since the last revision. Callers must hold h.mu.
+func (h *Handler) writeConfigWithHistoryLocked(c *gin.Context, source string, write func() error) error {
+	history := h.configHistoryLocked()
+	if history != nil {
+		h.recordConfigRevisionLocked(configHistoryContext(c), history, confighistory.Revision{Source: configHistorySourceExternal})
+	}
+	if err := write(); err != nil {
+		return err
+	}
+	if history != nil {
+		rev := confighistory.Revision{Source: source}
+		if principal := principalFromContext(c); principal != nil {
+			rev.Key = principal.Name
+		}
+		h.recordConfigRevisionLocked(configHistoryContext(c), history, rev)
+	}
+	return nil
+}
+
+func (h *Handler) recordConfigRevisionLocked(ctx context.Context, history *confighistory.History, rev confighistory.Revision) {
+	data, errRead := os.ReadFile(h.configFilePath)
+	if errRead != nil {
+		if !errors.Is(errRead, os.ErrNotExist) {
+			log.WithError(errRead).Warn("management: failed to read config for history")
+		}
+		return
+	}
+	if _, _, errRecord := history.Record(ctx, data, rev); errRecord != nil {
+		log.WithError(errRecord).Warn("management: failed to record config revision")
+	}
+}
+
+func configHistoryContext(c *gin.Context) context.Context {
+	if c == nil || c.Request == nil {
+		return context.Background()
+	}
+	return c.Request.Context()
+}
+
+func configHistorySource(c *gin.Context) string {
+	if c == nil || c.Request == nil {
+		return ""
+	}
+	route := c.FullPath()
+	if route == "" && c.Request.URL != nil {
+		route = c.Request.URL.Path
+	}
+	return c.Request.Method + " " + route
+}
+
+func (h *Handler) configHistoryOrError(c *gin.Context) *confighistory.History {
+	h.mu.Lock()
+	history := h.configHistoryLocked()
+	h.mu.Unlock()
+	if history == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "config history is disabled"})
+	}
+	return history
+}
+
+// ListConfigHistory returns the stored config revisions, newest first.
+func (h *Handler) ListConfigHistory(c *gin.Context) {
+	history := h.configHistoryOrError(c)
+	if history == nil {
+		return
+	}
+	revisions, err := history.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if revisions == nil {
+		revisions = []confighistory.Revision{}
+	}
+	c.JSON(http.StatusOK, gin.H{"revisions": revisions})
+}
+
+// GetConfigRevision returns the raw config.yaml stored in a revision.
+func (h *Handler) GetConfigRevision(c *gin.Context) {
+	history := h.configHistoryOrError(c)
+	if history == nil {
+		return
+	}
+	id, ok := configRevisionID(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	rev, data, err := history.Get(c.Request.Context(), id)
+	if err != nil {
+		writeConfigHistoryError(c, err)
+		return
+	}
+	c.Header("Content-Type", "application/yaml; charset=utf-8")
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("X-Config-Revision", strconv.FormatInt(rev.ID, 10))
+	_, _ = c.Writer.Write(data)
+}
+
+// DiffConfigHistory compares two revisions using the redacted config change details.
+//
+// Query parameters:
+//   - from: revision ID (required)
+//   - to: revision ID or "current" (default: the current config.yaml)
+func (h *Handler) DiffConfigHistory(c *gin.Context) {
+	history := h.configHistoryOrError(c)
+	if history == nil {
+		return
+	}
+	fromID, ok := configRevisionID(c, c.Query("from"))
+	if !ok {
+		return
+	}
+	fromCfg, ok := h.loadConfigRevision(c, history, fromID)
+	if !ok {
+		return
+	}
+
+	to := strings.TrimSpace(c.Query("to"))
+	var toCfg *config.Config
+	if to == "" || to == configHistoryCurrent {
+		to = configHistoryCurrent
+		data, errRead := os.ReadFile(h.configFilePath)
+		if errRead != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "read_failed", "message": errRead.Error()})
+			return
+		}
+		parsed, errParse := config.ParseConfigBytes(data)
+		if errParse != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "invalid_config", "message": errParse.Error()})
+			return
+		}
+		toCfg = parsed
+	} else {
+		toID, okTo := configRevisionID(c, to)
+		if !okTo {
+			return
+		}
+		if toCfg, ok = h.loadConfigRevision(c, history, toID); !ok {
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":    fromID,
+		"to":      to,
+		"changes": diff.BuildConfigChangeDetails(fromCfg, toCfg),
+	})
+}
+
+// RollbackConfigRevision restores config.yaml from a revision and applies it
+// through the same reload path as other management saves.
+func (h *Handler) RollbackConfigRevision(c *gin.Context) {
+	id, ok := configRevisionID(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	h.mu.Lock()
+	history := h.configHistoryLocked()
+	if history == nil {
+		h.mu.Unlock()
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "config history is disabled"})
+		return
+	}
+	_, data, err := history.Get(c.Request.Context(), id)
+	if err != nil {
+		h.mu.Unlock()
+		writeConfigHistoryError(c, err)
+		return
+	}
+	if _, errParse := config.ParseConfigBytes(data); errParse != nil {
+		h.mu.Unlock()
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "invalid_config", "message": errParse.Error()})
+		return
+	}
+	previous := h.cfg
+	errWrite := h.writeConfigWithHistoryLocked(c, fmt.Sprintf("rollback to %d", id), func() error {
+		return WriteConfig(h.configFilePath, data)
+	})
+	if errWrite != nil {
+		h.mu.Unlock()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "write_failed", "message": errWrite.Error()})
+		return
+	}
+	newCfg, errLoad := config.LoadConfig(h.configFilePath)
+	if errLoad != nil {
+		h.mu.Unlock()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "reload_failed", "message": errLoad.Error()})
+		return
+	}
+	h.cfg = newCfg
+	snapshot := h.reloadSnapshotConfigLocked()
+	h.mu.Unlock()
+
+	h.reloadConfigAfterManagementSave(c.Request.Context(), snapshot)
+	c.JSON(http.StatusOK, gin.H{
+		"status":      "ok",
+		"revision":    id,
+		"rolled_back": time.Now().UTC(),
+		"changes":     diff.BuildConfigChangeDetails(previous, newCfg),
+	})
+}
+
+func (h *Handler) loadConfigRevision(c *gin.Context, history *confighistory.History, id int64) (*config.Config, bool) {
+	_, data, err := history.Get(c.Request.Context(), id)
+	if err != nil {
+		writeConfigHistoryError(c, err)
+		return nil, false
+	}
+	cfg, errParse := config.ParseConfigBytes(data)
+	if errParse != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "invalid_config", "message": fmt.Sprintf("revision %d: %v", id, errParse)})
+		return nil, false
+	}
+	return cfg, true
+}
+
+func configRevisionID(c *gin.Context, raw string) (int64, bool) {
+	id, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_revision", "message": "revision must be a positive integer"})
+		return 0, false
+	}
+	return id, true
+}
+
+func writeConfigHistoryError(c *gin.Context, err error) {
+	if errors.Is(err, confighistory.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not_found", "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
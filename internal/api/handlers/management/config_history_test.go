@@ -0,0 +1,168 @@
+package management
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/confighistory"
+)
+
+func TestConfigHistory_DiffAndRollback(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("port: 8317\ndebug: false\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	h := NewHandlerWithoutConfigFilePath(cfg, nil)
+	h.configFilePath = configPath
+	var reloaded atomic.Pointer[config.Config]
+	h.SetConfigReloadHook(func(_ context.Context, cfg *config.Config) { reloaded.Store(cfg) })
+
+	engine := gin.New()
+	engine.PUT("/debug", h.PutDebug)
+	engine.GET("/config-history", h.ListConfigHistory)
+	engine.GET("/config-history/diff", h.DiffConfigHistory)
+	engine.POST("/config-history/:id/rollback", h.RollbackConfigRevision)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	if rec := do(http.MethodPut, "/debug", `{"value":true}`); rec.Code != http.StatusOK {
+		t.Fatalf("PUT /debug status = %d, body %s", rec.Code, rec.Body.String())
+	}
+
+	var list struct {
+		Revisions []struct {
+			ID     int64  `json:"id"`
+			Source string `json:"source"`
+		} `json:"revisions"`
+	}
+	rec := do(http.MethodGet, "/config-history", "")
+	if err = json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode history: %v", err)
+	}
+	if len(list.Revisions) != 2 || list.Revisions[0].Source != "PUT /debug" || list.Revisions[1].Source != "external" {
+		t.Fatalf("revisions = %+v, want the PUT and the prior external config", list.Revisions)
+	}
+
+	rec = do(http.MethodGet, "/config-history/diff?from=1&to=2", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "debug: false -\\u003e true") {
+		t.Fatalf("diff = %d %s, want the debug change", rec.Code, rec.Body.String())
+	}
+
+	rec = do(http.MethodPost, "/config-history/1/rollback", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("rollback status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	if applied := reloaded.Load(); h.cfg.Debug || applied == nil || applied.Debug {
+		t.Fatalf("rollback did not restore debug=false (handler=%t, reloaded=%v)", h.cfg.Debug, applied)
+	}
+	if rec = do(http.MethodPost, "/config-history/99/rollback", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown revision status = %d, want 404", rec.Code)
+	}
+}
+
+// memoryConfigHistory is an in-memory confighistory.Backend.
+type memoryConfigHistory struct {
+	mu        sync.Mutex
+	revisions map[int64]confighistory.Revision
+	data      map[int64][]byte
+}
+
+func (m *memoryConfigHistory) ListRevisions(_ context.Context) ([]confighistory.Revision, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]confighistory.Revision, 0, len(m.revisions))
+	for _, rev := range m.revisions {
+		out = append(out, rev)
+	}
+	return out, nil
+}
+
+func (m *memoryConfigHistory) GetRevision(_ context.Context, id int64) (confighistory.Revision, []byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rev, ok := m.revisions[id]
+	if !ok {
+		return rev, nil, confighistory.ErrNotFound
+	}
+	return rev, m.data[id], nil
+}
+
+func (m *memoryConfigHistory) PutRevision(_ context.Context, rev confighistory.Revision, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.revisions[rev.ID]; ok {
+		return confighistory.ErrRevisionExists
+	}
+	if m.revisions == nil {
+		m.revisions = make(map[int64]confighistory.Revision)
+		m.data = make(map[int64][]byte)
+	}
+	m.revisions[rev.ID] = rev
+	m.data[rev.ID] = data
+	return nil
+}
+
+func (m *memoryConfigHistory) DeleteRevisions(_ context.Context, ids ...int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range ids {
+		delete(m.revisions, id)
+		delete(m.data, id)
+	}
+	return nil
+}
+
+type configHistoryAuthStore struct {
+	memoryAuthStore
+	history *memoryConfigHistory
+}
+
+func (s *configHistoryAuthStore) ConfigHistory() confighistory.Backend { return s.history }
+
+func TestConfigHistory_UsesTokenStoreBackend(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("port: 8317\ndebug: false\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	h := NewHandlerWithoutConfigFilePath(cfg, nil)
+	h.configFilePath = configPath
+	store := &configHistoryAuthStore{history: &memoryConfigHistory{}}
+	h.tokenStore = store
+
+	engine := gin.New()
+	engine.PUT("/debug", h.PutDebug)
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/debug", strings.NewReader(`{"value":true}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT /debug status = %d, body %s", rec.Code, rec.Body.String())
+	}
+
+	revisions, _ := store.history.ListRevisions(context.Background())
+	if len(revisions) != 2 {
+		t.Fatalf("store revisions = %+v, want the PUT and the prior external config", revisions)
+	}
+	if _, errStat := os.Stat(filepath.Join(filepath.Dir(configPath), configHistoryDirName)); !os.IsNotExist(errStat) {
+		t.Fatalf("config history directory stat error = %v, want it absent when the store keeps history", errStat)
+	}
+}
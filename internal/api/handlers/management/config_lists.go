@@ -1,8 +1,10 @@
 package management
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -104,18 +106,204 @@ func (h *Handler) deleteFromStringList(c *gin.Context, target *[]string, after f
 	c.JSON(400, gin.H{"error": "missing index or value"})
 }
 
-// api-keys
-func (h *Handler) GetAPIKeys(c *gin.Context) { c.JSON(200, gin.H{"api-keys": h.cfg.APIKeys}) }
+// api-keys: []string plus structured client-api-keys
+func (h *Handler) GetAPIKeys(c *gin.Context) {
+	c.JSON(200, gin.H{"api-keys": h.cfg.APIKeys, "client-api-keys": h.cfg.ClientAPIKeys})
+}
+
+// PutAPIKeys replaces the client key lists. String items become plain api-keys
+// and object items become structured client-api-keys. A body without object
+// items is a legacy plain-key update and keeps the structured keys.
 func (h *Handler) PutAPIKeys(c *gin.Context) {
-	h.putStringList(c, func(v []string) {
-		h.cfg.APIKeys = append([]string(nil), v...)
-	}, nil)
+	data, err := c.GetRawData()
+	if err != nil {
+		c.JSON(400, gin.H{"error": "failed to read body"})
+		return
+	}
+	var arr []json.RawMessage
+	if err = json.Unmarshal(data, &arr); err != nil {
+		var obj struct {
+			Items []json.RawMessage `json:"items"`
+		}
+		if err2 := json.Unmarshal(data, &obj); err2 != nil || len(obj.Items) == 0 {
+			c.JSON(400, gin.H{"error": "invalid body"})
+			return
+		}
+		arr = obj.Items
+	}
+	plain := make([]string, 0, len(arr))
+	var structured []config.ClientAPIKey
+	for _, item := range arr {
+		var key string
+		if errString := json.Unmarshal(item, &key); errString == nil {
+			plain = append(plain, key)
+			continue
+		}
+		var entry config.ClientAPIKey
+		if errEntry := json.Unmarshal(item, &entry); errEntry != nil {
+			c.JSON(400, gin.H{"error": "invalid body"})
+			return
+		}
+		structured = append(structured, entry)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.cfg.APIKeys = plain
+	if len(structured) > 0 {
+		h.cfg.ClientAPIKeys = structured
+		h.cfg.SanitizeClientAPIKeys()
+	}
+	h.persistLocked(c)
 }
+
+// PatchAPIKeys updates a plain key ({old,new} or {index,value:string}) or
+// upserts a structured client key ({index|match,value:object}).
 func (h *Handler) PatchAPIKeys(c *gin.Context) {
+	data, err := c.GetRawData()
+	if err != nil {
+		c.JSON(400, gin.H{"error": "failed to read body"})
+		return
+	}
+	var probe struct {
+		Value json.RawMessage `json:"value"`
+	}
+	if errProbe := json.Unmarshal(data, &probe); errProbe == nil && strings.HasPrefix(strings.TrimSpace(string(probe.Value)), "{") {
+		h.patchClientAPIKey(c, data)
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(data))
 	h.patchStringList(c, &h.cfg.APIKeys, func() {})
 }
+
+func (h *Handler) patchClientAPIKey(c *gin.Context, data []byte) {
+	type clientAPIKeyPatch struct {
+		APIKey    *string   `json:"api-key"`
+		Name      *string   `json:"name"`
+		ExpiresAt *string   `json:"expires-at"`
+		Models    *[]string `json:"models"`
+		Endpoints *[]string `json:"endpoints"`
+		Disabled  *bool     `json:"disabled"`
+
+		RequestsPerMinute     *int   `json:"requests-per-minute"`
+		MaxConcurrentRequests *int   `json:"max-concurrent-requests"`
+		DailyTokenBudget      *int64 `json:"daily-token-budget"`
+		MonthlyTokenBudget    *int64 `json:"monthly-token-budget"`
+	}
+	var body struct {
+		Index *int               `json:"index"`
+		Match *string            `json:"match"`
+		Value *clientAPIKeyPatch `json:"value"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil || body.Value == nil {
+		c.JSON(400, gin.H{"error": "invalid body"})
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	targetIndex := -1
+	if body.Index != nil && *body.Index >= 0 && *body.Index < len(h.cfg.ClientAPIKeys) {
+		targetIndex = *body.Index
+	}
+	if targetIndex == -1 && body.Match != nil {
+		match := strings.TrimSpace(*body.Match)
+		if match != "" {
+			for i := range h.cfg.ClientAPIKeys {
+				if h.cfg.ClientAPIKeys[i].APIKey == match || h.cfg.ClientAPIKeys[i].Name == match {
+					targetIndex = i
+					break
+				}
+			}
+		}
+	}
+
+	var entry config.ClientAPIKey
+	if targetIndex == -1 {
+		// Unknown targets are appended so new keys can be issued without a full PUT.
+		if body.Value.APIKey == nil || strings.TrimSpace(*body.Value.APIKey) == "" {
+			c.JSON(404, gin.H{"error": "item not found"})
+			return
+		}
+	} else {
+		entry = h.cfg.ClientAPIKeys[targetIndex]
+	}
+	if body.Value.APIKey != nil {
+		trimmed := strings.TrimSpace(*body.Value.APIKey)
+		if trimmed == "" {
+			h.cfg.ClientAPIKeys = append(h.cfg.ClientAPIKeys[:targetIndex], h.cfg.ClientAPIKeys[targetIndex+1:]...)
+			h.cfg.SanitizeClientAPIKeys()
+			h.persistLocked(c)
+			return
+		}
+		entry.APIKey = trimmed
+	}
+	if body.Value.Name != nil {
+		entry.Name = *body.Value.Name
+	}
+	if body.Value.ExpiresAt != nil {
+		entry.ExpiresAt = *body.Value.ExpiresAt
+	}
+	if body.Value.Models != nil {
+		entry.Models = append([]string(nil), (*body.Value.Models)...)
+	}
+	if body.Value.Endpoints != nil {
+		entry.Endpoints = append([]string(nil), (*body.Value.Endpoints)...)
+	}
+	if body.Value.Disabled != nil {
+		entry.Disabled = *body.Value.Disabled
+	}
+	if body.Value.RequestsPerMinute != nil {
+		entry.RequestsPerMinute = *body.Value.RequestsPerMinute
+	}
+	if body.Value.MaxConcurrentRequests != nil {
+		entry.MaxConcurrentRequests = *body.Value.MaxConcurrentRequests
+	}
+	if body.Value.DailyTokenBudget != nil {
+		entry.DailyTokenBudget = *body.Value.DailyTokenBudget
+	}
+	if body.Value.MonthlyTokenBudget != nil {
+		entry.MonthlyTokenBudget = *body.Value.MonthlyTokenBudget
+	}
+	config.NormalizeClientAPIKey(&entry)
+	if entry.ExpiresAt != "" {
+		if _, ok := entry.ExpiryTime(); !ok {
+			c.JSON(400, gin.H{"error": "invalid expires-at"})
+			return
+		}
+	}
+	if targetIndex == -1 {
+		h.cfg.ClientAPIKeys = append(h.cfg.ClientAPIKeys, entry)
+	} else {
+		h.cfg.ClientAPIKeys[targetIndex] = entry
+	}
+	h.cfg.SanitizeClientAPIKeys()
+	h.persistLocked(c)
+}
+
+// DeleteAPIKeys removes structured client keys by ?api-key= or ?name=, and
+// plain keys by ?index= or ?value=.
 func (h *Handler) DeleteAPIKeys(c *gin.Context) {
-	h.deleteFromStringList(c, &h.cfg.APIKeys, func() {})
+	key := strings.TrimSpace(c.Query("api-key"))
+	name := strings.TrimSpace(c.Query("name"))
+	if key == "" && name == "" {
+		h.deleteFromStringList(c, &h.cfg.APIKeys, func() {})
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := make([]config.ClientAPIKey, 0, len(h.cfg.ClientAPIKeys))
+	for _, entry := range h.cfg.ClientAPIKeys {
+		if (key != "" && entry.APIKey == key) || (name != "" && entry.Name == name) {
+			continue
+		}
+		out = append(out, entry)
+	}
+	if len(out) == len(h.cfg.ClientAPIKeys) {
+		c.JSON(404, gin.H{"error": "item not found"})
+		return
+	}
+	h.cfg.ClientAPIKeys = out
+	h.persistLocked(c)
 }
 
 // gemini-api-key: []GeminiKey
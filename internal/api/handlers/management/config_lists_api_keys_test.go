@@ -0,0 +1,132 @@
+package management
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+)
+
+func TestPutAPIKeys_SplitsPlainAndStructuredKeys(t *testing.T) {
+	t.Parallel()
+
+	h := &Handler{cfg: &config.Config{}, configFilePath: writeTestConfigFile(t)}
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	body := `["plain-key",{"api-key":"team-key","name":"team-a","models":["gemini-*"]}]`
+	c.Request = httptest.NewRequest(http.MethodPut, "/v0/management/api-keys", strings.NewReader(body))
+
+	h.PutAPIKeys(c)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body=%s", rec.Code, rec.Body.String())
+	}
+	if len(h.cfg.APIKeys) != 1 || h.cfg.APIKeys[0] != "plain-key" {
+		t.Fatalf("api-keys = %#v", h.cfg.APIKeys)
+	}
+	if len(h.cfg.ClientAPIKeys) != 1 || h.cfg.ClientAPIKeys[0].Name != "team-a" {
+		t.Fatalf("client-api-keys = %#v", h.cfg.ClientAPIKeys)
+	}
+}
+
+func TestPutAPIKeys_PlainListKeepsStructuredKeys(t *testing.T) {
+	t.Parallel()
+
+	h := &Handler{
+		cfg: &config.Config{SDKConfig: config.SDKConfig{
+			APIKeys:       []string{"old-key"},
+			ClientAPIKeys: []config.ClientAPIKey{{APIKey: "team-key", Name: "team-a", Models: []string{"gemini-*"}}},
+		}},
+		configFilePath: writeTestConfigFile(t),
+	}
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodPut, "/v0/management/api-keys", strings.NewReader(`["k1"]`))
+
+	h.PutAPIKeys(c)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body=%s", rec.Code, rec.Body.String())
+	}
+	if len(h.cfg.APIKeys) != 1 || h.cfg.APIKeys[0] != "k1" {
+		t.Fatalf("api-keys = %#v", h.cfg.APIKeys)
+	}
+	if len(h.cfg.ClientAPIKeys) != 1 || h.cfg.ClientAPIKeys[0].Name != "team-a" || len(h.cfg.ClientAPIKeys[0].Models) != 1 {
+		t.Fatalf("client-api-keys = %#v, want the structured key kept", h.cfg.ClientAPIKeys)
+	}
+}
+
+func TestPatchAPIKeys_UpsertsStructuredKey(t *testing.T) {
+	t.Parallel()
+
+	h := &Handler{
+		cfg: &config.Config{SDKConfig: config.SDKConfig{
+			APIKeys:       []string{"plain-key"},
+			ClientAPIKeys: []config.ClientAPIKey{{APIKey: "team-key", Name: "team-a"}},
+		}},
+		configFilePath: writeTestConfigFile(t),
+	}
+
+	patch := func(body string) int {
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		c.Request = httptest.NewRequest(http.MethodPatch, "/v0/management/api-keys", strings.NewReader(body))
+		h.PatchAPIKeys(c)
+		return rec.Code
+	}
+
+	if code := patch(`{"match":"team-a","value":{"disabled":true,"expires-at":"2027-01-01"}}`); code != http.StatusOK {
+		t.Fatalf("update status = %d", code)
+	}
+	if entry := h.cfg.ClientAPIKeys[0]; !entry.Disabled || entry.ExpiresAt != "2027-01-01" {
+		t.Fatalf("updated entry = %#v", entry)
+	}
+	if code := patch(`{"match":"team-b","value":{"api-key":"team-b-key","name":"team-b"}}`); code != http.StatusOK {
+		t.Fatalf("insert status = %d", code)
+	}
+	if len(h.cfg.ClientAPIKeys) != 2 || h.cfg.ClientAPIKeys[1].APIKey != "team-b-key" {
+		t.Fatalf("client-api-keys = %#v", h.cfg.ClientAPIKeys)
+	}
+	if code := patch(`{"match":"team-a","value":{"expires-at":"later"}}`); code != http.StatusBadRequest {
+		t.Fatalf("invalid expiry status = %d", code)
+	}
+	if code := patch(`{"index":0,"value":"plain-key-2"}`); code != http.StatusOK {
+		t.Fatalf("plain patch status = %d", code)
+	}
+	if h.cfg.APIKeys[0] != "plain-key-2" {
+		t.Fatalf("api-keys = %#v", h.cfg.APIKeys)
+	}
+}
+
+func TestDeleteAPIKeys_ByName(t *testing.T) {
+	t.Parallel()
+
+	h := &Handler{
+		cfg: &config.Config{SDKConfig: config.SDKConfig{
+			APIKeys:       []string{"plain-key"},
+			ClientAPIKeys: []config.ClientAPIKey{{APIKey: "a", Name: "team-a"}, {APIKey: "b", Name: "team-b"}},
+		}},
+		configFilePath: writeTestConfigFile(t),
+	}
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodDelete, "/v0/management/api-keys?name=team-a", nil)
+
+	h.DeleteAPIKeys(c)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body=%s", rec.Code, rec.Body.String())
+	}
+	if len(h.cfg.ClientAPIKeys) != 1 || h.cfg.ClientAPIKeys[0].Name != "team-b" {
+		t.Fatalf("client-api-keys = %#v", h.cfg.ClientAPIKeys)
+	}
+	if len(h.cfg.APIKeys) != 1 {
+		t.Fatalf("plain keys should be untouched: %#v", h.cfg.APIKeys)
+	}
+}
@@ -14,14 +14,16 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/audit"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/buildinfo"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/configcheck"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/confighistory"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/pluginhost"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/pluginstore"
 	sdkAuth "github.com/router-for-me/CLIProxyAPI/v7/sdk/auth"
 	coreauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
 	log "github.com/sirupsen/logrus"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type attemptInfo struct {
@@ -46,6 +48,8 @@ type Handler struct {
 	appliedReloadGeneration uint64
 	attemptsMu              sync.Mutex
 	failedAttempts          map[string]*attemptInfo // keyed by client IP
+	verifiedKeysMu          sync.Mutex
+	verifiedKeys            map[[32]byte]string // sha256 of a verified key -> its bcrypt hash
 	authManager             *coreauth.Manager
 	tokenStore              coreauth.Store
 	localPassword           string
@@ -56,10 +60,13 @@ type Handler struct {
 	postAuthPersistHook     coreauth.PostAuthHook
 	pluginHost              *pluginhost.Host
 	configReloadHook        func(context.Context, *config.Config)
+	configModelPreview      configcheck.ModelPreviewFunc
 	pluginStoreRegistryURL  string
 	pluginStoreHTTPClient   pluginstore.HTTPDoer
 	pluginReleaseCacheMu    sync.Mutex
 	pluginReleaseCache      map[string]pluginReleaseCacheEntry
+	auditLog                *audit.Log
+	configHistory           *confighistory.History
 }
 
 type configReloadSnapshot struct {
@@ -176,7 +183,7 @@ func (h *Handler) reloadSnapshotConfigLocked() configReloadSnapshot {
 // saveConfigAndSnapshotLocked saves h.cfg and returns a full runtime config snapshot.
 // Callers must hold h.mu.
 func (h *Handler) saveConfigAndSnapshotLocked(c *gin.Context) (configReloadSnapshot, bool) {
-	if errSave := config.SaveConfigPreserveComments(h.configFilePath, h.cfg); errSave != nil {
+	if errSave := h.saveConfigLocked(c); errSave != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to save config: %v", errSave)})
 		return configReloadSnapshot{}, false
 	}
@@ -286,11 +293,12 @@ func (h *Handler) Middleware() gin.HandlerFunc {
 			provided = c.GetHeader("X-Management-Key")
 		}
 
-		allowed, statusCode, errMsg := h.AuthenticateManagementKey(clientIP, localClient, provided)
-		if !allowed {
+		principal, statusCode, errMsg := h.authenticateManagementPrincipal(clientIP, localClient, provided)
+		if principal == nil {
 			c.AbortWithStatusJSON(statusCode, gin.H{"error": errMsg})
 			return
 		}
+		c.Set(managementPrincipalContextKey, principal)
 		c.Next()
 	}
 }
@@ -298,21 +306,30 @@ func (h *Handler) Middleware() gin.HandlerFunc {
 // AuthenticateManagementKey verifies the provided management key for the given client.
 // It mirrors the behaviour of Middleware() so non-HTTP callers can reuse the same logic.
 func (h *Handler) AuthenticateManagementKey(clientIP string, localClient bool, provided string) (bool, int, string) {
+	principal, statusCode, errMsg := h.authenticateManagementPrincipal(clientIP, localClient, provided)
+	return principal != nil, statusCode, errMsg
+}
+
+// authenticateManagementPrincipal verifies the provided management key and
+// returns the name and scopes it grants.
+func (h *Handler) authenticateManagementPrincipal(clientIP string, localClient bool, provided string) (*managementPrincipal, int, string) {
 	const maxFailures = 5
 	const banDuration = 30 * time.Minute
 
 	if h == nil {
-		return false, http.StatusForbidden, "remote management disabled"
+		return nil, http.StatusForbidden, "remote management disabled"
 	}
 
 	cfg := h.cfg
 	var (
 		allowRemote bool
 		secretHash  string
+		keys        []config.ManagementKey
 	)
 	if cfg != nil {
 		allowRemote = cfg.RemoteManagement.AllowRemote
 		secretHash = cfg.RemoteManagement.SecretKey
+		keys = cfg.RemoteManagement.Keys
 	}
 	if h.allowRemoteOverride {
 		allowRemote = true
@@ -326,7 +343,7 @@ func (h *Handler) AuthenticateManagementKey(clientIP string, localClient bool, p
 		if now.Before(ai.blockedUntil) {
 			remaining := ai.blockedUntil.Sub(now).Round(time.Second)
 			h.attemptsMu.Unlock()
-			return false, http.StatusForbidden, fmt.Sprintf("IP banned due to too many failed attempts. Try again in %s", remaining)
+			return nil, http.StatusForbidden, fmt.Sprintf("IP banned due to too many failed attempts. Try again in %s", remaining)
 		}
 		// Ban expired, reset state
 		ai.blockedUntil = time.Time{}
@@ -335,7 +352,7 @@ func (h *Handler) AuthenticateManagementKey(clientIP string, localClient bool, p
 	h.attemptsMu.Unlock()
 
 	if !localClient && !allowRemote {
-		return false, http.StatusForbidden, "remote management disabled"
+		return nil, http.StatusForbidden, "remote management disabled"
 	}
 
 	fail := func() {
@@ -363,37 +380,57 @@ func (h *Handler) AuthenticateManagementKey(clientIP string, localClient bool, p
 		h.attemptsMu.Unlock()
 	}
 
-	if secretHash == "" && envSecret == "" {
-		return false, http.StatusForbidden, "remote management key not set"
+	if secretHash == "" && envSecret == "" && len(keys) == 0 {
+		return nil, http.StatusForbidden, "remote management key not set"
 	}
 
 	if provided == "" {
 		fail()
-		return false, http.StatusUnauthorized, "missing management key"
+		return nil, http.StatusUnauthorized, "missing management key"
 	}
 
 	if localClient {
 		if lp := h.localPassword; lp != "" {
 			if subtle.ConstantTimeCompare([]byte(provided), []byte(lp)) == 1 {
 				reset()
-				return true, 0, ""
+				return adminManagementPrincipal, 0, ""
 			}
 		}
 	}
 
 	if envSecret != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(envSecret)) == 1 {
 		reset()
-		return true, 0, ""
+		return adminManagementPrincipal, 0, ""
 	}
 
-	if secretHash == "" || bcrypt.CompareHashAndPassword([]byte(secretHash), []byte(provided)) != nil {
-		fail()
-		return false, http.StatusUnauthorized, "invalid management key"
+	// A key verified earlier matches without bcrypt while its hash is still configured.
+	if cached := h.verifiedManagementKeyHash(provided); cached != "" {
+		if cached == secretHash {
+			reset()
+			return adminManagementPrincipal, 0, ""
+		}
+		for _, key := range keys {
+			if key.Key == cached {
+				reset()
+				return &managementPrincipal{Name: key.Name, Scopes: key.Scopes}, 0, ""
+			}
+		}
+	}
+
+	if secretHash != "" && h.matchManagementKeyHash(secretHash, provided) {
+		reset()
+		return adminManagementPrincipal, 0, ""
 	}
 
-	reset()
+	for _, key := range keys {
+		if h.matchManagementKeyHash(key.Key, provided) {
+			reset()
+			return &managementPrincipal{Name: key.Name, Scopes: key.Scopes}, 0, ""
+		}
+	}
 
-	return true, 0, ""
+	fail()
+	return nil, http.StatusUnauthorized, "invalid management key"
 }
 
 // persist saves the current in-memory config to disk.
@@ -407,7 +444,7 @@ func (h *Handler) persist(c *gin.Context) bool {
 // It expects the caller to hold h.mu.
 func (h *Handler) persistLocked(c *gin.Context) bool {
 	// Preserve comments when writing
-	if err := config.SaveConfigPreserveComments(h.configFilePath, h.cfg); err != nil {
+	if err := h.saveConfigLocked(c); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to save config: %v", err)})
 		return false
 	}
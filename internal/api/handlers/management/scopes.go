@@ -0,0 +1,111 @@
+package management
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// managementPrincipalContextKey stores the authenticated management key in the gin context.
+const managementPrincipalContextKey = "managementPrincipal"
+
+// maxVerifiedManagementKeys caps the verified key cache; the cache is reset
+// when it fills up.
+const maxVerifiedManagementKeys = 256
+
+// managementPrincipal describes the management key that authenticated a request.
+type managementPrincipal struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// adminManagementPrincipal is granted to the secret-key, MANAGEMENT_PASSWORD
+// and the local password.
+var adminManagementPrincipal = &managementPrincipal{Name: "admin", Scopes: []string{config.ManagementScopeAdmin}}
+
+// verifiedManagementKeyHash returns the bcrypt hash provided last matched, if
+// any. The hash may be stale after a config reload re-hashed the key, so callers
+// only use it to find a still configured hash without a bcrypt comparison.
+func (h *Handler) verifiedManagementKeyHash(provided string) string {
+	sum := sha256.Sum256([]byte(provided))
+	h.verifiedKeysMu.Lock()
+	defer h.verifiedKeysMu.Unlock()
+	return h.verifiedKeys[sum]
+}
+
+// matchManagementKeyHash reports whether provided matches the bcrypt hash.
+// Successful matches are cached so named keys do not cost a bcrypt comparison
+// per key on every request.
+func (h *Handler) matchManagementKeyHash(hash, provided string) bool {
+	if hash == "" || provided == "" {
+		return false
+	}
+	if h.verifiedManagementKeyHash(provided) == hash {
+		return true
+	}
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(provided)) != nil {
+		return false
+	}
+	sum := sha256.Sum256([]byte(provided))
+	h.verifiedKeysMu.Lock()
+	if h.verifiedKeys == nil || len(h.verifiedKeys) >= maxVerifiedManagementKeys {
+		h.verifiedKeys = make(map[[32]byte]string)
+	}
+	h.verifiedKeys[sum] = hash
+	h.verifiedKeysMu.Unlock()
+	return true
+}
+
+func principalFromContext(c *gin.Context) *managementPrincipal {
+	if c == nil {
+		return nil
+	}
+	if v, ok := c.Get(managementPrincipalContextKey); ok {
+		if principal, okPrincipal := v.(*managementPrincipal); okPrincipal {
+			return principal
+		}
+	}
+	return nil
+}
+
+// AuthorizeScope reports whether the authenticated management key may access a
+// route group guarded by scope. GET and HEAD requests are also allowed by any of
+// readScopes. It writes a 403 response when access is denied.
+func (h *Handler) AuthorizeScope(c *gin.Context, scope string, readScopes ...string) bool {
+	principal := principalFromContext(c)
+	if principal == nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing management key"})
+		return false
+	}
+	read := c.Request != nil && (c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead)
+	if config.ManagementScopeAllows(principal.Scopes, read, scope, readScopes...) {
+		return true
+	}
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("management key %q lacks the %s scope", principal.Name, scope)})
+	return false
+}
+
+// RequireScope returns middleware that enforces AuthorizeScope for a route group.
+// It must run after Middleware.
+func (h *Handler) RequireScope(scope string, readScopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !h.AuthorizeScope(c, scope, readScopes...) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// GetWhoami returns the name and scopes of the management key used for the request.
+func (h *Handler) GetWhoami(c *gin.Context) {
+	principal := principalFromContext(c)
+	if principal == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing management key"})
+		return
+	}
+	c.JSON(http.StatusOK, principal)
+}
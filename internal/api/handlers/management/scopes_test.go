@@ -0,0 +1,97 @@
+package management
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestRequireScope_NamedManagementKeys(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("viewer-key"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt: %v", err)
+	}
+	h := &Handler{
+		cfg: &config.Config{RemoteManagement: config.RemoteManagement{Keys: []config.ManagementKey{
+			{Name: "viewer", Key: string(hash), Scopes: []string{config.ManagementScopeReadOnly}},
+		}}},
+		failedAttempts: make(map[string]*attemptInfo),
+		envSecret:      "admin-secret",
+	}
+
+	engine := gin.New()
+	mgmt := engine.Group("/v0/management", h.Middleware())
+	mgmt.GET("/whoami", h.GetWhoami)
+	settings := mgmt.Group("", h.RequireScope(config.ManagementScopeConfigAdmin, config.ManagementScopeReadOnly))
+	settings.GET("/debug", func(c *gin.Context) { c.Status(http.StatusOK) })
+	settings.PUT("/debug", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	do := func(method, path, key string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = "127.0.0.1:12345"
+		req.Header.Set("Authorization", "Bearer "+key)
+		engine.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(http.MethodGet, "/v0/management/debug", "viewer-key"); rec.Code != http.StatusOK {
+		t.Fatalf("read-only GET status = %d, want 200", rec.Code)
+	}
+	if rec := do(http.MethodPut, "/v0/management/debug", "viewer-key"); rec.Code != http.StatusForbidden {
+		t.Fatalf("read-only PUT status = %d, want 403", rec.Code)
+	}
+	if rec := do(http.MethodPut, "/v0/management/debug", "admin-secret"); rec.Code != http.StatusOK {
+		t.Fatalf("admin PUT status = %d, want 200", rec.Code)
+	}
+
+	rec := do(http.MethodGet, "/v0/management/whoami", "viewer-key")
+	var principal managementPrincipal
+	if err := json.Unmarshal(rec.Body.Bytes(), &principal); err != nil {
+		t.Fatalf("whoami body %s: %v", rec.Body.String(), err)
+	}
+	if principal.Name != "viewer" || len(principal.Scopes) != 1 || principal.Scopes[0] != config.ManagementScopeReadOnly {
+		t.Fatalf("whoami = %+v", principal)
+	}
+}
+
+func TestMiddleware_NamedKeySurvivesConfigReload(t *testing.T) {
+	newConfig := func() *config.Config {
+		// Plaintext keys are re-hashed with a fresh salt on every load.
+		hash, err := bcrypt.GenerateFromPassword([]byte("ops-key"), bcrypt.MinCost)
+		if err != nil {
+			t.Fatalf("bcrypt: %v", err)
+		}
+		return &config.Config{RemoteManagement: config.RemoteManagement{Keys: []config.ManagementKey{
+			{Name: "ops", Key: string(hash), Scopes: []string{config.ManagementScopeConfigAdmin}},
+		}}}
+	}
+	h := &Handler{cfg: newConfig(), failedAttempts: make(map[string]*attemptInfo)}
+	engine := gin.New()
+	engine.GET("/v0/management/whoami", h.Middleware(), h.GetWhoami)
+
+	do := func() int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/v0/management/whoami", nil)
+		req.RemoteAddr = "127.0.0.1:12345"
+		req.Header.Set("Authorization", "Bearer ops-key")
+		engine.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := do(); code != http.StatusOK {
+		t.Fatalf("first request status = %d, want 200", code)
+	}
+	h.SetConfig(newConfig())
+	if code := do(); code != http.StatusOK {
+		t.Fatalf("request after reload status = %d, want 200", code)
+	}
+	if ai := h.failedAttempts["127.0.0.1"]; ai != nil && ai.count != 0 {
+		t.Fatalf("failed attempts after reload = %d, want 0", ai.count)
+	}
+}
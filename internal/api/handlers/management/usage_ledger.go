@@ -0,0 +1,102 @@
+package management
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/usageledger"
+)
+
+// GetUsageLedgerStatus reports whether the persistent usage ledger is active.
+func (h *Handler) GetUsageLedgerStatus(c *gin.Context) {
+	if h == nil || h.cfg == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "handler unavailable"})
+		return
+	}
+	backend := strings.TrimSpace(h.cfg.UsageLedger.Backend)
+	if backend == "" {
+		backend = usageledger.BackendFile
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"enabled":        usageledger.Default().Enabled(),
+		"backend":        backend,
+		"retention-days": h.cfg.UsageLedger.RetentionDays,
+	})
+}
+
+// GetUsageLedgerAggregate aggregates persisted usage.
+//
+// Query parameters:
+//   - from, to: RFC3339 timestamps or YYYY-MM-DD dates (default: the last 7 days)
+//   - interval: hour, day, week, month or empty for a single bucket
+//   - group_by: comma-separated list of client_key, provider, auth_index, model
+//   - client_key, provider, auth_index, model: exact-match filters
+func (h *Handler) GetUsageLedgerAggregate(c *gin.Context) {
+	query, errQuery := parseUsageLedgerQuery(c)
+	if errQuery != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errQuery.Error()})
+		return
+	}
+	rows, errAggregate := usageledger.Default().Aggregate(c.Request.Context(), query)
+	if errAggregate != nil {
+		if errors.Is(errAggregate, usageledger.ErrDisabled) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": errAggregate.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errAggregate.Error()})
+		return
+	}
+	if rows == nil {
+		rows = []usageledger.Aggregate{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"from":     query.From,
+		"to":       query.To,
+		"interval": query.Interval,
+		"group_by": query.GroupBy,
+		"rows":     rows,
+	})
+}
+
+func parseUsageLedgerQuery(c *gin.Context) (usageledger.Query, error) {
+	query := usageledger.Query{
+		Interval:  c.Query("interval"),
+		ClientKey: strings.TrimSpace(c.Query("client_key")),
+		Provider:  strings.TrimSpace(c.Query("provider")),
+		AuthIndex: strings.TrimSpace(c.Query("auth_index")),
+		Model:     strings.TrimSpace(c.Query("model")),
+	}
+	if groupBy := strings.TrimSpace(c.Query("group_by")); groupBy != "" {
+		query.GroupBy = strings.Split(groupBy, ",")
+	}
+	var err error
+	if query.From, err = parseUsageLedgerTime(c.Query("from")); err != nil {
+		return query, fmt.Errorf("invalid from: %w", err)
+	}
+	if query.To, err = parseUsageLedgerTime(c.Query("to")); err != nil {
+		return query, fmt.Errorf("invalid to: %w", err)
+	}
+	if err = query.Normalize(); err != nil {
+		return query, err
+	}
+	return query, nil
+}
+
+func parseUsageLedgerTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed.UTC(), nil
+	}
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, errors.New("expected RFC3339 timestamp or YYYY-MM-DD date")
+	}
+	return parsed, nil
+}
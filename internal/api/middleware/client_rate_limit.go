@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/ratelimit"
+	"github.com/router-for-me/CLIProxyAPI/v7/sdk/api/handlers"
+)
+
+// ClientRateLimitMiddleware throttles authenticated requests according to the
+// limits of the client key stored by the auth middleware. Rejected requests
+// receive a 429 with Retry-After in the error format of the requested API.
+func ClientRateLimitMiddleware(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limiter == nil {
+			c.Next()
+			return
+		}
+		key, _ := c.Get("userApiKey")
+		keyString, _ := key.(string)
+		release, errLimit := limiter.Acquire(keyString)
+		if errLimit != nil {
+			c.Header("Retry-After", strconv.Itoa(errLimit.RetryAfterSeconds()))
+			c.Data(http.StatusTooManyRequests, "application/json", rateLimitErrorBody(c.Request.URL.Path, errLimit.Error()))
+			c.Abort()
+			return
+		}
+		defer release()
+		c.Next()
+	}
+}
+
+// rateLimitErrorBody renders a 429 error in the native format of the frontend serving path.
+func rateLimitErrorBody(path, message string) []byte {
+	switch {
+	case strings.HasPrefix(path, "/v1/messages"):
+		body, _ := json.Marshal(gin.H{
+			"type":  "error",
+			"error": gin.H{"type": "rate_limit_error", "message": message},
+		})
+		return body
+	case strings.HasPrefix(path, "/v1beta/"), strings.HasPrefix(path, "/v1internal"):
+		body, _ := json.Marshal(gin.H{
+			"error": gin.H{"code": http.StatusTooManyRequests, "message": message, "status": "RESOURCE_EXHAUSTED"},
+		})
+		return body
+	case strings.HasPrefix(path, "/api/"):
+		body, _ := json.Marshal(gin.H{"error": message})
+		return body
+	default:
+		return handlers.BuildErrorResponseBody(http.StatusTooManyRequests, message)
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/ratelimit"
+	"github.com/tidwall/gjson"
+)
+
+func TestClientRateLimitMiddlewareNativeErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limiter := ratelimit.NewLimiter()
+	limiter.Configure([]config.ClientAPIKey{{APIKey: "limited", RequestsPerMinute: 1}})
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userApiKey", "limited")
+		c.Next()
+	}, ClientRateLimitMiddleware(limiter))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.POST("/v1/chat/completions", ok)
+	router.POST("/v1/messages", ok)
+	router.POST("/v1beta/models/*action", ok)
+	router.POST("/api/chat", ok)
+
+	first := httptest.NewRecorder()
+	router.ServeHTTP(first, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil))
+	if first.Code != http.StatusOK {
+		t.Fatalf("first status = %d", first.Code)
+	}
+
+	cases := []struct {
+		path  string
+		field string
+		want  string
+	}{
+		{path: "/v1/chat/completions", field: "error.code", want: "rate_limit_exceeded"},
+		{path: "/v1/messages", field: "error.type", want: "rate_limit_error"},
+		{path: "/v1beta/models/gemini-2.5-pro:generateContent", field: "error.status", want: "RESOURCE_EXHAUSTED"},
+		{path: "/api/chat", field: "error", want: "rate limit of 1 requests per minute exceeded"},
+	}
+	for _, tc := range cases {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tc.path, nil))
+		if rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("%s status = %d", tc.path, rec.Code)
+		}
+		if rec.Header().Get("Retry-After") == "" {
+			t.Fatalf("%s missing Retry-After", tc.path)
+		}
+		if got := gjson.Get(rec.Body.String(), tc.field).String(); got != tc.want {
+			t.Fatalf("%s %s = %q, body=%s", tc.path, tc.field, got, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/logging"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/metrics"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/tracing"
+)
+
+// TracingMiddleware opens the server span for AI API requests, continuing an
+// incoming W3C traceparent when the client sends one. It must run after the
+// Gin logger, which assigns the request id used to recognise those paths.
+func TracingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := logging.GetGinRequestID(c)
+		if requestID == "" || !tracing.Enabled() {
+			c.Next()
+			return
+		}
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		ctx := tracing.Extract(c.Request.Context(), c.Request.Header)
+		ctx, span := tracing.StartKind(ctx, c.Request.Method+" "+route, tracing.SpanKindServer,
+			tracing.String("http.request.method", c.Request.Method),
+			tracing.String("http.route", route),
+			tracing.String("request.id", requestID),
+			tracing.String("frontend", metrics.Frontend(c.Request.URL.Path)),
+		)
+		c.Request = c.Request.WithContext(ctx)
+		defer span.End()
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(tracing.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(tracing.StatusError, http.StatusText(status))
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	proxyconfig "github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestManagementReadOnlyKeyCannotReadSecrets(t *testing.T) {
+	t.Setenv("MANAGEMENT_PASSWORD", "test-management-key")
+	server := newTestServer(t)
+	hash, err := bcrypt.GenerateFromPassword([]byte("viewer-key"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt: %v", err)
+	}
+	server.cfg.RemoteManagement.Keys = []proxyconfig.ManagementKey{
+		{Name: "viewer", Key: string(hash), Scopes: []string{proxyconfig.ManagementScopeReadOnly}},
+	}
+
+	do := func(path, key string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "127.0.0.1:12345"
+		req.Header.Set("Authorization", "Bearer "+key)
+		rr := httptest.NewRecorder()
+		server.engine.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	if code := do("/v0/management/debug", "viewer-key"); code != http.StatusOK {
+		t.Fatalf("read-only GET /debug status = %d, want 200", code)
+	}
+	for _, path := range []string{"/v0/management/claude-api-key", "/v0/management/api-keys", "/v0/management/config", "/v0/management/logs"} {
+		if code := do(path, "viewer-key"); code != http.StatusForbidden {
+			t.Fatalf("read-only GET %s status = %d, want 403", path, code)
+		}
+	}
+	if code := do("/v0/management/claude-api-key", "test-management-key"); code != http.StatusOK {
+		t.Fatalf("admin GET /claude-api-key status = %d, want 200", code)
+	}
+}
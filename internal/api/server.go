@@ -27,16 +27,19 @@ import (
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/api/middleware"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/cache"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/configcheck"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/home"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/logging"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/managementasset"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/pluginhost"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/ratelimit"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/redisqueue"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/util"
 	sdkaccess "github.com/router-for-me/CLIProxyAPI/v7/sdk/access"
 	"github.com/router-for-me/CLIProxyAPI/v7/sdk/api/handlers"
 	"github.com/router-for-me/CLIProxyAPI/v7/sdk/api/handlers/claude"
 	"github.com/router-for-me/CLIProxyAPI/v7/sdk/api/handlers/gemini"
+	"github.com/router-for-me/CLIProxyAPI/v7/sdk/api/handlers/ollama"
 	"github.com/router-for-me/CLIProxyAPI/v7/sdk/api/handlers/openai"
 	sdkAuth "github.com/router-for-me/CLIProxyAPI/v7/sdk/auth"
 	"github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
@@ -73,6 +76,7 @@ type serverOptionConfig struct {
 	postAuthPersistHook  auth.PostAuthHook
 	pluginHost           *pluginhost.Host
 	configReloadHook     func(context.Context, *config.Config)
+	configModelPreview   configcheck.ModelPreviewFunc
 }
 
 // ServerOption customises HTTP server construction.
@@ -172,6 +176,14 @@ func WithConfigReloadHook(hook func(context.Context, *config.Config)) ServerOpti
 	}
 }
 
+// WithConfigModelPreview registers the callback that resolves client-visible
+// models for management config dry runs.
+func WithConfigModelPreview(preview configcheck.ModelPreviewFunc) ServerOption {
+	return func(cfg *serverOptionConfig) {
+		cfg.configModelPreview = preview
+	}
+}
+
 // Server represents the main API server.
 // It encapsulates the Gin engine, HTTP server, handlers, and configuration.
 type Server struct {
@@ -200,6 +212,9 @@ type Server struct {
 	// accessManager handles request authentication providers.
 	accessManager *sdkaccess.Manager
 
+	// rateLimiter enforces per-client-key request rates and token budgets.
+	rateLimiter *ratelimit.Limiter
+
 	// requestLogger is the request logger instance for dynamic configuration updates.
 	requestLogger logging.RequestLogger
 	loggerToggle  func(bool)
@@ -270,6 +285,7 @@ func NewServer(cfg *config.Config, authManager *auth.Manager, accessManager *sdk
 	// Add middleware
 	engine.Use(logging.GinLogrusLogger())
 	engine.Use(logging.GinLogrusRecovery())
+	engine.Use(middleware.TracingMiddleware())
 	for _, mw := range optionState.extraMiddleware {
 		engine.Use(mw)
 	}
@@ -306,6 +322,7 @@ func NewServer(cfg *config.Config, authManager *auth.Manager, accessManager *sdk
 		handlers:            handlers.NewBaseAPIHandlers(effectiveSDKConfig(cfg), authManager),
 		cfg:                 cfg,
 		accessManager:       accessManager,
+		rateLimiter:         ratelimit.Default(),
 		requestLogger:       requestLogger,
 		loggerToggle:        toggle,
 		configFilePath:      configFilePath,
@@ -333,6 +350,7 @@ func NewServer(cfg *config.Config, authManager *auth.Manager, accessManager *sdk
 	s.mgmt = managementHandlers.NewHandler(cfg, configFilePath, authManager)
 	s.mgmt.SetPluginHost(optionState.pluginHost)
 	s.mgmt.SetConfigReloadHook(optionState.configReloadHook)
+	s.mgmt.SetConfigModelPreview(optionState.configModelPreview)
 	if optionState.localPassword != "" {
 		s.mgmt.SetLocalPassword(optionState.localPassword)
 	}
@@ -360,7 +378,7 @@ func NewServer(cfg *config.Config, authManager *auth.Manager, accessManager *sdk
 
 	// Register management routes when configuration or environment secrets are available,
 	// or when a local management password is provided (e.g. TUI mode).
-	hasManagementSecret := cfg.RemoteManagement.SecretKey != "" || envManagementSecret || s.localPassword != ""
+	hasManagementSecret := cfg.RemoteManagement.HasKeys() || envManagementSecret || s.localPassword != ""
 	s.managementRoutesEnabled.Store(hasManagementSecret)
 	redisqueue.SetEnabled(hasManagementSecret || (cfg != nil && cfg.Home.Enabled))
 	if hasManagementSecret {
@@ -424,16 +442,20 @@ func (s *Server) setupRoutes() {
 	geminiCLIHandlers := gemini.NewGeminiCLIAPIHandler(s.handlers)
 	claudeCodeHandlers := claude.NewClaudeCodeAPIHandler(s.handlers)
 	openaiResponsesHandlers := openai.NewOpenAIResponsesAPIHandler(s.handlers)
+	ollamaHandlers := ollama.NewOllamaAPIHandler(s.handlers)
 
 	// OpenAI compatible API routes
 	v1 := s.engine.Group("/v1")
-	v1.Use(AuthMiddleware(s.accessManager))
+	v1.Use(AuthMiddleware(s.accessManager), middleware.ClientRateLimitMiddleware(s.rateLimiter))
 	{
 		v1.GET("/models", s.unifiedModelsHandler(openaiHandlers, claudeCodeHandlers))
 		v1.POST("/chat/completions", openaiHandlers.ChatCompletions)
 		v1.POST("/completions", openaiHandlers.Completions)
+		v1.POST("/embeddings", openaiHandlers.Embeddings)
 		v1.POST("/images/generations", openaiHandlers.ImagesGenerations)
 		v1.POST("/images/edits", openaiHandlers.ImagesEdits)
+		v1.POST("/audio/transcriptions", openaiHandlers.AudioTranscriptions)
+		v1.POST("/audio/speech", openaiHandlers.AudioSpeech)
 		v1.POST("/videos", openaiHandlers.XAIVideosGenerations)
 		v1.POST("/videos/generations", openaiHandlers.XAIVideosGenerations)
 		v1.POST("/videos/edits", openaiHandlers.XAIVideosEdits)
@@ -444,10 +466,12 @@ func (s *Server) setupRoutes() {
 		v1.GET("/responses", openaiResponsesHandlers.ResponsesWebsocket)
 		v1.POST("/responses", openaiResponsesHandlers.Responses)
 		v1.POST("/responses/compact", openaiResponsesHandlers.Compact)
+		v1.GET("/responses/:id", openaiResponsesHandlers.GetResponse)
+		v1.DELETE("/responses/:id", openaiResponsesHandlers.DeleteResponse)
 	}
 
 	openaiV1 := s.engine.Group("/openai/v1")
-	openaiV1.Use(AuthMiddleware(s.accessManager))
+	openaiV1.Use(AuthMiddleware(s.accessManager), middleware.ClientRateLimitMiddleware(s.rateLimiter))
 	{
 		openaiV1.POST("/videos", openaiHandlers.VideosCreate)
 		openaiV1.GET("/videos/:video_id/content", openaiHandlers.VideosContent)
@@ -456,22 +480,34 @@ func (s *Server) setupRoutes() {
 
 	// Codex CLI direct route aliases (chatgpt_base_url compatible)
 	codexDirect := s.engine.Group("/backend-api/codex")
-	codexDirect.Use(AuthMiddleware(s.accessManager))
+	codexDirect.Use(AuthMiddleware(s.accessManager), middleware.ClientRateLimitMiddleware(s.rateLimiter))
 	{
 		codexDirect.GET("/responses", openaiResponsesHandlers.ResponsesWebsocket)
 		codexDirect.POST("/responses", openaiResponsesHandlers.Responses)
 		codexDirect.POST("/responses/compact", openaiResponsesHandlers.Compact)
+		codexDirect.GET("/responses/:id", openaiResponsesHandlers.GetResponse)
+		codexDirect.DELETE("/responses/:id", openaiResponsesHandlers.DeleteResponse)
 	}
 
 	// Gemini compatible API routes
 	v1beta := s.engine.Group("/v1beta")
-	v1beta.Use(AuthMiddleware(s.accessManager))
+	v1beta.Use(AuthMiddleware(s.accessManager), middleware.ClientRateLimitMiddleware(s.rateLimiter))
 	{
 		v1beta.GET("/models", s.geminiModelsHandler(geminiHandlers))
 		v1beta.POST("/models/*action", geminiHandlers.GeminiHandler)
 		v1beta.GET("/models/*action", s.geminiGetHandler(geminiHandlers))
 	}
 
+	// Ollama compatible API routes
+	ollamaAPI := s.engine.Group("/api")
+	ollamaAPI.Use(AuthMiddleware(s.accessManager), middleware.ClientRateLimitMiddleware(s.rateLimiter))
+	{
+		ollamaAPI.POST("/chat", ollamaHandlers.Chat)
+		ollamaAPI.POST("/generate", ollamaHandlers.Generate)
+		ollamaAPI.GET("/tags", ollamaHandlers.Tags)
+		ollamaAPI.POST("/show", ollamaHandlers.Show)
+	}
+
 	// Root endpoint
 	s.engine.GET("/", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -562,7 +598,8 @@ func (s *Server) setupRoutes() {
 }
 
 // AttachWebsocketRoute registers a websocket upgrade handler on the primary Gin engine.
-// The handler is served as-is without additional middleware beyond the standard stack already configured.
+// Besides the optional websocket auth, the upgrade is throttled by the client key rate limiter
+// like every other API route; an open connection holds one concurrent request slot of its key.
 func (s *Server) AttachWebsocketRoute(path string, handler http.Handler) {
 	if s == nil || s.engine == nil || handler == nil {
 		return
@@ -595,7 +632,7 @@ func (s *Server) AttachWebsocketRoute(path string, handler http.Handler) {
 		c.Abort()
 	}
 
-	s.engine.GET(trimmed, conditionalAuth, finalHandler)
+	s.engine.GET(trimmed, conditionalAuth, middleware.ClientRateLimitMiddleware(s.rateLimiter), finalHandler)
 }
 
 func (s *Server) registerManagementRoutes() {
@@ -609,143 +646,179 @@ func (s *Server) registerManagementRoutes() {
 	log.Info("management routes registered after secret key configuration")
 
 	mgmt := s.engine.Group("/v0/management")
-	mgmt.Use(s.managementAvailabilityMiddleware(), s.mgmt.Middleware())
+	mgmt.Use(s.managementAvailabilityMiddleware(), s.mgmt.Middleware(), s.mgmt.AuditMutations())
 	{
-		mgmt.GET("/config", s.mgmt.GetConfig)
-		mgmt.GET("/config.yaml", s.mgmt.GetConfigYAML)
-		mgmt.PUT("/config.yaml", s.mgmt.PutConfigYAML)
+		mgmt.GET("/whoami", s.mgmt.GetWhoami)
 		mgmt.GET("/latest-version", s.mgmt.GetLatestVersion)
-		mgmt.GET("/plugins", s.mgmt.ListPlugins)
-		mgmt.GET("/plugin-store", s.mgmt.ListPluginStore)
-		mgmt.POST("/plugin-store/:id/install", s.mgmt.InstallPluginFromStore)
-		mgmt.DELETE("/plugins/:id", s.mgmt.DeletePlugin)
-		mgmt.PATCH("/plugins/:id/enabled", s.mgmt.PatchPluginEnabled)
-		mgmt.GET("/plugins/:id/config", s.mgmt.GetPluginConfig)
-		mgmt.PUT("/plugins/:id/config", s.mgmt.PutPluginConfig)
-		mgmt.PATCH("/plugins/:id/config", s.mgmt.PatchPluginConfig)
-
-		mgmt.GET("/debug", s.mgmt.GetDebug)
-		mgmt.PUT("/debug", s.mgmt.PutDebug)
-		mgmt.PATCH("/debug", s.mgmt.PutDebug)
-
-		mgmt.GET("/logging-to-file", s.mgmt.GetLoggingToFile)
-		mgmt.PUT("/logging-to-file", s.mgmt.PutLoggingToFile)
-		mgmt.PATCH("/logging-to-file", s.mgmt.PutLoggingToFile)
-
-		mgmt.GET("/logs-max-total-size-mb", s.mgmt.GetLogsMaxTotalSizeMB)
-		mgmt.PUT("/logs-max-total-size-mb", s.mgmt.PutLogsMaxTotalSizeMB)
-		mgmt.PATCH("/logs-max-total-size-mb", s.mgmt.PutLogsMaxTotalSizeMB)
-
-		mgmt.GET("/error-logs-max-files", s.mgmt.GetErrorLogsMaxFiles)
-		mgmt.PUT("/error-logs-max-files", s.mgmt.PutErrorLogsMaxFiles)
-		mgmt.PATCH("/error-logs-max-files", s.mgmt.PutErrorLogsMaxFiles)
-
-		mgmt.GET("/usage-statistics-enabled", s.mgmt.GetUsageStatisticsEnabled)
-		mgmt.PUT("/usage-statistics-enabled", s.mgmt.PutUsageStatisticsEnabled)
-		mgmt.PATCH("/usage-statistics-enabled", s.mgmt.PutUsageStatisticsEnabled)
-
-		mgmt.GET("/proxy-url", s.mgmt.GetProxyURL)
-		mgmt.PUT("/proxy-url", s.mgmt.PutProxyURL)
-		mgmt.PATCH("/proxy-url", s.mgmt.PutProxyURL)
-		mgmt.DELETE("/proxy-url", s.mgmt.DeleteProxyURL)
-
-		mgmt.POST("/api-call", s.mgmt.APICall)
-
-		mgmt.GET("/quota-exceeded/switch-project", s.mgmt.GetSwitchProject)
-		mgmt.PUT("/quota-exceeded/switch-project", s.mgmt.PutSwitchProject)
-		mgmt.PATCH("/quota-exceeded/switch-project", s.mgmt.PutSwitchProject)
-
-		mgmt.GET("/quota-exceeded/switch-preview-model", s.mgmt.GetSwitchPreviewModel)
-		mgmt.PUT("/quota-exceeded/switch-preview-model", s.mgmt.PutSwitchPreviewModel)
-		mgmt.PATCH("/quota-exceeded/switch-preview-model", s.mgmt.PutSwitchPreviewModel)
-
-		mgmt.GET("/api-keys", s.mgmt.GetAPIKeys)
-		mgmt.PUT("/api-keys", s.mgmt.PutAPIKeys)
-		mgmt.PATCH("/api-keys", s.mgmt.PatchAPIKeys)
-		mgmt.DELETE("/api-keys", s.mgmt.DeleteAPIKeys)
-		mgmt.GET("/api-key-usage", s.mgmt.GetAPIKeyUsage)
-		mgmt.GET("/usage-queue", s.mgmt.GetUsageQueue)
-
-		mgmt.GET("/gemini-api-key", s.mgmt.GetGeminiKeys)
-		mgmt.PUT("/gemini-api-key", s.mgmt.PutGeminiKeys)
-		mgmt.PATCH("/gemini-api-key", s.mgmt.PatchGeminiKey)
-		mgmt.DELETE("/gemini-api-key", s.mgmt.DeleteGeminiKey)
-
-		mgmt.GET("/logs", s.mgmt.GetLogs)
-		mgmt.DELETE("/logs", s.mgmt.DeleteLogs)
-		mgmt.GET("/request-error-logs", s.mgmt.GetRequestErrorLogs)
-		mgmt.GET("/request-error-logs/:name", s.mgmt.DownloadRequestErrorLog)
-		mgmt.GET("/request-log-by-id/:id", s.mgmt.GetRequestLogByID)
-		mgmt.GET("/request-log", s.mgmt.GetRequestLog)
-		mgmt.PUT("/request-log", s.mgmt.PutRequestLog)
-		mgmt.PATCH("/request-log", s.mgmt.PutRequestLog)
-		mgmt.GET("/ws-auth", s.mgmt.GetWebsocketAuth)
-		mgmt.PUT("/ws-auth", s.mgmt.PutWebsocketAuth)
-		mgmt.PATCH("/ws-auth", s.mgmt.PutWebsocketAuth)
-
-		mgmt.GET("/request-retry", s.mgmt.GetRequestRetry)
-		mgmt.PUT("/request-retry", s.mgmt.PutRequestRetry)
-		mgmt.PATCH("/request-retry", s.mgmt.PutRequestRetry)
-		mgmt.GET("/max-retry-interval", s.mgmt.GetMaxRetryInterval)
-		mgmt.PUT("/max-retry-interval", s.mgmt.PutMaxRetryInterval)
-		mgmt.PATCH("/max-retry-interval", s.mgmt.PutMaxRetryInterval)
-
-		mgmt.GET("/force-model-prefix", s.mgmt.GetForceModelPrefix)
-		mgmt.PUT("/force-model-prefix", s.mgmt.PutForceModelPrefix)
-		mgmt.PATCH("/force-model-prefix", s.mgmt.PutForceModelPrefix)
-
-		mgmt.GET("/routing/strategy", s.mgmt.GetRoutingStrategy)
-		mgmt.PUT("/routing/strategy", s.mgmt.PutRoutingStrategy)
-		mgmt.PATCH("/routing/strategy", s.mgmt.PutRoutingStrategy)
-
-		mgmt.GET("/claude-api-key", s.mgmt.GetClaudeKeys)
-		mgmt.PUT("/claude-api-key", s.mgmt.PutClaudeKeys)
-		mgmt.PATCH("/claude-api-key", s.mgmt.PatchClaudeKey)
-		mgmt.DELETE("/claude-api-key", s.mgmt.DeleteClaudeKey)
-
-		mgmt.GET("/codex-api-key", s.mgmt.GetCodexKeys)
-		mgmt.PUT("/codex-api-key", s.mgmt.PutCodexKeys)
-		mgmt.PATCH("/codex-api-key", s.mgmt.PatchCodexKey)
-		mgmt.DELETE("/codex-api-key", s.mgmt.DeleteCodexKey)
-
-		mgmt.GET("/openai-compatibility", s.mgmt.GetOpenAICompat)
-		mgmt.PUT("/openai-compatibility", s.mgmt.PutOpenAICompat)
-		mgmt.PATCH("/openai-compatibility", s.mgmt.PatchOpenAICompat)
-		mgmt.DELETE("/openai-compatibility", s.mgmt.DeleteOpenAICompat)
-
-		mgmt.GET("/vertex-api-key", s.mgmt.GetVertexCompatKeys)
-		mgmt.PUT("/vertex-api-key", s.mgmt.PutVertexCompatKeys)
-		mgmt.PATCH("/vertex-api-key", s.mgmt.PatchVertexCompatKey)
-		mgmt.DELETE("/vertex-api-key", s.mgmt.DeleteVertexCompatKey)
-
-		mgmt.GET("/oauth-excluded-models", s.mgmt.GetOAuthExcludedModels)
-		mgmt.PUT("/oauth-excluded-models", s.mgmt.PutOAuthExcludedModels)
-		mgmt.PATCH("/oauth-excluded-models", s.mgmt.PatchOAuthExcludedModels)
-		mgmt.DELETE("/oauth-excluded-models", s.mgmt.DeleteOAuthExcludedModels)
-
-		mgmt.GET("/oauth-model-alias", s.mgmt.GetOAuthModelAlias)
-		mgmt.PUT("/oauth-model-alias", s.mgmt.PutOAuthModelAlias)
-		mgmt.PATCH("/oauth-model-alias", s.mgmt.PatchOAuthModelAlias)
-		mgmt.DELETE("/oauth-model-alias", s.mgmt.DeleteOAuthModelAlias)
-
-		mgmt.GET("/auth-files", s.mgmt.ListAuthFiles)
-		mgmt.GET("/auth-files/models", s.mgmt.GetAuthFileModels)
-		mgmt.GET("/model-definitions/:channel", s.mgmt.GetStaticModelDefinitions)
-		mgmt.GET("/auth-files/download", s.mgmt.DownloadAuthFile)
-		mgmt.POST("/auth-files", s.mgmt.UploadAuthFile)
-		mgmt.DELETE("/auth-files", s.mgmt.DeleteAuthFile)
-		mgmt.PATCH("/auth-files/status", s.mgmt.PatchAuthFileStatus)
-		mgmt.PATCH("/auth-files/fields", s.mgmt.PatchAuthFileFields)
-		mgmt.POST("/vertex/import", s.mgmt.ImportVertexCredential)
-
-		mgmt.GET("/anthropic-auth-url", s.mgmt.RequestAnthropicToken)
-		mgmt.GET("/codex-auth-url", s.mgmt.RequestCodexToken)
-		mgmt.GET("/gemini-cli-auth-url", s.mgmt.RequestGeminiCLIToken)
-		mgmt.GET("/antigravity-auth-url", s.mgmt.RequestAntigravityToken)
-		mgmt.GET("/kimi-auth-url", s.mgmt.RequestKimiToken)
-		mgmt.GET("/xai-auth-url", s.mgmt.RequestXAIToken)
-		mgmt.POST("/oauth-callback", s.mgmt.PostOAuthCallback)
-		mgmt.GET("/get-auth-status", s.mgmt.GetAuthStatus)
+	}
+
+	// Raw config access, secrets, log contents, rollbacks and arbitrary upstream calls need
+	// config-admin.
+	rawConfig := mgmt.Group("", s.mgmt.RequireScope(config.ManagementScopeConfigAdmin))
+	{
+		rawConfig.GET("/config.yaml", s.mgmt.GetConfigYAML)
+		rawConfig.PUT("/config.yaml", s.mgmt.PutConfigYAML)
+		rawConfig.POST("/config.yaml/dry-run", s.mgmt.DryRunConfig)
+		rawConfig.POST("/api-call", s.mgmt.APICall)
+		rawConfig.GET("/config-history/:id", s.mgmt.GetConfigRevision)
+		rawConfig.POST("/config-history/:id/rollback", s.mgmt.RollbackConfigRevision)
+
+		// The full config, provider and client keys and log contents carry secrets.
+		rawConfig.GET("/config", s.mgmt.GetConfig)
+
+		rawConfig.GET("/api-keys", s.mgmt.GetAPIKeys)
+		rawConfig.PUT("/api-keys", s.mgmt.PutAPIKeys)
+		rawConfig.PATCH("/api-keys", s.mgmt.PatchAPIKeys)
+		rawConfig.DELETE("/api-keys", s.mgmt.DeleteAPIKeys)
+
+		rawConfig.GET("/gemini-api-key", s.mgmt.GetGeminiKeys)
+		rawConfig.PUT("/gemini-api-key", s.mgmt.PutGeminiKeys)
+		rawConfig.PATCH("/gemini-api-key", s.mgmt.PatchGeminiKey)
+		rawConfig.DELETE("/gemini-api-key", s.mgmt.DeleteGeminiKey)
+
+		rawConfig.GET("/claude-api-key", s.mgmt.GetClaudeKeys)
+		rawConfig.PUT("/claude-api-key", s.mgmt.PutClaudeKeys)
+		rawConfig.PATCH("/claude-api-key", s.mgmt.PatchClaudeKey)
+		rawConfig.DELETE("/claude-api-key", s.mgmt.DeleteClaudeKey)
+
+		rawConfig.GET("/codex-api-key", s.mgmt.GetCodexKeys)
+		rawConfig.PUT("/codex-api-key", s.mgmt.PutCodexKeys)
+		rawConfig.PATCH("/codex-api-key", s.mgmt.PatchCodexKey)
+		rawConfig.DELETE("/codex-api-key", s.mgmt.DeleteCodexKey)
+
+		rawConfig.GET("/openai-compatibility", s.mgmt.GetOpenAICompat)
+		rawConfig.PUT("/openai-compatibility", s.mgmt.PutOpenAICompat)
+		rawConfig.PATCH("/openai-compatibility", s.mgmt.PatchOpenAICompat)
+		rawConfig.DELETE("/openai-compatibility", s.mgmt.DeleteOpenAICompat)
+
+		rawConfig.GET("/vertex-api-key", s.mgmt.GetVertexCompatKeys)
+		rawConfig.PUT("/vertex-api-key", s.mgmt.PutVertexCompatKeys)
+		rawConfig.PATCH("/vertex-api-key", s.mgmt.PatchVertexCompatKey)
+		rawConfig.DELETE("/vertex-api-key", s.mgmt.DeleteVertexCompatKey)
+
+		rawConfig.GET("/logs", s.mgmt.GetLogs)
+		rawConfig.DELETE("/logs", s.mgmt.DeleteLogs)
+		rawConfig.GET("/request-error-logs", s.mgmt.GetRequestErrorLogs)
+		rawConfig.GET("/request-error-logs/:name", s.mgmt.DownloadRequestErrorLog)
+		rawConfig.GET("/request-log-by-id/:id", s.mgmt.GetRequestLogByID)
+	}
+
+	settings := mgmt.Group("", s.mgmt.RequireScope(config.ManagementScopeConfigAdmin, config.ManagementScopeReadOnly))
+	{
+		settings.GET("/audit", s.mgmt.GetAudit)
+		settings.GET("/config-history", s.mgmt.ListConfigHistory)
+		settings.GET("/config-history/diff", s.mgmt.DiffConfigHistory)
+
+		settings.GET("/debug", s.mgmt.GetDebug)
+		settings.PUT("/debug", s.mgmt.PutDebug)
+		settings.PATCH("/debug", s.mgmt.PutDebug)
+
+		settings.GET("/logging-to-file", s.mgmt.GetLoggingToFile)
+		settings.PUT("/logging-to-file", s.mgmt.PutLoggingToFile)
+		settings.PATCH("/logging-to-file", s.mgmt.PutLoggingToFile)
+
+		settings.GET("/logs-max-total-size-mb", s.mgmt.GetLogsMaxTotalSizeMB)
+		settings.PUT("/logs-max-total-size-mb", s.mgmt.PutLogsMaxTotalSizeMB)
+		settings.PATCH("/logs-max-total-size-mb", s.mgmt.PutLogsMaxTotalSizeMB)
+
+		settings.GET("/error-logs-max-files", s.mgmt.GetErrorLogsMaxFiles)
+		settings.PUT("/error-logs-max-files", s.mgmt.PutErrorLogsMaxFiles)
+		settings.PATCH("/error-logs-max-files", s.mgmt.PutErrorLogsMaxFiles)
+
+		settings.GET("/usage-statistics-enabled", s.mgmt.GetUsageStatisticsEnabled)
+		settings.PUT("/usage-statistics-enabled", s.mgmt.PutUsageStatisticsEnabled)
+		settings.PATCH("/usage-statistics-enabled", s.mgmt.PutUsageStatisticsEnabled)
+
+		settings.GET("/proxy-url", s.mgmt.GetProxyURL)
+		settings.PUT("/proxy-url", s.mgmt.PutProxyURL)
+		settings.PATCH("/proxy-url", s.mgmt.PutProxyURL)
+		settings.DELETE("/proxy-url", s.mgmt.DeleteProxyURL)
+
+		settings.GET("/quota-exceeded/switch-project", s.mgmt.GetSwitchProject)
+		settings.PUT("/quota-exceeded/switch-project", s.mgmt.PutSwitchProject)
+		settings.PATCH("/quota-exceeded/switch-project", s.mgmt.PutSwitchProject)
+
+		settings.GET("/quota-exceeded/switch-preview-model", s.mgmt.GetSwitchPreviewModel)
+		settings.PUT("/quota-exceeded/switch-preview-model", s.mgmt.PutSwitchPreviewModel)
+		settings.PATCH("/quota-exceeded/switch-preview-model", s.mgmt.PutSwitchPreviewModel)
+
+		settings.GET("/request-log", s.mgmt.GetRequestLog)
+		settings.PUT("/request-log", s.mgmt.PutRequestLog)
+		settings.PATCH("/request-log", s.mgmt.PutRequestLog)
+		settings.GET("/ws-auth", s.mgmt.GetWebsocketAuth)
+		settings.PUT("/ws-auth", s.mgmt.PutWebsocketAuth)
+		settings.PATCH("/ws-auth", s.mgmt.PutWebsocketAuth)
+
+		settings.GET("/request-retry", s.mgmt.GetRequestRetry)
+		settings.PUT("/request-retry", s.mgmt.PutRequestRetry)
+		settings.PATCH("/request-retry", s.mgmt.PutRequestRetry)
+		settings.GET("/max-retry-interval", s.mgmt.GetMaxRetryInterval)
+		settings.PUT("/max-retry-interval", s.mgmt.PutMaxRetryInterval)
+		settings.PATCH("/max-retry-interval", s.mgmt.PutMaxRetryInterval)
+
+		settings.GET("/force-model-prefix", s.mgmt.GetForceModelPrefix)
+		settings.PUT("/force-model-prefix", s.mgmt.PutForceModelPrefix)
+		settings.PATCH("/force-model-prefix", s.mgmt.PutForceModelPrefix)
+
+		settings.GET("/routing/strategy", s.mgmt.GetRoutingStrategy)
+		settings.PUT("/routing/strategy", s.mgmt.PutRoutingStrategy)
+		settings.PATCH("/routing/strategy", s.mgmt.PutRoutingStrategy)
+
+		settings.GET("/oauth-excluded-models", s.mgmt.GetOAuthExcludedModels)
+		settings.PUT("/oauth-excluded-models", s.mgmt.PutOAuthExcludedModels)
+		settings.PATCH("/oauth-excluded-models", s.mgmt.PatchOAuthExcludedModels)
+		settings.DELETE("/oauth-excluded-models", s.mgmt.DeleteOAuthExcludedModels)
+
+		settings.GET("/oauth-model-alias", s.mgmt.GetOAuthModelAlias)
+		settings.PUT("/oauth-model-alias", s.mgmt.PutOAuthModelAlias)
+		settings.PATCH("/oauth-model-alias", s.mgmt.PatchOAuthModelAlias)
+		settings.DELETE("/oauth-model-alias", s.mgmt.DeleteOAuthModelAlias)
+	}
+
+	usage := mgmt.Group("", s.mgmt.RequireScope(config.ManagementScopeConfigAdmin, config.ManagementScopeReadOnly, config.ManagementScopeUsageViewer))
+	{
+		usage.GET("/api-key-usage", s.mgmt.GetAPIKeyUsage)
+		usage.GET("/usage-queue", s.mgmt.GetUsageQueue)
+		usage.GET("/usage-ledger", s.mgmt.GetUsageLedgerStatus)
+		usage.GET("/usage-ledger/aggregate", s.mgmt.GetUsageLedgerAggregate)
+	}
+
+	plugins := mgmt.Group("", s.mgmt.RequireScope(config.ManagementScopePluginsAdmin, config.ManagementScopeReadOnly))
+	{
+		plugins.GET("/plugins", s.mgmt.ListPlugins)
+		plugins.GET("/plugin-store", s.mgmt.ListPluginStore)
+		plugins.POST("/plugin-store/:id/install", s.mgmt.InstallPluginFromStore)
+		plugins.DELETE("/plugins/:id", s.mgmt.DeletePlugin)
+		plugins.PATCH("/plugins/:id/enabled", s.mgmt.PatchPluginEnabled)
+		plugins.GET("/plugins/:id/config", s.mgmt.GetPluginConfig)
+		plugins.PUT("/plugins/:id/config", s.mgmt.PutPluginConfig)
+		plugins.PATCH("/plugins/:id/config", s.mgmt.PatchPluginConfig)
+	}
+
+	authFiles := mgmt.Group("", s.mgmt.RequireScope(config.ManagementScopeAuthFiles, config.ManagementScopeReadOnly))
+	{
+		authFiles.GET("/auth-files", s.mgmt.ListAuthFiles)
+		authFiles.GET("/auth-files/models", s.mgmt.GetAuthFileModels)
+		authFiles.GET("/model-definitions/:channel", s.mgmt.GetStaticModelDefinitions)
+		authFiles.POST("/auth-files", s.mgmt.UploadAuthFile)
+		authFiles.DELETE("/auth-files", s.mgmt.DeleteAuthFile)
+		authFiles.PATCH("/auth-files/status", s.mgmt.PatchAuthFileStatus)
+		authFiles.PATCH("/auth-files/fields", s.mgmt.PatchAuthFileFields)
+		authFiles.POST("/vertex/import", s.mgmt.ImportVertexCredential)
+	}
+
+	// Credential downloads and OAuth logins are never granted by read-only.
+	authLogin := mgmt.Group("", s.mgmt.RequireScope(config.ManagementScopeAuthFiles))
+	{
+		authLogin.GET("/auth-files/download", s.mgmt.DownloadAuthFile)
+		authLogin.GET("/anthropic-auth-url", s.mgmt.RequestAnthropicToken)
+		authLogin.GET("/codex-auth-url", s.mgmt.RequestCodexToken)
+		authLogin.GET("/gemini-cli-auth-url", s.mgmt.RequestGeminiCLIToken)
+		authLogin.GET("/antigravity-auth-url", s.mgmt.RequestAntigravityToken)
+		authLogin.GET("/kimi-auth-url", s.mgmt.RequestKimiToken)
+		authLogin.GET("/xai-auth-url", s.mgmt.RequestXAIToken)
+		authLogin.POST("/oauth-callback", s.mgmt.PostOAuthCallback)
+		authLogin.GET("/get-auth-status", s.mgmt.GetAuthStatus)
 	}
 }
 
@@ -826,11 +899,16 @@ func (s *Server) pluginManagementNoRoute(c *gin.Context) {
 	if c.IsAborted() {
 		return
 	}
-	if s.mgmt.ServePluginAuthURL(c) {
-		c.Abort()
-		return
-	}
-	if s.pluginHost.ServeManagementHTTP(c.Writer, c.Request) {
+	handled := s.mgmt.AuditMutation(c, func() bool {
+		if s.mgmt.ServePluginAuthURL(c) {
+			return true
+		}
+		if !s.mgmt.AuthorizeScope(c, config.ManagementScopePluginsAdmin, config.ManagementScopeReadOnly) {
+			return true
+		}
+		return s.pluginHost.ServeManagementHTTP(c.Writer, c.Request)
+	})
+	if handled {
 		c.Abort()
 		return
 	}
@@ -1536,6 +1614,7 @@ func (s *Server) applyAccessConfig(oldCfg, newCfg *config.Config) {
 	if s == nil || s.accessManager == nil || newCfg == nil {
 		return
 	}
+	s.rateLimiter.Configure(newCfg.ClientAPIKeys)
 	if _, err := access.ApplyAccessProviders(s.accessManager, oldCfg, newCfg); err != nil {
 		return
 	}
@@ -1614,9 +1693,9 @@ func (s *Server) UpdateClients(cfg *config.Config) {
 
 	prevSecretEmpty := true
 	if oldCfg != nil {
-		prevSecretEmpty = oldCfg.RemoteManagement.SecretKey == ""
+		prevSecretEmpty = !oldCfg.RemoteManagement.HasKeys()
 	}
-	newSecretEmpty := cfg.RemoteManagement.SecretKey == ""
+	newSecretEmpty := !cfg.RemoteManagement.HasKeys()
 	if s.envManagementSecret {
 		s.registerManagementRoutes()
 		if s.managementRoutesEnabled.CompareAndSwap(false, true) {
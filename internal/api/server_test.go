@@ -14,6 +14,7 @@ import (
 	proxyconfig "github.com/router-for-me/CLIProxyAPI/v7/internal/config"
 	internallogging "github.com/router-for-me/CLIProxyAPI/v7/internal/logging"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/pluginhost"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/ratelimit"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/redisqueue"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/registry"
 	sdkaccess "github.com/router-for-me/CLIProxyAPI/v7/sdk/access"
@@ -626,3 +627,23 @@ func TestHomeModelsErrorMessage(t *testing.T) {
 		t.Fatalf("default message = %q, want fallback", msg)
 	}
 }
+
+func TestAttachWebsocketRouteAppliesClientRateLimit(t *testing.T) {
+	server := newTestServer(t)
+	server.rateLimiter = ratelimit.NewLimiter()
+	server.rateLimiter.Configure([]proxyconfig.ClientAPIKey{{APIKey: "test-key", RequestsPerMinute: 1}})
+	server.wsAuthEnabled.Store(true)
+	server.AttachWebsocketRoute("/v1/ws", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for i, want := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/ws", nil)
+		req.Header.Set("Authorization", "Bearer test-key")
+		rr := httptest.NewRecorder()
+		server.engine.ServeHTTP(rr, req)
+		if rr.Code != want {
+			t.Fatalf("request %d status = %d, want %d body=%s", i+1, rr.Code, want, rr.Body.String())
+		}
+	}
+}
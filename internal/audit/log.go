@@ -0,0 +1,150 @@
+// Package audit records management API mutations in an append-only JSONL file.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// maxLineSize bounds a single JSONL entry when reading the log back.
+const maxLineSize = 4 << 20
+
+// Entry is one recorded management API call.
+type Entry struct {
+	// ID increases monotonically within a log file.
+	ID int64 `json:"id"`
+	// Timestamp is when the call completed, in UTC.
+	Timestamp time.Time `json:"timestamp"`
+	// Key is the name of the management key that made the call.
+	Key string `json:"key"`
+	// ClientIP is the remote address of the caller.
+	ClientIP string `json:"client_ip"`
+	// Method is the HTTP method.
+	Method string `json:"method"`
+	// Route is the matched route pattern, or the request path for plugin routes.
+	Route string `json:"route"`
+	// Path is the request path without the query string.
+	Path string `json:"path"`
+	// Status is the HTTP status returned to the caller.
+	Status int `json:"status"`
+	// Changes lists the redacted config and auth changes made by the call.
+	Changes []string `json:"changes,omitempty"`
+}
+
+// Log appends entries to a JSONL file. Entries are never rewritten.
+type Log struct {
+	mu     sync.Mutex
+	path   string
+	nextID int64
+}
+
+// NewLog returns a log writing to path. The file is created on first append.
+func NewLog(path string) *Log {
+	return &Log{path: path}
+}
+
+// Path returns the file backing the log.
+func (l *Log) Path() string {
+	if l == nil {
+		return ""
+	}
+	return l.path
+}
+
+// Append assigns the next ID to entry and writes it to the log.
+func (l *Log) Append(entry Entry) (Entry, error) {
+	if l == nil {
+		return entry, nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.nextID == 0 {
+		lastID := int64(0)
+		if err := l.scanLocked(func(e Entry) { lastID = max(lastID, e.ID) }); err != nil {
+			return entry, err
+		}
+		l.nextID = lastID + 1
+	}
+	entry.ID = l.nextID
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+	entry.Timestamp = entry.Timestamp.UTC()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return entry, fmt.Errorf("audit: create directory: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return entry, fmt.Errorf("audit: open %s: %w", filepath.Base(l.path), err)
+	}
+	if err = json.NewEncoder(f).Encode(entry); err != nil {
+		_ = f.Close()
+		return entry, fmt.Errorf("audit: write entry: %w", err)
+	}
+	if err = f.Close(); err != nil {
+		return entry, fmt.Errorf("audit: close %s: %w", filepath.Base(l.path), err)
+	}
+	l.nextID++
+	return entry, nil
+}
+
+// List returns up to limit entries, newest first, after skipping offset
+// entries, together with the total number of entries in the log.
+func (l *Log) List(offset, limit int) ([]Entry, int, error) {
+	if l == nil {
+		return nil, 0, nil
+	}
+	l.mu.Lock()
+	var all []Entry
+	err := l.scanLocked(func(e Entry) { all = append(all, e) })
+	l.mu.Unlock()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := len(all)
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= total || limit <= 0 {
+		return []Entry{}, total, nil
+	}
+	end := min(offset+limit, total)
+	out := make([]Entry, 0, end-offset)
+	for i := total - 1 - offset; i >= total-end; i-- {
+		out = append(out, all[i])
+	}
+	return out, total, nil
+}
+
+func (l *Log) scanLocked(fn func(Entry)) error {
+	f, err := os.Open(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("audit: open %s: %w", filepath.Base(l.path), err)
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		var entry Entry
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil {
+			continue
+		}
+		fn(entry)
+	}
+	if err = scanner.Err(); err != nil {
+		return fmt.Errorf("audit: read %s: %w", filepath.Base(l.path), err)
+	}
+	return nil
+}
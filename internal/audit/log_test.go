@@ -0,0 +1,34 @@
+package audit
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLog_AppendAndListNewestFirst(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log := NewLog(path)
+	for _, route := range []string{"/a", "/b", "/c"} {
+		if _, err := log.Append(Entry{Route: route}); err != nil {
+			t.Fatalf("Append(%s): %v", route, err)
+		}
+	}
+
+	// A fresh log continues numbering from the existing file.
+	reopened := NewLog(path)
+	entry, err := reopened.Append(Entry{Route: "/d"})
+	if err != nil || entry.ID != 4 {
+		t.Fatalf("Append after reopen = %+v, %v; want ID 4", entry, err)
+	}
+
+	page, total, err := reopened.List(1, 2)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if total != 4 || len(page) != 2 || page[0].Route != "/c" || page[1].Route != "/b" {
+		t.Fatalf("List(1, 2) = %+v (total %d), want /c, /b of 4", page, total)
+	}
+	if page, _, _ = reopened.List(10, 2); len(page) != 0 {
+		t.Fatalf("List past the end = %+v, want empty", page)
+	}
+}
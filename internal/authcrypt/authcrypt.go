@@ -0,0 +1,374 @@
+// Package authcrypt implements envelope encryption for auth records at rest.
+//
+// Each record is encrypted with a random data key using AES-256-GCM. The data
+// key is wrapped with the key-encryption key configured for the process and
+// stored next to the ciphertext, so rotating the key-encryption key only needs
+// previous keys to stay available for reading until records are re-sealed.
+// Sealed records remain JSON objects, which keeps the file, git, Postgres and
+// object stores agnostic of the encryption.
+package authcrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+)
+
+// KeySize is the size in bytes of key-encryption keys and data keys.
+const KeySize = 32
+
+// envelopeVersion identifies the envelope format in the envelope marker field.
+const envelopeVersion = 1
+
+// envelopeMarker is the JSON field that marks a sealed auth record.
+const envelopeMarker = "cliproxy_envelope"
+
+// ErrNoKey is returned when a sealed record is read without a matching key.
+var ErrNoKey = errors.New("authcrypt: no key available to decrypt auth record")
+
+// envelope is the on-disk form of a sealed auth record.
+type envelope struct {
+	Version int    `json:"cliproxy_envelope"`
+	KeyID   string `json:"kid"`
+	DataKey string `json:"dek"`
+	Data    string `json:"data"`
+}
+
+// Keyring holds the key used to seal records and the previous keys accepted
+// when opening them.
+type Keyring struct {
+	primaryID string
+	keys      map[string][]byte
+}
+
+var current atomic.Pointer[Keyring]
+
+// SetKeyring installs the process-wide keyring. A nil keyring disables
+// encryption; sealed records can then no longer be read.
+func SetKeyring(k *Keyring) {
+	current.Store(k)
+}
+
+// CurrentKeyring returns the process-wide keyring, or nil when encryption is disabled.
+func CurrentKeyring() *Keyring {
+	return current.Load()
+}
+
+// Enabled reports whether new auth records are sealed.
+func Enabled() bool {
+	return current.Load() != nil
+}
+
+// NewKeyring returns a keyring that seals with primary and also opens records
+// sealed with any of previous.
+func NewKeyring(primary []byte, previous ...[]byte) (*Keyring, error) {
+	if len(primary) != KeySize {
+		return nil, fmt.Errorf("authcrypt: key must be %d bytes, got %d", KeySize, len(primary))
+	}
+	k := &Keyring{primaryID: keyID(primary), keys: make(map[string][]byte, len(previous)+1)}
+	k.keys[k.primaryID] = append([]byte(nil), primary...)
+	for i, key := range previous {
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("authcrypt: previous key %d must be %d bytes, got %d", i+1, KeySize, len(key))
+		}
+		id := keyID(key)
+		if _, exists := k.keys[id]; !exists {
+			k.keys[id] = append([]byte(nil), key...)
+		}
+	}
+	return k, nil
+}
+
+// LoadKeyring builds a keyring from configuration values. key is an encoded
+// key, keyFile a path to a file holding one, and previous a comma-separated
+// list of encoded keys that may still be used for decryption. It returns nil
+// when neither key nor keyFile is set.
+func LoadKeyring(key, keyFile, previous string) (*Keyring, error) {
+	key = strings.TrimSpace(key)
+	keyFile = strings.TrimSpace(keyFile)
+	if key == "" && keyFile != "" {
+		data, errRead := os.ReadFile(keyFile)
+		if errRead != nil {
+			return nil, fmt.Errorf("authcrypt: read key file: %w", errRead)
+		}
+		key = strings.TrimSpace(string(data))
+	}
+	if key == "" {
+		if strings.TrimSpace(previous) != "" {
+			return nil, fmt.Errorf("authcrypt: previous keys configured without a current key")
+		}
+		return nil, nil
+	}
+	primary, errParse := ParseKey(key)
+	if errParse != nil {
+		return nil, errParse
+	}
+	var olds [][]byte
+	for _, item := range strings.Split(previous, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		old, errOld := ParseKey(item)
+		if errOld != nil {
+			return nil, fmt.Errorf("authcrypt: previous key: %w", errOld)
+		}
+		olds = append(olds, old)
+	}
+	return NewKeyring(primary, olds...)
+}
+
+// ParseKey decodes a 32-byte key encoded as base64 (standard or URL alphabet,
+// padded or not) or hex.
+func ParseKey(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if len(s) == hex.EncodedLen(KeySize) {
+		if key, err := hex.DecodeString(s); err == nil {
+			return key, nil
+		}
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if key, err := enc.DecodeString(s); err == nil && len(key) == KeySize {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("authcrypt: key must be %d bytes encoded as base64 or hex", KeySize)
+}
+
+// GenerateKey returns a new random key encoded as base64.
+func GenerateKey() (string, error) {
+	key := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", fmt.Errorf("authcrypt: generate key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+func keyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+// IsSealed reports whether data is a sealed auth record.
+func IsSealed(data []byte) bool {
+	_, ok := parseEnvelope(data)
+	return ok
+}
+
+func parseEnvelope(data []byte) (envelope, bool) {
+	var env envelope
+	if !bytes.Contains(data, []byte(envelopeMarker)) {
+		return env, false
+	}
+	if err := json.Unmarshal(data, &env); err != nil || env.Version != envelopeVersion || env.Data == "" || env.DataKey == "" {
+		return env, false
+	}
+	return env, true
+}
+
+// Seal encrypts data with the process-wide keyring. Data is returned unchanged
+// when encryption is disabled or data is already sealed with the current key.
+func Seal(data []byte) ([]byte, error) {
+	k := current.Load()
+	if k == nil {
+		return data, nil
+	}
+	if env, ok := parseEnvelope(data); ok {
+		if env.KeyID == k.primaryID {
+			return data, nil
+		}
+		plain, errOpen := k.Open(data)
+		if errOpen != nil {
+			return nil, errOpen
+		}
+		data = plain
+	}
+	return k.Seal(data)
+}
+
+// Open decrypts a sealed record with the process-wide keyring. Plaintext
+// records are returned unchanged.
+func Open(data []byte) ([]byte, error) {
+	if !IsSealed(data) {
+		return data, nil
+	}
+	k := current.Load()
+	if k == nil {
+		return nil, ErrNoKey
+	}
+	return k.Open(data)
+}
+
+// NeedsSeal reports whether data should be rewritten: encryption is enabled
+// and data is plaintext or sealed with a previous key.
+func NeedsSeal(data []byte) bool {
+	k := current.Load()
+	if k == nil || len(bytes.TrimSpace(data)) == 0 {
+		return false
+	}
+	env, ok := parseEnvelope(data)
+	return !ok || env.KeyID != k.primaryID
+}
+
+// Seal encrypts data with a new data key wrapped by the primary key.
+func (k *Keyring) Seal(data []byte) ([]byte, error) {
+	dataKey := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, fmt.Errorf("authcrypt: generate data key: %w", err)
+	}
+	ciphertext, err := gcmSeal(dataKey, data, nil)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := gcmSeal(k.keys[k.primaryID], dataKey, []byte(k.primaryID))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(envelope{
+		Version: envelopeVersion,
+		KeyID:   k.primaryID,
+		DataKey: base64.StdEncoding.EncodeToString(wrapped),
+		Data:    base64.StdEncoding.EncodeToString(ciphertext),
+	})
+}
+
+// Open decrypts a sealed record.
+func (k *Keyring) Open(data []byte) ([]byte, error) {
+	env, ok := parseEnvelope(data)
+	if !ok {
+		return nil, fmt.Errorf("authcrypt: not a sealed auth record")
+	}
+	key, ok := k.keys[env.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w (key id %s)", ErrNoKey, env.KeyID)
+	}
+	wrapped, errWrapped := base64.StdEncoding.DecodeString(env.DataKey)
+	if errWrapped != nil {
+		return nil, fmt.Errorf("authcrypt: decode data key: %w", errWrapped)
+	}
+	ciphertext, errData := base64.StdEncoding.DecodeString(env.Data)
+	if errData != nil {
+		return nil, fmt.Errorf("authcrypt: decode data: %w", errData)
+	}
+	dataKey, err := gcmOpen(key, wrapped, []byte(env.KeyID))
+	if err != nil {
+		return nil, fmt.Errorf("authcrypt: unwrap data key: %w", err)
+	}
+	plain, err := gcmOpen(dataKey, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("authcrypt: decrypt auth record: %w", err)
+	}
+	return plain, nil
+}
+
+func gcmSeal(key, plaintext, additional []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("authcrypt: generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additional), nil
+}
+
+func gcmOpen(key, sealed, additional []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("authcrypt: ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additional)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("authcrypt: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("authcrypt: %w", err)
+	}
+	return aead, nil
+}
+
+// ReadFile reads an auth file and decrypts it when sealed.
+func ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Open(data)
+}
+
+// WriteFile seals data and writes it to path through a temporary file.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	sealed, err := Seal(data)
+	if err != nil {
+		return err
+	}
+	return writeAtomic(path, sealed, perm)
+}
+
+// SealFile seals the auth file at path in place when NeedsSeal reports it.
+// Stores call it after token storages write plaintext JSON to path.
+func SealFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if !NeedsSeal(data) {
+		return nil
+	}
+	sealed, err := Seal(data)
+	if err != nil {
+		return err
+	}
+	return writeAtomic(path, sealed, 0o600)
+}
+
+func writeAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName)
+		return err
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName)
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	if err = os.Rename(tmpName, path); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	return nil
+}
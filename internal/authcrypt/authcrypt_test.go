@@ -0,0 +1,159 @@
+package authcrypt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testKeyring(t *testing.T, previous ...string) (*Keyring, string) {
+	t.Helper()
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	k, err := LoadKeyring(key, "", strings.Join(previous, ","))
+	if err != nil {
+		t.Fatalf("LoadKeyring: %v", err)
+	}
+	return k, key
+}
+
+func TestSealOpen_RoundTrip(t *testing.T) {
+	k, _ := testKeyring(t)
+	SetKeyring(k)
+	t.Cleanup(func() { SetKeyring(nil) })
+
+	plain := []byte(`{"type":"codex","access_token":"secret"}`)
+	sealed, err := Seal(plain)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if !IsSealed(sealed) || bytes.Contains(sealed, []byte("secret")) {
+		t.Fatalf("sealed record = %s", sealed)
+	}
+	if NeedsSeal(sealed) || !NeedsSeal(plain) {
+		t.Fatal("NeedsSeal should only report plaintext records")
+	}
+	again, err := Seal(sealed)
+	if err != nil || !bytes.Equal(again, sealed) {
+		t.Fatalf("Seal of a current record should be a no-op, err %v", err)
+	}
+	opened, err := Open(sealed)
+	if err != nil || !bytes.Equal(opened, plain) {
+		t.Fatalf("Open = %s, %v", opened, err)
+	}
+	passthrough, err := Open(plain)
+	if err != nil || !bytes.Equal(passthrough, plain) {
+		t.Fatalf("Open plaintext = %s, %v", passthrough, err)
+	}
+
+	SetKeyring(nil)
+	if _, err = Open(sealed); !errors.Is(err, ErrNoKey) {
+		t.Fatalf("Open without key error = %v, want ErrNoKey", err)
+	}
+}
+
+func TestSeal_RotatesPreviousKey(t *testing.T) {
+	oldRing, oldKey := testKeyring(t)
+	SetKeyring(oldRing)
+	t.Cleanup(func() { SetKeyring(nil) })
+	plain := []byte(`{"type":"claude"}`)
+	sealedOld, err := Seal(plain)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	newRing, _ := testKeyring(t, oldKey)
+	SetKeyring(newRing)
+	if !NeedsSeal(sealedOld) {
+		t.Fatal("record sealed with a previous key should need sealing")
+	}
+	opened, err := Open(sealedOld)
+	if err != nil || !bytes.Equal(opened, plain) {
+		t.Fatalf("Open with previous key = %s, %v", opened, err)
+	}
+	sealedNew, err := Seal(sealedOld)
+	if err != nil {
+		t.Fatalf("re-Seal: %v", err)
+	}
+	if NeedsSeal(sealedNew) {
+		t.Fatal("re-sealed record should use the current key")
+	}
+
+	SetKeyring(oldRing)
+	if _, err = Open(sealedNew); !errors.Is(err, ErrNoKey) {
+		t.Fatalf("Open with old keyring error = %v, want ErrNoKey", err)
+	}
+}
+
+func TestLoadKeyring(t *testing.T) {
+	if k, err := LoadKeyring("", "", ""); k != nil || err != nil {
+		t.Fatalf("empty config = %v, %v; want nil, nil", k, err)
+	}
+	if _, err := LoadKeyring("", "", "abc"); err == nil {
+		t.Fatal("previous keys without a current key should fail")
+	}
+	if _, err := LoadKeyring("short", "", ""); err == nil {
+		t.Fatal("invalid key should fail")
+	}
+
+	raw := bytes.Repeat([]byte{7}, KeySize)
+	keyFile := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(keyFile, []byte(base64.RawURLEncoding.EncodeToString(raw)+"\n"), 0o600); err != nil {
+		t.Fatalf("write key file: %v", err)
+	}
+	fromFile, err := LoadKeyring("", keyFile, "")
+	if err != nil {
+		t.Fatalf("LoadKeyring from file: %v", err)
+	}
+	fromHex, err := LoadKeyring("0707070707070707070707070707070707070707070707070707070707070707", "", "")
+	if err != nil {
+		t.Fatalf("LoadKeyring hex: %v", err)
+	}
+	if fromFile.primaryID != fromHex.primaryID {
+		t.Fatal("base64 and hex encodings of the same key should match")
+	}
+}
+
+func TestSealFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "auth.json")
+	plain := []byte(`{"type":"gemini"}`)
+	if err := os.WriteFile(path, plain, 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	if err := SealFile(path); err != nil {
+		t.Fatalf("SealFile disabled: %v", err)
+	}
+	if data, _ := os.ReadFile(path); !bytes.Equal(data, plain) {
+		t.Fatal("SealFile should not change files when encryption is disabled")
+	}
+
+	k, _ := testKeyring(t)
+	SetKeyring(k)
+	t.Cleanup(func() { SetKeyring(nil) })
+	if err := SealFile(path); err != nil {
+		t.Fatalf("SealFile: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !IsSealed(data) {
+		t.Fatalf("file not sealed: %s, %v", data, err)
+	}
+	opened, err := ReadFile(path)
+	if err != nil || !bytes.Equal(opened, plain) {
+		t.Fatalf("ReadFile = %s, %v", opened, err)
+	}
+	if err = SealFile(filepath.Join(dir, "missing.json")); err != nil {
+		t.Fatalf("SealFile missing: %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Fatalf("unexpected files left in dir: %d", len(entries))
+	}
+}
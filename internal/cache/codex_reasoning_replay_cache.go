@@ -9,6 +9,7 @@ import (
 	"time"
 
 	homekv "github.com/router-for-me/CLIProxyAPI/v7/internal/home"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/sharedstate"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/signature"
 	log "github.com/sirupsen/logrus"
 	"github.com/tidwall/gjson"
@@ -47,7 +48,7 @@ type codexReasoningReplayKVClient interface {
 }
 
 var currentCodexReasoningReplayKVClient = func() (codexReasoningReplayKVClient, bool, error) {
-	return homekv.CurrentKVClient()
+	return sharedstate.CurrentKV()
 }
 
 // CacheCodexReasoningReplayItem stores a final GPT/Codex reasoning item for
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	homekv "github.com/router-for-me/CLIProxyAPI/v7/internal/home"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/sharedstate"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -48,7 +49,7 @@ type signatureKVClient interface {
 }
 
 var currentSignatureKVClient = func() (signatureKVClient, bool, error) {
-	return homekv.CurrentKVClient()
+	return sharedstate.CurrentKV()
 }
 
 // groupCache is the inner map type
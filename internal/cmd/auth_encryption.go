@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/authcrypt"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	sdkAuth "github.com/router-for-me/CLIProxyAPI/v7/sdk/auth"
+	log "github.com/sirupsen/logrus"
+)
+
+// DoEncryptAuthFiles re-saves every auth record of the registered token store
+// so plaintext records and records sealed with a previous key are sealed with
+// the current key. It serves both as the migration for existing plaintext
+// files and as the key rotation step. It reports whether all records were saved.
+func DoEncryptAuthFiles(cfg *config.Config) bool {
+	if !authcrypt.Enabled() {
+		log.Error("encrypt-auth-files: AUTH_ENCRYPTION_KEY or AUTH_ENCRYPTION_KEY_FILE is not set")
+		return false
+	}
+	ctx := context.Background()
+	store := sdkAuth.GetTokenStore()
+	if setter, ok := store.(interface{ SetBaseDir(string) }); ok && cfg != nil {
+		setter.SetBaseDir(cfg.AuthDir)
+	}
+	auths, errList := store.List(ctx)
+	if errList != nil {
+		log.Errorf("encrypt-auth-files: list auth records failed: %v", errList)
+		return false
+	}
+	failed := 0
+	for _, auth := range auths {
+		if auth == nil {
+			continue
+		}
+		if _, errSave := store.Save(ctx, auth); errSave != nil {
+			log.Errorf("encrypt-auth-files: save %s failed: %v", auth.ID, errSave)
+			failed++
+		}
+	}
+	fmt.Printf("Encrypted %d auth records with the current key (%d failed)\n", len(auths)-failed, failed)
+	return failed == 0
+}
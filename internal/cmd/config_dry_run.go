@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/configcheck"
+	"github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy"
+	log "github.com/sirupsen/logrus"
+)
+
+// DoConfigDryRun validates the candidate config at candidatePath, compares it
+// with cfg and prints the report as JSON. samplePath optionally names a JSON
+// sample request evaluated against the candidate payload rules. It reports
+// whether the candidate is valid.
+func DoConfigDryRun(cfg *config.Config, candidatePath, samplePath string) bool {
+	data, errRead := os.ReadFile(strings.TrimSpace(candidatePath))
+	if errRead != nil {
+		log.Errorf("dry-run: read config failed: %v", errRead)
+		return false
+	}
+	opts := configcheck.Options{
+		Current:       cfg,
+		PreviewModels: cliproxy.PreviewConfigModels,
+	}
+	if path := strings.TrimSpace(samplePath); path != "" {
+		raw, errSample := os.ReadFile(path)
+		if errSample != nil {
+			log.Errorf("dry-run: read sample request failed: %v", errSample)
+			return false
+		}
+		var sample configcheck.SampleRequest
+		if errDecode := json.Unmarshal(raw, &sample); errDecode != nil {
+			log.Errorf("dry-run: invalid sample request: %v", errDecode)
+			return false
+		}
+		opts.Sample = &sample
+	}
+
+	report := configcheck.Run(context.Background(), data, opts)
+	out, errMarshal := json.MarshalIndent(report, "", "  ")
+	if errMarshal != nil {
+		log.Errorf("dry-run: encode report failed: %v", errMarshal)
+		return false
+	}
+	fmt.Println(string(out))
+	return report.Valid
+}
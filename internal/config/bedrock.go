@@ -0,0 +1,133 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/registry"
+)
+
+const (
+	// BedrockAPIConverse selects the Bedrock Converse/ConverseStream endpoints.
+	BedrockAPIConverse = "converse"
+	// BedrockAPIInvoke selects the Anthropic-native invoke-model endpoints.
+	BedrockAPIInvoke = "invoke"
+
+	// DefaultBedrockRegion is used when a Bedrock key does not specify a region.
+	DefaultBedrockRegion = "us-east-1"
+)
+
+// BedrockKey represents an AWS Bedrock credential. Requests are signed with
+// AWS Signature Version 4 using the configured access keys.
+type BedrockKey struct {
+	// AccessKeyID is the AWS access key ID used for SigV4 signing.
+	AccessKeyID string `yaml:"access-key-id" json:"access-key-id"`
+
+	// SecretAccessKey is the AWS secret access key used for SigV4 signing.
+	SecretAccessKey string `yaml:"secret-access-key" json:"secret-access-key"`
+
+	// SessionToken is an optional STS session token for temporary credentials.
+	SessionToken string `yaml:"session-token,omitempty" json:"session-token,omitempty"`
+
+	// Region is the AWS region hosting the Bedrock runtime; defaults to us-east-1.
+	Region string `yaml:"region,omitempty" json:"region,omitempty"`
+
+	// API selects the upstream API: "converse" (default) or "invoke" for the
+	// Anthropic-native invoke-model endpoints.
+	API string `yaml:"api,omitempty" json:"api,omitempty"`
+
+	// Priority controls selection preference when multiple credentials match.
+	// Higher values are preferred; defaults to 0.
+	Priority int `yaml:"priority,omitempty" json:"priority,omitempty"`
+
+	// Prefix optionally namespaces models for this credential (e.g., "teamA/claude-sonnet-4").
+	Prefix string `yaml:"prefix,omitempty" json:"prefix,omitempty"`
+
+	// BaseURL optionally overrides the Bedrock runtime endpoint.
+	// When empty, https://bedrock-runtime.{region}.amazonaws.com is used.
+	BaseURL string `yaml:"base-url,omitempty" json:"base-url,omitempty"`
+
+	// ProxyURL overrides the global proxy setting for this credential if provided.
+	ProxyURL string `yaml:"proxy-url,omitempty" json:"proxy-url,omitempty"`
+
+	// Models maps Bedrock model IDs (or inference profile IDs) to client-facing aliases.
+	Models []BedrockModel `yaml:"models,omitempty" json:"models,omitempty"`
+
+	// Headers optionally adds extra HTTP headers for requests sent with this credential.
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+
+	// ExcludedModels lists model IDs that should be excluded for this provider.
+	ExcludedModels []string `yaml:"excluded-models,omitempty" json:"excluded-models,omitempty"`
+
+	// DisableCooling disables auth/model cooldown scheduling for this credential when true.
+	DisableCooling bool `yaml:"disable-cooling,omitempty" json:"disable-cooling,omitempty"`
+}
+
+func (k BedrockKey) GetAPIKey() string  { return k.AccessKeyID }
+func (k BedrockKey) GetBaseURL() string { return k.BaseURL }
+
+// BedrockModel maps a Bedrock model ID to the alias exposed to clients.
+type BedrockModel struct {
+	// Name is the Bedrock model ID or inference profile ID,
+	// e.g. "us.anthropic.claude-sonnet-4-20250514-v1:0".
+	Name string `yaml:"name" json:"name"`
+
+	// Alias is the client-facing model name that maps to Name.
+	Alias string `yaml:"alias" json:"alias"`
+
+	// Pricing overrides the catalog prices used for cost accounting.
+	Pricing *registry.ModelPricing `yaml:"pricing,omitempty" json:"pricing,omitempty"`
+}
+
+func (m BedrockModel) GetName() string                    { return m.Name }
+func (m BedrockModel) GetAlias() string                   { return m.Alias }
+func (m BedrockModel) GetPricing() *registry.ModelPricing { return m.Pricing }
+
+// SanitizeBedrockKeys normalizes Bedrock credentials and drops entries without access keys.
+func (cfg *Config) SanitizeBedrockKeys() {
+	if cfg == nil {
+		return
+	}
+
+	seen := make(map[string]struct{}, len(cfg.BedrockKey))
+	out := cfg.BedrockKey[:0]
+	for i := range cfg.BedrockKey {
+		entry := cfg.BedrockKey[i]
+		entry.AccessKeyID = strings.TrimSpace(entry.AccessKeyID)
+		entry.SecretAccessKey = strings.TrimSpace(entry.SecretAccessKey)
+		entry.SessionToken = strings.TrimSpace(entry.SessionToken)
+		if entry.AccessKeyID == "" || entry.SecretAccessKey == "" {
+			continue
+		}
+		entry.Region = strings.TrimSpace(entry.Region)
+		if entry.Region == "" {
+			entry.Region = DefaultBedrockRegion
+		}
+		entry.API = strings.ToLower(strings.TrimSpace(entry.API))
+		if entry.API != BedrockAPIInvoke {
+			entry.API = BedrockAPIConverse
+		}
+		entry.Prefix = normalizeModelPrefix(entry.Prefix)
+		entry.BaseURL = strings.TrimSpace(entry.BaseURL)
+		entry.ProxyURL = strings.TrimSpace(entry.ProxyURL)
+		entry.Headers = NormalizeHeaders(entry.Headers)
+		entry.ExcludedModels = NormalizeExcludedModels(entry.ExcludedModels)
+
+		sanitizedModels := make([]BedrockModel, 0, len(entry.Models))
+		for _, model := range entry.Models {
+			model.Name = strings.TrimSpace(model.Name)
+			model.Alias = strings.TrimSpace(model.Alias)
+			if model.Name != "" {
+				sanitizedModels = append(sanitizedModels, model)
+			}
+		}
+		entry.Models = sanitizedModels
+
+		uniqueKey := entry.AccessKeyID + "|" + entry.Region + "|" + entry.BaseURL
+		if _, exists := seen[uniqueKey]; exists {
+			continue
+		}
+		seen[uniqueKey] = struct{}{}
+		out = append(out, entry)
+	}
+	cfg.BedrockKey = out
+}
@@ -0,0 +1,211 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+// ClientAPIKey describes a client-facing proxy key with optional restrictions.
+// Plain entries in api-keys remain unrestricted; entries here can be labelled,
+// disabled, expired and limited to a subset of models and endpoints.
+type ClientAPIKey struct {
+	// APIKey is the secret presented by the client.
+	APIKey string `yaml:"api-key" json:"api-key"`
+
+	// Name labels the key in usage records, request logs and management output.
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+
+	// ExpiresAt is an optional RFC3339 timestamp after which the key is rejected.
+	ExpiresAt string `yaml:"expires-at,omitempty" json:"expires-at,omitempty"`
+
+	// Models lists allowed model globs (e.g. "gemini-*", "*/claude-*").
+	// An empty list allows every model.
+	Models []string `yaml:"models,omitempty" json:"models,omitempty"`
+
+	// Endpoints lists allowed request path globs (e.g. "/v1/chat/completions", "/v1beta/*").
+	// An empty list allows every endpoint.
+	Endpoints []string `yaml:"endpoints,omitempty" json:"endpoints,omitempty"`
+
+	// Disabled rejects the key without removing it from the configuration.
+	Disabled bool `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+
+	// RequestsPerMinute caps requests in any sliding one-minute window. 0 disables the limit.
+	RequestsPerMinute int `yaml:"requests-per-minute,omitempty" json:"requests-per-minute,omitempty"`
+
+	// MaxConcurrentRequests caps in-flight requests. 0 disables the limit.
+	MaxConcurrentRequests int `yaml:"max-concurrent-requests,omitempty" json:"max-concurrent-requests,omitempty"`
+
+	// DailyTokenBudget caps total tokens per UTC day. 0 disables the budget.
+	DailyTokenBudget int64 `yaml:"daily-token-budget,omitempty" json:"daily-token-budget,omitempty"`
+
+	// MonthlyTokenBudget caps total tokens per UTC calendar month. 0 disables the budget.
+	MonthlyTokenBudget int64 `yaml:"monthly-token-budget,omitempty" json:"monthly-token-budget,omitempty"`
+}
+
+// Label returns the key name, falling back to a masked form of the secret.
+func (k ClientAPIKey) Label() string {
+	if name := strings.TrimSpace(k.Name); name != "" {
+		return name
+	}
+	return MaskClientAPIKey(k.APIKey)
+}
+
+// ExpiryTime parses ExpiresAt. The boolean is false when no valid expiry is set.
+func (k ClientAPIKey) ExpiryTime() (time.Time, bool) {
+	raw := strings.TrimSpace(k.ExpiresAt)
+	if raw == "" {
+		return time.Time{}, false
+	}
+	if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
+		return parsed, true
+	}
+	if parsed, err := time.Parse("2006-01-02", raw); err == nil {
+		return parsed, true
+	}
+	return time.Time{}, false
+}
+
+// IsExpired reports whether the key has passed its expiry at the given time.
+func (k ClientAPIKey) IsExpired(now time.Time) bool {
+	expiry, ok := k.ExpiryTime()
+	return ok && !now.Before(expiry)
+}
+
+// AllowsModel reports whether the model matches one of the configured globs.
+func (k ClientAPIKey) AllowsModel(model string) bool {
+	return MatchClientAPIKeyPatterns(k.Models, model)
+}
+
+// AllowsEndpoint reports whether the request path matches one of the configured globs.
+func (k ClientAPIKey) AllowsEndpoint(path string) bool {
+	return MatchClientAPIKeyPatterns(k.Endpoints, path)
+}
+
+// MatchClientAPIKeyPatterns performs case-insensitive glob matching where '*'
+// matches any substring. An empty pattern list matches everything.
+func MatchClientAPIKeyPatterns(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, pattern := range patterns {
+		if matchClientAPIKeyPattern(strings.ToLower(strings.TrimSpace(pattern)), value) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchClientAPIKeyPattern(pattern, value string) bool {
+	if pattern == "" {
+		return false
+	}
+	if !strings.Contains(pattern, "*") {
+		return pattern == value
+	}
+	parts := strings.Split(pattern, "*")
+	if prefix := parts[0]; prefix != "" {
+		if !strings.HasPrefix(value, prefix) {
+			return false
+		}
+		value = value[len(prefix):]
+	}
+	if suffix := parts[len(parts)-1]; suffix != "" {
+		if !strings.HasSuffix(value, suffix) {
+			return false
+		}
+		value = value[:len(value)-len(suffix)]
+	}
+	for i := 1; i < len(parts)-1; i++ {
+		segment := parts[i]
+		if segment == "" {
+			continue
+		}
+		idx := strings.Index(value, segment)
+		if idx < 0 {
+			return false
+		}
+		value = value[idx+len(segment):]
+	}
+	return true
+}
+
+// MaskClientAPIKey hides all but the first and last four characters of a key.
+func MaskClientAPIKey(key string) string {
+	key = strings.TrimSpace(key)
+	if len(key) <= 8 {
+		return strings.Repeat("*", len(key))
+	}
+	return key[:4] + "..." + key[len(key)-4:]
+}
+
+// NormalizeClientAPIKey trims fields and drops empty list entries in place.
+func NormalizeClientAPIKey(entry *ClientAPIKey) {
+	if entry == nil {
+		return
+	}
+	entry.APIKey = strings.TrimSpace(entry.APIKey)
+	entry.Name = strings.TrimSpace(entry.Name)
+	entry.ExpiresAt = strings.TrimSpace(entry.ExpiresAt)
+	entry.Models = normalizeClientAPIKeyPatterns(entry.Models)
+	entry.Endpoints = normalizeClientAPIKeyPatterns(entry.Endpoints)
+	if entry.RequestsPerMinute < 0 {
+		entry.RequestsPerMinute = 0
+	}
+	if entry.MaxConcurrentRequests < 0 {
+		entry.MaxConcurrentRequests = 0
+	}
+	if entry.DailyTokenBudget < 0 {
+		entry.DailyTokenBudget = 0
+	}
+	if entry.MonthlyTokenBudget < 0 {
+		entry.MonthlyTokenBudget = 0
+	}
+}
+
+func normalizeClientAPIKeyPatterns(patterns []string) []string {
+	if len(patterns) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(patterns))
+	seen := make(map[string]struct{}, len(patterns))
+	for _, pattern := range patterns {
+		trimmed := strings.TrimSpace(pattern)
+		if trimmed == "" {
+			continue
+		}
+		key := strings.ToLower(trimmed)
+		if _, exists := seen[key]; exists {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, trimmed)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// SanitizeClientAPIKeys normalizes structured client keys and removes entries
+// without a secret or duplicating an earlier secret.
+func (cfg *SDKConfig) SanitizeClientAPIKeys() {
+	if cfg == nil || len(cfg.ClientAPIKeys) == 0 {
+		return
+	}
+	out := make([]ClientAPIKey, 0, len(cfg.ClientAPIKeys))
+	seen := make(map[string]struct{}, len(cfg.ClientAPIKeys))
+	for i := range cfg.ClientAPIKeys {
+		entry := cfg.ClientAPIKeys[i]
+		NormalizeClientAPIKey(&entry)
+		if entry.APIKey == "" {
+			continue
+		}
+		if _, exists := seen[entry.APIKey]; exists {
+			continue
+		}
+		seen[entry.APIKey] = struct{}{}
+		out = append(out, entry)
+	}
+	cfg.ClientAPIKeys = out
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMatchClientAPIKeyPatterns(t *testing.T) {
+	cases := []struct {
+		patterns []string
+		value    string
+		want     bool
+	}{
+		{patterns: nil, value: "anything", want: true},
+		{patterns: []string{"gemini-*"}, value: "Gemini-2.5-Pro", want: true},
+		{patterns: []string{"gemini-*"}, value: "claude-sonnet-4", want: false},
+		{patterns: []string{"*-mini"}, value: "gpt-5-mini", want: true},
+		{patterns: []string{"gpt-*-codex*"}, value: "gpt-5-codex-high", want: true},
+		{patterns: []string{"gpt-*-codex*"}, value: "gpt-5", want: false},
+		{patterns: []string{"/v1/chat/completions"}, value: "/v1/chat/completions", want: true},
+		{patterns: []string{"/v1beta/*"}, value: "/v1/models", want: false},
+		{patterns: []string{""}, value: "x", want: false},
+	}
+	for _, tc := range cases {
+		if got := MatchClientAPIKeyPatterns(tc.patterns, tc.value); got != tc.want {
+			t.Errorf("MatchClientAPIKeyPatterns(%v, %q) = %v, want %v", tc.patterns, tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestClientAPIKeyExpiry(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if (ClientAPIKey{}).IsExpired(now) {
+		t.Fatal("key without expiry should not expire")
+	}
+	if !(ClientAPIKey{ExpiresAt: "2026-03-01"}).IsExpired(now) {
+		t.Fatal("date-only expiry in the past should be expired")
+	}
+	if (ClientAPIKey{ExpiresAt: "2026-03-01T13:00:00Z"}).IsExpired(now) {
+		t.Fatal("future RFC3339 expiry should not be expired")
+	}
+	if (ClientAPIKey{ExpiresAt: "soon"}).IsExpired(now) {
+		t.Fatal("unparseable expiry should be ignored")
+	}
+}
+
+func TestSanitizeClientAPIKeys(t *testing.T) {
+	cfg := &SDKConfig{ClientAPIKeys: []ClientAPIKey{
+		{APIKey: " key-a ", Name: " team-a ", Models: []string{" gemini-* ", "", "GEMINI-*"}},
+		{APIKey: ""},
+		{APIKey: "key-a", Name: "duplicate"},
+		{APIKey: "key-b"},
+	}}
+	cfg.SanitizeClientAPIKeys()
+	if len(cfg.ClientAPIKeys) != 2 {
+		t.Fatalf("entries = %d, want 2: %#v", len(cfg.ClientAPIKeys), cfg.ClientAPIKeys)
+	}
+	first := cfg.ClientAPIKeys[0]
+	if first.APIKey != "key-a" || first.Name != "team-a" {
+		t.Fatalf("first entry = %#v", first)
+	}
+	if len(first.Models) != 1 || first.Models[0] != "gemini-*" {
+		t.Fatalf("models = %#v", first.Models)
+	}
+	if got := cfg.ClientAPIKeys[1].Label(); got != "*****" {
+		t.Fatalf("label = %q", got)
+	}
+}
@@ -22,6 +22,7 @@ import (
 const (
 	DefaultPanelGitHubRepository = "https://github.com/router-for-me/Cli-Proxy-API-Management-Center"
 	DefaultPprofAddr             = "127.0.0.1:8316"
+	DefaultMetricsAddr           = "127.0.0.1:8318"
 	DefaultAuthDir               = "~/.cli-proxy-api"
 )
 
@@ -55,6 +56,12 @@ type Config struct {
 	// Pprof config controls the optional pprof HTTP debug server.
 	Pprof PprofConfig `yaml:"pprof" json:"pprof"`
 
+	// Metrics config controls the optional Prometheus metrics listener.
+	Metrics MetricsConfig `yaml:"metrics" json:"metrics"`
+
+	// Tracing config controls OpenTelemetry trace export.
+	Tracing TracingConfig `yaml:"tracing" json:"tracing"`
+
 	// CommercialMode disables high-overhead request logging and HTTP middleware features to minimize per-request memory usage.
 	CommercialMode bool `yaml:"commercial-mode" json:"commercial-mode"`
 
@@ -77,9 +84,28 @@ type Config struct {
 	// Default: 60. Max: 3600.
 	RedisUsageQueueRetentionSeconds int `yaml:"redis-usage-queue-retention-seconds" json:"redis-usage-queue-retention-seconds"`
 
+	// UsageLedger configures the durable usage ledger used for historical aggregates.
+	UsageLedger UsageLedgerConfig `yaml:"usage-ledger" json:"usage-ledger"`
+
+	// ResponsesStore configures server-side storage of Responses API results.
+	ResponsesStore ResponsesStoreConfig `yaml:"responses-store" json:"responses-store"`
+
+	// ResponseCache configures the exact-match cache of upstream responses.
+	ResponseCache ResponseCacheConfig `yaml:"response-cache" json:"response-cache"`
+
 	// DisableCooling disables quota cooldown scheduling when true.
 	DisableCooling bool `yaml:"disable-cooling" json:"disable-cooling"`
 
+	// QuotaPolling configures background polling of remaining provider quota.
+	QuotaPolling QuotaPollingConfig `yaml:"quota-polling" json:"quota-polling"`
+
+	// SharedState configures the store that replicas use to share cooldowns, session
+	// affinity bindings and signature caches when Home is not connected.
+	SharedState SharedStateConfig `yaml:"shared-state" json:"shared-state"`
+
+	// HealthCheck configures periodic synthetic probes of every credential.
+	HealthCheck HealthCheckConfig `yaml:"health-check" json:"health-check"`
+
 	// AuthAutoRefreshWorkers overrides the size of the core auth auto-refresh worker pool.
 	// When <= 0, the default worker count is used.
 	AuthAutoRefreshWorkers int `yaml:"auth-auto-refresh-workers" json:"auth-auto-refresh-workers"`
@@ -143,6 +169,9 @@ type Config struct {
 	// Used for services that use Vertex AI-style paths but with simple API key authentication.
 	VertexCompatAPIKey []VertexCompatKey `yaml:"vertex-api-key" json:"vertex-api-key"`
 
+	// BedrockKey defines AWS Bedrock credentials signed with SigV4.
+	BedrockKey []BedrockKey `yaml:"bedrock-api-key" json:"bedrock-api-key"`
+
 	// OAuthExcludedModels defines per-provider global model exclusions applied to OAuth/file-backed auth entries.
 	OAuthExcludedModels map[string][]string `yaml:"oauth-excluded-models,omitempty" json:"oauth-excluded-models,omitempty"`
 
@@ -154,6 +183,9 @@ type Config struct {
 	// gemini-api-key, codex-api-key, claude-api-key, openai-compatibility, and vertex-api-key.
 	OAuthModelAlias map[string][]OAuthModelAlias `yaml:"oauth-model-alias,omitempty" json:"oauth-model-alias,omitempty"`
 
+	// ModelFallbacks declares per-model fallback chains walked when a model has no usable credential.
+	ModelFallbacks []ModelFallback `yaml:"model-fallbacks,omitempty" json:"model-fallbacks,omitempty"`
+
 	// Payload defines default and override rules for provider payload parameters.
 	Payload PayloadConfig `yaml:"payload" json:"payload"`
 }
@@ -287,12 +319,133 @@ type PprofConfig struct {
 	Addr string `yaml:"addr" json:"addr"`
 }
 
+// MetricsConfig holds Prometheus metrics listener settings.
+type MetricsConfig struct {
+	// Enable toggles the metrics HTTP listener.
+	Enable bool `yaml:"enable" json:"enable"`
+	// Addr is the host:port address serving /metrics.
+	Addr string `yaml:"addr" json:"addr"`
+}
+
+// TracingConfig holds OpenTelemetry trace export settings.
+type TracingConfig struct {
+	// Enable toggles span recording and export.
+	Enable bool `yaml:"enable" json:"enable"`
+	// Exporter selects "otlp" (OTLP/HTTP), "stdout" or "file". Defaults to "otlp".
+	Exporter string `yaml:"exporter,omitempty" json:"exporter,omitempty"`
+	// Endpoint is the OTLP/HTTP traces URL. Defaults to http://127.0.0.1:4318/v1/traces.
+	Endpoint string `yaml:"endpoint,omitempty" json:"endpoint,omitempty"`
+	// Headers are sent with every OTLP export request, e.g. collector credentials.
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+	// File is the output path of the file exporter. Spans are appended as JSON lines.
+	File string `yaml:"file,omitempty" json:"file,omitempty"`
+	// ServiceName sets the service.name resource attribute. Defaults to "cli-proxy-api".
+	ServiceName string `yaml:"service-name,omitempty" json:"service-name,omitempty"`
+	// SampleRatio is the fraction of new traces that are recorded. Zero or unset records every trace.
+	SampleRatio float64 `yaml:"sample-ratio,omitempty" json:"sample-ratio,omitempty"`
+	// Propagate sends a W3C traceparent header on upstream provider requests.
+	Propagate bool `yaml:"propagate,omitempty" json:"propagate,omitempty"`
+}
+
+// QuotaPollingConfig holds settings for proactive upstream quota polling.
+type QuotaPollingConfig struct {
+	// Enable toggles the background quota poller.
+	Enable bool `yaml:"enable" json:"enable"`
+	// Interval is how often each credential is polled. Defaults to 5m.
+	Interval string `yaml:"interval,omitempty" json:"interval,omitempty"`
+	// LowThreshold is the remaining quota fraction (0-1) at or below which a
+	// credential, or one of its models for per-model quotas, is moved behind its
+	// healthy peers. Defaults to 0.1.
+	LowThreshold float64 `yaml:"low-threshold,omitempty" json:"low-threshold,omitempty"`
+}
+
+// SharedStateConfig holds settings for the cross-replica shared state store.
+type SharedStateConfig struct {
+	// Enable toggles sharing request-time state through the configured backend.
+	Enable bool `yaml:"enable" json:"enable"`
+	// Backend selects the storage backend: "redis" (default) or "postgres".
+	Backend string `yaml:"backend,omitempty" json:"backend,omitempty"`
+	// RedisURL is the connection URL for the redis backend, e.g. redis://host:6379/0.
+	RedisURL string `yaml:"redis-url,omitempty" json:"-"`
+	// PostgresDSN is the connection string for the postgres backend.
+	PostgresDSN string `yaml:"postgres-dsn,omitempty" json:"-"`
+	// PostgresSchema optionally places the state table in a dedicated schema.
+	PostgresSchema string `yaml:"postgres-schema,omitempty" json:"postgres-schema,omitempty"`
+	// PostgresTable overrides the state table name. Defaults to "shared_state".
+	PostgresTable string `yaml:"postgres-table,omitempty" json:"postgres-table,omitempty"`
+	// KeyPrefix namespaces keys so several deployments can share one backend.
+	KeyPrefix string `yaml:"key-prefix,omitempty" json:"key-prefix,omitempty"`
+	// SyncInterval is how often cooldowns published by other replicas are pulled. Defaults to 5s.
+	SyncInterval string `yaml:"sync-interval,omitempty" json:"sync-interval,omitempty"`
+}
+
+// HealthCheckConfig holds settings for synthetic credential health probes.
+type HealthCheckConfig struct {
+	// Enable toggles the background health prober.
+	Enable bool `yaml:"enable" json:"enable"`
+	// Interval is how often each credential is probed. Defaults to 10m.
+	Interval string `yaml:"interval,omitempty" json:"interval,omitempty"`
+	// Timeout bounds a single probe. Defaults to 20s.
+	Timeout string `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	// FailureThreshold is the number of consecutive failed probes after which a
+	// credential is quarantined until a probe succeeds again. Defaults to 3.
+	FailureThreshold int `yaml:"failure-threshold,omitempty" json:"failure-threshold,omitempty"`
+}
+
+// UsageLedgerConfig holds settings for the persistent usage ledger.
+type UsageLedgerConfig struct {
+	// Enable toggles writing usage records to the ledger.
+	Enable bool `yaml:"enable" json:"enable"`
+	// Backend selects the storage backend: "file" (default) or "postgres".
+	Backend string `yaml:"backend,omitempty" json:"backend,omitempty"`
+	// Dir is the directory for the file backend. Defaults to "usage-ledger" under the writable path.
+	Dir string `yaml:"dir,omitempty" json:"dir,omitempty"`
+	// PostgresDSN is the connection string for the postgres backend.
+	PostgresDSN string `yaml:"postgres-dsn,omitempty" json:"-"`
+	// PostgresSchema optionally places the ledger table in a dedicated schema.
+	PostgresSchema string `yaml:"postgres-schema,omitempty" json:"postgres-schema,omitempty"`
+	// PostgresTable overrides the ledger table name. Defaults to "usage_ledger".
+	PostgresTable string `yaml:"postgres-table,omitempty" json:"postgres-table,omitempty"`
+	// RetentionDays removes ledger entries older than the given number of days. 0 keeps everything.
+	RetentionDays int `yaml:"retention-days,omitempty" json:"retention-days,omitempty"`
+}
+
+// ResponsesStoreConfig holds settings for the Responses API state store.
+type ResponsesStoreConfig struct {
+	// Enable toggles storing responses and expanding previous_response_id locally.
+	Enable bool `yaml:"enable" json:"enable"`
+	// TTLSeconds is how long a stored response stays retrievable. Defaults to 86400.
+	TTLSeconds int `yaml:"ttl-seconds,omitempty" json:"ttl-seconds,omitempty"`
+	// MaxEntries caps the number of responses kept in memory. Defaults to 10000.
+	MaxEntries int `yaml:"max-entries,omitempty" json:"max-entries,omitempty"`
+	// Backend selects an optional durable backend, e.g. "file". Empty keeps responses in memory only.
+	Backend string `yaml:"backend,omitempty" json:"backend,omitempty"`
+	// Dir is the directory for the file backend. Defaults to "responses-store" under the writable path.
+	Dir string `yaml:"dir,omitempty" json:"dir,omitempty"`
+}
+
+// ResponseCacheConfig holds settings for the exact-match response cache.
+type ResponseCacheConfig struct {
+	// Enable toggles replaying cached responses for identical requests.
+	Enable bool `yaml:"enable" json:"enable"`
+	// TTLSeconds is how long a cached response is replayed. Defaults to 600.
+	TTLSeconds int `yaml:"ttl-seconds,omitempty" json:"ttl-seconds,omitempty"`
+	// MaxEntries caps the number of cached responses. Defaults to 1000.
+	MaxEntries int `yaml:"max-entries,omitempty" json:"max-entries,omitempty"`
+	// MaxSizeMB caps the total size of cached responses. Defaults to 64.
+	MaxSizeMB int `yaml:"max-size-mb,omitempty" json:"max-size-mb,omitempty"`
+	// MaxEntrySizeKB skips caching responses larger than this. Defaults to 1024.
+	MaxEntrySizeKB int `yaml:"max-entry-size-kb,omitempty" json:"max-entry-size-kb,omitempty"`
+}
+
 // RemoteManagement holds management API configuration under 'remote-management'.
 type RemoteManagement struct {
 	// AllowRemote toggles remote (non-localhost) access to management API.
 	AllowRemote bool `yaml:"allow-remote"`
 	// SecretKey is the management key (plaintext or bcrypt hashed). YAML key intentionally 'secret-key'.
 	SecretKey string `yaml:"secret-key"`
+	// Keys lists additional named management keys restricted to scopes.
+	Keys []ManagementKey `yaml:"keys,omitempty"`
 	// DisableControlPanel skips serving and syncing the bundled management UI when true.
 	DisableControlPanel bool `yaml:"disable-control-panel"`
 	// DisableAutoUpdatePanel disables automatic periodic background updates of the management panel asset from GitHub.
@@ -301,6 +454,16 @@ type RemoteManagement struct {
 	// PanelGitHubRepository overrides the GitHub repository used to fetch the management panel asset.
 	// Accepts either a repository URL (https://github.com/org/repo) or an API releases endpoint.
 	PanelGitHubRepository string `yaml:"panel-github-repository"`
+	// DisableAuditLog stops recording management API mutations.
+	DisableAuditLog bool `yaml:"disable-audit-log,omitempty"`
+	// AuditLogFile is the append-only JSONL audit log. Defaults to "management-audit.jsonl"
+	// under the writable path, or next to the config file.
+	AuditLogFile string `yaml:"audit-log-file,omitempty"`
+	// DisableConfigHistory stops keeping revisions of config.yaml written through the management API.
+	// Revisions are kept in the Postgres or object store when one is used, otherwise next to config.yaml.
+	DisableConfigHistory bool `yaml:"disable-config-history,omitempty"`
+	// ConfigHistoryMaxRevisions bounds the config history. Defaults to 20.
+	ConfigHistoryMaxRevisions int `yaml:"config-history-max-revisions,omitempty"`
 }
 
 // QuotaExceeded defines the behavior when API quota limits are exceeded.
@@ -321,7 +484,8 @@ type QuotaExceeded struct {
 // RoutingConfig configures how credentials are selected for requests.
 type RoutingConfig struct {
 	// Strategy selects the credential selection strategy.
-	// Supported values: "round-robin" (default), "fill-first".
+	// Supported values: "round-robin" (default), "fill-first", "least-in-flight",
+	// "latency" (inverse EWMA latency weighted) and "weighted" (priority share).
 	Strategy string `yaml:"strategy,omitempty" json:"strategy,omitempty"`
 
 	// SessionAffinity enables universal session-sticky routing for all clients.
@@ -471,10 +635,14 @@ type ClaudeModel struct {
 
 	// Alias is the client-facing model name that maps to Name.
 	Alias string `yaml:"alias" json:"alias"`
+
+	// Pricing overrides the catalog prices used for cost accounting.
+	Pricing *registry.ModelPricing `yaml:"pricing,omitempty" json:"pricing,omitempty"`
 }
 
-func (m ClaudeModel) GetName() string  { return m.Name }
-func (m ClaudeModel) GetAlias() string { return m.Alias }
+func (m ClaudeModel) GetName() string                    { return m.Name }
+func (m ClaudeModel) GetAlias() string                   { return m.Alias }
+func (m ClaudeModel) GetPricing() *registry.ModelPricing { return m.Pricing }
 
 // CodexKey represents the configuration for a Codex API key,
 // including the API key itself and an optional base URL for the API endpoint.
@@ -522,10 +690,14 @@ type CodexModel struct {
 
 	// Alias is the client-facing model name that maps to Name.
 	Alias string `yaml:"alias" json:"alias"`
+
+	// Pricing overrides the catalog prices used for cost accounting.
+	Pricing *registry.ModelPricing `yaml:"pricing,omitempty" json:"pricing,omitempty"`
 }
 
-func (m CodexModel) GetName() string  { return m.Name }
-func (m CodexModel) GetAlias() string { return m.Alias }
+func (m CodexModel) GetName() string                    { return m.Name }
+func (m CodexModel) GetAlias() string                   { return m.Alias }
+func (m CodexModel) GetPricing() *registry.ModelPricing { return m.Pricing }
 
 // GeminiKey represents the configuration for a Gemini API key,
 // including optional overrides for upstream base URL, proxy routing, and headers.
@@ -569,10 +741,14 @@ type GeminiModel struct {
 
 	// Alias is the client-facing model name that maps to Name.
 	Alias string `yaml:"alias" json:"alias"`
+
+	// Pricing overrides the catalog prices used for cost accounting.
+	Pricing *registry.ModelPricing `yaml:"pricing,omitempty" json:"pricing,omitempty"`
 }
 
-func (m GeminiModel) GetName() string  { return m.Name }
-func (m GeminiModel) GetAlias() string { return m.Alias }
+func (m GeminiModel) GetName() string                    { return m.Name }
+func (m GeminiModel) GetAlias() string                   { return m.Alias }
+func (m GeminiModel) GetPricing() *registry.ModelPricing { return m.Pricing }
 
 // OpenAICompatibility represents the configuration for OpenAI API compatibility
 // with external providers, allowing model aliases to be routed through OpenAI API format.
@@ -630,6 +806,9 @@ type OpenAICompatibilityModel struct {
 	// Thinking configures the thinking/reasoning capability for this model.
 	// If nil, the model defaults to level-based reasoning with levels ["low", "medium", "high"].
 	Thinking *registry.ThinkingSupport `yaml:"thinking,omitempty" json:"thinking,omitempty"`
+
+	// Pricing sets the prices used for cost accounting of this model.
+	Pricing *registry.ModelPricing `yaml:"pricing,omitempty" json:"pricing,omitempty"`
 }
 
 func (m OpenAICompatibilityModel) GetName() string  { return m.Name }
@@ -687,6 +866,8 @@ func LoadConfigOptional(configFile string, optional bool) (*Config, error) {
 	cfg.DisableImageGeneration = DisableImageGenerationOff
 	cfg.Pprof.Enable = false
 	cfg.Pprof.Addr = DefaultPprofAddr
+	cfg.Metrics.Enable = false
+	cfg.Metrics.Addr = DefaultMetricsAddr
 	cfg.RemoteManagement.PanelGitHubRepository = DefaultPanelGitHubRepository
 	if err = yaml.Unmarshal(data, &cfg); err != nil {
 		if optional {
@@ -711,6 +892,9 @@ func LoadConfigOptional(configFile string, optional bool) (*Config, error) {
 		// Preserve YAML comments and ordering; update only the nested key.
 		_ = SaveConfigPreserveCommentsUpdateNestedScalar(configFile, []string{"remote-management", "secret-key"}, hashed)
 	}
+	if errKeys := cfg.sanitizeManagementKeys(); errKeys != nil {
+		return nil, errKeys
+	}
 
 	cfg.RemoteManagement.PanelGitHubRepository = strings.TrimSpace(cfg.RemoteManagement.PanelGitHubRepository)
 	if cfg.RemoteManagement.PanelGitHubRepository == "" {
@@ -722,6 +906,11 @@ func LoadConfigOptional(configFile string, optional bool) (*Config, error) {
 		cfg.Pprof.Addr = DefaultPprofAddr
 	}
 
+	cfg.Metrics.Addr = strings.TrimSpace(cfg.Metrics.Addr)
+	if cfg.Metrics.Addr == "" {
+		cfg.Metrics.Addr = DefaultMetricsAddr
+	}
+
 	if cfg.LogsMaxTotalSizeMB < 0 {
 		cfg.LogsMaxTotalSizeMB = 0
 	}
@@ -749,6 +938,15 @@ func LoadConfigOptional(configFile string, optional bool) (*Config, error) {
 	// Sanitize Vertex-compatible API keys.
 	cfg.SanitizeVertexCompatKeys()
 
+	// Sanitize AWS Bedrock credentials.
+	cfg.SanitizeBedrockKeys()
+
+	// Normalize model fallback chains.
+	cfg.SanitizeModelFallbacks()
+
+	// Normalize declarative routing rules.
+	cfg.SanitizeRoutingRules()
+
 	// Sanitize Codex keys: drop entries without base-url
 	cfg.SanitizeCodexKeys()
 
@@ -764,6 +962,9 @@ func LoadConfigOptional(configFile string, optional bool) (*Config, error) {
 	// Sanitize OpenAI compatibility providers: drop entries without base-url
 	cfg.SanitizeOpenAICompatibility()
 
+	// Sanitize structured client API keys.
+	cfg.SanitizeClientAPIKeys()
+
 	// Normalize OAuth provider model exclusion map.
 	cfg.OAuthExcludedModels = NormalizeOAuthExcludedModels(cfg.OAuthExcludedModels)
 
@@ -1420,6 +1621,8 @@ func isKnownDefaultValue(path []string, node *yaml.Node) bool {
 		switch fullPath {
 		case "pprof.addr":
 			return node.Value == DefaultPprofAddr
+		case "metrics.addr":
+			return node.Value == DefaultMetricsAddr
 		case "remote-management.panel-github-repository":
 			return node.Value == DefaultPanelGitHubRepository
 		case "plugins.dir":
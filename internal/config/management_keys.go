@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Management key scopes. A scope grants full access to its route group; the
+// read-only scope grants GET access to every group except raw config, provider
+// and client keys, log contents and credential downloads.
+const (
+	// ManagementScopeAdmin grants every management route. The legacy
+	// secret-key, MANAGEMENT_PASSWORD and the local password act as admin.
+	ManagementScopeAdmin = "admin"
+	// ManagementScopeReadOnly grants read access to settings, usage, auth file
+	// listings and plugins.
+	ManagementScopeReadOnly = "read-only"
+	// ManagementScopeUsageViewer grants read access to usage statistics.
+	ManagementScopeUsageViewer = "usage-viewer"
+	// ManagementScopeAuthFiles grants auth file management and OAuth logins.
+	ManagementScopeAuthFiles = "auth-files"
+	// ManagementScopeConfigAdmin grants changes to config.yaml and settings.
+	ManagementScopeConfigAdmin = "config-admin"
+	// ManagementScopePluginsAdmin grants plugin install, removal and configuration.
+	ManagementScopePluginsAdmin = "plugins-admin"
+)
+
+var managementScopes = map[string]struct{}{
+	ManagementScopeAdmin:        {},
+	ManagementScopeReadOnly:     {},
+	ManagementScopeUsageViewer:  {},
+	ManagementScopeAuthFiles:    {},
+	ManagementScopeConfigAdmin:  {},
+	ManagementScopePluginsAdmin: {},
+}
+
+// ManagementKey is a named management key restricted to a set of scopes.
+type ManagementKey struct {
+	// Name labels the key in logs and management responses.
+	Name string `yaml:"name"`
+	// Key is the management key (plaintext or bcrypt hashed).
+	Key string `yaml:"key" json:"-"`
+	// Scopes lists the route groups the key may access.
+	Scopes []string `yaml:"scopes"`
+}
+
+// HasKeys reports whether any management key is configured.
+func (rm RemoteManagement) HasKeys() bool {
+	return rm.SecretKey != "" || len(rm.Keys) > 0
+}
+
+// HasManagementScope reports whether scopes include scope. The admin scope
+// includes every other scope.
+func HasManagementScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == ManagementScopeAdmin || s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ManagementScopeAllows reports whether scopes grant access to a route group
+// guarded by scope. Read requests are also granted by any of readScopes.
+func ManagementScopeAllows(scopes []string, read bool, scope string, readScopes ...string) bool {
+	if HasManagementScope(scopes, scope) {
+		return true
+	}
+	if !read {
+		return false
+	}
+	for _, readScope := range readScopes {
+		if HasManagementScope(scopes, readScope) {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeManagementKeys trims names and scopes, drops keys without a secret
+// or a known scope and hashes plaintext keys in memory.
+func (cfg *Config) sanitizeManagementKeys() error {
+	if cfg == nil || len(cfg.RemoteManagement.Keys) == 0 {
+		return nil
+	}
+	out := make([]ManagementKey, 0, len(cfg.RemoteManagement.Keys))
+	for i, key := range cfg.RemoteManagement.Keys {
+		key.Name = strings.TrimSpace(key.Name)
+		key.Key = strings.TrimSpace(key.Key)
+		if key.Name == "" {
+			key.Name = fmt.Sprintf("key-%d", i+1)
+		}
+		if key.Key == "" {
+			continue
+		}
+		scopes := make([]string, 0, len(key.Scopes))
+		for _, scope := range key.Scopes {
+			scope = strings.ToLower(strings.TrimSpace(scope))
+			if _, ok := managementScopes[scope]; !ok {
+				continue
+			}
+			scopes = append(scopes, scope)
+		}
+		if len(scopes) == 0 {
+			continue
+		}
+		key.Scopes = scopes
+		if !looksLikeBcrypt(key.Key) {
+			hashed, errHash := hashSecret(key.Key)
+			if errHash != nil {
+				return fmt.Errorf("failed to hash management key %q: %w", key.Name, errHash)
+			}
+			key.Key = hashed
+		}
+		out = append(out, key)
+	}
+	cfg.RemoteManagement.Keys = out
+	return nil
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestSanitizeManagementKeys(t *testing.T) {
+	cfg := &Config{RemoteManagement: RemoteManagement{Keys: []ManagementKey{
+		{Name: " grafana ", Key: " usage-key ", Scopes: []string{" Usage-Viewer ", "unknown"}},
+		{Name: "empty", Key: "", Scopes: []string{ManagementScopeAdmin}},
+		{Name: "no-scope", Key: "k", Scopes: []string{"unknown"}},
+	}}}
+	if err := cfg.sanitizeManagementKeys(); err != nil {
+		t.Fatalf("sanitizeManagementKeys() error = %v", err)
+	}
+	if len(cfg.RemoteManagement.Keys) != 1 {
+		t.Fatalf("keys = %+v, want only the scoped key with a secret", cfg.RemoteManagement.Keys)
+	}
+	key := cfg.RemoteManagement.Keys[0]
+	if key.Name != "grafana" || len(key.Scopes) != 1 || key.Scopes[0] != ManagementScopeUsageViewer {
+		t.Fatalf("key = %+v", key)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(key.Key), []byte("usage-key")) != nil {
+		t.Fatal("plaintext key was not hashed")
+	}
+	if !cfg.RemoteManagement.HasKeys() {
+		t.Fatal("HasKeys() = false with a named key configured")
+	}
+}
+
+func TestManagementScopeAllows(t *testing.T) {
+	cases := []struct {
+		scopes []string
+		read   bool
+		want   bool
+	}{
+		{[]string{ManagementScopeAdmin}, false, true},
+		{[]string{ManagementScopeConfigAdmin}, false, true},
+		{[]string{ManagementScopeReadOnly}, true, true},
+		{[]string{ManagementScopeReadOnly}, false, false},
+		{[]string{ManagementScopeUsageViewer}, true, false},
+	}
+	for _, tc := range cases {
+		if got := ManagementScopeAllows(tc.scopes, tc.read, ManagementScopeConfigAdmin, ManagementScopeReadOnly); got != tc.want {
+			t.Errorf("ManagementScopeAllows(%v, read=%t) = %t, want %t", tc.scopes, tc.read, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package config
+
+import "strings"
+
+// ModelFallback declares an ordered chain of models that are tried, in order,
+// when a client-visible model has no usable credential. Each fallback may be
+// served by a different provider; the original request is translated to that
+// provider's format like any other request.
+type ModelFallback struct {
+	// Model is the client-visible model name the chain applies to.
+	Model string `yaml:"model" json:"model"`
+
+	// Fallbacks lists the models to try after Model has been exhausted.
+	Fallbacks []string `yaml:"fallbacks" json:"fallbacks"`
+}
+
+// SanitizeModelFallbacks trims model names, drops empty chains and
+// self-references, deduplicates fallbacks within a chain and keeps only the
+// first chain declared for a model (case-insensitive).
+func (cfg *Config) SanitizeModelFallbacks() {
+	if cfg == nil || len(cfg.ModelFallbacks) == 0 {
+		return
+	}
+
+	seen := make(map[string]struct{}, len(cfg.ModelFallbacks))
+	out := make([]ModelFallback, 0, len(cfg.ModelFallbacks))
+	for _, entry := range cfg.ModelFallbacks {
+		model := strings.TrimSpace(entry.Model)
+		if model == "" {
+			continue
+		}
+		key := strings.ToLower(model)
+		if _, exists := seen[key]; exists {
+			continue
+		}
+
+		chainSeen := map[string]struct{}{key: {}}
+		fallbacks := make([]string, 0, len(entry.Fallbacks))
+		for _, fallback := range entry.Fallbacks {
+			fallback = strings.TrimSpace(fallback)
+			if fallback == "" {
+				continue
+			}
+			fallbackKey := strings.ToLower(fallback)
+			if _, exists := chainSeen[fallbackKey]; exists {
+				continue
+			}
+			chainSeen[fallbackKey] = struct{}{}
+			fallbacks = append(fallbacks, fallback)
+		}
+		if len(fallbacks) == 0 {
+			continue
+		}
+
+		seen[key] = struct{}{}
+		out = append(out, ModelFallback{Model: model, Fallbacks: fallbacks})
+	}
+	cfg.ModelFallbacks = out
+}
+
+// ModelFallbackChain returns the fallback models configured for model, or nil.
+func (cfg *Config) ModelFallbackChain(model string) []string {
+	if cfg == nil {
+		return nil
+	}
+	model = strings.TrimSpace(model)
+	if model == "" {
+		return nil
+	}
+	for i := range cfg.ModelFallbacks {
+		if strings.EqualFold(cfg.ModelFallbacks[i].Model, model) {
+			return cfg.ModelFallbacks[i].Fallbacks
+		}
+	}
+	return nil
+}
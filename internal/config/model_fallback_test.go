@@ -0,0 +1,27 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSanitizeModelFallbacks(t *testing.T) {
+	cfg := &Config{ModelFallbacks: []ModelFallback{
+		{Model: " claude-opus ", Fallbacks: []string{"gemini-pro", " ", "Claude-Opus", "gemini-pro", "kimi-k2"}},
+		{Model: "CLAUDE-OPUS", Fallbacks: []string{"ignored"}},
+		{Model: "", Fallbacks: []string{"orphan"}},
+		{Model: "gpt-5", Fallbacks: []string{"gpt-5"}},
+	}}
+	cfg.SanitizeModelFallbacks()
+
+	want := []ModelFallback{{Model: "claude-opus", Fallbacks: []string{"gemini-pro", "kimi-k2"}}}
+	if !reflect.DeepEqual(cfg.ModelFallbacks, want) {
+		t.Fatalf("ModelFallbacks = %+v, want %+v", cfg.ModelFallbacks, want)
+	}
+	if got := cfg.ModelFallbackChain("Claude-Opus"); !reflect.DeepEqual(got, want[0].Fallbacks) {
+		t.Fatalf("ModelFallbackChain() = %v", got)
+	}
+	if got := cfg.ModelFallbackChain("gpt-5"); got != nil {
+		t.Fatalf("ModelFallbackChain(gpt-5) = %v, want nil", got)
+	}
+}
@@ -28,6 +28,8 @@ func ParseConfigBytes(data []byte) (*Config, error) {
 	cfg.DisableImageGeneration = DisableImageGenerationOff
 	cfg.Pprof.Enable = false
 	cfg.Pprof.Addr = DefaultPprofAddr
+	cfg.Metrics.Enable = false
+	cfg.Metrics.Addr = DefaultMetricsAddr
 	cfg.RemoteManagement.PanelGitHubRepository = DefaultPanelGitHubRepository
 
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
@@ -42,6 +44,9 @@ func ParseConfigBytes(data []byte) (*Config, error) {
 		}
 		cfg.RemoteManagement.SecretKey = string(hashed)
 	}
+	if errKeys := cfg.sanitizeManagementKeys(); errKeys != nil {
+		return nil, errKeys
+	}
 
 	cfg.RemoteManagement.PanelGitHubRepository = strings.TrimSpace(cfg.RemoteManagement.PanelGitHubRepository)
 	if cfg.RemoteManagement.PanelGitHubRepository == "" {
@@ -53,6 +58,11 @@ func ParseConfigBytes(data []byte) (*Config, error) {
 		cfg.Pprof.Addr = DefaultPprofAddr
 	}
 
+	cfg.Metrics.Addr = strings.TrimSpace(cfg.Metrics.Addr)
+	if cfg.Metrics.Addr == "" {
+		cfg.Metrics.Addr = DefaultMetricsAddr
+	}
+
 	if cfg.LogsMaxTotalSizeMB < 0 {
 		cfg.LogsMaxTotalSizeMB = 0
 	}
@@ -77,11 +87,15 @@ func ParseConfigBytes(data []byte) (*Config, error) {
 	// Apply the same sanitization pipeline.
 	cfg.SanitizeGeminiKeys()
 	cfg.SanitizeVertexCompatKeys()
+	cfg.SanitizeBedrockKeys()
+	cfg.SanitizeModelFallbacks()
+	cfg.SanitizeRoutingRules()
 	cfg.SanitizeCodexKeys()
 	cfg.SanitizeCodexHeaderDefaults()
 	cfg.SanitizeClaudeHeaderDefaults()
 	cfg.SanitizeClaudeKeys()
 	cfg.SanitizeOpenAICompatibility()
+	cfg.SanitizeClientAPIKeys()
 	cfg.OAuthExcludedModels = NormalizeOAuthExcludedModels(cfg.OAuthExcludedModels)
 	cfg.SanitizeOAuthModelAlias()
 	cfg.SanitizePayloadRules()
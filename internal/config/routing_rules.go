@@ -0,0 +1,104 @@
+package config
+
+import "strings"
+
+// RoutingRule routes matching requests to a provider set, credential prefix or
+// explicit credentials, optionally rewriting the model. Rules are evaluated in
+// order and the first rule whose conditions all hold wins.
+type RoutingRule struct {
+	// Name labels the rule in logs.
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+
+	// ClientKeys matches the authenticated client key label or raw key (wildcards supported).
+	ClientKeys []string `yaml:"client-keys,omitempty" json:"client-keys,omitempty"`
+	// Models matches the client-requested model name (wildcards supported).
+	Models []string `yaml:"models,omitempty" json:"models,omitempty"`
+	// Protocols matches the client frontend protocol (e.g. "openai", "claude", "gemini", "openai-response").
+	Protocols []string `yaml:"protocols,omitempty" json:"protocols,omitempty"`
+	// Headers requires request headers to match all configured wildcard patterns.
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+	// Match requires payload JSON paths to equal the configured values.
+	Match []map[string]any `yaml:"match,omitempty" json:"match,omitempty"`
+	// NotMatch requires payload JSON paths to not equal the configured values.
+	NotMatch []map[string]any `yaml:"not-match,omitempty" json:"not-match,omitempty"`
+	// Exist requires payload JSON paths to exist and not be null.
+	Exist []string `yaml:"exist,omitempty" json:"exist,omitempty"`
+	// NotExist requires payload JSON paths to be missing or null.
+	NotExist []string `yaml:"not-exist,omitempty" json:"not-exist,omitempty"`
+
+	// Providers pins matching requests to these provider keys.
+	Providers []string `yaml:"providers,omitempty" json:"providers,omitempty"`
+	// Prefix restricts credential selection to credentials with this prefix.
+	Prefix string `yaml:"prefix,omitempty" json:"prefix,omitempty"`
+	// AuthIndexes restricts credential selection to these auth indexes.
+	AuthIndexes []string `yaml:"auth-indexes,omitempty" json:"auth-indexes,omitempty"`
+	// Model rewrites the requested model before provider resolution.
+	Model string `yaml:"model,omitempty" json:"model,omitempty"`
+}
+
+// HasAction reports whether the rule changes routing when it matches.
+func (r RoutingRule) HasAction() bool {
+	return len(r.Providers) > 0 || r.Prefix != "" || len(r.AuthIndexes) > 0 || r.Model != ""
+}
+
+// SanitizeRoutingRules trims rule fields, lowercases provider keys and drops
+// rules that would not change routing.
+func (cfg *Config) SanitizeRoutingRules() {
+	if cfg == nil || len(cfg.RoutingRules) == 0 {
+		return
+	}
+
+	out := make([]RoutingRule, 0, len(cfg.RoutingRules))
+	for _, rule := range cfg.RoutingRules {
+		rule.Name = strings.TrimSpace(rule.Name)
+		rule.ClientKeys = normalizeRoutingRuleValues(rule.ClientKeys, false)
+		rule.Models = normalizeRoutingRuleValues(rule.Models, false)
+		rule.Protocols = normalizeRoutingRuleValues(rule.Protocols, true)
+		rule.Providers = normalizeRoutingRuleValues(rule.Providers, true)
+		rule.AuthIndexes = normalizeRoutingRuleValues(rule.AuthIndexes, false)
+		rule.Exist = normalizeRoutingRuleValues(rule.Exist, false)
+		rule.NotExist = normalizeRoutingRuleValues(rule.NotExist, false)
+		rule.Prefix = normalizeModelPrefix(rule.Prefix)
+		rule.Model = strings.TrimSpace(rule.Model)
+		if len(rule.Headers) > 0 {
+			headers := make(map[string]string, len(rule.Headers))
+			for key, pattern := range rule.Headers {
+				if key = strings.TrimSpace(key); key != "" {
+					headers[key] = strings.TrimSpace(pattern)
+				}
+			}
+			rule.Headers = headers
+		}
+		if !rule.HasAction() {
+			continue
+		}
+		out = append(out, rule)
+	}
+	cfg.RoutingRules = out
+}
+
+func normalizeRoutingRuleValues(values []string, lower bool) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if lower {
+			value = strings.ToLower(value)
+		}
+		if value == "" {
+			continue
+		}
+		if _, exists := seen[value]; exists {
+			continue
+		}
+		seen[value] = struct{}{}
+		out = append(out, value)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,24 @@
+package config
+
+import "testing"
+
+func TestSanitizeRoutingRules(t *testing.T) {
+	cfg := &Config{SDKConfig: SDKConfig{RoutingRules: []RoutingRule{
+		{Name: " no-action ", ClientKeys: []string{"team-a"}},
+		{Name: "pin", Providers: []string{" Claude ", "claude", ""}, Protocols: []string{"OpenAI"}, Prefix: "/teamA/"},
+		{Headers: map[string]string{" X-Team ": " a* ", "": "ignored"}, Model: " gemini-2.5-pro "},
+	}}}
+	cfg.SanitizeRoutingRules()
+
+	if len(cfg.RoutingRules) != 2 {
+		t.Fatalf("rules = %+v, want 2 rules with actions", cfg.RoutingRules)
+	}
+	pin := cfg.RoutingRules[0]
+	if len(pin.Providers) != 1 || pin.Providers[0] != "claude" || pin.Protocols[0] != "openai" || pin.Prefix != "teamA" {
+		t.Fatalf("pin rule = %+v", pin)
+	}
+	rewrite := cfg.RoutingRules[1]
+	if rewrite.Model != "gemini-2.5-pro" || len(rewrite.Headers) != 1 || rewrite.Headers["X-Team"] != "a*" {
+		t.Fatalf("rewrite rule = %+v", rewrite)
+	}
+}
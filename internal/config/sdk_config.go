@@ -48,6 +48,15 @@ type SDKConfig struct {
 	// APIKeys is a list of keys for authenticating clients to this proxy server.
 	APIKeys []string `yaml:"api-keys" json:"api-keys"`
 
+	// ClientAPIKeys lists structured client keys carrying a label, optional expiry,
+	// allowed model/endpoint globs and a disabled flag.
+	ClientAPIKeys []ClientAPIKey `yaml:"client-api-keys,omitempty" json:"client-api-keys,omitempty"`
+
+	// RoutingRules declares ordered request routing rules matched on client key, headers,
+	// frontend protocol and payload. The first matching rule pins the request to providers,
+	// a credential prefix or auth indexes, and may rewrite the model.
+	RoutingRules []RoutingRule `yaml:"routing-rules,omitempty" json:"routing-rules,omitempty"`
+
 	// PassthroughHeaders controls whether upstream response headers are forwarded to downstream clients.
 	// Default is false (disabled).
 	PassthroughHeaders bool `yaml:"passthrough-headers" json:"passthrough-headers"`
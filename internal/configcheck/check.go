@@ -0,0 +1,331 @@
+// Package configcheck validates candidate config.yaml payloads and previews the
+// auths, models and payload rules they would produce without applying them.
+package configcheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/runtime/executor/helps"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/util"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/watcher/diff"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/watcher/synthesizer"
+	coreauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
+	"gopkg.in/yaml.v3"
+)
+
+var yamlLinePattern = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// Issue is a problem found in a candidate config. Line is 1-based and zero
+// when the problem has no source position.
+type Issue struct {
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+// AuthChange describes an auth that the candidate config would add, remove or change.
+type AuthChange struct {
+	ID       string   `json:"id"`
+	Provider string   `json:"provider"`
+	Label    string   `json:"label,omitempty"`
+	Action   string   `json:"action"`
+	Details  []string `json:"details,omitempty"`
+}
+
+// Model is a client-visible model and the auths that would serve it.
+type Model struct {
+	ID        string   `json:"id"`
+	Providers []string `json:"providers"`
+	Auths     int      `json:"auths"`
+}
+
+// ModelPreviewFunc resolves the client-visible models of auths under cfg
+// without registering them.
+type ModelPreviewFunc func(ctx context.Context, cfg *config.Config, auths []*coreauth.Auth) []Model
+
+// SampleRequest is a request used to evaluate payload rules.
+type SampleRequest struct {
+	// Model is the client-visible model name.
+	Model string `json:"model"`
+	// Protocol is the upstream translator format, for example "openai" or "gemini".
+	Protocol string `json:"protocol"`
+	// FromProtocol is the client request format.
+	FromProtocol string `json:"from-protocol,omitempty"`
+	// Path is the inbound request path used by endpoint-scoped settings.
+	Path string `json:"path,omitempty"`
+	// Headers are the inbound request headers.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is the JSON request payload.
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// PayloadPreview lists the payload rules that match a sample request and the
+// payload after they are applied.
+type PayloadPreview struct {
+	Rules   []helps.PayloadRuleMatch `json:"rules"`
+	Payload json.RawMessage          `json:"payload,omitempty"`
+}
+
+// Options controls a dry run.
+type Options struct {
+	// Current is the running config; auth and config changes are reported against it.
+	Current *config.Config
+	// Sample is evaluated against the candidate payload rules when set.
+	Sample *SampleRequest
+	// PluginAuthParser parses plugin-owned auth files.
+	PluginAuthParser synthesizer.PluginAuthParser
+	// PreviewModels resolves client-visible models; the model list is omitted when nil.
+	PreviewModels ModelPreviewFunc
+}
+
+// Report is the result of a dry run.
+type Report struct {
+	Valid    bool            `json:"valid"`
+	Errors   []Issue         `json:"errors"`
+	Warnings []Issue         `json:"warnings"`
+	Changes  []string        `json:"changes"`
+	Auths    []AuthChange    `json:"auths"`
+	Models   []Model         `json:"models"`
+	Payload  *PayloadPreview `json:"payload,omitempty"`
+}
+
+// Run validates data as config.yaml and previews its effect. The candidate is
+// never persisted or applied.
+func Run(ctx context.Context, data []byte, opts Options) *Report {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	report := &Report{Errors: []Issue{}, Warnings: []Issue{}, Changes: []string{}, Auths: []AuthChange{}, Models: []Model{}}
+	candidate, errs, warnings := Validate(data)
+	report.Errors = append(report.Errors, errs...)
+	report.Warnings = append(report.Warnings, warnings...)
+	if candidate == nil {
+		return report
+	}
+	report.Valid = len(report.Errors) == 0
+
+	if opts.Current != nil {
+		report.Changes = append(report.Changes, diff.BuildConfigChangeDetails(opts.Current, candidate)...)
+	}
+
+	candidateAuths, errAuths := synthesizeAuths(candidate, opts.PluginAuthParser)
+	if errAuths != nil {
+		report.Warnings = append(report.Warnings, Issue{Message: errAuths.Error()})
+	}
+	var currentAuths []*coreauth.Auth
+	if opts.Current != nil {
+		currentAuths, _ = synthesizeAuths(opts.Current, opts.PluginAuthParser)
+	}
+	report.Auths = append(report.Auths, DiffAuths(currentAuths, candidateAuths)...)
+
+	if opts.PreviewModels != nil {
+		report.Models = append(report.Models, opts.PreviewModels(ctx, candidate, candidateAuths)...)
+	}
+
+	if opts.Sample != nil {
+		preview, errSample := PreviewPayload(candidate, opts.Sample)
+		if errSample != nil {
+			report.Errors = append(report.Errors, Issue{Message: errSample.Error()})
+			report.Valid = false
+		} else {
+			report.Payload = preview
+		}
+	}
+	return report
+}
+
+// Validate parses data as config.yaml. Syntax and type errors are returned as
+// errors; unknown fields, which are ignored when loading, as warnings. The
+// parsed config is nil when data cannot be loaded.
+func Validate(data []byte) (*config.Config, []Issue, []Issue) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, []Issue{{Message: "config payload is empty"}}, nil
+	}
+
+	var errs, warnings []Issue
+	var node yaml.Node
+	if errSyntax := yaml.Unmarshal(data, &node); errSyntax != nil {
+		return nil, []Issue{yamlIssue(errSyntax.Error())}, nil
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var strict config.Config
+	if errDecode := decoder.Decode(&strict); errDecode != nil {
+		var typeErr *yaml.TypeError
+		if !errors.As(errDecode, &typeErr) {
+			return nil, []Issue{yamlIssue(errDecode.Error())}, nil
+		}
+		for _, msg := range typeErr.Errors {
+			issue := yamlIssue(msg)
+			if strings.Contains(issue.Message, "not found in type") {
+				warnings = append(warnings, issue)
+				continue
+			}
+			errs = append(errs, issue)
+		}
+	}
+
+	cfg, errParse := config.ParseConfigBytes(data)
+	if errParse != nil {
+		if len(errs) == 0 {
+			errs = append(errs, Issue{Message: errParse.Error()})
+		}
+		return nil, errs, warnings
+	}
+	return cfg, errs, warnings
+}
+
+func yamlIssue(msg string) Issue {
+	msg = strings.TrimSpace(msg)
+	if match := yamlLinePattern.FindStringSubmatch(msg); match != nil {
+		line, _ := strconv.Atoi(match[1])
+		return Issue{Line: line, Message: match[2]}
+	}
+	return Issue{Message: strings.TrimPrefix(msg, "yaml: ")}
+}
+
+func synthesizeAuths(cfg *config.Config, parser synthesizer.PluginAuthParser) ([]*coreauth.Auth, error) {
+	authDir, errDir := util.ResolveAuthDir(cfg.AuthDir)
+	if errDir != nil {
+		return nil, errDir
+	}
+	ctx := &synthesizer.SynthesisContext{
+		Config:           cfg,
+		AuthDir:          authDir,
+		Now:              time.Now(),
+		IDGenerator:      synthesizer.NewStableIDGenerator(),
+		PluginAuthParser: parser,
+	}
+	var out []*coreauth.Auth
+	if auths, errConfig := synthesizer.NewConfigSynthesizer().Synthesize(ctx); errConfig == nil {
+		out = append(out, auths...)
+	}
+	auths, errFile := synthesizer.NewFileSynthesizer().Synthesize(ctx)
+	if errFile != nil {
+		return out, fmt.Errorf("auth-dir %s: %w", authDir, errFile)
+	}
+	return append(out, auths...), nil
+}
+
+// DiffAuths describes the auths added, removed or changed between two
+// synthesized sets. Secrets are never printed; changed attributes are listed
+// by name only.
+func DiffAuths(before, after []*coreauth.Auth) []AuthChange {
+	oldByID := indexAuths(before)
+	newByID := indexAuths(after)
+	ids := make([]string, 0, len(oldByID)+len(newByID))
+	for id := range oldByID {
+		ids = append(ids, id)
+	}
+	for id := range newByID {
+		if _, ok := oldByID[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	changes := make([]AuthChange, 0)
+	for _, id := range ids {
+		oldAuth, hadOld := oldByID[id]
+		newAuth, hasNew := newByID[id]
+		switch {
+		case !hadOld:
+			changes = append(changes, AuthChange{ID: id, Provider: newAuth.Provider, Label: newAuth.Label, Action: "added"})
+		case !hasNew:
+			changes = append(changes, AuthChange{ID: id, Provider: oldAuth.Provider, Label: oldAuth.Label, Action: "removed"})
+		default:
+			if details := authDetails(oldAuth, newAuth); len(details) > 0 {
+				changes = append(changes, AuthChange{ID: id, Provider: newAuth.Provider, Label: newAuth.Label, Action: "changed", Details: details})
+			}
+		}
+	}
+	return changes
+}
+
+func indexAuths(auths []*coreauth.Auth) map[string]*coreauth.Auth {
+	out := make(map[string]*coreauth.Auth, len(auths))
+	for _, auth := range auths {
+		if auth == nil || auth.ID == "" {
+			continue
+		}
+		out[auth.ID] = auth
+	}
+	return out
+}
+
+func authDetails(oldAuth, newAuth *coreauth.Auth) []string {
+	var details []string
+	if oldAuth.Provider != newAuth.Provider {
+		details = append(details, fmt.Sprintf("provider: %s -> %s", oldAuth.Provider, newAuth.Provider))
+	}
+	if oldAuth.Disabled != newAuth.Disabled {
+		details = append(details, fmt.Sprintf("disabled: %t -> %t", oldAuth.Disabled, newAuth.Disabled))
+	}
+	if oldAuth.Prefix != newAuth.Prefix {
+		details = append(details, fmt.Sprintf("prefix: %s -> %s", oldAuth.Prefix, newAuth.Prefix))
+	}
+	if oldAuth.Label != newAuth.Label {
+		details = append(details, fmt.Sprintf("label: %s -> %s", oldAuth.Label, newAuth.Label))
+	}
+	if oldAuth.ProxyURL != newAuth.ProxyURL {
+		details = append(details, "proxy-url: updated")
+	}
+	keys := make(map[string]struct{}, len(oldAuth.Attributes)+len(newAuth.Attributes))
+	for key := range oldAuth.Attributes {
+		keys[key] = struct{}{}
+	}
+	for key := range newAuth.Attributes {
+		keys[key] = struct{}{}
+	}
+	names := make([]string, 0, len(keys))
+	for key := range keys {
+		if oldAuth.Attributes[key] != newAuth.Attributes[key] {
+			names = append(names, key)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		details = append(details, fmt.Sprintf("attribute %s: updated", name))
+	}
+	return details
+}
+
+// PreviewPayload lists the payload rules of cfg that match sample and returns
+// the payload after they are applied.
+func PreviewPayload(cfg *config.Config, sample *SampleRequest) (*PayloadPreview, error) {
+	if sample == nil {
+		return nil, nil
+	}
+	model := strings.TrimSpace(sample.Model)
+	if model == "" {
+		return nil, fmt.Errorf("sample request: model is required")
+	}
+	body := []byte(sample.Body)
+	if len(bytes.TrimSpace(body)) == 0 {
+		body = []byte("{}")
+	}
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("sample request: body is not valid JSON")
+	}
+	headers := make(http.Header, len(sample.Headers))
+	for key, value := range sample.Headers {
+		headers.Set(key, value)
+	}
+	rules := helps.MatchingPayloadRules(cfg, model, sample.Protocol, sample.FromProtocol, body, model, headers)
+	if rules == nil {
+		rules = []helps.PayloadRuleMatch{}
+	}
+	out := helps.ApplyPayloadConfigWithRequest(cfg, model, sample.Protocol, sample.FromProtocol, "", body, body, model, sample.Path, headers)
+	return &PayloadPreview{Rules: rules, Payload: json.RawMessage(out)}, nil
+}
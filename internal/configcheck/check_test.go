@@ -0,0 +1,116 @@
+package configcheck
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	coreauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
+)
+
+func TestValidate_ReportsLineNumbers(t *testing.T) {
+	data := []byte("port: 8317\nunknown-field: true\nrequest-retry: many\n")
+
+	cfg, errs, warnings := Validate(data)
+	if cfg != nil {
+		t.Fatal("expected no config for a type error")
+	}
+	if len(errs) != 1 || errs[0].Line != 3 || !strings.Contains(errs[0].Message, "many") {
+		t.Fatalf("errors = %+v, want a line 3 type error", errs)
+	}
+	if len(warnings) != 1 || warnings[0].Line != 2 || !strings.Contains(warnings[0].Message, "unknown-field") {
+		t.Fatalf("warnings = %+v, want a line 2 unknown field", warnings)
+	}
+}
+
+func TestValidate_SyntaxError(t *testing.T) {
+	_, errs, _ := Validate([]byte("port: 8317\n  host: [\n"))
+	if len(errs) != 1 || errs[0].Line == 0 {
+		t.Fatalf("errors = %+v, want one syntax error with a line", errs)
+	}
+}
+
+func TestRun_ReportsAuthAndConfigChanges(t *testing.T) {
+	authDir := t.TempDir()
+	current := &config.Config{AuthDir: authDir}
+	current.ClaudeKey = []config.ClaudeKey{{APIKey: "sk-old"}}
+	data := []byte("auth-dir: " + authDir + "\nport: 9000\nclaude-api-key:\n  - api-key: sk-new\n    prefix: team\n")
+
+	var previewed int
+	report := Run(context.Background(), data, Options{
+		Current: current,
+		PreviewModels: func(_ context.Context, cfg *config.Config, auths []*coreauth.Auth) []Model {
+			previewed = len(auths)
+			return []Model{{ID: "team/claude", Providers: []string{"claude"}, Auths: 1}}
+		},
+	})
+	if !report.Valid {
+		t.Fatalf("report invalid: %+v", report.Errors)
+	}
+	if previewed != 1 || len(report.Models) != 1 {
+		t.Fatalf("previewed %d auths, models = %+v", previewed, report.Models)
+	}
+	actions := map[string]int{}
+	for _, change := range report.Auths {
+		actions[change.Action]++
+		for _, detail := range change.Details {
+			if strings.Contains(detail, "sk-") {
+				t.Fatalf("auth change leaked a key: %q", detail)
+			}
+		}
+	}
+	if actions["added"] != 1 || actions["removed"] != 1 {
+		t.Fatalf("auth changes = %+v, want one added and one removed", report.Auths)
+	}
+	if len(report.Changes) == 0 {
+		t.Fatal("expected config changes against the current config")
+	}
+}
+
+func TestDiffAuths_Changed(t *testing.T) {
+	before := []*coreauth.Auth{{ID: "a", Provider: "codex", Prefix: "old", Attributes: map[string]string{"api_key": "sk-1"}}}
+	after := []*coreauth.Auth{{ID: "a", Provider: "codex", Prefix: "new", Attributes: map[string]string{"api_key": "sk-2"}}}
+
+	changes := DiffAuths(before, after)
+	if len(changes) != 1 || changes[0].Action != "changed" {
+		t.Fatalf("changes = %+v, want one changed auth", changes)
+	}
+	got := strings.Join(changes[0].Details, "; ")
+	if got != "prefix: old -> new; attribute api_key: updated" {
+		t.Fatalf("details = %q", got)
+	}
+}
+
+func TestPreviewPayload_MatchesRules(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Payload.Default = []config.PayloadRule{
+		{Models: []config.PayloadModelRule{{Name: "gpt-*"}}, Params: map[string]any{"temperature": 0.2}},
+	}
+	cfg.Payload.Override = []config.PayloadRule{
+		{Models: []config.PayloadModelRule{{Name: "gemini-*"}}, Params: map[string]any{"top_p": 0.5}},
+		{Models: []config.PayloadModelRule{{Name: "gpt-5", Protocol: "openai"}}, Params: map[string]any{"store": false}},
+	}
+
+	preview, err := PreviewPayload(cfg, &SampleRequest{
+		Model:    "gpt-5",
+		Protocol: "openai",
+		Body:     json.RawMessage(`{"messages":[]}`),
+	})
+	if err != nil {
+		t.Fatalf("PreviewPayload: %v", err)
+	}
+	if len(preview.Rules) != 2 ||
+		preview.Rules[0].Section != "default" || preview.Rules[0].Index != 0 ||
+		preview.Rules[1].Section != "override" || preview.Rules[1].Index != 1 {
+		t.Fatalf("rules = %+v", preview.Rules)
+	}
+	var payload map[string]any
+	if errDecode := json.Unmarshal(preview.Payload, &payload); errDecode != nil {
+		t.Fatalf("decode payload: %v", errDecode)
+	}
+	if payload["temperature"] != 0.2 || payload["store"] != false {
+		t.Fatalf("payload = %s", preview.Payload)
+	}
+}
@@ -0,0 +1,304 @@
+// Package confighistory keeps a bounded history of config.yaml revisions.
+//
+// Every store backend (file, git, Postgres, object store) mirrors the active
+// configuration to a local config.yaml, so revisions are captured from that
+// file and rollbacks are written back to it. Where the revisions themselves are
+// kept depends on the Backend: the file and git stores keep them on disk next
+// to config.yaml, while the Postgres and object stores keep them in the store
+// so every replica shares one history.
+package confighistory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMaxRevisions is the number of revisions kept when no limit is configured.
+const DefaultMaxRevisions = 20
+
+const (
+	revisionMetaSuffix = ".json"
+	revisionDataSuffix = ".yaml"
+	// recordAttempts bounds retries when another replica takes the next revision ID first.
+	recordAttempts = 3
+)
+
+var (
+	// ErrNotFound is returned when a revision does not exist.
+	ErrNotFound = errors.New("config revision not found")
+	// ErrRevisionExists is returned by Backend.PutRevision when the ID is already taken.
+	ErrRevisionExists = errors.New("config revision already exists")
+)
+
+// Revision describes one saved config.yaml.
+type Revision struct {
+	// ID increases monotonically; higher IDs are newer.
+	ID int64 `json:"id"`
+	// Timestamp is when the revision was captured, in UTC.
+	Timestamp time.Time `json:"timestamp"`
+	// Key is the management key that wrote the revision, if any.
+	Key string `json:"key,omitempty"`
+	// Source describes what produced the revision (a management route, "external" or "rollback").
+	Source string `json:"source,omitempty"`
+	// Size is the config size in bytes.
+	Size int `json:"size"`
+	// SHA256 is the hex digest of the config content.
+	SHA256 string `json:"sha256"`
+}
+
+// Backend persists revisions for a History.
+type Backend interface {
+	// ListRevisions returns every stored revision in any order.
+	ListRevisions(ctx context.Context) ([]Revision, error)
+	// GetRevision returns a revision and its content, or ErrNotFound.
+	GetRevision(ctx context.Context, id int64) (Revision, []byte, error)
+	// PutRevision stores a new revision, or returns ErrRevisionExists when its ID is taken.
+	PutRevision(ctx context.Context, rev Revision, data []byte) error
+	// DeleteRevisions removes revisions; missing IDs are ignored.
+	DeleteRevisions(ctx context.Context, ids ...int64) error
+}
+
+// History records config revisions in a Backend and prunes the oldest ones.
+type History struct {
+	mu           sync.Mutex
+	backend      Backend
+	maxRevisions int
+}
+
+// New returns a history stored as files in dir that keeps at most maxRevisions
+// revisions. Non-positive limits use DefaultMaxRevisions.
+func New(dir string, maxRevisions int) *History {
+	return NewWithBackend(NewFileBackend(dir), maxRevisions)
+}
+
+// NewWithBackend returns a history stored in backend that keeps at most
+// maxRevisions revisions. Non-positive limits use DefaultMaxRevisions.
+func NewWithBackend(backend Backend, maxRevisions int) *History {
+	if maxRevisions <= 0 {
+		maxRevisions = DefaultMaxRevisions
+	}
+	return &History{backend: backend, maxRevisions: maxRevisions}
+}
+
+// Backend returns the backend holding the revisions.
+func (h *History) Backend() Backend {
+	if h == nil {
+		return nil
+	}
+	return h.backend
+}
+
+// SetMaxRevisions updates the retention limit applied on the next Record.
+func (h *History) SetMaxRevisions(maxRevisions int) {
+	if h == nil {
+		return
+	}
+	if maxRevisions <= 0 {
+		maxRevisions = DefaultMaxRevisions
+	}
+	h.mu.Lock()
+	h.maxRevisions = maxRevisions
+	h.mu.Unlock()
+}
+
+// Record stores data as a new revision unless it matches the latest revision.
+// It reports whether a revision was written.
+func (h *History) Record(ctx context.Context, data []byte, rev Revision) (Revision, bool, error) {
+	if h == nil || h.backend == nil || len(data) == 0 {
+		return rev, false, nil
+	}
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	if rev.Timestamp.IsZero() {
+		rev.Timestamp = time.Now()
+	}
+	rev.Timestamp = rev.Timestamp.UTC()
+	rev.Size = len(data)
+	rev.SHA256 = digest
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var taken int64
+	for attempt := 1; ; attempt++ {
+		revisions, err := h.listLocked(ctx)
+		if err != nil {
+			return rev, false, err
+		}
+		if len(revisions) > 0 && revisions[0].SHA256 == digest {
+			return revisions[0], false, nil
+		}
+		rev.ID = taken + 1
+		if len(revisions) > 0 && revisions[0].ID >= rev.ID {
+			rev.ID = revisions[0].ID + 1
+		}
+		err = h.backend.PutRevision(ctx, rev, data)
+		if errors.Is(err, ErrRevisionExists) {
+			// Skip past IDs that are taken but not listed yet, e.g. a revision still being written.
+			taken = rev.ID
+		}
+		if errors.Is(err, ErrRevisionExists) && attempt < recordAttempts {
+			continue
+		}
+		if err != nil {
+			return rev, false, err
+		}
+
+		revisions = append([]Revision{rev}, revisions...)
+		if len(revisions) > h.maxRevisions {
+			stale := make([]int64, 0, len(revisions)-h.maxRevisions)
+			for _, old := range revisions[h.maxRevisions:] {
+				stale = append(stale, old.ID)
+			}
+			if errDelete := h.backend.DeleteRevisions(ctx, stale...); errDelete != nil {
+				return rev, true, errDelete
+			}
+		}
+		return rev, true, nil
+	}
+}
+
+// List returns the stored revisions, newest first.
+func (h *History) List(ctx context.Context) ([]Revision, error) {
+	if h == nil || h.backend == nil {
+		return nil, nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.listLocked(ctx)
+}
+
+// Get returns a revision and its config content.
+func (h *History) Get(ctx context.Context, id int64) (Revision, []byte, error) {
+	if h == nil || h.backend == nil {
+		return Revision{}, nil, ErrNotFound
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.backend.GetRevision(ctx, id)
+}
+
+func (h *History) listLocked(ctx context.Context) ([]Revision, error) {
+	revisions, err := h.backend.ListRevisions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].ID > revisions[j].ID })
+	return revisions, nil
+}
+
+// fileBackend stores revisions as <id>.yaml files with <id>.json metadata.
+type fileBackend struct {
+	dir string
+}
+
+// NewFileBackend returns a backend storing revisions as files in dir.
+func NewFileBackend(dir string) Backend {
+	return fileBackend{dir: dir}
+}
+
+func (b fileBackend) ListRevisions(_ context.Context) ([]Revision, error) {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("config history: read directory: %w", err)
+	}
+	revisions := make([]Revision, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, revisionMetaSuffix) {
+			continue
+		}
+		if _, errID := strconv.ParseInt(strings.TrimSuffix(name, revisionMetaSuffix), 10, 64); errID != nil {
+			continue
+		}
+		data, errRead := os.ReadFile(filepath.Join(b.dir, name))
+		if errRead != nil {
+			continue
+		}
+		var rev Revision
+		if json.Unmarshal(data, &rev) != nil || rev.ID <= 0 {
+			continue
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, nil
+}
+
+func (b fileBackend) GetRevision(_ context.Context, id int64) (Revision, []byte, error) {
+	var rev Revision
+	meta, err := os.ReadFile(b.pathFor(id, revisionMetaSuffix))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return rev, nil, ErrNotFound
+		}
+		return rev, nil, fmt.Errorf("config history: read revision: %w", err)
+	}
+	if err = json.Unmarshal(meta, &rev); err != nil {
+		return rev, nil, fmt.Errorf("config history: decode revision %d: %w", id, err)
+	}
+	data, err := os.ReadFile(b.pathFor(id, revisionDataSuffix))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return rev, nil, ErrNotFound
+		}
+		return rev, nil, fmt.Errorf("config history: read revision: %w", err)
+	}
+	return rev, data, nil
+}
+
+func (b fileBackend) PutRevision(_ context.Context, rev Revision, data []byte) error {
+	if err := os.MkdirAll(b.dir, 0o700); err != nil {
+		return fmt.Errorf("config history: create directory: %w", err)
+	}
+	meta, err := json.Marshal(rev)
+	if err != nil {
+		return fmt.Errorf("config history: encode revision: %w", err)
+	}
+	// Reserve the ID with an empty metadata file, which listings skip, so the
+	// content is always written before the revision becomes visible.
+	file, err := os.OpenFile(b.pathFor(rev.ID, revisionMetaSuffix), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return ErrRevisionExists
+		}
+		return fmt.Errorf("config history: write revision metadata: %w", err)
+	}
+	err = os.WriteFile(b.pathFor(rev.ID, revisionDataSuffix), data, 0o600)
+	if err == nil {
+		_, err = file.Write(meta)
+	}
+	if errClose := file.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		_ = os.Remove(b.pathFor(rev.ID, revisionMetaSuffix))
+		_ = os.Remove(b.pathFor(rev.ID, revisionDataSuffix))
+		return fmt.Errorf("config history: write revision: %w", err)
+	}
+	return nil
+}
+
+func (b fileBackend) DeleteRevisions(_ context.Context, ids ...int64) error {
+	for _, id := range ids {
+		_ = os.Remove(b.pathFor(id, revisionMetaSuffix))
+		_ = os.Remove(b.pathFor(id, revisionDataSuffix))
+	}
+	return nil
+}
+
+func (b fileBackend) pathFor(id int64, suffix string) string {
+	return filepath.Join(b.dir, fmt.Sprintf("%06d%s", id, suffix))
+}
@@ -0,0 +1,48 @@
+package confighistory
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHistory_RecordSkipsDuplicatesAndPrunes(t *testing.T) {
+	ctx := context.Background()
+	history := New(t.TempDir(), 2)
+	for i, content := range []string{"port: 1\n", "port: 1\n", "port: 2\n", "port: 3\n"} {
+		if _, _, err := history.Record(ctx, []byte(content), Revision{Source: "test"}); err != nil {
+			t.Fatalf("Record(%d): %v", i, err)
+		}
+	}
+
+	revisions, err := history.List(ctx)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(revisions) != 2 || revisions[0].ID != 3 || revisions[1].ID != 2 {
+		t.Fatalf("revisions = %+v, want IDs 3 and 2", revisions)
+	}
+	rev, data, err := history.Get(ctx, 3)
+	if err != nil || string(data) != "port: 3\n" || rev.Source != "test" {
+		t.Fatalf("Get(3) = %+v, %q, %v", rev, data, err)
+	}
+	if _, _, err = history.Get(ctx, 1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get(1) error = %v, want ErrNotFound after pruning", err)
+	}
+}
+
+func TestHistory_RecordSkipsReservedRevisionID(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	// An empty metadata file is a revision another writer has reserved but not finished.
+	if err := os.WriteFile(filepath.Join(dir, "000001.json"), nil, 0o600); err != nil {
+		t.Fatalf("reserve revision: %v", err)
+	}
+	history := New(dir, 5)
+	rev, written, err := history.Record(ctx, []byte("port: 1\n"), Revision{})
+	if err != nil || !written || rev.ID != 2 {
+		t.Fatalf("Record = %+v, %t, %v, want revision 2", rev, written, err)
+	}
+}
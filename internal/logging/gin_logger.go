@@ -13,6 +13,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/util"
+	sdkaccess "github.com/router-for-me/CLIProxyAPI/v7/sdk/access"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -27,6 +28,8 @@ var aiAPIPrefixes = []string{
 	"/openai/v1/videos",
 	"/v1beta/models/",
 	"/backend-api/codex/",
+	"/api/chat",
+	"/api/generate",
 }
 
 const (
@@ -92,6 +95,9 @@ func GinLogrusLogger() gin.HandlerFunc {
 		}
 
 		entry := log.WithField("request_id", requestID)
+		if keyName := accessKeyName(c); keyName != "" {
+			entry = entry.WithField("api_key_name", keyName)
+		}
 
 		switch {
 		case statusCode >= http.StatusInternalServerError:
@@ -169,3 +175,19 @@ func creditsUsed(c *gin.Context) bool {
 	flag, ok := val.(bool)
 	return ok && flag
 }
+
+// accessKeyName returns the client key label attached by the access middleware.
+func accessKeyName(c *gin.Context) string {
+	if c == nil {
+		return ""
+	}
+	val, exists := c.Get("accessMetadata")
+	if !exists {
+		return ""
+	}
+	metadata, ok := val.(map[string]string)
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(metadata[sdkaccess.MetadataKeyName])
+}
@@ -95,6 +95,17 @@ func TestIsAIAPIPathIncludesCodexBackend(t *testing.T) {
 	}
 }
 
+func TestIsAIAPIPathIncludesOllama(t *testing.T) {
+	for _, path := range []string{"/api/chat", "/api/generate"} {
+		if !isAIAPIPath(path) {
+			t.Fatalf("expected %s to be treated as AI API path", path)
+		}
+	}
+	if isAIAPIPath("/api/tags") {
+		t.Fatalf("expected /api/tags not to be treated as AI API path")
+	}
+}
+
 func TestGinLogrusLoggerAddsRequestIDForCodexBackend(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
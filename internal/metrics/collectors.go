@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"strings"
+	"time"
+)
+
+// pluginBuckets are plugin call latency buckets in seconds.
+var pluginBuckets = []float64{0.0005, 0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 5, 30}
+
+var (
+	// Requests counts upstream requests by client frontend, provider, model and status.
+	Requests = newCounterVec("cliproxy_requests_total",
+		"Upstream requests by client frontend, provider, model and status.",
+		"frontend", "provider", "model", "status")
+	// UpstreamLatency observes total upstream request latency.
+	UpstreamLatency = newHistogramVec("cliproxy_upstream_latency_seconds",
+		"Upstream request latency in seconds.", nil, "provider", "model")
+	// UpstreamTTFT observes time to first token for streamed requests.
+	UpstreamTTFT = newHistogramVec("cliproxy_upstream_ttft_seconds",
+		"Upstream time to first token in seconds.", nil, "provider", "model")
+	// Tokens counts tokens by provider, model and token type.
+	Tokens = newCounterVec("cliproxy_tokens_total",
+		"Tokens by provider, model and type (input, output, reasoning, cached).",
+		"provider", "model", "type")
+	// Cost accumulates the estimated USD cost from the model pricing catalog.
+	Cost = newCounterVec("cliproxy_cost_usd_total",
+		"Estimated request cost in USD.", "provider", "model")
+	// Retries counts upstream attempts made after an earlier attempt of the same request failed.
+	Retries = newCounterVec("cliproxy_retries_total",
+		"Upstream attempts retried after a failure within the same client request.", "provider")
+	// CredentialSwitches counts moves to a different credential after a failure.
+	CredentialSwitches = newCounterVec("cliproxy_credential_switches_total",
+		"Switches to another credential after a failed attempt.", "provider")
+	// ModelFallbacks counts requests served by a configured model fallback.
+	ModelFallbacks = newCounterVec("cliproxy_model_fallbacks_total",
+		"Requests served by a configured model fallback after the requested model failed.", "from", "to")
+	// Cooldowns counts credentials placed into cooldown by the scheduler.
+	Cooldowns = newCounterVec("cliproxy_auth_cooldowns_total",
+		"Credentials placed into cooldown by the scheduler.", "provider")
+	// ActiveStreams tracks streaming responses currently being forwarded.
+	ActiveStreams = newGaugeVec("cliproxy_active_streams",
+		"Streaming responses currently being forwarded to clients.", "frontend")
+	// WebsocketSessions tracks open client websocket sessions.
+	WebsocketSessions = newGaugeVec("cliproxy_websocket_sessions",
+		"Open client websocket sessions.", "frontend")
+	// PluginCallDuration observes plugin host call durations.
+	PluginCallDuration = newHistogramVec("cliproxy_plugin_call_duration_seconds",
+		"Plugin call duration in seconds.", pluginBuckets, "plugin", "method")
+)
+
+// ObservePluginCall records the duration of a plugin call started at start.
+// It is meant to be deferred at the call site.
+func ObservePluginCall(pluginID, method string, start time.Time) {
+	PluginCallDuration.WithLabelValues(pluginID, method).Observe(time.Since(start).Seconds())
+}
+
+// Frontend maps a request path, optionally prefixed with the HTTP method, to
+// the client-facing API family.
+func Frontend(endpoint string) string {
+	path := strings.TrimSpace(endpoint)
+	if idx := strings.IndexByte(path, ' '); idx >= 0 {
+		path = strings.TrimSpace(path[idx+1:])
+	}
+	switch {
+	case path == "":
+		return "unknown"
+	case strings.HasPrefix(path, "/v1/messages"):
+		return "claude"
+	case strings.HasPrefix(path, "/v1beta"), strings.HasPrefix(path, "/v1internal"):
+		return "gemini"
+	case strings.HasPrefix(path, "/v1/responses"), strings.HasPrefix(path, "/backend-api/codex"):
+		return "openai-responses"
+	case strings.HasPrefix(path, "/v1/"), strings.HasPrefix(path, "/openai/"):
+		return "openai"
+	case strings.HasPrefix(path, "/api/"):
+		return "ollama"
+	default:
+		return "other"
+	}
+}
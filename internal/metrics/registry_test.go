@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	internallogging "github.com/router-for-me/CLIProxyAPI/v7/internal/logging"
+	coreusage "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/usage"
+)
+
+func TestHandlerExposesUsageMetrics(t *testing.T) {
+	ctx := internallogging.WithEndpoint(context.Background(), "POST /v1/messages")
+	usagePlugin{}.HandleUsage(ctx, coreusage.Record{
+		Provider: "test-provider",
+		Model:    "test-model",
+		Latency:  750 * time.Millisecond,
+		Detail:   coreusage.Detail{InputTokens: 12, OutputTokens: 3},
+	})
+	usagePlugin{}.HandleUsage(ctx, coreusage.Record{
+		Provider: "test-provider",
+		Model:    "test-model",
+		Failed:   true,
+		Fail:     coreusage.Failure{StatusCode: http.StatusTooManyRequests},
+	})
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		`cliproxy_requests_total{frontend="claude",model="test-model",provider="test-provider",status="200"} 1`,
+		`cliproxy_requests_total{frontend="claude",model="test-model",provider="test-provider",status="429"} 1`,
+		`cliproxy_tokens_total{model="test-model",provider="test-provider",type="input"} 12`,
+		`cliproxy_upstream_latency_seconds_bucket{model="test-model",provider="test-provider",le="1"} 1`,
+		`cliproxy_upstream_latency_seconds_count{model="test-model",provider="test-provider"} 1`,
+		"\ngo_goroutines ",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("metrics output missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, `type="reasoning"`) {
+		t.Fatalf("zero token counts should not create series:\n%s", body)
+	}
+}
+
+func TestFrontend(t *testing.T) {
+	cases := map[string]string{
+		"":                                 "unknown",
+		"POST /v1/messages":                "claude",
+		"POST /v1/chat/completions":        "openai",
+		"POST /v1/responses":               "openai-responses",
+		"/backend-api/codex/responses":     "openai-responses",
+		"POST /v1beta/models/x:generate":   "gemini",
+		"POST /v1internal:generateContent": "gemini",
+		"GET /v0/management/config":        "other",
+		"POST /openai/v1/chat/completions": "openai",
+		"POST /api/chat":                   "ollama",
+	}
+	for endpoint, want := range cases {
+		if got := Frontend(endpoint); got != want {
+			t.Errorf("Frontend(%q) = %q, want %q", endpoint, got, want)
+		}
+	}
+}
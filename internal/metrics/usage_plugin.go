@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	internallogging "github.com/router-for-me/CLIProxyAPI/v7/internal/logging"
+	coreusage "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/usage"
+)
+
+func init() {
+	coreusage.RegisterPlugin(usagePlugin{})
+}
+
+// usagePlugin derives request, latency, token and cost metrics from usage records.
+type usagePlugin struct{}
+
+func (usagePlugin) HandleUsage(ctx context.Context, record coreusage.Record) {
+	provider := strings.TrimSpace(record.Provider)
+	model := strings.TrimSpace(record.Model)
+	Requests.WithLabelValues(Frontend(internallogging.GetEndpoint(ctx)), provider, model, recordStatus(record)).Inc()
+	if record.Latency > 0 {
+		UpstreamLatency.WithLabelValues(provider, model).Observe(record.Latency.Seconds())
+	}
+	if record.TTFT > 0 {
+		UpstreamTTFT.WithLabelValues(provider, model).Observe(record.TTFT.Seconds())
+	}
+	detail := record.Detail
+	for _, tokens := range []struct {
+		kind  string
+		count int64
+	}{
+		{"input", detail.InputTokens},
+		{"output", detail.OutputTokens},
+		{"reasoning", detail.ReasoningTokens},
+		{"cached", detail.CachedTokens},
+	} {
+		if tokens.count > 0 {
+			Tokens.WithLabelValues(provider, model, tokens.kind).Add(float64(tokens.count))
+		}
+	}
+	if record.CostUSD > 0 {
+		Cost.WithLabelValues(provider, model).Add(record.CostUSD)
+	}
+}
+
+func recordStatus(record coreusage.Record) string {
+	if !record.Failed {
+		return "200"
+	}
+	if record.Fail.StatusCode > 0 {
+		return strconv.Itoa(record.Fail.StatusCode)
+	}
+	return "error"
+}
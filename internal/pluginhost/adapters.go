@@ -12,7 +12,9 @@ import (
 	"runtime/debug"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/metrics"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/registry"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/thinking"
 	sdkaccess "github.com/router-for-me/CLIProxyAPI/v7/sdk/access"
@@ -515,6 +517,7 @@ func (h *Host) callRequestInterceptor(ctx context.Context, pluginID, method stri
 	if h == nil || call == nil || h.isPluginFused(pluginID) {
 		return pluginapi.RequestInterceptResponse{}, false
 	}
+	defer metrics.ObservePluginCall(pluginID, method, time.Now())
 	defer func() {
 		if recovered := recover(); recovered != nil {
 			h.fusePlugin(pluginID, method, recovered)
@@ -534,6 +537,7 @@ func (h *Host) callResponseInterceptor(ctx context.Context, pluginID string, int
 	if h == nil || interceptor == nil || h.isPluginFused(pluginID) {
 		return pluginapi.ResponseInterceptResponse{}, false
 	}
+	defer metrics.ObservePluginCall(pluginID, "ResponseInterceptor.InterceptResponse", time.Now())
 	defer func() {
 		if recovered := recover(); recovered != nil {
 			h.fusePlugin(pluginID, "ResponseInterceptor.InterceptResponse", recovered)
@@ -553,6 +557,7 @@ func (h *Host) callStreamChunkInterceptor(ctx context.Context, pluginID string,
 	if h == nil || interceptor == nil || h.isPluginFused(pluginID) {
 		return pluginapi.StreamChunkInterceptResponse{}, false
 	}
+	defer metrics.ObservePluginCall(pluginID, "StreamChunkInterceptor.InterceptStreamChunk", time.Now())
 	defer func() {
 		if recovered := recover(); recovered != nil {
 			h.fusePlugin(pluginID, "StreamChunkInterceptor.InterceptStreamChunk", recovered)
@@ -1274,6 +1279,7 @@ func (a *accessAdapter) Authenticate(ctx context.Context, r *http.Request) (resu
 	if a == nil || a.provider == nil || a.host.isPluginFused(a.pluginID) {
 		return nil, sdkaccess.NewNotHandledError()
 	}
+	defer metrics.ObservePluginCall(a.pluginID, "FrontendAuthProvider.Authenticate", time.Now())
 	defer func() {
 		if recovered := recover(); recovered != nil {
 			a.host.fusePlugin(a.pluginID, "FrontendAuthProvider.Authenticate", recovered)
@@ -1575,6 +1581,7 @@ func (a *executorAdapter) Execute(ctx context.Context, auth *coreauth.Auth, req
 	if a == nil || a.executor == nil || a.host.isPluginFused(a.pluginID) {
 		return coreexecutor.Response{}, fmt.Errorf("plugin executor %s is unavailable", a.Identifier())
 	}
+	defer metrics.ObservePluginCall(a.pluginID, "Executor.Execute", time.Now())
 	defer func() {
 		if recovered := recover(); recovered != nil {
 			a.host.fusePlugin(a.pluginID, "Executor.Execute", recovered)
@@ -1602,6 +1609,7 @@ func (a *executorAdapter) ExecuteStream(ctx context.Context, auth *coreauth.Auth
 	if a == nil || a.executor == nil || a.host.isPluginFused(a.pluginID) {
 		return nil, fmt.Errorf("plugin executor %s is unavailable", a.Identifier())
 	}
+	defer metrics.ObservePluginCall(a.pluginID, "Executor.ExecuteStream", time.Now())
 	defer func() {
 		if recovered := recover(); recovered != nil {
 			a.host.fusePlugin(a.pluginID, "Executor.ExecuteStream", recovered)
@@ -1701,6 +1709,7 @@ func (a *executorAdapter) CountTokens(ctx context.Context, auth *coreauth.Auth,
 	if a == nil || a.executor == nil || a.host.isPluginFused(a.pluginID) {
 		return coreexecutor.Response{}, fmt.Errorf("plugin executor %s is unavailable", a.Identifier())
 	}
+	defer metrics.ObservePluginCall(a.pluginID, "Executor.CountTokens", time.Now())
 	defer func() {
 		if recovered := recover(); recovered != nil {
 			a.host.fusePlugin(a.pluginID, "Executor.CountTokens", recovered)
@@ -1731,6 +1740,7 @@ func (a *executorAdapter) HttpRequest(ctx context.Context, auth *coreauth.Auth,
 	if req == nil {
 		return nil, fmt.Errorf("plugin executor %s received nil HTTP request", a.Identifier())
 	}
+	defer metrics.ObservePluginCall(a.pluginID, "Executor.HttpRequest", time.Now())
 	defer func() {
 		if recovered := recover(); recovered != nil {
 			a.host.fusePlugin(a.pluginID, "Executor.HttpRequest", recovered)
@@ -1792,6 +1802,7 @@ func (a *usageAdapter) HandleUsage(ctx context.Context, record coreusage.Record)
 	if plugin == nil {
 		return
 	}
+	defer metrics.ObservePluginCall(a.pluginID, "UsagePlugin.HandleUsage", time.Now())
 	defer func() {
 		if recovered := recover(); recovered != nil {
 			a.host.fusePlugin(a.pluginID, "UsagePlugin.HandleUsage", recovered)
@@ -1803,6 +1814,7 @@ func (a *usageAdapter) HandleUsage(ctx context.Context, record coreusage.Record)
 		Model:           record.Model,
 		Alias:           record.Alias,
 		APIKey:          record.APIKey,
+		APIKeyName:      record.APIKeyName,
 		AuthID:          record.AuthID,
 		AuthIndex:       record.AuthIndex,
 		AuthType:        record.AuthType,
@@ -1827,6 +1839,7 @@ func (a *usageAdapter) HandleUsage(ctx context.Context, record coreusage.Record)
 			TotalTokens:         record.Detail.TotalTokens,
 		},
 		ResponseHeaders: cloneHeader(record.ResponseHeaders),
+		CostUSD:         record.CostUSD,
 	})
 }
 
@@ -1834,6 +1847,7 @@ func (a *thinkingAdapter) Apply(body []byte, config thinking.ThinkingConfig, mod
 	if a == nil || a.applier == nil || a.host == nil || a.host.isPluginFused(a.pluginID) {
 		return bytes.Clone(body), nil
 	}
+	defer metrics.ObservePluginCall(a.pluginID, "ThinkingApplier.ApplyThinking", time.Now())
 	defer func() {
 		if recovered := recover(); recovered != nil {
 			a.host.fusePlugin(a.pluginID, "ThinkingApplier.ApplyThinking", recovered)
@@ -1924,6 +1938,7 @@ func (h *Host) NormalizeResponseAfter(ctx context.Context, from, to sdktranslato
 }
 
 func (h *Host) callRequestNormalizer(ctx context.Context, record capabilityRecord, from, to sdktranslator.Format, model string, body []byte, stream bool) (out []byte, ok bool) {
+	defer metrics.ObservePluginCall(record.id, "RequestNormalizer.NormalizeRequest", time.Now())
 	defer func() {
 		if recovered := recover(); recovered != nil {
 			h.fusePlugin(record.id, "RequestNormalizer.NormalizeRequest", recovered)
@@ -1945,6 +1960,7 @@ func (h *Host) callRequestNormalizer(ctx context.Context, record capabilityRecor
 }
 
 func (h *Host) callRequestTranslator(ctx context.Context, record capabilityRecord, from, to sdktranslator.Format, model string, body []byte, stream bool) (out []byte, ok bool) {
+	defer metrics.ObservePluginCall(record.id, "RequestTranslator.TranslateRequest", time.Now())
 	defer func() {
 		if recovered := recover(); recovered != nil {
 			h.fusePlugin(record.id, "RequestTranslator.TranslateRequest", recovered)
@@ -1966,6 +1982,7 @@ func (h *Host) callRequestTranslator(ctx context.Context, record capabilityRecor
 }
 
 func (h *Host) callResponseNormalizer(ctx context.Context, pluginID, method string, normalizer pluginapi.ResponseNormalizer, from, to sdktranslator.Format, model string, originalRequestRawJSON, requestRawJSON, body []byte, stream bool) (out []byte, ok bool) {
+	defer metrics.ObservePluginCall(pluginID, method, time.Now())
 	defer func() {
 		if recovered := recover(); recovered != nil {
 			h.fusePlugin(pluginID, method, recovered)
@@ -1989,6 +2006,7 @@ func (h *Host) callResponseNormalizer(ctx context.Context, pluginID, method stri
 }
 
 func (h *Host) callResponseTranslator(ctx context.Context, pluginID string, translator pluginapi.ResponseTranslator, from, to sdktranslator.Format, model string, originalRequestRawJSON, requestRawJSON, body []byte, stream bool) (out []byte, ok bool) {
+	defer metrics.ObservePluginCall(pluginID, "ResponseTranslator.TranslateResponse", time.Now())
 	defer func() {
 		if recovered := recover(); recovered != nil {
 			h.fusePlugin(pluginID, "ResponseTranslator.TranslateResponse", recovered)
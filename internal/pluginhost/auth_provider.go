@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/authcrypt"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
 	coreauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
 	"github.com/router-for-me/CLIProxyAPI/v7/sdk/pluginapi"
@@ -354,7 +355,7 @@ func pluginTokenStorageFileCurrent(path string, payload []byte) bool {
 	if strings.TrimSpace(path) == "" || len(bytes.TrimSpace(payload)) == 0 {
 		return false
 	}
-	current, errRead := os.ReadFile(path)
+	current, errRead := authcrypt.ReadFile(path)
 	if errRead != nil {
 		return false
 	}
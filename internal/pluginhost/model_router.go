@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"strings"
+	"time"
 
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/metrics"
 	"github.com/router-for-me/CLIProxyAPI/v7/sdk/pluginapi"
 	log "github.com/sirupsen/logrus"
 )
@@ -78,6 +80,7 @@ func (h *Host) callModelRouter(ctx context.Context, pluginID string, router plug
 	if h == nil || router == nil || h.isPluginFused(pluginID) {
 		return pluginapi.ModelRouteResponse{}, false
 	}
+	defer metrics.ObservePluginCall(pluginID, "ModelRouter.RouteModel", time.Now())
 	defer func() {
 		if recovered := recover(); recovered != nil {
 			h.fusePlugin(pluginID, "ModelRouter.RouteModel", recovered)
@@ -3,7 +3,9 @@ package pluginhost
 import (
 	"context"
 	"strings"
+	"time"
 
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/metrics"
 	"github.com/router-for-me/CLIProxyAPI/v7/sdk/pluginapi"
 	log "github.com/sirupsen/logrus"
 )
@@ -53,6 +55,7 @@ func (h *Host) callScheduler(ctx context.Context, record capabilityRecord, req p
 	if h == nil || scheduler == nil || h.isPluginFused(record.id) {
 		return pluginapi.SchedulerPickResponse{}, false, nil
 	}
+	defer metrics.ObservePluginCall(record.id, "Scheduler.Pick", time.Now())
 	defer func() {
 		if recovered := recover(); recovered != nil {
 			h.fusePlugin(record.id, "Scheduler.Pick", recovered)
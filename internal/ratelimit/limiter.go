@@ -0,0 +1,231 @@
+// Package ratelimit enforces per-client-key request rates, concurrency caps and
+// token budgets before requests are dispatched to upstream providers.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+)
+
+// Limits describes the throttling applied to a single client key.
+// Zero values disable the corresponding limit.
+type Limits struct {
+	RequestsPerMinute  int
+	MaxConcurrent      int
+	DailyTokenBudget   int64
+	MonthlyTokenBudget int64
+}
+
+// Enabled reports whether any limit is configured.
+func (l Limits) Enabled() bool {
+	return l.RequestsPerMinute > 0 || l.MaxConcurrent > 0 || l.DailyTokenBudget > 0 || l.MonthlyTokenBudget > 0
+}
+
+// LimitsFromClientKey extracts the rate limit settings of a structured client key.
+func LimitsFromClientKey(entry config.ClientAPIKey) Limits {
+	return Limits{
+		RequestsPerMinute:  entry.RequestsPerMinute,
+		MaxConcurrent:      entry.MaxConcurrentRequests,
+		DailyTokenBudget:   entry.DailyTokenBudget,
+		MonthlyTokenBudget: entry.MonthlyTokenBudget,
+	}
+}
+
+// Error reports a rejected request and how long the client should wait.
+type Error struct {
+	Reason     string
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	if e == nil {
+		return ""
+	}
+	return e.Reason
+}
+
+// RetryAfterSeconds returns the Retry-After header value, rounded up to whole seconds.
+func (e *Error) RetryAfterSeconds() int {
+	if e == nil || e.RetryAfter <= 0 {
+		return 1
+	}
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+type keyState struct {
+	requests []time.Time
+	inflight int
+
+	day         string
+	dailyTokens int64
+
+	month         string
+	monthlyTokens int64
+}
+
+// Limiter tracks request and token usage per client key.
+type Limiter struct {
+	mu     sync.Mutex
+	limits map[string]Limits
+	states map[string]*keyState
+	now    func() time.Time
+}
+
+// NewLimiter constructs an empty limiter. Keys without configured limits are never throttled.
+func NewLimiter() *Limiter {
+	return &Limiter{
+		limits: make(map[string]Limits),
+		states: make(map[string]*keyState),
+		now:    time.Now,
+	}
+}
+
+var defaultLimiter = NewLimiter()
+
+// Default returns the process-wide limiter used by the HTTP server and usage plugin.
+func Default() *Limiter { return defaultLimiter }
+
+// Configure replaces the per-key limits from the structured client keys.
+// Counters of keys that remain configured are preserved across reloads.
+func (l *Limiter) Configure(entries []config.ClientAPIKey) {
+	if l == nil {
+		return
+	}
+	limits := make(map[string]Limits, len(entries))
+	for _, entry := range entries {
+		key := strings.TrimSpace(entry.APIKey)
+		if key == "" {
+			continue
+		}
+		if entryLimits := LimitsFromClientKey(entry); entryLimits.Enabled() {
+			limits[key] = entryLimits
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limits = limits
+	for key, state := range l.states {
+		if _, ok := limits[key]; !ok && state.inflight == 0 {
+			delete(l.states, key)
+		}
+	}
+}
+
+// Acquire admits a request for the key. On success the returned release function
+// must be called once the request completes; it is never nil.
+func (l *Limiter) Acquire(key string) (func(), *Error) {
+	noop := func() {}
+	if l == nil || key == "" {
+		return noop, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	limits, ok := l.limits[key]
+	if !ok {
+		return noop, nil
+	}
+	now := l.now()
+	state := l.stateLocked(key, now)
+
+	if limits.DailyTokenBudget > 0 && state.dailyTokens >= limits.DailyTokenBudget {
+		return noop, &Error{
+			Reason:     fmt.Sprintf("daily token budget of %d tokens exhausted", limits.DailyTokenBudget),
+			RetryAfter: nextDay(now).Sub(now),
+		}
+	}
+	if limits.MonthlyTokenBudget > 0 && state.monthlyTokens >= limits.MonthlyTokenBudget {
+		return noop, &Error{
+			Reason:     fmt.Sprintf("monthly token budget of %d tokens exhausted", limits.MonthlyTokenBudget),
+			RetryAfter: nextMonth(now).Sub(now),
+		}
+	}
+	if limits.RequestsPerMinute > 0 {
+		cutoff := now.Add(-time.Minute)
+		kept := state.requests[:0]
+		for _, at := range state.requests {
+			if at.After(cutoff) {
+				kept = append(kept, at)
+			}
+		}
+		state.requests = kept
+		if len(state.requests) >= limits.RequestsPerMinute {
+			return noop, &Error{
+				Reason:     fmt.Sprintf("rate limit of %d requests per minute exceeded", limits.RequestsPerMinute),
+				RetryAfter: state.requests[0].Add(time.Minute).Sub(now),
+			}
+		}
+	}
+	if limits.MaxConcurrent > 0 && state.inflight >= limits.MaxConcurrent {
+		return noop, &Error{
+			Reason:     fmt.Sprintf("concurrency limit of %d requests exceeded", limits.MaxConcurrent),
+			RetryAfter: time.Second,
+		}
+	}
+
+	if limits.RequestsPerMinute > 0 {
+		state.requests = append(state.requests, now)
+	}
+	state.inflight++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			if state.inflight > 0 {
+				state.inflight--
+			}
+			l.mu.Unlock()
+		})
+	}, nil
+}
+
+// RecordTokens adds consumed tokens to the current daily and monthly budgets of the key.
+func (l *Limiter) RecordTokens(key string, tokens int64) {
+	if l == nil || key == "" || tokens <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	limits, ok := l.limits[key]
+	if !ok || (limits.DailyTokenBudget <= 0 && limits.MonthlyTokenBudget <= 0) {
+		return
+	}
+	state := l.stateLocked(key, l.now())
+	state.dailyTokens += tokens
+	state.monthlyTokens += tokens
+}
+
+// stateLocked returns the state for key, rolling budget windows forward. Callers hold l.mu.
+func (l *Limiter) stateLocked(key string, now time.Time) *keyState {
+	state, ok := l.states[key]
+	if !ok {
+		state = &keyState{}
+		l.states[key] = state
+	}
+	utc := now.UTC()
+	if day := utc.Format("2006-01-02"); state.day != day {
+		state.day = day
+		state.dailyTokens = 0
+	}
+	if month := utc.Format("2006-01"); state.month != month {
+		state.month = month
+		state.monthlyTokens = 0
+	}
+	return state
+}
+
+func nextDay(now time.Time) time.Time {
+	utc := now.UTC()
+	return time.Date(utc.Year(), utc.Month(), utc.Day()+1, 0, 0, 0, 0, time.UTC)
+}
+
+func nextMonth(now time.Time) time.Time {
+	utc := now.UTC()
+	return time.Date(utc.Year(), utc.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+)
+
+func newTestLimiter(now *time.Time, entries ...config.ClientAPIKey) *Limiter {
+	l := NewLimiter()
+	l.now = func() time.Time { return *now }
+	l.Configure(entries)
+	return l
+}
+
+func TestLimiterRequestsPerMinute(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := newTestLimiter(&now, config.ClientAPIKey{APIKey: "k", RequestsPerMinute: 2})
+
+	for i := 0; i < 2; i++ {
+		release, errLimit := l.Acquire("k")
+		if errLimit != nil {
+			t.Fatalf("request %d rejected: %v", i, errLimit)
+		}
+		release()
+		now = now.Add(10 * time.Second)
+	}
+	_, errLimit := l.Acquire("k")
+	if errLimit == nil {
+		t.Fatal("expected third request to be rejected")
+	}
+	if got := errLimit.RetryAfterSeconds(); got != 40 {
+		t.Fatalf("retry after = %d, want 40", got)
+	}
+
+	now = now.Add(41 * time.Second)
+	if _, errLimit = l.Acquire("k"); errLimit != nil {
+		t.Fatalf("request after window rejected: %v", errLimit)
+	}
+}
+
+func TestLimiterConcurrency(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := newTestLimiter(&now, config.ClientAPIKey{APIKey: "k", MaxConcurrentRequests: 1})
+
+	release, errLimit := l.Acquire("k")
+	if errLimit != nil {
+		t.Fatalf("first request rejected: %v", errLimit)
+	}
+	if _, errLimit = l.Acquire("k"); errLimit == nil {
+		t.Fatal("expected concurrent request to be rejected")
+	}
+	release()
+	release()
+	if _, errLimit = l.Acquire("k"); errLimit != nil {
+		t.Fatalf("request after release rejected: %v", errLimit)
+	}
+}
+
+func TestLimiterTokenBudgets(t *testing.T) {
+	now := time.Date(2026, 1, 31, 23, 0, 0, 0, time.UTC)
+	l := newTestLimiter(&now,
+		config.ClientAPIKey{APIKey: "daily", DailyTokenBudget: 100},
+		config.ClientAPIKey{APIKey: "monthly", MonthlyTokenBudget: 100},
+	)
+
+	l.RecordTokens("daily", 100)
+	l.RecordTokens("monthly", 150)
+	_, errLimit := l.Acquire("daily")
+	if errLimit == nil {
+		t.Fatal("expected daily budget rejection")
+	}
+	if got := errLimit.RetryAfter; got != time.Hour {
+		t.Fatalf("daily retry after = %v, want 1h", got)
+	}
+	if _, errLimit = l.Acquire("monthly"); errLimit == nil {
+		t.Fatal("expected monthly budget rejection")
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, errLimit = l.Acquire("daily"); errLimit != nil {
+		t.Fatalf("daily budget should reset: %v", errLimit)
+	}
+	if _, errLimit = l.Acquire("monthly"); errLimit != nil {
+		t.Fatalf("monthly budget should reset: %v", errLimit)
+	}
+}
+
+func TestLimiterIgnoresUnlimitedKeys(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newTestLimiter(&now, config.ClientAPIKey{APIKey: "k", Name: "no limits"})
+
+	for i := 0; i < 10; i++ {
+		if _, errLimit := l.Acquire("k"); errLimit != nil {
+			t.Fatalf("unlimited key rejected: %v", errLimit)
+		}
+	}
+	l.RecordTokens("k", 1000)
+	if len(l.states) != 0 {
+		t.Fatalf("unlimited keys should not be tracked: %#v", l.states)
+	}
+}
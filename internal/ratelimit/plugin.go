@@ -0,0 +1,31 @@
+package ratelimit
+
+import (
+	"context"
+	"strings"
+
+	coreusage "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/usage"
+)
+
+func init() {
+	coreusage.RegisterPlugin(&usagePlugin{limiter: defaultLimiter})
+}
+
+// usagePlugin charges completed requests against the token budgets of the client key.
+type usagePlugin struct {
+	limiter *Limiter
+}
+
+func (p *usagePlugin) HandleUsage(_ context.Context, record coreusage.Record) {
+	if p == nil || p.limiter == nil {
+		return
+	}
+	p.limiter.RecordTokens(strings.TrimSpace(record.APIKey), recordTokens(record.Detail))
+}
+
+func recordTokens(detail coreusage.Detail) int64 {
+	if detail.TotalTokens > 0 {
+		return detail.TotalTokens
+	}
+	return detail.InputTokens + detail.OutputTokens + detail.ReasoningTokens
+}
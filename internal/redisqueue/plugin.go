@@ -104,9 +104,11 @@ func (p *usageQueuePlugin) HandleUsage(ctx context.Context, record coreusage.Rec
 		Endpoint:        resolveEndpoint(ctx),
 		AuthType:        authType,
 		APIKey:          apiKey,
+		APIKeyName:      strings.TrimSpace(record.APIKeyName),
 		RequestID:       requestID,
 		ReasoningEffort: reasoningEffort,
 		ServiceTier:     serviceTier,
+		CostUSD:         record.CostUSD,
 	})
 	if err != nil {
 		return
@@ -116,16 +118,18 @@ func (p *usageQueuePlugin) HandleUsage(ctx context.Context, record coreusage.Rec
 
 type queuedUsageDetail struct {
 	requestDetail
-	Provider        string `json:"provider"`
-	ExecutorType    string `json:"executor_type"`
-	Model           string `json:"model"`
-	Alias           string `json:"alias"`
-	Endpoint        string `json:"endpoint"`
-	AuthType        string `json:"auth_type"`
-	APIKey          string `json:"api_key"`
-	RequestID       string `json:"request_id"`
-	ReasoningEffort string `json:"reasoning_effort"`
-	ServiceTier     string `json:"service_tier"`
+	Provider        string  `json:"provider"`
+	ExecutorType    string  `json:"executor_type"`
+	Model           string  `json:"model"`
+	Alias           string  `json:"alias"`
+	Endpoint        string  `json:"endpoint"`
+	AuthType        string  `json:"auth_type"`
+	APIKey          string  `json:"api_key"`
+	APIKeyName      string  `json:"api_key_name,omitempty"`
+	RequestID       string  `json:"request_id"`
+	ReasoningEffort string  `json:"reasoning_effort"`
+	ServiceTier     string  `json:"service_tier"`
+	CostUSD         float64 `json:"cost_usd,omitempty"`
 }
 
 type requestDetail struct {
@@ -12,6 +12,8 @@ const (
 	xaiBuiltinImageQualityModelID   = "grok-imagine-image-quality"
 	xaiBuiltinVideoModelID          = "grok-imagine-video"
 	xaiBuiltinVideo15PreviewModelID = "grok-imagine-video-1.5-preview"
+	geminiBuiltinEmbeddingModelID   = "gemini-embedding-001"
+	vertexBuiltinTextEmbeddingID    = "text-embedding-005"
 )
 
 // staticModelsJSON mirrors the top-level structure of models.json.
@@ -37,12 +39,12 @@ func GetClaudeModels() []*ModelInfo {
 
 // GetGeminiModels returns the standard Gemini model definitions.
 func GetGeminiModels() []*ModelInfo {
-	return cloneModelInfos(getModels().Gemini)
+	return WithGeminiEmbeddingBuiltins(cloneModelInfos(getModels().Gemini))
 }
 
 // GetGeminiVertexModels returns Gemini model definitions for Vertex AI.
 func GetGeminiVertexModels() []*ModelInfo {
-	return cloneModelInfos(getModels().Vertex)
+	return WithVertexEmbeddingBuiltins(cloneModelInfos(getModels().Vertex))
 }
 
 // GetGeminiCLIModels returns Gemini model definitions for the Gemini CLI.
@@ -128,6 +130,18 @@ func WithXAIBuiltins(models []*ModelInfo) []*ModelInfo {
 	return upsertModelInfos(models, xaiBuiltinImageModelInfo(), xaiBuiltinImageQualityModelInfo(), xaiBuiltinVideoModelInfo(), xaiBuiltinVideo15PreviewModelInfo())
 }
 
+// WithGeminiEmbeddingBuiltins injects the Gemini embedding model served through
+// the OpenAI-compatible /v1/embeddings endpoint.
+func WithGeminiEmbeddingBuiltins(models []*ModelInfo) []*ModelInfo {
+	return upsertModelInfos(models, geminiBuiltinEmbeddingModelInfo())
+}
+
+// WithVertexEmbeddingBuiltins injects the Vertex AI embedding models served
+// through the OpenAI-compatible /v1/embeddings endpoint.
+func WithVertexEmbeddingBuiltins(models []*ModelInfo) []*ModelInfo {
+	return upsertModelInfos(models, geminiBuiltinEmbeddingModelInfo(), vertexBuiltinTextEmbeddingModelInfo())
+}
+
 func normalizeAntigravityCapabilityModelID(modelID string) string {
 	modelID = strings.ToLower(strings.TrimSpace(modelID))
 	if open := strings.LastIndex(modelID, "("); open >= 0 && strings.HasSuffix(modelID, ")") {
@@ -200,6 +214,38 @@ func xaiBuiltinVideo15PreviewModelInfo() *ModelInfo {
 	}
 }
 
+func geminiBuiltinEmbeddingModelInfo() *ModelInfo {
+	return &ModelInfo{
+		ID:                         geminiBuiltinEmbeddingModelID,
+		Object:                     "model",
+		Created:                    1752019200, // 2025-07-09
+		OwnedBy:                    "google",
+		Type:                       "gemini",
+		DisplayName:                "Gemini Embedding 001",
+		Name:                       "models/" + geminiBuiltinEmbeddingModelID,
+		Version:                    "001",
+		Description:                "Gemini text embedding model.",
+		InputTokenLimit:            2048,
+		SupportedGenerationMethods: []string{"embedContent", "batchEmbedContents"},
+	}
+}
+
+func vertexBuiltinTextEmbeddingModelInfo() *ModelInfo {
+	return &ModelInfo{
+		ID:                         vertexBuiltinTextEmbeddingID,
+		Object:                     "model",
+		Created:                    1731974400, // 2024-11-19
+		OwnedBy:                    "google",
+		Type:                       "gemini",
+		DisplayName:                "Text Embedding 005",
+		Name:                       "models/" + vertexBuiltinTextEmbeddingID,
+		Version:                    "005",
+		Description:                "Vertex AI text embedding model.",
+		InputTokenLimit:            2048,
+		SupportedGenerationMethods: []string{"embedContent", "batchEmbedContents"},
+	}
+}
+
 func upsertModelInfos(models []*ModelInfo, extras ...*ModelInfo) []*ModelInfo {
 	if len(extras) == 0 {
 		return models
@@ -0,0 +1,56 @@
+package registry
+
+// ModelPricing lists USD prices per one million tokens.
+// Zero CacheRead and CacheWrite fall back to the input price and a zero
+// Reasoning price falls back to the output price.
+type ModelPricing struct {
+	// Input is the price of uncached prompt tokens.
+	Input float64 `json:"input,omitempty" yaml:"input,omitempty"`
+	// Output is the price of completion tokens.
+	Output float64 `json:"output,omitempty" yaml:"output,omitempty"`
+	// CacheRead is the price of prompt tokens served from the provider cache.
+	CacheRead float64 `json:"cache-read,omitempty" yaml:"cache-read,omitempty"`
+	// CacheWrite is the price of prompt tokens written to the provider cache.
+	CacheWrite float64 `json:"cache-write,omitempty" yaml:"cache-write,omitempty"`
+	// Reasoning is the price of reasoning/thinking tokens.
+	Reasoning float64 `json:"reasoning,omitempty" yaml:"reasoning,omitempty"`
+}
+
+// IsZero reports whether no price is configured.
+func (p *ModelPricing) IsZero() bool {
+	return p == nil || (p.Input == 0 && p.Output == 0 && p.CacheRead == 0 && p.CacheWrite == 0 && p.Reasoning == 0)
+}
+
+// Cost returns the USD cost of the given token counts. Each count must already
+// be disjoint from the others (e.g. input excludes cached tokens).
+func (p *ModelPricing) Cost(input, output, reasoning, cacheRead, cacheWrite int64) float64 {
+	if p.IsZero() {
+		return 0
+	}
+	cacheReadPrice := p.CacheRead
+	if cacheReadPrice == 0 {
+		cacheReadPrice = p.Input
+	}
+	cacheWritePrice := p.CacheWrite
+	if cacheWritePrice == 0 {
+		cacheWritePrice = p.Input
+	}
+	reasoningPrice := p.Reasoning
+	if reasoningPrice == 0 {
+		reasoningPrice = p.Output
+	}
+	total := float64(input)*p.Input +
+		float64(output)*p.Output +
+		float64(reasoning)*reasoningPrice +
+		float64(cacheRead)*cacheReadPrice +
+		float64(cacheWrite)*cacheWritePrice
+	return total / 1_000_000
+}
+
+func cloneModelPricing(p *ModelPricing) *ModelPricing {
+	if p == nil {
+		return nil
+	}
+	copyPricing := *p
+	return &copyPricing
+}
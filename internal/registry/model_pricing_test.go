@@ -0,0 +1,63 @@
+package registry
+
+import (
+	"math"
+	"testing"
+)
+
+func TestModelPricingCostFallsBackToInputAndOutputPrices(t *testing.T) {
+	pricing := &ModelPricing{Input: 2, Output: 10}
+	got := pricing.Cost(1_000_000, 500_000, 100_000, 200_000, 300_000)
+	want := 2.0 + 5.0 + 1.0 + 0.4 + 0.6
+	if math.Abs(got-want) > 1e-9 {
+		t.Fatalf("cost = %v, want %v", got, want)
+	}
+
+	pricing = &ModelPricing{Input: 3, Output: 15, CacheRead: 0.3, CacheWrite: 3.75, Reasoning: 20}
+	got = pricing.Cost(0, 0, 1_000_000, 1_000_000, 1_000_000)
+	want = 20 + 0.3 + 3.75
+	if math.Abs(got-want) > 1e-9 {
+		t.Fatalf("cost = %v, want %v", got, want)
+	}
+
+	var empty *ModelPricing
+	if got = empty.Cost(1, 1, 1, 1, 1); got != 0 {
+		t.Fatalf("nil pricing cost = %v", got)
+	}
+}
+
+func TestStaticCatalogIncludesPricing(t *testing.T) {
+	info := LookupStaticModelInfo("claude-sonnet-4-5-20250929")
+	if info == nil {
+		t.Fatal("expected static model definition")
+	}
+	if info.Pricing.IsZero() || info.Pricing.Output == 0 || info.Pricing.CacheRead == 0 || info.Pricing.CacheWrite == 0 {
+		t.Fatalf("pricing = %#v", info.Pricing)
+	}
+}
+
+func TestInheritMissingPricingKeepsKnownPrices(t *testing.T) {
+	oldData := &staticModelsJSON{Claude: []*ModelInfo{
+		{ID: "priced", Pricing: &ModelPricing{Input: 1, Output: 2}},
+	}}
+	newData := &staticModelsJSON{Claude: []*ModelInfo{
+		{ID: "priced"},
+		{ID: "overridden", Pricing: &ModelPricing{Input: 5}},
+		{ID: "unknown"},
+	}}
+	inheritMissingPricing(newData, oldData)
+
+	if got := newData.Claude[0].Pricing; got == nil || got.Output != 2 {
+		t.Fatalf("inherited pricing = %#v", got)
+	}
+	if got := newData.Claude[1].Pricing; got.Input != 5 {
+		t.Fatalf("explicit pricing was replaced: %#v", got)
+	}
+	if newData.Claude[2].Pricing != nil {
+		t.Fatalf("unexpected pricing = %#v", newData.Claude[2].Pricing)
+	}
+	newData.Claude[0].Pricing.Output = 99
+	if oldData.Claude[0].Pricing.Output != 2 {
+		t.Fatal("inherited pricing should be a copy")
+	}
+}
@@ -62,6 +62,9 @@ type ModelInfo struct {
 	// This is optional and currently used for Gemini thinking budget normalization.
 	Thinking *ThinkingSupport `json:"thinking,omitempty"`
 
+	// Pricing holds optional per-million-token prices used for cost accounting.
+	Pricing *ModelPricing `json:"pricing,omitempty"`
+
 	// UserDefined indicates this model was defined through config file's models[]
 	// array (e.g., openai-compatibility.*.models[], *-api-key.models[]).
 	// UserDefined models have thinking configuration passed through without validation.
@@ -550,6 +553,7 @@ func cloneModelInfo(model *ModelInfo) *ModelInfo {
 		}
 		copyModel.Thinking = &copyThinking
 	}
+	copyModel.Pricing = cloneModelPricing(model.Pricing)
 	return &copyModel
 }
 
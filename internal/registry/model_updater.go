@@ -121,6 +121,8 @@ func tryRefreshModels(ctx context.Context, label string) {
 		return
 	}
 
+	inheritMissingPricing(parsed, oldData)
+
 	// Detect changes before updating store.
 	changed := detectChangedProviders(oldData, parsed)
 
@@ -369,3 +371,41 @@ func validateModelSection(section string, models []*ModelInfo) error {
 	}
 	return nil
 }
+
+// inheritMissingPricing copies prices from the current catalog onto refreshed
+// models that do not declare their own, so remote catalogs without pricing do
+// not silently disable cost accounting.
+func inheritMissingPricing(newData, oldData *staticModelsJSON) {
+	if newData == nil || oldData == nil {
+		return
+	}
+	prices := make(map[string]*ModelPricing)
+	for _, list := range oldData.sections() {
+		for _, model := range list {
+			if model != nil && !model.Pricing.IsZero() {
+				prices[model.ID] = model.Pricing
+			}
+		}
+	}
+	if len(prices) == 0 {
+		return
+	}
+	for _, list := range newData.sections() {
+		for _, model := range list {
+			if model == nil || model.Pricing != nil {
+				continue
+			}
+			if pricing, ok := prices[model.ID]; ok {
+				model.Pricing = cloneModelPricing(pricing)
+			}
+		}
+	}
+}
+
+func (d *staticModelsJSON) sections() [][]*ModelInfo {
+	return [][]*ModelInfo{
+		d.Claude, d.Gemini, d.Vertex, d.GeminiCLI, d.AIStudio,
+		d.CodexFree, d.CodexTeam, d.CodexPlus, d.CodexPro,
+		d.Kimi, d.Antigravity, d.XAI,
+	}
+}
@@ -0,0 +1,205 @@
+// Package responsecache replays upstream responses for exact repeats of a
+// request so identical prompts do not spend provider quota again.
+package responsecache
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+)
+
+const (
+	defaultTTL          = 10 * time.Minute
+	defaultMaxEntries   = 1000
+	defaultMaxSizeMB    = 64
+	defaultMaxEntrySize = 1024
+)
+
+// Entry is a cached response. Non-streaming responses carry Body, streaming
+// responses carry the SSE chunks in the order they were sent to the client.
+type Entry struct {
+	Body     []byte
+	Chunks   [][]byte
+	Headers  http.Header
+	StoredAt time.Time
+}
+
+func (e *Entry) size() int64 {
+	size := int64(len(e.Body))
+	for _, chunk := range e.Chunks {
+		size += int64(len(chunk))
+	}
+	return size
+}
+
+type cacheItem struct {
+	key   string
+	entry *Entry
+	size  int64
+}
+
+// Cache is an in-memory LRU of responses with a TTL and size limits.
+type Cache struct {
+	mu           sync.Mutex
+	enabled      bool
+	ttl          time.Duration
+	maxEntries   int
+	maxSize      int64
+	maxEntrySize int64
+	size         int64
+	entries      map[string]*list.Element
+	order        *list.List
+	now          func() time.Time
+}
+
+// New returns a disabled cache. Call Apply to enable it.
+func New() *Cache {
+	return &Cache{entries: make(map[string]*list.Element), order: list.New(), now: time.Now}
+}
+
+var defaultCache = New()
+
+// Default returns the process-wide cache used by the API handlers.
+func Default() *Cache { return defaultCache }
+
+// Enabled reports whether responses are being cached.
+func (c *Cache) Enabled() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.enabled
+}
+
+// Apply (re)configures the cache. Disabling it drops every cached response.
+func (c *Cache) Apply(cfg config.ResponseCacheConfig) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !cfg.Enable {
+		c.enabled = false
+		c.entries = make(map[string]*list.Element)
+		c.order.Init()
+		c.size = 0
+		return
+	}
+	c.enabled = true
+	c.ttl = defaultTTL
+	if cfg.TTLSeconds > 0 {
+		c.ttl = time.Duration(cfg.TTLSeconds) * time.Second
+	}
+	c.maxEntries = defaultMaxEntries
+	if cfg.MaxEntries > 0 {
+		c.maxEntries = cfg.MaxEntries
+	}
+	c.maxSize = defaultMaxSizeMB << 20
+	if cfg.MaxSizeMB > 0 {
+		c.maxSize = int64(cfg.MaxSizeMB) << 20
+	}
+	c.maxEntrySize = defaultMaxEntrySize << 10
+	if cfg.MaxEntrySizeKB > 0 {
+		c.maxEntrySize = int64(cfg.MaxEntrySizeKB) << 10
+	}
+	c.evictLocked()
+}
+
+// Get returns the live entry for key. The returned entry must not be modified.
+func (c *Cache) Get(key string) (*Entry, bool) {
+	if c == nil || key == "" {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.enabled {
+		return nil, false
+	}
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	item := element.Value.(*cacheItem)
+	if c.now().Sub(item.entry.StoredAt) >= c.ttl {
+		c.removeLocked(element)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return item.entry, true
+}
+
+// Put stores entry under key unless it exceeds the per-entry size limit.
+func (c *Cache) Put(key string, entry *Entry) {
+	if c == nil || key == "" || entry == nil {
+		return
+	}
+	size := entry.size()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.enabled || size > c.maxEntrySize || size > c.maxSize {
+		return
+	}
+	if entry.StoredAt.IsZero() {
+		entry.StoredAt = c.now()
+	}
+	if element, ok := c.entries[key]; ok {
+		c.removeLocked(element)
+	}
+	c.entries[key] = c.order.PushFront(&cacheItem{key: key, entry: entry, size: size})
+	c.size += size
+	c.evictLocked()
+}
+
+func (c *Cache) evictLocked() {
+	for c.order.Len() > 0 && (c.order.Len() > c.maxEntries || c.size > c.maxSize) {
+		c.removeLocked(c.order.Back())
+	}
+}
+
+func (c *Cache) removeLocked(element *list.Element) {
+	item := element.Value.(*cacheItem)
+	c.order.Remove(element)
+	delete(c.entries, item.key)
+	c.size -= item.size
+}
+
+// Key derives the cache key of a request. The payload is normalized so key
+// order and whitespace do not matter, and its top-level "stream" flag is
+// replaced by the stream argument.
+func Key(format, model string, stream bool, payload []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(format))
+	hash.Write([]byte{0})
+	hash.Write([]byte(model))
+	hash.Write([]byte{0})
+	hash.Write([]byte(strconv.FormatBool(stream)))
+	hash.Write([]byte{0})
+	hash.Write(normalizePayload(payload))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func normalizePayload(payload []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return payload
+	}
+	if object, ok := value.(map[string]any); ok {
+		delete(object, "stream")
+	}
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return payload
+	}
+	return normalized
+}
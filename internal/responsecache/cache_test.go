@@ -0,0 +1,54 @@
+package responsecache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+)
+
+func TestKey_NormalizesPayload(t *testing.T) {
+	a := Key("openai", "m", false, []byte(`{"model":"m","messages":[{"role":"user","content":"hi"}],"temperature":0}`))
+	b := Key("openai", "m", false, []byte(`{ "temperature": 0, "messages": [{"content":"hi","role":"user"}], "model": "m", "stream": false }`))
+	if a != b {
+		t.Fatal("equivalent payloads produced different keys")
+	}
+	if a == Key("openai", "m", true, []byte(`{"model":"m","messages":[{"role":"user","content":"hi"}],"temperature":0}`)) {
+		t.Fatal("stream and non-stream requests share a key")
+	}
+	if a == Key("claude", "m", false, []byte(`{"model":"m","messages":[{"role":"user","content":"hi"}],"temperature":0}`)) {
+		t.Fatal("different formats share a key")
+	}
+}
+
+func TestCache_TTLAndLimits(t *testing.T) {
+	now := time.Now()
+	cache := New()
+	cache.now = func() time.Time { return now }
+	cache.Put("ignored", &Entry{Body: []byte("x")})
+	if _, ok := cache.Get("ignored"); ok {
+		t.Fatal("disabled cache returned an entry")
+	}
+
+	cache.Apply(config.ResponseCacheConfig{Enable: true, TTLSeconds: 60, MaxEntries: 2, MaxEntrySizeKB: 1})
+	cache.Put("a", &Entry{Body: []byte("a")})
+	cache.Put("b", &Entry{Body: []byte("b")})
+	cache.Get("a")
+	cache.Put("c", &Entry{Body: []byte("c")})
+	if _, ok := cache.Get("b"); ok {
+		t.Fatal("least recently used entry was not evicted")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("recently used entry was evicted")
+	}
+
+	cache.Put("big", &Entry{Chunks: [][]byte{make([]byte, 1024), []byte("!")}})
+	if _, ok := cache.Get("big"); ok {
+		t.Fatal("entry above max-entry-size-kb was cached")
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := cache.Get("c"); ok {
+		t.Fatal("expired entry was returned")
+	}
+}
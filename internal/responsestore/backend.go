@@ -0,0 +1,177 @@
+package responsestore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/util"
+)
+
+// BackendFile persists each response as a JSON file.
+const BackendFile = "file"
+
+const defaultDirName = "responses-store"
+
+// Backend is a durable store behind the in-memory cache. Get returns nil
+// without an error when the id is unknown.
+type Backend interface {
+	Put(ctx context.Context, record *Record) error
+	Get(ctx context.Context, id string) (*Record, error)
+	Delete(ctx context.Context, id string) error
+	Prune(ctx context.Context, before time.Time) error
+	Close() error
+}
+
+// BackendFactory opens a backend from the store configuration.
+type BackendFactory func(cfg config.ResponsesStoreConfig) (Backend, error)
+
+var (
+	backendsMu sync.RWMutex
+	backends   = map[string]BackendFactory{}
+)
+
+func init() {
+	RegisterBackend(BackendFile, func(cfg config.ResponsesStoreConfig) (Backend, error) {
+		dir := strings.TrimSpace(cfg.Dir)
+		if dir == "" {
+			base := util.WritablePath()
+			if base == "" {
+				base = "."
+			}
+			dir = filepath.Join(base, defaultDirName)
+		}
+		return NewFileBackend(dir)
+	})
+}
+
+// RegisterBackend makes a durable backend selectable by name in responses-store.backend.
+func RegisterBackend(name string, factory BackendFactory) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || factory == nil {
+		return
+	}
+	backendsMu.Lock()
+	backends[name] = factory
+	backendsMu.Unlock()
+}
+
+func openBackend(cfg config.ResponsesStoreConfig) (Backend, error) {
+	backendsMu.RLock()
+	factory, ok := backends[cfg.Backend]
+	backendsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("responses store: unknown backend %q", cfg.Backend)
+	}
+	return factory(cfg)
+}
+
+var safeFileName = regexp.MustCompile(`^[A-Za-z0-9_-]{1,128}$`)
+
+// FileBackend stores one JSON document per response.
+type FileBackend struct {
+	dir string
+}
+
+// NewFileBackend prepares dir for response files.
+func NewFileBackend(dir string) (*FileBackend, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return nil, fmt.Errorf("responses store: file backend directory is required")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("responses store: create directory: %w", err)
+	}
+	return &FileBackend{dir: dir}, nil
+}
+
+func (b *FileBackend) path(id string) string {
+	name := id
+	if !safeFileName.MatchString(name) {
+		sum := sha256.Sum256([]byte(id))
+		name = hex.EncodeToString(sum[:])
+	}
+	return filepath.Join(b.dir, name+".json")
+}
+
+// Put writes the record atomically.
+func (b *FileBackend) Put(_ context.Context, record *Record) error {
+	if record == nil || record.ID == "" {
+		return nil
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("responses store: encode record: %w", err)
+	}
+	path := b.path(record.ID)
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("responses store: write record: %w", err)
+	}
+	if err = os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("responses store: write record: %w", err)
+	}
+	return nil
+}
+
+// Get reads the record for id.
+func (b *FileBackend) Get(_ context.Context, id string) (*Record, error) {
+	data, err := os.ReadFile(b.path(id))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("responses store: read record: %w", err)
+	}
+	var record Record
+	if err = json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("responses store: decode record: %w", err)
+	}
+	if record.ID != id {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+// Delete removes the record for id.
+func (b *FileBackend) Delete(_ context.Context, id string) error {
+	if err := os.Remove(b.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("responses store: delete record: %w", err)
+	}
+	return nil
+}
+
+// Prune removes files last written before the cutoff.
+func (b *FileBackend) Prune(_ context.Context, before time.Time) error {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return fmt.Errorf("responses store: list directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		info, errInfo := entry.Info()
+		if errInfo != nil || !info.ModTime().Before(before) {
+			continue
+		}
+		if errRemove := os.Remove(filepath.Join(b.dir, entry.Name())); errRemove != nil && !errors.Is(errRemove, os.ErrNotExist) {
+			return fmt.Errorf("responses store: prune %s: %w", entry.Name(), errRemove)
+		}
+	}
+	return nil
+}
+
+// Close is a no-op for the file backend.
+func (b *FileBackend) Close() error { return nil }
@@ -0,0 +1,91 @@
+// Package responsestore keeps Responses API results keyed by response id so
+// previous_response_id can be expanded locally for backends that have no
+// notion of stored conversations.
+package responsestore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// Record is a stored response together with the full input that produced it.
+type Record struct {
+	ID        string          `json:"id"`
+	Owner     string          `json:"owner,omitempty"`
+	Model     string          `json:"model,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	Input     json.RawMessage `json:"input"`
+	Response  json.RawMessage `json:"response"`
+}
+
+// Transcript returns the stored input items followed by the response output items.
+func (r *Record) Transcript() []byte {
+	if r == nil {
+		return []byte("[]")
+	}
+	return joinItems(gjson.ParseBytes(r.Input), gjson.GetBytes(r.Response, "output"))
+}
+
+func joinItems(lists ...gjson.Result) []byte {
+	items := make([]string, 0)
+	for _, list := range lists {
+		if !list.IsArray() {
+			continue
+		}
+		for _, item := range list.Array() {
+			items = append(items, item.Raw)
+		}
+	}
+	return []byte("[" + strings.Join(items, ",") + "]")
+}
+
+// OwnerKey derives a stable, non-reversible owner identifier from a client principal.
+func OwnerKey(principal string) string {
+	principal = strings.TrimSpace(principal)
+	if principal == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(principal))
+	return hex.EncodeToString(sum[:16])
+}
+
+// NormalizeInput converts a Responses API input value into an array of items.
+// A bare string becomes a single user message.
+func NormalizeInput(input gjson.Result) ([]byte, error) {
+	switch {
+	case !input.Exists() || input.Type == gjson.Null:
+		return []byte("[]"), nil
+	case input.IsArray():
+		return []byte(input.Raw), nil
+	case input.Type == gjson.String:
+		item, err := sjson.SetBytes([]byte(`{"type":"message","role":"user","content":""}`), "content", input.String())
+		if err != nil {
+			return nil, err
+		}
+		return append(append([]byte("["), item...), ']'), nil
+	default:
+		return nil, fmt.Errorf("input must be a string or an array")
+	}
+}
+
+// ExpandRequest replaces previous_response_id in a Responses API request with
+// the full transcript of prev followed by the request's own input.
+func ExpandRequest(rawJSON []byte, prev *Record) ([]byte, error) {
+	current, err := NormalizeInput(gjson.GetBytes(rawJSON, "input"))
+	if err != nil {
+		return nil, err
+	}
+	merged := joinItems(gjson.ParseBytes(prev.Transcript()), gjson.ParseBytes(current))
+	out, err := sjson.SetRawBytes(rawJSON, "input", merged)
+	if err != nil {
+		return nil, err
+	}
+	return sjson.DeleteBytes(out, "previous_response_id")
+}
@@ -0,0 +1,275 @@
+package responsestore
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultTTL        = 24 * time.Hour
+	defaultMaxEntries = 10000
+	pruneInterval     = time.Hour
+	backendTimeout    = 10 * time.Second
+)
+
+// ErrDisabled is returned when the store is not enabled.
+var ErrDisabled = errors.New("responses store is disabled")
+
+type memoryEntry struct {
+	record  *Record
+	element *list.Element
+}
+
+// Store caches responses in memory with a TTL and optionally mirrors them to
+// a durable backend so they survive restarts.
+type Store struct {
+	mu         sync.Mutex
+	cfg        config.ResponsesStoreConfig
+	enabled    bool
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*memoryEntry
+	order      *list.List
+	backend    Backend
+	lastPrune  time.Time
+	now        func() time.Time
+}
+
+// NewStore returns a disabled store. Call Apply to enable it.
+func NewStore() *Store {
+	return &Store{entries: make(map[string]*memoryEntry), order: list.New(), now: time.Now}
+}
+
+var defaultStore = NewStore()
+
+// Default returns the process-wide store used by the Responses API handlers.
+func Default() *Store { return defaultStore }
+
+// Enabled reports whether responses are being stored.
+func (s *Store) Enabled() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enabled
+}
+
+// Apply (re)configures the store. Cached responses are kept across reloads;
+// the backend is only reopened when its settings change.
+func (s *Store) Apply(cfg config.ResponsesStoreConfig) error {
+	if s == nil {
+		return nil
+	}
+	cfg.Backend = strings.ToLower(strings.TrimSpace(cfg.Backend))
+	cfg.Dir = strings.TrimSpace(cfg.Dir)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !cfg.Enable {
+		s.closeLocked()
+		return nil
+	}
+	s.enabled = true
+	s.ttl = defaultTTL
+	if cfg.TTLSeconds > 0 {
+		s.ttl = time.Duration(cfg.TTLSeconds) * time.Second
+	}
+	s.maxEntries = defaultMaxEntries
+	if cfg.MaxEntries > 0 {
+		s.maxEntries = cfg.MaxEntries
+	}
+	s.evictLocked()
+
+	if s.backend != nil && cfg.Backend == s.cfg.Backend && cfg.Dir == s.cfg.Dir {
+		s.cfg = cfg
+		return nil
+	}
+	s.closeBackendLocked()
+	s.cfg = cfg
+	if cfg.Backend == "" {
+		return nil
+	}
+	backend, err := openBackend(cfg)
+	if err != nil {
+		return err
+	}
+	s.backend = backend
+	log.Infof("responses store enabled (backend=%s)", cfg.Backend)
+	return nil
+}
+
+// Put stores a record, replacing any previous record with the same id.
+func (s *Store) Put(ctx context.Context, record *Record) error {
+	if s == nil || record == nil || strings.TrimSpace(record.ID) == "" {
+		return nil
+	}
+	s.mu.Lock()
+	if !s.enabled {
+		s.mu.Unlock()
+		return ErrDisabled
+	}
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = s.now()
+	}
+	s.putLocked(record)
+	backend := s.backend
+	prune := backend != nil && s.now().Sub(s.lastPrune) >= pruneInterval
+	if prune {
+		s.lastPrune = s.now()
+	}
+	cutoff := s.now().Add(-s.ttl)
+	s.mu.Unlock()
+
+	if backend == nil {
+		return nil
+	}
+	if prune {
+		go func() {
+			pruneCtx, cancel := context.WithTimeout(context.Background(), backendTimeout)
+			defer cancel()
+			if err := backend.Prune(pruneCtx, cutoff); err != nil {
+				log.Warnf("responses store: prune: %v", err)
+			}
+		}()
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), backendTimeout)
+	defer cancel()
+	return backend.Put(ctx, record)
+}
+
+// Get returns the record for id when it exists, has not expired and belongs to owner.
+func (s *Store) Get(ctx context.Context, id, owner string) (*Record, error) {
+	if s == nil {
+		return nil, ErrDisabled
+	}
+	id = strings.TrimSpace(id)
+	s.mu.Lock()
+	if !s.enabled {
+		s.mu.Unlock()
+		return nil, ErrDisabled
+	}
+	if entry, ok := s.entries[id]; ok {
+		record := entry.record
+		if s.expiredLocked(record) {
+			s.removeLocked(id)
+			s.mu.Unlock()
+			return nil, nil
+		}
+		s.mu.Unlock()
+		return ownedBy(record, owner), nil
+	}
+	backend := s.backend
+	s.mu.Unlock()
+	if backend == nil || id == "" {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, backendTimeout)
+	defer cancel()
+	record, err := backend.Get(ctx, id)
+	if err != nil || record == nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	if s.expiredLocked(record) {
+		s.mu.Unlock()
+		return nil, nil
+	}
+	s.putLocked(record)
+	s.mu.Unlock()
+	return ownedBy(record, owner), nil
+}
+
+// Delete removes the record for id when it belongs to owner. It reports whether a record was removed.
+func (s *Store) Delete(ctx context.Context, id, owner string) (bool, error) {
+	record, err := s.Get(ctx, id, owner)
+	if err != nil || record == nil {
+		return false, err
+	}
+	s.mu.Lock()
+	s.removeLocked(record.ID)
+	backend := s.backend
+	s.mu.Unlock()
+	if backend == nil {
+		return true, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, backendTimeout)
+	defer cancel()
+	if err = backend.Delete(ctx, record.ID); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// Close drops cached responses and releases the backend.
+func (s *Store) Close() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeLocked()
+}
+
+func ownedBy(record *Record, owner string) *Record {
+	if record == nil || record.Owner != owner {
+		return nil
+	}
+	return record
+}
+
+func (s *Store) expiredLocked(record *Record) bool {
+	return s.ttl > 0 && s.now().Sub(record.CreatedAt) > s.ttl
+}
+
+func (s *Store) putLocked(record *Record) {
+	s.removeLocked(record.ID)
+	element := s.order.PushBack(record.ID)
+	s.entries[record.ID] = &memoryEntry{record: record, element: element}
+	s.evictLocked()
+}
+
+func (s *Store) removeLocked(id string) {
+	entry, ok := s.entries[id]
+	if !ok {
+		return
+	}
+	s.order.Remove(entry.element)
+	delete(s.entries, id)
+}
+
+func (s *Store) evictLocked() {
+	for s.maxEntries > 0 && len(s.entries) > s.maxEntries {
+		oldest := s.order.Front()
+		if oldest == nil {
+			return
+		}
+		s.removeLocked(oldest.Value.(string))
+	}
+}
+
+func (s *Store) closeLocked() {
+	s.enabled = false
+	s.entries = make(map[string]*memoryEntry)
+	s.order.Init()
+	s.closeBackendLocked()
+	s.cfg = config.ResponsesStoreConfig{}
+}
+
+func (s *Store) closeBackendLocked() {
+	if s.backend == nil {
+		return
+	}
+	if err := s.backend.Close(); err != nil {
+		log.Warnf("responses store: close backend: %v", err)
+	}
+	s.backend = nil
+}
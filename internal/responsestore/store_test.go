@@ -0,0 +1,102 @@
+package responsestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/tidwall/gjson"
+)
+
+func TestExpandRequestPrependsTranscript(t *testing.T) {
+	prev := &Record{
+		ID:       "resp_1",
+		Input:    []byte(`[{"type":"message","role":"user","content":"hi"}]`),
+		Response: []byte(`{"id":"resp_1","output":[{"type":"message","role":"assistant","content":[{"type":"output_text","text":"hello"}]}]}`),
+	}
+	out, err := ExpandRequest([]byte(`{"model":"m","previous_response_id":"resp_1","input":"again"}`), prev)
+	if err != nil {
+		t.Fatalf("ExpandRequest: %v", err)
+	}
+	if gjson.GetBytes(out, "previous_response_id").Exists() {
+		t.Fatalf("previous_response_id kept: %s", out)
+	}
+	input := gjson.GetBytes(out, "input").Array()
+	if len(input) != 3 {
+		t.Fatalf("input = %s", gjson.GetBytes(out, "input").Raw)
+	}
+	if input[1].Get("role").String() != "assistant" || input[2].Get("content").String() != "again" {
+		t.Fatalf("unexpected order: %s", gjson.GetBytes(out, "input").Raw)
+	}
+
+	if _, err = ExpandRequest([]byte(`{"input":{"bad":true}}`), prev); err == nil {
+		t.Fatal("expected error for object input")
+	}
+}
+
+func TestStoreTTLOwnerAndEviction(t *testing.T) {
+	store := NewStore()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	if err := store.Apply(config.ResponsesStoreConfig{Enable: true, TTLSeconds: 60, MaxEntries: 2}); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	ctx := context.Background()
+	for _, id := range []string{"a", "b", "c"} {
+		if err := store.Put(ctx, &Record{ID: id, Owner: "owner"}); err != nil {
+			t.Fatalf("Put %s: %v", id, err)
+		}
+	}
+	if record, _ := store.Get(ctx, "a", "owner"); record != nil {
+		t.Fatal("oldest record should be evicted")
+	}
+	if record, _ := store.Get(ctx, "b", "other"); record != nil {
+		t.Fatal("record must not be visible to another owner")
+	}
+	if record, _ := store.Get(ctx, "b", "owner"); record == nil {
+		t.Fatal("expected record b")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if record, _ := store.Get(ctx, "c", "owner"); record != nil {
+		t.Fatal("expired record should not be returned")
+	}
+
+	store.Close()
+	if _, err := store.Get(ctx, "b", "owner"); err != ErrDisabled {
+		t.Fatalf("err = %v, want ErrDisabled", err)
+	}
+}
+
+func TestStoreFileBackendSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.ResponsesStoreConfig{Enable: true, Backend: BackendFile, Dir: dir}
+	ctx := context.Background()
+
+	first := NewStore()
+	if err := first.Apply(cfg); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if err := first.Put(ctx, &Record{ID: "resp_abc", Owner: "o", Response: []byte(`{"id":"resp_abc"}`)}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	first.Close()
+
+	second := NewStore()
+	if err := second.Apply(cfg); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	defer second.Close()
+	record, err := second.Get(ctx, "resp_abc", "o")
+	if err != nil || record == nil {
+		t.Fatalf("Get = %v, %v", record, err)
+	}
+	deleted, err := second.Delete(ctx, "resp_abc", "o")
+	if err != nil || !deleted {
+		t.Fatalf("Delete = %v, %v", deleted, err)
+	}
+	if record, _ = second.Get(ctx, "resp_abc", "o"); record != nil {
+		t.Fatal("record should be deleted from the backend")
+	}
+}
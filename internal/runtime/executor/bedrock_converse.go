@@ -0,0 +1,447 @@
+package executor
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// convertClaudeRequestToBedrockConverse maps an Anthropic Messages request onto
+// the Bedrock Converse request shape. Fields without a Converse equivalent that
+// Anthropic models still understand (thinking, top_k, output_config, betas) are
+// forwarded through additionalModelRequestFields.
+func convertClaudeRequestToBedrockConverse(body []byte, betas []string) []byte {
+	root := gjson.ParseBytes(body)
+	out := []byte(`{"messages":[]}`)
+
+	system := root.Get("system")
+	if system.Type == gjson.String {
+		if text := system.String(); strings.TrimSpace(text) != "" {
+			out, _ = sjson.SetBytes(out, "system.-1", map[string]string{"text": text})
+		}
+	} else if system.IsArray() {
+		for _, block := range system.Array() {
+			if text := block.Get("text").String(); strings.TrimSpace(text) != "" {
+				out, _ = sjson.SetBytes(out, "system.-1", map[string]string{"text": text})
+			}
+			if block.Get("cache_control").Exists() {
+				out, _ = sjson.SetRawBytes(out, "system.-1", []byte(`{"cachePoint":{"type":"default"}}`))
+			}
+		}
+	}
+
+	for _, message := range root.Get("messages").Array() {
+		role := message.Get("role").String()
+		if role != "user" && role != "assistant" {
+			continue
+		}
+		content := convertClaudeContentToConverse(message.Get("content"))
+		if len(content) == 0 {
+			continue
+		}
+		msg := []byte(`{"role":"","content":[]}`)
+		msg, _ = sjson.SetBytes(msg, "role", role)
+		for _, block := range content {
+			msg, _ = sjson.SetRawBytes(msg, "content.-1", block)
+		}
+		out, _ = sjson.SetRawBytes(out, "messages.-1", msg)
+	}
+
+	if v := root.Get("max_tokens"); v.Exists() {
+		out, _ = sjson.SetBytes(out, "inferenceConfig.maxTokens", v.Int())
+	}
+	if v := root.Get("temperature"); v.Exists() {
+		out, _ = sjson.SetRawBytes(out, "inferenceConfig.temperature", []byte(v.Raw))
+	}
+	if v := root.Get("top_p"); v.Exists() {
+		out, _ = sjson.SetRawBytes(out, "inferenceConfig.topP", []byte(v.Raw))
+	}
+	if v := root.Get("stop_sequences"); v.IsArray() && len(v.Array()) > 0 {
+		out, _ = sjson.SetRawBytes(out, "inferenceConfig.stopSequences", []byte(v.Raw))
+	}
+
+	for _, field := range []string{"top_k", "thinking", "output_config"} {
+		if v := root.Get(field); v.Exists() {
+			out, _ = sjson.SetRawBytes(out, "additionalModelRequestFields."+field, []byte(v.Raw))
+		}
+	}
+	if len(betas) > 0 {
+		out, _ = sjson.SetBytes(out, "additionalModelRequestFields.anthropic_beta", betas)
+	}
+
+	toolChoice := root.Get("tool_choice")
+	if toolChoice.Get("type").String() != "none" {
+		for _, tool := range root.Get("tools").Array() {
+			name := tool.Get("name").String()
+			schema := tool.Get("input_schema")
+			if name == "" || !schema.Exists() {
+				// Anthropic server tools (web search, code execution, ...) have no Converse equivalent.
+				continue
+			}
+			spec := []byte(`{"toolSpec":{"name":"","inputSchema":{"json":{}}}}`)
+			spec, _ = sjson.SetBytes(spec, "toolSpec.name", name)
+			if desc := tool.Get("description").String(); desc != "" {
+				spec, _ = sjson.SetBytes(spec, "toolSpec.description", desc)
+			}
+			spec, _ = sjson.SetRawBytes(spec, "toolSpec.inputSchema.json", []byte(schema.Raw))
+			out, _ = sjson.SetRawBytes(out, "toolConfig.tools.-1", spec)
+			if tool.Get("cache_control").Exists() {
+				out, _ = sjson.SetRawBytes(out, "toolConfig.tools.-1", []byte(`{"cachePoint":{"type":"default"}}`))
+			}
+		}
+		if gjson.GetBytes(out, "toolConfig.tools").Exists() {
+			switch toolChoice.Get("type").String() {
+			case "auto":
+				out, _ = sjson.SetRawBytes(out, "toolConfig.toolChoice", []byte(`{"auto":{}}`))
+			case "any":
+				out, _ = sjson.SetRawBytes(out, "toolConfig.toolChoice", []byte(`{"any":{}}`))
+			case "tool":
+				out, _ = sjson.SetBytes(out, "toolConfig.toolChoice.tool.name", toolChoice.Get("name").String())
+			}
+		}
+	}
+	return out
+}
+
+// convertClaudeContentToConverse converts one Anthropic message content value
+// (string or block array) into Converse content blocks.
+func convertClaudeContentToConverse(content gjson.Result) [][]byte {
+	if content.Type == gjson.String {
+		if strings.TrimSpace(content.String()) == "" {
+			return nil
+		}
+		block, _ := sjson.SetBytes([]byte(`{}`), "text", content.String())
+		return [][]byte{block}
+	}
+	var out [][]byte
+	for _, part := range content.Array() {
+		switch part.Get("type").String() {
+		case "text":
+			if strings.TrimSpace(part.Get("text").String()) == "" {
+				continue
+			}
+			block, _ := sjson.SetBytes([]byte(`{}`), "text", part.Get("text").String())
+			out = append(out, block)
+		case "image":
+			if block := convertClaudeImageToConverse(part); block != nil {
+				out = append(out, block)
+			}
+		case "document":
+			if part.Get("source.type").String() != "base64" {
+				continue
+			}
+			format := strings.TrimPrefix(part.Get("source.media_type").String(), "application/")
+			if format == "" {
+				format = "pdf"
+			}
+			name := part.Get("title").String()
+			if name == "" {
+				name = "document"
+			}
+			block := []byte(`{"document":{}}`)
+			block, _ = sjson.SetBytes(block, "document.format", format)
+			block, _ = sjson.SetBytes(block, "document.name", name)
+			block, _ = sjson.SetBytes(block, "document.source.bytes", part.Get("source.data").String())
+			out = append(out, block)
+		case "tool_use":
+			input := part.Get("input").Raw
+			if input == "" {
+				input = "{}"
+			}
+			block := []byte(`{"toolUse":{}}`)
+			block, _ = sjson.SetBytes(block, "toolUse.toolUseId", part.Get("id").String())
+			block, _ = sjson.SetBytes(block, "toolUse.name", part.Get("name").String())
+			block, _ = sjson.SetRawBytes(block, "toolUse.input", []byte(input))
+			out = append(out, block)
+		case "tool_result":
+			block := []byte(`{"toolResult":{"content":[]}}`)
+			block, _ = sjson.SetBytes(block, "toolResult.toolUseId", part.Get("tool_use_id").String())
+			resultContent := part.Get("content")
+			if resultContent.Type == gjson.String {
+				block, _ = sjson.SetBytes(block, "toolResult.content.-1", map[string]string{"text": resultContent.String()})
+			} else {
+				for _, item := range resultContent.Array() {
+					switch item.Get("type").String() {
+					case "text":
+						block, _ = sjson.SetBytes(block, "toolResult.content.-1", map[string]string{"text": item.Get("text").String()})
+					case "image":
+						if image := convertClaudeImageToConverse(item); image != nil {
+							block, _ = sjson.SetRawBytes(block, "toolResult.content.-1", image)
+						}
+					}
+				}
+			}
+			if len(gjson.GetBytes(block, "toolResult.content").Array()) == 0 {
+				block, _ = sjson.SetBytes(block, "toolResult.content.-1", map[string]string{"text": ""})
+			}
+			if part.Get("is_error").Bool() {
+				block, _ = sjson.SetBytes(block, "toolResult.status", "error")
+			}
+			out = append(out, block)
+		case "thinking":
+			block := []byte(`{"reasoningContent":{"reasoningText":{}}}`)
+			block, _ = sjson.SetBytes(block, "reasoningContent.reasoningText.text", part.Get("thinking").String())
+			if signature := part.Get("signature").String(); signature != "" {
+				block, _ = sjson.SetBytes(block, "reasoningContent.reasoningText.signature", signature)
+			}
+			out = append(out, block)
+		case "redacted_thinking":
+			block, _ := sjson.SetBytes([]byte(`{"reasoningContent":{}}`), "reasoningContent.redactedContent", part.Get("data").String())
+			out = append(out, block)
+		}
+		if part.Get("cache_control").Exists() && len(out) > 0 {
+			out = append(out, []byte(`{"cachePoint":{"type":"default"}}`))
+		}
+	}
+	return out
+}
+
+func convertClaudeImageToConverse(part gjson.Result) []byte {
+	if part.Get("source.type").String() != "base64" {
+		return nil
+	}
+	format := strings.TrimPrefix(part.Get("source.media_type").String(), "image/")
+	if format == "jpg" {
+		format = "jpeg"
+	}
+	block := []byte(`{"image":{}}`)
+	block, _ = sjson.SetBytes(block, "image.format", format)
+	block, _ = sjson.SetBytes(block, "image.source.bytes", part.Get("source.data").String())
+	return block
+}
+
+// bedrockStopReasonToClaude maps Converse stop reasons to Anthropic stop_reason values.
+func bedrockStopReasonToClaude(reason string) string {
+	switch reason {
+	case "end_turn", "tool_use", "max_tokens", "stop_sequence", "model_context_window_exceeded":
+		return reason
+	case "guardrail_intervened", "content_filtered":
+		return "refusal"
+	default:
+		return "end_turn"
+	}
+}
+
+// bedrockUsageToClaude converts Converse token usage into an Anthropic usage object.
+func bedrockUsageToClaude(usageNode gjson.Result) []byte {
+	out := []byte(`{"input_tokens":0,"output_tokens":0}`)
+	out, _ = sjson.SetBytes(out, "input_tokens", usageNode.Get("inputTokens").Int())
+	out, _ = sjson.SetBytes(out, "output_tokens", usageNode.Get("outputTokens").Int())
+	if v := usageNode.Get("cacheReadInputTokens"); v.Exists() {
+		out, _ = sjson.SetBytes(out, "cache_read_input_tokens", v.Int())
+	}
+	if v := usageNode.Get("cacheWriteInputTokens"); v.Exists() {
+		out, _ = sjson.SetBytes(out, "cache_creation_input_tokens", v.Int())
+	}
+	return out
+}
+
+func newBedrockMessageID() string {
+	return "msg_bdrk_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+}
+
+// convertBedrockConverseResponseToClaude converts a Converse response into an
+// Anthropic Messages response.
+func convertBedrockConverseResponseToClaude(data []byte, model string) []byte {
+	root := gjson.ParseBytes(data)
+	out := []byte(`{"id":"","type":"message","role":"assistant","model":"","content":[],"stop_reason":"","stop_sequence":null,"usage":{}}`)
+	out, _ = sjson.SetBytes(out, "id", newBedrockMessageID())
+	out, _ = sjson.SetBytes(out, "model", model)
+
+	for _, block := range root.Get("output.message.content").Array() {
+		switch {
+		case block.Get("text").Exists():
+			out, _ = sjson.SetBytes(out, "content.-1", map[string]string{"type": "text", "text": block.Get("text").String()})
+		case block.Get("toolUse").Exists():
+			input := block.Get("toolUse.input").Raw
+			if input == "" {
+				input = "{}"
+			}
+			item := []byte(`{"type":"tool_use","id":"","name":"","input":{}}`)
+			item, _ = sjson.SetBytes(item, "id", block.Get("toolUse.toolUseId").String())
+			item, _ = sjson.SetBytes(item, "name", block.Get("toolUse.name").String())
+			item, _ = sjson.SetRawBytes(item, "input", []byte(input))
+			out, _ = sjson.SetRawBytes(out, "content.-1", item)
+		case block.Get("reasoningContent.reasoningText").Exists():
+			item := []byte(`{"type":"thinking","thinking":"","signature":""}`)
+			item, _ = sjson.SetBytes(item, "thinking", block.Get("reasoningContent.reasoningText.text").String())
+			item, _ = sjson.SetBytes(item, "signature", block.Get("reasoningContent.reasoningText.signature").String())
+			out, _ = sjson.SetRawBytes(out, "content.-1", item)
+		case block.Get("reasoningContent.redactedContent").Exists():
+			out, _ = sjson.SetBytes(out, "content.-1", map[string]string{"type": "redacted_thinking", "data": block.Get("reasoningContent.redactedContent").String()})
+		}
+	}
+
+	out, _ = sjson.SetBytes(out, "stop_reason", bedrockStopReasonToClaude(root.Get("stopReason").String()))
+	out, _ = sjson.SetRawBytes(out, "usage", bedrockUsageToClaude(root.Get("usage")))
+	return out
+}
+
+// bedrockConverseStreamState converts ConverseStream events into Anthropic SSE
+// events. Converse only announces tool-use blocks explicitly, so text and
+// reasoning blocks are opened lazily on their first delta.
+type bedrockConverseStreamState struct {
+	model      string
+	started    bool
+	finished   bool
+	stopReason string
+	openBlocks map[int64]string
+}
+
+func newBedrockConverseStreamState(model string) *bedrockConverseStreamState {
+	return &bedrockConverseStreamState{model: model, openBlocks: make(map[int64]string)}
+}
+
+// Convert handles one ConverseStream event and returns zero or more Anthropic
+// SSE events, each formatted as "event: ...\ndata: ...\n\n".
+func (s *bedrockConverseStreamState) Convert(eventType string, payload []byte) [][]byte {
+	if s.finished {
+		return nil
+	}
+	root := gjson.ParseBytes(payload)
+	var out [][]byte
+	switch eventType {
+	case "messageStart":
+		out = append(out, s.ensureStarted()...)
+	case "contentBlockStart":
+		out = append(out, s.ensureStarted()...)
+		index := root.Get("contentBlockIndex").Int()
+		if toolUse := root.Get("start.toolUse"); toolUse.Exists() {
+			block := []byte(`{"type":"tool_use","id":"","name":"","input":{}}`)
+			block, _ = sjson.SetBytes(block, "id", toolUse.Get("toolUseId").String())
+			block, _ = sjson.SetBytes(block, "name", toolUse.Get("name").String())
+			out = append(out, s.startBlock(index, "tool_use", block))
+		}
+	case "contentBlockDelta":
+		out = append(out, s.ensureStarted()...)
+		index := root.Get("contentBlockIndex").Int()
+		delta := root.Get("delta")
+		switch {
+		case delta.Get("text").Exists():
+			if _, ok := s.openBlocks[index]; !ok {
+				out = append(out, s.startBlock(index, "text", []byte(`{"type":"text","text":""}`)))
+			}
+			out = append(out, s.blockDelta(index, "text_delta", "text", delta.Get("text").String()))
+		case delta.Get("toolUse").Exists():
+			out = append(out, s.blockDelta(index, "input_json_delta", "partial_json", delta.Get("toolUse.input").String()))
+		case delta.Get("reasoningContent.redactedContent").Exists():
+			block, _ := sjson.SetBytes([]byte(`{"type":"redacted_thinking"}`), "data", delta.Get("reasoningContent.redactedContent").String())
+			out = append(out, s.startBlock(index, "redacted_thinking", block))
+		case delta.Get("reasoningContent").Exists():
+			if _, ok := s.openBlocks[index]; !ok {
+				out = append(out, s.startBlock(index, "thinking", []byte(`{"type":"thinking","thinking":""}`)))
+			}
+			if text := delta.Get("reasoningContent.text"); text.Exists() {
+				out = append(out, s.blockDelta(index, "thinking_delta", "thinking", text.String()))
+			}
+			if signature := delta.Get("reasoningContent.signature"); signature.Exists() {
+				out = append(out, s.blockDelta(index, "signature_delta", "signature", signature.String()))
+			}
+		}
+	case "contentBlockStop":
+		index := root.Get("contentBlockIndex").Int()
+		if _, ok := s.openBlocks[index]; ok {
+			delete(s.openBlocks, index)
+			out = append(out, s.blockStop(index))
+		}
+	case "messageStop":
+		s.stopReason = root.Get("stopReason").String()
+	case "metadata":
+		out = append(out, s.ensureStarted()...)
+		out = append(out, s.finish(bedrockUsageToClaude(root.Get("usage")))...)
+	}
+	return out
+}
+
+// Flush closes the message when the stream ended without a metadata event.
+func (s *bedrockConverseStreamState) Flush() [][]byte {
+	if s.finished || !s.started {
+		return nil
+	}
+	return s.finish([]byte(`{"input_tokens":0,"output_tokens":0}`))
+}
+
+func (s *bedrockConverseStreamState) ensureStarted() [][]byte {
+	if s.started {
+		return nil
+	}
+	s.started = true
+	message := []byte(`{"type":"message_start","message":{"id":"","type":"message","role":"assistant","model":"","content":[],"stop_reason":null,"stop_sequence":null,"usage":{"input_tokens":0,"output_tokens":0}}}`)
+	message, _ = sjson.SetBytes(message, "message.id", newBedrockMessageID())
+	message, _ = sjson.SetBytes(message, "message.model", s.model)
+	return [][]byte{formatClaudeSSEEvent("message_start", message)}
+}
+
+func (s *bedrockConverseStreamState) startBlock(index int64, blockType string, block []byte) []byte {
+	s.openBlocks[index] = blockType
+	event := []byte(`{"type":"content_block_start","index":0,"content_block":{}}`)
+	event, _ = sjson.SetBytes(event, "index", index)
+	event, _ = sjson.SetRawBytes(event, "content_block", block)
+	return formatClaudeSSEEvent("content_block_start", event)
+}
+
+func (s *bedrockConverseStreamState) blockDelta(index int64, deltaType, field, value string) []byte {
+	event := []byte(`{"type":"content_block_delta","index":0,"delta":{}}`)
+	event, _ = sjson.SetBytes(event, "index", index)
+	event, _ = sjson.SetBytes(event, "delta.type", deltaType)
+	event, _ = sjson.SetBytes(event, "delta."+field, value)
+	return formatClaudeSSEEvent("content_block_delta", event)
+}
+
+func (s *bedrockConverseStreamState) blockStop(index int64) []byte {
+	event, _ := sjson.SetBytes([]byte(`{"type":"content_block_stop","index":0}`), "index", index)
+	return formatClaudeSSEEvent("content_block_stop", event)
+}
+
+func (s *bedrockConverseStreamState) finish(usageJSON []byte) [][]byte {
+	var out [][]byte
+	indexes := make([]int64, 0, len(s.openBlocks))
+	for index := range s.openBlocks {
+		indexes = append(indexes, index)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+	for _, index := range indexes {
+		out = append(out, s.blockStop(index))
+	}
+	s.openBlocks = make(map[int64]string)
+	delta := []byte(`{"type":"message_delta","delta":{"stop_reason":"","stop_sequence":null},"usage":{}}`)
+	delta, _ = sjson.SetBytes(delta, "delta.stop_reason", bedrockStopReasonToClaude(s.stopReason))
+	delta, _ = sjson.SetRawBytes(delta, "usage", usageJSON)
+	out = append(out, formatClaudeSSEEvent("message_delta", delta))
+	out = append(out, formatClaudeSSEEvent("message_stop", []byte(`{"type":"message_stop"}`)))
+	s.finished = true
+	return out
+}
+
+// convertBedrockInvokeChunkToClaude unwraps an InvokeModelWithResponseStream
+// chunk, whose payload carries a base64-encoded Anthropic stream event.
+func convertBedrockInvokeChunkToClaude(payload []byte) ([]byte, error) {
+	encoded := gjson.GetBytes(payload, "bytes").String()
+	if encoded == "" {
+		return nil, nil
+	}
+	event, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("bedrock executor: decode invoke chunk: %w", err)
+	}
+	eventType := gjson.GetBytes(event, "type").String()
+	if eventType == "" {
+		return nil, nil
+	}
+	return formatClaudeSSEEvent(eventType, event), nil
+}
+
+func formatClaudeSSEEvent(eventType string, data []byte) []byte {
+	out := make([]byte, 0, len(eventType)+len(data)+16)
+	out = append(out, "event: "...)
+	out = append(out, eventType...)
+	out = append(out, "\ndata: "...)
+	out = append(out, data...)
+	out = append(out, "\n\n"...)
+	return out
+}
@@ -0,0 +1,118 @@
+package executor
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// bedrockEventMessage is a single frame of the AWS event-stream encoding used by
+// ConverseStream and InvokeModelWithResponseStream.
+type bedrockEventMessage struct {
+	Headers map[string]string
+	Payload []byte
+}
+
+// bedrockMaxEventMessageSize bounds a single frame to guard against corrupt length prefixes.
+const bedrockMaxEventMessageSize = 16 << 20
+
+// bedrockEventStreamDecoder reads length-prefixed event-stream frames:
+// total length, headers length and prelude CRC (4 bytes each), then headers,
+// payload and a trailing CRC32 of the whole message.
+type bedrockEventStreamDecoder struct {
+	r io.Reader
+}
+
+func newBedrockEventStreamDecoder(r io.Reader) *bedrockEventStreamDecoder {
+	return &bedrockEventStreamDecoder{r: r}
+}
+
+// Next returns the next frame, or io.EOF once the stream ends on a frame boundary.
+func (d *bedrockEventStreamDecoder) Next() (*bedrockEventMessage, error) {
+	prelude := make([]byte, 12)
+	if _, err := io.ReadFull(d.r, prelude); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("bedrock event stream: truncated prelude")
+		}
+		return nil, err
+	}
+	totalLen := binary.BigEndian.Uint32(prelude[0:4])
+	headersLen := binary.BigEndian.Uint32(prelude[4:8])
+	if crc32.ChecksumIEEE(prelude[:8]) != binary.BigEndian.Uint32(prelude[8:12]) {
+		return nil, fmt.Errorf("bedrock event stream: prelude checksum mismatch")
+	}
+	if totalLen < 16 || totalLen > bedrockMaxEventMessageSize || headersLen > totalLen-16 {
+		return nil, fmt.Errorf("bedrock event stream: invalid frame length %d", totalLen)
+	}
+
+	message := make([]byte, totalLen)
+	copy(message, prelude)
+	if _, err := io.ReadFull(d.r, message[12:]); err != nil {
+		return nil, fmt.Errorf("bedrock event stream: truncated frame: %w", err)
+	}
+	if crc32.ChecksumIEEE(message[:totalLen-4]) != binary.BigEndian.Uint32(message[totalLen-4:]) {
+		return nil, fmt.Errorf("bedrock event stream: message checksum mismatch")
+	}
+
+	headers, err := parseBedrockEventHeaders(message[12 : 12+headersLen])
+	if err != nil {
+		return nil, err
+	}
+	return &bedrockEventMessage{
+		Headers: headers,
+		Payload: message[12+headersLen : totalLen-4],
+	}, nil
+}
+
+// parseBedrockEventHeaders decodes the header block. Only string values are
+// retained; other value types are skipped since Bedrock does not use them.
+func parseBedrockEventHeaders(data []byte) (map[string]string, error) {
+	headers := make(map[string]string)
+	for len(data) > 0 {
+		nameLen := int(data[0])
+		if len(data) < 1+nameLen+1 {
+			return nil, fmt.Errorf("bedrock event stream: malformed header")
+		}
+		name := string(data[1 : 1+nameLen])
+		valueType := data[1+nameLen]
+		data = data[2+nameLen:]
+
+		var size int
+		switch valueType {
+		case 0, 1: // bool true / false
+			size = 0
+		case 2: // byte
+			size = 1
+		case 3: // int16
+			size = 2
+		case 4: // int32
+			size = 4
+		case 5, 8: // int64, timestamp
+			size = 8
+		case 9: // uuid
+			size = 16
+		case 6, 7: // byte array, string
+			if len(data) < 2 {
+				return nil, fmt.Errorf("bedrock event stream: malformed header %q", name)
+			}
+			valueLen := int(binary.BigEndian.Uint16(data[:2]))
+			if len(data) < 2+valueLen {
+				return nil, fmt.Errorf("bedrock event stream: malformed header %q", name)
+			}
+			if valueType == 7 {
+				headers[name] = string(data[2 : 2+valueLen])
+			}
+			data = data[2+valueLen:]
+			continue
+		default:
+			return nil, fmt.Errorf("bedrock event stream: unknown header type %d", valueType)
+		}
+		if len(data) < size {
+			return nil, fmt.Errorf("bedrock event stream: malformed header %q", name)
+		}
+		data = data[size:]
+	}
+	return headers, nil
+}
@@ -0,0 +1,575 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/registry"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/runtime/executor/helps"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/thinking"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/util"
+	cliproxyauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
+	cliproxyexecutor "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/executor"
+	"github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/usage"
+	sdktranslator "github.com/router-for-me/CLIProxyAPI/v7/sdk/translator"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// bedrockAnthropicVersion is the anthropic_version required by invoke-model for Claude models.
+const bedrockAnthropicVersion = "bedrock-2023-05-31"
+
+// BedrockExecutor calls AWS Bedrock runtime with SigV4-signed requests. Requests
+// are first translated to the Anthropic Messages format so the Claude thinking
+// applier and usage parsing are reused, then sent either through Converse /
+// ConverseStream or the Anthropic-native invoke-model endpoints.
+type BedrockExecutor struct {
+	cfg *config.Config
+}
+
+func NewBedrockExecutor(cfg *config.Config) *BedrockExecutor { return &BedrockExecutor{cfg: cfg} }
+
+func (e *BedrockExecutor) Identifier() string { return "bedrock" }
+
+// bedrockPreparedRequest carries a translated request ready to be signed and sent.
+type bedrockPreparedRequest struct {
+	baseModel          string
+	api                string
+	bodyForTranslation []byte
+	payload            []byte
+}
+
+// PrepareRequest applies custom headers and signs the outgoing HTTP request.
+func (e *BedrockExecutor) PrepareRequest(req *http.Request, auth *cliproxyauth.Auth) error {
+	if req == nil {
+		return nil
+	}
+	creds, _, _ := bedrockCreds(auth)
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil
+	}
+	var attrs map[string]string
+	if auth != nil {
+		attrs = auth.Attributes
+	}
+	util.ApplyCustomHeadersFromAttrs(req, attrs)
+	var body []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		_ = req.Body.Close()
+		body = data
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	signBedrockRequest(req, body, creds, bedrockSigningService, time.Now())
+	return nil
+}
+
+// HttpRequest signs the request with the Bedrock credentials and executes it.
+func (e *BedrockExecutor) HttpRequest(ctx context.Context, auth *cliproxyauth.Auth, req *http.Request) (*http.Response, error) {
+	if req == nil {
+		return nil, fmt.Errorf("bedrock executor: request is nil")
+	}
+	if ctx == nil {
+		ctx = req.Context()
+	}
+	httpReq := req.WithContext(ctx)
+	if err := e.PrepareRequest(httpReq, auth); err != nil {
+		return nil, err
+	}
+	httpClient := helps.NewProxyAwareHTTPClient(ctx, e.cfg, auth, 0)
+	return httpClient.Do(httpReq)
+}
+
+func (e *BedrockExecutor) Execute(ctx context.Context, auth *cliproxyauth.Auth, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (resp cliproxyexecutor.Response, err error) {
+	if opts.Alt == "responses/compact" {
+		return resp, statusErr{code: http.StatusNotImplemented, msg: "/responses/compact not supported"}
+	}
+	baseModel := thinking.ParseSuffix(req.Model).ModelName
+	reporter := helps.NewExecutorUsageReporter(ctx, e, baseModel, auth)
+	defer reporter.TrackFailure(ctx, &err)
+
+	to := sdktranslator.FromString("claude")
+	responseFormat := cliproxyexecutor.ResponseFormatOrSource(opts)
+	// Claude-to-X non-stream translators consume SSE, so only Claude clients use the unary endpoints.
+	stream := responseFormat != to
+	prepared, err := e.prepareRequest(ctx, auth, req, opts, stream)
+	if err != nil {
+		return resp, err
+	}
+	reporter.SetTranslatedReasoningEffort(prepared.bodyForTranslation, to.String())
+
+	httpResp, err := e.send(ctx, auth, prepared, bedrockAction(prepared.api, stream), stream, reporter)
+	if err != nil {
+		return resp, err
+	}
+	defer func() {
+		if errClose := httpResp.Body.Close(); errClose != nil {
+			log.Errorf("response body close error: %v", errClose)
+		}
+	}()
+
+	var data []byte
+	if stream {
+		var buf bytes.Buffer
+		var acc bedrockUsageAccumulator
+		err = readBedrockEventStream(httpResp.Body, prepared.api, baseModel, func(event []byte) bool {
+			helps.AppendAPIResponseChunk(ctx, e.cfg, event)
+			acc.Observe(event)
+			buf.Write(event)
+			return true
+		})
+		if err != nil {
+			helps.RecordAPIResponseError(ctx, e.cfg, err)
+			return resp, err
+		}
+		if detail, ok := acc.Detail(); ok {
+			reporter.Publish(ctx, detail)
+		}
+		data = buf.Bytes()
+	} else {
+		data, err = io.ReadAll(httpResp.Body)
+		if err != nil {
+			helps.RecordAPIResponseError(ctx, e.cfg, err)
+			return resp, err
+		}
+		helps.AppendAPIResponseChunk(ctx, e.cfg, data)
+		if prepared.api == config.BedrockAPIConverse {
+			data = convertBedrockConverseResponseToClaude(data, baseModel)
+		}
+		reporter.Publish(ctx, helps.ParseClaudeUsage(data))
+	}
+
+	var param any
+	out := sdktranslator.TranslateNonStream(ctx, to, responseFormat, req.Model, opts.OriginalRequest, prepared.bodyForTranslation, data, &param)
+	resp = cliproxyexecutor.Response{Payload: out, Headers: httpResp.Header.Clone()}
+	return resp, nil
+}
+
+func (e *BedrockExecutor) ExecuteStream(ctx context.Context, auth *cliproxyauth.Auth, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (_ *cliproxyexecutor.StreamResult, err error) {
+	if opts.Alt == "responses/compact" {
+		return nil, statusErr{code: http.StatusNotImplemented, msg: "/responses/compact not supported"}
+	}
+	baseModel := thinking.ParseSuffix(req.Model).ModelName
+	reporter := helps.NewExecutorUsageReporter(ctx, e, baseModel, auth)
+	defer reporter.TrackFailure(ctx, &err)
+
+	to := sdktranslator.FromString("claude")
+	responseFormat := cliproxyexecutor.ResponseFormatOrSource(opts)
+	prepared, err := e.prepareRequest(ctx, auth, req, opts, true)
+	if err != nil {
+		return nil, err
+	}
+	reporter.SetTranslatedReasoningEffort(prepared.bodyForTranslation, to.String())
+
+	httpResp, err := e.send(ctx, auth, prepared, bedrockAction(prepared.api, true), true, reporter)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan cliproxyexecutor.StreamChunk)
+	go func() {
+		defer close(out)
+		defer func() {
+			if errClose := httpResp.Body.Close(); errClose != nil {
+				log.Errorf("response body close error: %v", errClose)
+			}
+		}()
+
+		var acc bedrockUsageAccumulator
+		var param any
+		errStream := readBedrockEventStream(httpResp.Body, prepared.api, baseModel, func(event []byte) bool {
+			helps.AppendAPIResponseChunk(ctx, e.cfg, event)
+			acc.Observe(event)
+			if responseFormat == to {
+				select {
+				case out <- cliproxyexecutor.StreamChunk{Payload: bytes.Clone(event)}:
+					return true
+				case <-ctx.Done():
+					return false
+				}
+			}
+			// Feed the translator line by line, matching the Claude SSE scanner.
+			for _, line := range bytes.Split(bytes.TrimRight(event, "\n"), []byte("\n")) {
+				chunks := sdktranslator.TranslateStream(ctx, to, responseFormat, req.Model, opts.OriginalRequest, prepared.bodyForTranslation, bytes.Clone(line), &param)
+				for i := range chunks {
+					select {
+					case out <- cliproxyexecutor.StreamChunk{Payload: chunks[i]}:
+					case <-ctx.Done():
+						return false
+					}
+				}
+			}
+			return true
+		})
+		if detail, ok := acc.Detail(); ok {
+			reporter.Publish(ctx, detail)
+		}
+		if errStream != nil {
+			helps.RecordAPIResponseError(ctx, e.cfg, errStream)
+			reporter.PublishFailure(ctx, errStream)
+			select {
+			case out <- cliproxyexecutor.StreamChunk{Err: errStream}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return &cliproxyexecutor.StreamResult{Headers: httpResp.Header.Clone(), Chunks: out}, nil
+}
+
+// CountTokens uses the Bedrock CountTokens API with the same request shape as generation.
+func (e *BedrockExecutor) CountTokens(ctx context.Context, auth *cliproxyauth.Auth, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (cliproxyexecutor.Response, error) {
+	to := sdktranslator.FromString("claude")
+	responseFormat := cliproxyexecutor.ResponseFormatOrSource(opts)
+	prepared, err := e.prepareRequest(ctx, auth, req, opts, false)
+	if err != nil {
+		return cliproxyexecutor.Response{}, err
+	}
+
+	input := []byte(`{"input":{}}`)
+	if prepared.api == config.BedrockAPIInvoke {
+		input, _ = sjson.SetBytes(input, "input.invokeModel.body", base64.StdEncoding.EncodeToString(prepared.payload))
+	} else {
+		converse := []byte(`{}`)
+		converse, _ = sjson.SetRawBytes(converse, "messages", []byte(gjson.GetBytes(prepared.payload, "messages").Raw))
+		if system := gjson.GetBytes(prepared.payload, "system"); system.Exists() {
+			converse, _ = sjson.SetRawBytes(converse, "system", []byte(system.Raw))
+		}
+		input, _ = sjson.SetRawBytes(input, "input.converse", converse)
+	}
+	prepared.payload = input
+
+	httpResp, err := e.send(ctx, auth, prepared, "count-tokens", false, nil)
+	if err != nil {
+		return cliproxyexecutor.Response{}, err
+	}
+	defer func() {
+		if errClose := httpResp.Body.Close(); errClose != nil {
+			log.Errorf("response body close error: %v", errClose)
+		}
+	}()
+	data, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		helps.RecordAPIResponseError(ctx, e.cfg, err)
+		return cliproxyexecutor.Response{}, err
+	}
+	helps.AppendAPIResponseChunk(ctx, e.cfg, data)
+	count := gjson.GetBytes(data, "inputTokens").Int()
+	claudeCount, _ := sjson.SetBytes([]byte(`{}`), "input_tokens", count)
+	out := sdktranslator.TranslateTokenCount(ctx, to, responseFormat, count, claudeCount)
+	return cliproxyexecutor.Response{Payload: out, Headers: httpResp.Header.Clone()}, nil
+}
+
+// Refresh is a no-op; Bedrock credentials are static access keys from config.
+func (e *BedrockExecutor) Refresh(_ context.Context, auth *cliproxyauth.Auth) (*cliproxyauth.Auth, error) {
+	log.Debugf("bedrock executor: refresh called")
+	return auth, nil
+}
+
+// prepareRequest translates the client payload to Anthropic Messages, applies
+// thinking and payload rules, and builds the upstream body for the configured API.
+func (e *BedrockExecutor) prepareRequest(ctx context.Context, auth *cliproxyauth.Auth, req cliproxyexecutor.Request, opts cliproxyexecutor.Options, stream bool) (*bedrockPreparedRequest, error) {
+	baseModel := thinking.ParseSuffix(req.Model).ModelName
+	from := opts.SourceFormat
+	to := sdktranslator.FromString("claude")
+
+	originalPayload := req.Payload
+	if len(opts.OriginalRequest) > 0 {
+		originalPayload = opts.OriginalRequest
+	}
+	originalTranslated := sdktranslator.TranslateRequestContext(ctx, from, to, baseModel, originalPayload, stream)
+	body := sdktranslator.TranslateRequestContext(ctx, from, to, baseModel, req.Payload, stream)
+	body, _ = sjson.SetBytes(body, "model", baseModel)
+
+	body, err := thinking.ApplyThinking(body, req.Model, from.String(), to.String(), e.Identifier())
+	if err != nil {
+		return nil, err
+	}
+
+	requestedModel := helps.PayloadRequestedModel(opts, req.Model)
+	requestPath := helps.PayloadRequestPath(opts)
+	body = helps.ApplyPayloadConfigWithRequest(e.cfg, baseModel, to.String(), from.String(), "", body, originalTranslated, requestedModel, requestPath, opts.Headers)
+	body = ensureBedrockMaxTokens(body, baseModel)
+	body = disableThinkingIfToolChoiceForced(body)
+	body = normalizeClaudeTemperatureForThinking(body)
+
+	var betas []string
+	betas, body = extractAndRemoveBetas(body)
+
+	_, _, api := bedrockCreds(auth)
+	prepared := &bedrockPreparedRequest{baseModel: baseModel, api: api, bodyForTranslation: body}
+	if api == config.BedrockAPIInvoke {
+		payload, _ := sjson.DeleteBytes(body, "model")
+		payload, _ = sjson.DeleteBytes(payload, "stream")
+		payload, _ = sjson.SetBytes(payload, "anthropic_version", bedrockAnthropicVersion)
+		if len(betas) > 0 {
+			payload, _ = sjson.SetBytes(payload, "anthropic_beta", betas)
+		}
+		prepared.payload = payload
+	} else {
+		prepared.payload = convertClaudeRequestToBedrockConverse(body, betas)
+	}
+	return prepared, nil
+}
+
+// send signs and executes a Bedrock runtime call. Non-2xx responses are
+// returned as statusErr with the upstream body.
+func (e *BedrockExecutor) send(ctx context.Context, auth *cliproxyauth.Auth, prepared *bedrockPreparedRequest, action string, stream bool, reporter *helps.UsageReporter) (*http.Response, error) {
+	creds, baseURL, _ := bedrockCreds(auth)
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil, statusErr{code: http.StatusUnauthorized, msg: "bedrock executor: missing access key credentials"}
+	}
+	url := strings.TrimRight(baseURL, "/") + "/model/" + awsURIEscape(prepared.baseModel) + "/" + action
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(prepared.payload))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if stream {
+		httpReq.Header.Set("Accept", "application/vnd.amazon.eventstream")
+		httpReq.Header.Set("X-Amzn-Bedrock-Accept", "application/json")
+	} else {
+		httpReq.Header.Set("Accept", "application/json")
+	}
+	if auth != nil {
+		util.ApplyCustomHeadersFromAttrs(httpReq, auth.Attributes)
+	}
+	signBedrockRequest(httpReq, prepared.payload, creds, bedrockSigningService, time.Now())
+
+	var authID, authLabel, authType, authValue string
+	if auth != nil {
+		authID = auth.ID
+		authLabel = auth.Label
+		authType, authValue = auth.AccountInfo()
+	}
+	helps.RecordAPIRequest(ctx, e.cfg, helps.UpstreamRequestLog{
+		URL:       url,
+		Method:    http.MethodPost,
+		Headers:   httpReq.Header.Clone(),
+		Body:      prepared.payload,
+		Provider:  e.Identifier(),
+		AuthID:    authID,
+		AuthLabel: authLabel,
+		AuthType:  authType,
+		AuthValue: authValue,
+	})
+
+	httpClient := helps.NewProxyAwareHTTPClient(ctx, e.cfg, auth, 0)
+	if reporter != nil {
+		httpClient = reporter.TrackHTTPClient(httpClient)
+	}
+	httpResp, err := httpClient.Do(httpReq)
+	if err != nil {
+		helps.RecordAPIResponseError(ctx, e.cfg, err)
+		return nil, err
+	}
+	helps.RecordAPIResponseMetadata(ctx, e.cfg, httpResp.StatusCode, httpResp.Header.Clone())
+	if httpResp.StatusCode >= 200 && httpResp.StatusCode < 300 {
+		return httpResp, nil
+	}
+	b, readErr := io.ReadAll(httpResp.Body)
+	if readErr != nil {
+		helps.RecordAPIResponseError(ctx, e.cfg, readErr)
+		msg := fmt.Sprintf("failed to read error response body: %v", readErr)
+		helps.LogWithRequestID(ctx).Warn(msg)
+		b = []byte(msg)
+	}
+	if errClose := httpResp.Body.Close(); errClose != nil {
+		log.Errorf("response body close error: %v", errClose)
+	}
+	helps.AppendAPIResponseChunk(ctx, e.cfg, b)
+	helps.LogWithRequestID(ctx).Debugf("request error, error status: %d, error message: %s", httpResp.StatusCode, helps.SummarizeErrorBody(httpResp.Header.Get("Content-Type"), b))
+	return nil, statusErr{code: httpResp.StatusCode, msg: string(b)}
+}
+
+func bedrockAction(api string, stream bool) string {
+	switch {
+	case api == config.BedrockAPIInvoke && stream:
+		return "invoke-with-response-stream"
+	case api == config.BedrockAPIInvoke:
+		return "invoke"
+	case stream:
+		return "converse-stream"
+	default:
+		return "converse"
+	}
+}
+
+// readBedrockEventStream decodes an event-stream response body and invokes emit
+// with each resulting Anthropic SSE event. Returning false from emit stops reading.
+func readBedrockEventStream(body io.Reader, api, model string, emit func(event []byte) bool) error {
+	decoder := newBedrockEventStreamDecoder(bufio.NewReader(body))
+	converse := newBedrockConverseStreamState(model)
+	for {
+		message, err := decoder.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if errStream := bedrockEventStreamError(message); errStream != nil {
+			return errStream
+		}
+		eventType := message.Headers[":event-type"]
+		var events [][]byte
+		if api == config.BedrockAPIInvoke {
+			if eventType != "chunk" {
+				continue
+			}
+			event, errChunk := convertBedrockInvokeChunkToClaude(message.Payload)
+			if errChunk != nil {
+				return errChunk
+			}
+			if event != nil {
+				events = append(events, event)
+			}
+		} else {
+			events = converse.Convert(eventType, message.Payload)
+		}
+		for _, event := range events {
+			if !emit(event) {
+				return nil
+			}
+		}
+	}
+	for _, event := range converse.Flush() {
+		if !emit(event) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// bedrockEventStreamError converts an in-stream exception frame into a statusErr.
+func bedrockEventStreamError(message *bedrockEventMessage) error {
+	messageType := message.Headers[":message-type"]
+	if messageType != "exception" && messageType != "error" {
+		return nil
+	}
+	kind := message.Headers[":exception-type"]
+	if kind == "" {
+		kind = message.Headers[":error-code"]
+	}
+	msg := gjson.GetBytes(message.Payload, "message").String()
+	if msg == "" {
+		msg = message.Headers[":error-message"]
+	}
+	if msg == "" {
+		msg = string(message.Payload)
+	}
+	code := http.StatusInternalServerError
+	switch kind {
+	case "throttlingException":
+		code = http.StatusTooManyRequests
+	case "validationException":
+		code = http.StatusBadRequest
+	case "accessDeniedException":
+		code = http.StatusForbidden
+	case "resourceNotFoundException":
+		code = http.StatusNotFound
+	case "modelTimeoutException":
+		code = http.StatusRequestTimeout
+	case "serviceUnavailableException", "modelNotReadyException":
+		code = http.StatusServiceUnavailable
+	}
+	return statusErr{code: code, msg: fmt.Sprintf("bedrock %s: %s", kind, msg)}
+}
+
+// bedrockUsageAccumulator merges usage seen across Anthropic stream events.
+// message_start carries input tokens and message_delta output tokens, so the
+// detail is published once at the end of the stream instead of per line.
+type bedrockUsageAccumulator struct {
+	detail usage.Detail
+	seen   bool
+}
+
+func (a *bedrockUsageAccumulator) Observe(event []byte) {
+	for _, line := range bytes.Split(event, []byte("\n")) {
+		detail, ok := helps.ParseClaudeStreamUsage(line)
+		if !ok {
+			continue
+		}
+		a.seen = true
+		a.detail.InputTokens = max(a.detail.InputTokens, detail.InputTokens)
+		a.detail.OutputTokens = max(a.detail.OutputTokens, detail.OutputTokens)
+		a.detail.CachedTokens = max(a.detail.CachedTokens, detail.CachedTokens)
+		a.detail.CacheReadTokens = max(a.detail.CacheReadTokens, detail.CacheReadTokens)
+		a.detail.CacheCreationTokens = max(a.detail.CacheCreationTokens, detail.CacheCreationTokens)
+	}
+}
+
+func (a *bedrockUsageAccumulator) Detail() (usage.Detail, bool) {
+	if !a.seen {
+		return usage.Detail{}, false
+	}
+	detail := a.detail
+	detail.TotalTokens = detail.InputTokens + detail.OutputTokens + detail.CacheReadTokens + detail.CacheCreationTokens
+	return detail, true
+}
+
+// ensureBedrockMaxTokens fills max_tokens, which invoke-model requires for Claude,
+// using the catalog entry of the wrapped Anthropic model when available.
+func ensureBedrockMaxTokens(body []byte, modelID string) []byte {
+	if gjson.GetBytes(body, "max_tokens").Exists() {
+		return body
+	}
+	maxTokens := defaultModelMaxTokens
+	if info := registry.LookupStaticModelInfo(BedrockCatalogModelID(modelID)); info != nil && info.MaxCompletionTokens > 0 {
+		maxTokens = info.MaxCompletionTokens
+	}
+	body, _ = sjson.SetBytes(body, "max_tokens", maxTokens)
+	return body
+}
+
+var bedrockModelVersionSuffix = regexp.MustCompile(`-v\d+(:\d+)?$`)
+
+// BedrockCatalogModelID maps a Bedrock model or inference profile ID to the
+// provider's own model name, e.g. "us.anthropic.claude-sonnet-4-20250514-v1:0"
+// becomes "claude-sonnet-4-20250514".
+func BedrockCatalogModelID(modelID string) string {
+	id := strings.TrimSpace(modelID)
+	if strings.HasPrefix(id, "arn:") {
+		id = id[strings.LastIndex(id, "/")+1:]
+	}
+	if idx := strings.LastIndex(id, "."); idx >= 0 {
+		id = id[idx+1:]
+	}
+	return bedrockModelVersionSuffix.ReplaceAllString(id, "")
+}
+
+// bedrockCreds extracts SigV4 credentials, the runtime base URL and the API
+// selection from auth attributes.
+func bedrockCreds(a *cliproxyauth.Auth) (creds bedrockCredentials, baseURL, api string) {
+	creds.Region = config.DefaultBedrockRegion
+	api = config.BedrockAPIConverse
+	if a != nil && a.Attributes != nil {
+		creds.AccessKeyID = strings.TrimSpace(a.Attributes["api_key"])
+		creds.SecretAccessKey = strings.TrimSpace(a.Attributes["secret_access_key"])
+		creds.SessionToken = strings.TrimSpace(a.Attributes["session_token"])
+		if region := strings.TrimSpace(a.Attributes["region"]); region != "" {
+			creds.Region = region
+		}
+		baseURL = strings.TrimSpace(a.Attributes["base_url"])
+		if strings.EqualFold(strings.TrimSpace(a.Attributes["api"]), config.BedrockAPIInvoke) {
+			api = config.BedrockAPIInvoke
+		}
+	}
+	if baseURL == "" {
+		baseURL = "https://bedrock-runtime." + creds.Region + ".amazonaws.com"
+	}
+	return creds, baseURL, api
+}
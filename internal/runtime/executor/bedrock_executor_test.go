@@ -0,0 +1,273 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	cliproxyauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
+	cliproxyexecutor "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/executor"
+	sdktranslator "github.com/router-for-me/CLIProxyAPI/v7/sdk/translator"
+	"github.com/tidwall/gjson"
+)
+
+const bedrockTestModel = "us.anthropic.claude-sonnet-4-20250514-v1:0"
+
+// encodeBedrockEvent builds one event-stream frame with string headers.
+func encodeBedrockEvent(headers map[string]string, payload []byte) []byte {
+	var hdr bytes.Buffer
+	for _, name := range []string{":message-type", ":event-type", ":exception-type", ":content-type"} {
+		value, ok := headers[name]
+		if !ok {
+			continue
+		}
+		hdr.WriteByte(byte(len(name)))
+		hdr.WriteString(name)
+		hdr.WriteByte(7)
+		_ = binary.Write(&hdr, binary.BigEndian, uint16(len(value)))
+		hdr.WriteString(value)
+	}
+	total := 12 + hdr.Len() + len(payload) + 4
+	frame := make([]byte, 0, total)
+	frame = binary.BigEndian.AppendUint32(frame, uint32(total))
+	frame = binary.BigEndian.AppendUint32(frame, uint32(hdr.Len()))
+	frame = binary.BigEndian.AppendUint32(frame, crc32.ChecksumIEEE(frame[:8]))
+	frame = append(frame, hdr.Bytes()...)
+	frame = append(frame, payload...)
+	return binary.BigEndian.AppendUint32(frame, crc32.ChecksumIEEE(frame))
+}
+
+func bedrockEvent(eventType, payload string) []byte {
+	return encodeBedrockEvent(map[string]string{":message-type": "event", ":event-type": eventType, ":content-type": "application/json"}, []byte(payload))
+}
+
+func newBedrockTestAuth(baseURL, api string) *cliproxyauth.Auth {
+	return &cliproxyauth.Auth{ID: "bedrock-test", Provider: "bedrock", Attributes: map[string]string{
+		"api_key":           "AKIDEXAMPLE",
+		"secret_access_key": "secret",
+		"region":            "us-west-2",
+		"api":               api,
+		"base_url":          baseURL,
+	}}
+}
+
+func TestSignBedrockRequestMatchesAWSExample(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	creds := bedrockCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", Region: "us-east-1"}
+	signBedrockRequest(req, nil, creds, "iam", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, SignedHeaders=content-type;host;x-amz-date, Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Fatalf("Authorization = %q, want %q", got, want)
+	}
+}
+
+func TestBedrockCanonicalURIDoubleEncodesModelID(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "https://bedrock-runtime.us-east-1.amazonaws.com/model/"+awsURIEscape(bedrockTestModel)+"/converse", nil)
+	if got := req.URL.EscapedPath(); got != "/model/us.anthropic.claude-sonnet-4-20250514-v1%3A0/converse" {
+		t.Fatalf("request path = %q", got)
+	}
+	if got := bedrockCanonicalURI(req.URL); got != "/model/us.anthropic.claude-sonnet-4-20250514-v1%253A0/converse" {
+		t.Fatalf("canonical URI = %q", got)
+	}
+}
+
+func TestBedrockExecutorConverseNonStream(t *testing.T) {
+	var gotPath, gotAuth string
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		gotAuth = r.Header.Get("Authorization")
+		gotBody, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"output":{"message":{"role":"assistant","content":[{"reasoningContent":{"reasoningText":{"text":"hmm","signature":"sig"}}},{"text":"hello"},{"toolUse":{"toolUseId":"tu_1","name":"lookup","input":{"q":"x"}}}]}},"stopReason":"tool_use","usage":{"inputTokens":12,"outputTokens":7,"totalTokens":19,"cacheReadInputTokens":3}}`))
+	}))
+	defer server.Close()
+
+	executor := NewBedrockExecutor(&config.Config{})
+	payload := []byte(`{"model":"claude-sonnet-4","max_tokens":256,"system":"be brief","messages":[{"role":"user","content":"hi"}],"tools":[{"name":"lookup","input_schema":{"type":"object"}}],"tool_choice":{"type":"auto"}}`)
+	resp, err := executor.Execute(context.Background(), newBedrockTestAuth(server.URL, "converse"), cliproxyexecutor.Request{Model: bedrockTestModel, Payload: payload}, cliproxyexecutor.Options{
+		SourceFormat: sdktranslator.FromString("claude"),
+	})
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+
+	if gotPath != "/model/us.anthropic.claude-sonnet-4-20250514-v1%3A0/converse" {
+		t.Fatalf("upstream path = %q", gotPath)
+	}
+	if !strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") || !strings.Contains(gotAuth, "/us-west-2/bedrock/aws4_request") {
+		t.Fatalf("unexpected Authorization header: %q", gotAuth)
+	}
+	body := gjson.ParseBytes(gotBody)
+	if body.Get("system.0.text").String() != "be brief" || body.Get("messages.0.content.0.text").String() != "hi" {
+		t.Fatalf("unexpected converse body: %s", gotBody)
+	}
+	if body.Get("inferenceConfig.maxTokens").Int() != 256 || body.Get("toolConfig.tools.0.toolSpec.name").String() != "lookup" || !body.Get("toolConfig.toolChoice.auto").Exists() {
+		t.Fatalf("unexpected converse body: %s", gotBody)
+	}
+
+	out := gjson.ParseBytes(resp.Payload)
+	if out.Get("content.0.type").String() != "thinking" || out.Get("content.1.text").String() != "hello" || out.Get("content.2.input.q").String() != "x" {
+		t.Fatalf("unexpected claude response: %s", resp.Payload)
+	}
+	if out.Get("stop_reason").String() != "tool_use" || out.Get("usage.input_tokens").Int() != 12 || out.Get("usage.cache_read_input_tokens").Int() != 3 {
+		t.Fatalf("unexpected claude response: %s", resp.Payload)
+	}
+}
+
+func TestBedrockExecutorConverseStream(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		w.Header().Set("Content-Type", "application/vnd.amazon.eventstream")
+		var body bytes.Buffer
+		body.Write(bedrockEvent("messageStart", `{"role":"assistant"}`))
+		body.Write(bedrockEvent("contentBlockDelta", `{"contentBlockIndex":0,"delta":{"text":"Hel"}}`))
+		body.Write(bedrockEvent("contentBlockDelta", `{"contentBlockIndex":0,"delta":{"text":"lo"}}`))
+		body.Write(bedrockEvent("contentBlockStop", `{"contentBlockIndex":0}`))
+		body.Write(bedrockEvent("messageStop", `{"stopReason":"end_turn"}`))
+		body.Write(bedrockEvent("metadata", `{"usage":{"inputTokens":5,"outputTokens":2},"metrics":{"latencyMs":10}}`))
+		_, _ = w.Write(body.Bytes())
+	}))
+	defer server.Close()
+
+	executor := NewBedrockExecutor(&config.Config{})
+	payload := []byte(`{"model":"claude-sonnet-4","max_tokens":64,"stream":true,"messages":[{"role":"user","content":"hi"}]}`)
+	result, err := executor.ExecuteStream(context.Background(), newBedrockTestAuth(server.URL, "converse"), cliproxyexecutor.Request{Model: bedrockTestModel, Payload: payload}, cliproxyexecutor.Options{
+		SourceFormat: sdktranslator.FromString("claude"),
+		Stream:       true,
+	})
+	if err != nil {
+		t.Fatalf("ExecuteStream error: %v", err)
+	}
+	var stream bytes.Buffer
+	for chunk := range result.Chunks {
+		if chunk.Err != nil {
+			t.Fatalf("stream error: %v", chunk.Err)
+		}
+		stream.Write(chunk.Payload)
+	}
+
+	if !strings.HasSuffix(gotPath, "/converse-stream") {
+		t.Fatalf("upstream path = %q", gotPath)
+	}
+	var events []string
+	var text string
+	var usage gjson.Result
+	for _, line := range strings.Split(stream.String(), "\n") {
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		data := gjson.Parse(strings.TrimPrefix(line, "data: "))
+		events = append(events, data.Get("type").String())
+		text += data.Get("delta.text").String()
+		if data.Get("type").String() == "message_delta" {
+			usage = data.Get("usage")
+		}
+	}
+	want := "message_start,content_block_start,content_block_delta,content_block_delta,content_block_stop,message_delta,message_stop"
+	if got := strings.Join(events, ","); got != want {
+		t.Fatalf("events = %s, want %s", got, want)
+	}
+	if text != "Hello" || usage.Get("input_tokens").Int() != 5 || usage.Get("output_tokens").Int() != 2 {
+		t.Fatalf("unexpected stream: %s", stream.String())
+	}
+}
+
+func TestBedrockExecutorInvokeTranslatesForOpenAIClients(t *testing.T) {
+	var gotPath string
+	var gotBody []byte
+	chunk := func(event string) []byte {
+		return bedrockEvent("chunk", `{"bytes":"`+base64.StdEncoding.EncodeToString([]byte(event))+`"}`)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		gotBody, _ = io.ReadAll(r.Body)
+		var body bytes.Buffer
+		body.Write(chunk(`{"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","model":"claude","content":[],"usage":{"input_tokens":9,"output_tokens":1}}}`))
+		body.Write(chunk(`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`))
+		body.Write(chunk(`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"pong"}}`))
+		body.Write(chunk(`{"type":"content_block_stop","index":0}`))
+		body.Write(chunk(`{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":4}}`))
+		body.Write(chunk(`{"type":"message_stop"}`))
+		_, _ = w.Write(body.Bytes())
+	}))
+	defer server.Close()
+
+	executor := NewBedrockExecutor(&config.Config{})
+	payload := []byte(`{"model":"gpt-x","messages":[{"role":"user","content":"ping"}],"max_tokens":32}`)
+	resp, err := executor.Execute(context.Background(), newBedrockTestAuth(server.URL, "invoke"), cliproxyexecutor.Request{Model: bedrockTestModel, Payload: payload}, cliproxyexecutor.Options{
+		SourceFormat: sdktranslator.FromString("openai"),
+	})
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+
+	if !strings.HasSuffix(gotPath, "/invoke-with-response-stream") {
+		t.Fatalf("upstream path = %q", gotPath)
+	}
+	body := gjson.ParseBytes(gotBody)
+	if body.Get("anthropic_version").String() != bedrockAnthropicVersion || body.Get("model").Exists() || body.Get("stream").Exists() {
+		t.Fatalf("unexpected invoke body: %s", gotBody)
+	}
+	out := gjson.ParseBytes(resp.Payload)
+	if out.Get("choices.0.message.content").String() != "pong" {
+		t.Fatalf("unexpected openai response: %s", resp.Payload)
+	}
+	if out.Get("usage.prompt_tokens").Int() != 9 || out.Get("usage.completion_tokens").Int() != 4 {
+		t.Fatalf("unexpected openai usage: %s", resp.Payload)
+	}
+}
+
+func TestBedrockExecutorStreamException(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(encodeBedrockEvent(map[string]string{":message-type": "exception", ":exception-type": "throttlingException"}, []byte(`{"message":"slow down"}`)))
+	}))
+	defer server.Close()
+
+	executor := NewBedrockExecutor(&config.Config{})
+	payload := []byte(`{"model":"m","max_tokens":8,"messages":[{"role":"user","content":"hi"}]}`)
+	result, err := executor.ExecuteStream(context.Background(), newBedrockTestAuth(server.URL, "converse"), cliproxyexecutor.Request{Model: bedrockTestModel, Payload: payload}, cliproxyexecutor.Options{
+		SourceFormat: sdktranslator.FromString("claude"),
+		Stream:       true,
+	})
+	if err != nil {
+		t.Fatalf("ExecuteStream error: %v", err)
+	}
+	var streamErr error
+	for chunk := range result.Chunks {
+		if chunk.Err != nil {
+			streamErr = chunk.Err
+		}
+	}
+	status, ok := streamErr.(statusErr)
+	if !ok || status.StatusCode() != http.StatusTooManyRequests || !strings.Contains(status.Error(), "slow down") {
+		t.Fatalf("unexpected stream error: %v", streamErr)
+	}
+}
+
+func TestBedrockCatalogModelID(t *testing.T) {
+	cases := map[string]string{
+		"us.anthropic.claude-sonnet-4-20250514-v1:0":                                             "claude-sonnet-4-20250514",
+		"anthropic.claude-3-5-haiku-20241022-v1:0":                                               "claude-3-5-haiku-20241022",
+		"global.anthropic.claude-opus-4-6":                                                       "claude-opus-4-6",
+		"arn:aws:bedrock:us-east-1:1:inference-profile/eu.anthropic.claude-opus-4-20250514-v1:0": "claude-opus-4-20250514",
+		"claude-sonnet-4-6": "claude-sonnet-4-6",
+	}
+	for input, want := range cases {
+		if got := BedrockCatalogModelID(input); got != want {
+			t.Errorf("BedrockCatalogModelID(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
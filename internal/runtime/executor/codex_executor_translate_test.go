@@ -2,6 +2,7 @@ package executor
 
 import (
 	"bytes"
+	"context"
 	"sync/atomic"
 	"testing"
 
@@ -24,7 +25,7 @@ func TestTranslateCodexRequestPairReusesEqualPayload(t *testing.T) {
 	}, sdktranslator.ResponseTransform{})
 
 	payload := []byte(`{"model":"test-model","input":[{"role":"user"}]}`)
-	originalTranslated, body := translateCodexRequestPair(from, to, "test-model", payload, bytes.Clone(payload), true)
+	originalTranslated, body := translateCodexRequestPair(context.Background(), from, to, "test-model", payload, bytes.Clone(payload), true)
 
 	if gotCalls := atomic.LoadInt32(&calls); gotCalls != 1 {
 		t.Fatalf("TranslateRequest calls = %d, want 1", gotCalls)
@@ -45,7 +46,7 @@ func TestTranslateCodexRequestPairTranslatesDifferentPayloads(t *testing.T) {
 
 	originalPayload := []byte(`{"model":"test-model","input":[{"role":"system"}]}`)
 	payload := []byte(`{"model":"test-model","input":[{"role":"user"}]}`)
-	originalTranslated, body := translateCodexRequestPair(from, to, "test-model", originalPayload, payload, false)
+	originalTranslated, body := translateCodexRequestPair(context.Background(), from, to, "test-model", originalPayload, payload, false)
 
 	if gotCalls := atomic.LoadInt32(&calls); gotCalls != 2 {
 		t.Fatalf("TranslateRequest calls = %d, want 2", gotCalls)
@@ -130,8 +130,8 @@ func (e *GeminiCLIExecutor) Execute(ctx context.Context, auth *cliproxyauth.Auth
 		originalPayloadSource = opts.OriginalRequest
 	}
 	originalPayload := originalPayloadSource
-	originalTranslated := sdktranslator.TranslateRequest(from, to, baseModel, originalPayload, false)
-	basePayload := sdktranslator.TranslateRequest(from, to, baseModel, req.Payload, false)
+	originalTranslated := sdktranslator.TranslateRequestContext(ctx, from, to, baseModel, originalPayload, false)
+	basePayload := sdktranslator.TranslateRequestContext(ctx, from, to, baseModel, req.Payload, false)
 
 	basePayload, err = thinking.ApplyThinking(basePayload, req.Model, from.String(), to.String(), e.Identifier())
 	if err != nil {
@@ -290,8 +290,8 @@ func (e *GeminiCLIExecutor) ExecuteStream(ctx context.Context, auth *cliproxyaut
 		originalPayloadSource = opts.OriginalRequest
 	}
 	originalPayload := originalPayloadSource
-	originalTranslated := sdktranslator.TranslateRequest(from, to, baseModel, originalPayload, true)
-	basePayload := sdktranslator.TranslateRequest(from, to, baseModel, req.Payload, true)
+	originalTranslated := sdktranslator.TranslateRequestContext(ctx, from, to, baseModel, originalPayload, true)
+	basePayload := sdktranslator.TranslateRequestContext(ctx, from, to, baseModel, req.Payload, true)
 
 	basePayload, err = thinking.ApplyThinking(basePayload, req.Model, from.String(), to.String(), e.Identifier())
 	if err != nil {
@@ -528,7 +528,7 @@ func (e *GeminiCLIExecutor) CountTokens(ctx context.Context, auth *cliproxyauth.
 	// The loop variable attemptModel is only used as the concrete model id sent to the upstream
 	// Gemini CLI endpoint when iterating fallback variants.
 	for range models {
-		payload := sdktranslator.TranslateRequest(from, to, baseModel, req.Payload, false)
+		payload := sdktranslator.TranslateRequestContext(ctx, from, to, baseModel, req.Payload, false)
 
 		payload, err = thinking.ApplyThinking(payload, req.Model, from.String(), to.String(), e.Identifier())
 		if err != nil {
@@ -622,42 +622,7 @@ func prepareGeminiCLITokenSource(ctx context.Context, cfg *config.Config, auth *
 		return nil, nil, fmt.Errorf("gemini-cli auth metadata missing")
 	}
 
-	buildToken := func(meta map[string]any) (map[string]any, oauth2.Token) {
-		var base map[string]any
-		if tokenRaw, ok := meta["token"].(map[string]any); ok && tokenRaw != nil {
-			base = cloneMap(tokenRaw)
-		} else {
-			base = make(map[string]any)
-		}
-
-		var token oauth2.Token
-		if len(base) > 0 {
-			if raw, err := json.Marshal(base); err == nil {
-				_ = json.Unmarshal(raw, &token)
-			}
-		}
-
-		if token.AccessToken == "" {
-			token.AccessToken = stringValue(meta, "access_token")
-		}
-		if token.RefreshToken == "" {
-			token.RefreshToken = stringValue(meta, "refresh_token")
-		}
-		if token.TokenType == "" {
-			token.TokenType = stringValue(meta, "token_type")
-		}
-		if token.Expiry.IsZero() {
-			if expiry := stringValue(meta, "expiry"); expiry != "" {
-				if ts, err := time.Parse(time.RFC3339, expiry); err == nil {
-					token.Expiry = ts
-				}
-			}
-		}
-
-		return base, token
-	}
-
-	base, token := buildToken(metadata)
+	base, token := geminiCLIStoredToken(metadata)
 
 	conf := &oauth2.Config{
 		ClientID:     geminiOAuthClientID,
@@ -684,7 +649,7 @@ func prepareGeminiCLITokenSource(ctx context.Context, cfg *config.Config, auth *
 				if metadata == nil {
 					return nil, nil, fmt.Errorf("gemini-cli auth metadata missing")
 				}
-				base, token = buildToken(metadata)
+				base, token = geminiCLIStoredToken(metadata)
 			}
 		}
 		if token.AccessToken == "" {
@@ -703,6 +668,43 @@ func prepareGeminiCLITokenSource(ctx context.Context, cfg *config.Config, auth *
 	return oauth2.ReuseTokenSource(currentToken, src), base, nil
 }
 
+// geminiCLIStoredToken reads the OAuth token stored in the credential metadata and
+// returns it with the raw token map it was decoded from.
+func geminiCLIStoredToken(meta map[string]any) (map[string]any, oauth2.Token) {
+	var base map[string]any
+	if tokenRaw, ok := meta["token"].(map[string]any); ok && tokenRaw != nil {
+		base = cloneMap(tokenRaw)
+	} else {
+		base = make(map[string]any)
+	}
+
+	var token oauth2.Token
+	if len(base) > 0 {
+		if raw, err := json.Marshal(base); err == nil {
+			_ = json.Unmarshal(raw, &token)
+		}
+	}
+
+	if token.AccessToken == "" {
+		token.AccessToken = stringValue(meta, "access_token")
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = stringValue(meta, "refresh_token")
+	}
+	if token.TokenType == "" {
+		token.TokenType = stringValue(meta, "token_type")
+	}
+	if token.Expiry.IsZero() {
+		if expiry := stringValue(meta, "expiry"); expiry != "" {
+			if ts, err := time.Parse(time.RFC3339, expiry); err == nil {
+				token.Expiry = ts
+			}
+		}
+	}
+
+	return base, token
+}
+
 func updateGeminiCLITokenMetadata(auth *cliproxyauth.Auth, base map[string]any, tok *oauth2.Token) {
 	if auth == nil || tok == nil {
 		return
@@ -0,0 +1,120 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/runtime/executor/helps"
+	cliproxyauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// vertexHealthCheckModel is counted against when a Vertex credential has no configured model.
+	vertexHealthCheckModel        = "gemini-2.5-flash"
+	healthCheckResponseLimitBytes = 512
+	healthCheckCountTokensPayload = `{"contents":[{"role":"user","parts":[{"text":"ping"}]}]}`
+)
+
+// doHealthCheckRequest executes a health probe and maps a non-2xx response to a statusErr.
+func doHealthCheckRequest(do func(*http.Request) (*http.Response, error), req *http.Request) error {
+	resp, errDo := do(req)
+	if errDo != nil {
+		return errDo
+	}
+	defer func() {
+		if errClose := resp.Body.Close(); errClose != nil {
+			log.Debugf("health check: close response body error: %v", errClose)
+		}
+	}()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, healthCheckResponseLimitBytes))
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return statusErr{code: resp.StatusCode, msg: strings.TrimSpace(string(body))}
+	}
+	return nil
+}
+
+// CheckHealth verifies a Claude credential by listing one model.
+func (e *ClaudeExecutor) CheckHealth(ctx context.Context, auth *cliproxyauth.Auth) error {
+	apiKey, baseURL := claudeCreds(auth)
+	if strings.TrimSpace(apiKey) == "" {
+		return statusErr{code: http.StatusUnauthorized, msg: "missing credentials"}
+	}
+	if baseURL == "" {
+		baseURL = "https://api.anthropic.com"
+	}
+	url := strings.TrimSuffix(baseURL, "/") + "/v1/models?limit=1"
+	req, errReq := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if errReq != nil {
+		return errReq
+	}
+	if errHeaders := applyClaudeHeaders(req, auth, apiKey, false, nil, e.cfg); errHeaders != nil {
+		return errHeaders
+	}
+	req.Header.Del("Content-Type")
+	// Only the status matters, so skip negotiating a compressed body.
+	req.Header.Set("Accept-Encoding", "identity")
+	return doHealthCheckRequest(helps.NewUtlsHTTPClient(ctx, e.cfg, auth, 0).Do, req)
+}
+
+// CheckHealth verifies a Gemini credential by listing one model.
+func (e *GeminiExecutor) CheckHealth(ctx context.Context, auth *cliproxyauth.Auth) error {
+	url := fmt.Sprintf("%s/%s/models?pageSize=1", resolveGeminiBaseURL(auth), glAPIVersion)
+	req, errReq := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if errReq != nil {
+		return errReq
+	}
+	req.Header.Set("Accept", "application/json")
+	return doHealthCheckRequest(func(r *http.Request) (*http.Response, error) { return e.HttpRequest(ctx, auth, r) }, req)
+}
+
+// CheckHealth verifies a Vertex credential with a token count, which is not billed. Service
+// accounts are checked against their project and location, API keys against their base URL.
+func (e *GeminiVertexExecutor) CheckHealth(ctx context.Context, auth *cliproxyauth.Auth) error {
+	model := vertexHealthCheckModel
+	if entry := e.resolveVertexConfig(auth); entry != nil {
+		for _, m := range entry.Models {
+			if name := strings.TrimSpace(m.Name); name != "" {
+				model = name
+				break
+			}
+		}
+	}
+	var url string
+	if apiKey, baseURL := vertexAPICreds(auth); apiKey != "" {
+		if baseURL == "" {
+			baseURL = "https://aiplatform.googleapis.com"
+		}
+		url = fmt.Sprintf("%s/%s/publishers/google/models/%s:countTokens", strings.TrimSuffix(baseURL, "/"), vertexAPIVersion, model)
+	} else {
+		projectID, location, _, errCreds := vertexCreds(auth)
+		if errCreds != nil {
+			return errCreds
+		}
+		url = fmt.Sprintf("%s/%s/projects/%s/locations/%s/publishers/google/models/%s:countTokens", vertexBaseURL(location), vertexAPIVersion, projectID, location, model)
+	}
+	req, errReq := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader([]byte(healthCheckCountTokensPayload)))
+	if errReq != nil {
+		return errReq
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return doHealthCheckRequest(func(r *http.Request) (*http.Response, error) { return e.HttpRequest(ctx, auth, r) }, req)
+}
+
+// CheckHealth verifies an OpenAI-compatible credential by listing the provider's models.
+func (e *OpenAICompatExecutor) CheckHealth(ctx context.Context, auth *cliproxyauth.Auth) error {
+	baseURL, _ := e.resolveCredentials(auth)
+	if baseURL == "" {
+		return statusErr{code: http.StatusUnauthorized, msg: "missing provider baseURL"}
+	}
+	req, errReq := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(baseURL, "/")+"/models", nil)
+	if errReq != nil {
+		return errReq
+	}
+	req.Header.Set("Accept", "application/json")
+	return doHealthCheckRequest(func(r *http.Request) (*http.Response, error) { return e.HttpRequest(ctx, auth, r) }, req)
+}
@@ -0,0 +1,56 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	cliproxyauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
+)
+
+func TestCheckHealth_ProbesProviderEndpoints(t *testing.T) {
+	var gotPath, gotAuth string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization") + r.Header.Get("x-goog-api-key")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"error":"invalid key"}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cases := []struct {
+		name     string
+		checker  cliproxyauth.HealthChecker
+		attrs    map[string]string
+		wantPath string
+		wantAuth string
+	}{
+		{"claude", NewClaudeExecutor(cfg), map[string]string{"api_key": "sk-claude", "base_url": server.URL}, "/v1/models", "Bearer sk-claude"},
+		{"gemini", NewGeminiExecutor(cfg), map[string]string{"api_key": "gm-key", "base_url": server.URL}, "/v1beta/models", "gm-key"},
+		{"vertex", NewGeminiVertexExecutor(cfg), map[string]string{"api_key": "vx-key", "base_url": server.URL}, "/v1/publishers/google/models/" + vertexHealthCheckModel + ":countTokens", "vx-key"},
+		{"openai-compat", NewOpenAICompatExecutor("compat", cfg), map[string]string{"api_key": "oa-key", "base_url": server.URL + "/v1"}, "/v1/models", "Bearer oa-key"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			auth := &cliproxyauth.Auth{ID: tc.name, Attributes: tc.attrs}
+			status = http.StatusOK
+			if err := tc.checker.CheckHealth(context.Background(), auth); err != nil {
+				t.Fatalf("CheckHealth: %v", err)
+			}
+			if gotPath != tc.wantPath || gotAuth != tc.wantAuth {
+				t.Fatalf("probe hit %s with %q, want %s with %q", gotPath, gotAuth, tc.wantPath, tc.wantAuth)
+			}
+
+			status = http.StatusUnauthorized
+			err := tc.checker.CheckHealth(context.Background(), auth)
+			se, ok := err.(statusErr)
+			if !ok || se.StatusCode() != http.StatusUnauthorized {
+				t.Fatalf("CheckHealth on 401 = %v, want status error", err)
+			}
+		})
+	}
+}
@@ -244,6 +244,64 @@ func payloadModelRulesMatch(rules []config.PayloadModelRule, protocol string, fr
 	return false
 }
 
+// PayloadConditionsMatch reports whether payload satisfies the match, not-match,
+// exist and not-exist conditions of rule. Model, protocol and header
+// constraints of rule are ignored.
+func PayloadConditionsMatch(payload []byte, rule config.PayloadModelRule) bool {
+	return payloadModelRuleConditionsMatch(payload, "", rule)
+}
+
+// PayloadHeadersMatch reports whether headers satisfy every wildcard pattern in rules.
+func PayloadHeadersMatch(headers http.Header, rules map[string]string) bool {
+	return payloadHeadersMatch(headers, rules)
+}
+
+// PayloadRuleMatch identifies a payload rule by config section and index.
+type PayloadRuleMatch struct {
+	Section string `json:"section"`
+	Index   int    `json:"index"`
+}
+
+// MatchingPayloadRules lists the payload rules of cfg whose model entries match
+// a request, in evaluation order. Conditions are checked against payload as
+// sent by the client, before any rule is applied.
+func MatchingPayloadRules(cfg *config.Config, model, protocol, fromProtocol string, payload []byte, requestedModel string, headers http.Header) []PayloadRuleMatch {
+	if cfg == nil {
+		return nil
+	}
+	candidates := payloadModelCandidates(model, requestedModel)
+	if len(candidates) == 0 {
+		return nil
+	}
+	var matches []PayloadRuleMatch
+	appendMatches := func(section string, models [][]config.PayloadModelRule) {
+		for i, rules := range models {
+			if payloadModelRulesMatch(rules, protocol, fromProtocol, headers, payload, "", candidates) {
+				matches = append(matches, PayloadRuleMatch{Section: section, Index: i})
+			}
+		}
+	}
+	rules := cfg.Payload
+	appendMatches("default", payloadRuleModels(rules.Default))
+	appendMatches("default-raw", payloadRuleModels(rules.DefaultRaw))
+	appendMatches("override", payloadRuleModels(rules.Override))
+	appendMatches("override-raw", payloadRuleModels(rules.OverrideRaw))
+	filterModels := make([][]config.PayloadModelRule, len(rules.Filter))
+	for i := range rules.Filter {
+		filterModels[i] = rules.Filter[i].Models
+	}
+	appendMatches("filter", filterModels)
+	return matches
+}
+
+func payloadRuleModels(rules []config.PayloadRule) [][]config.PayloadModelRule {
+	out := make([][]config.PayloadModelRule, len(rules))
+	for i := range rules {
+		out[i] = rules[i].Models
+	}
+	return out
+}
+
 func payloadModelRuleConditionsMatch(payload []byte, root string, rule config.PayloadModelRule) bool {
 	if !payloadMatchConditionsMatch(payload, root, rule.Match) {
 		return false
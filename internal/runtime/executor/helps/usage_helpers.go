@@ -14,7 +14,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	internallogging "github.com/router-for-me/CLIProxyAPI/v7/internal/logging"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/registry"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/thinking"
+	sdkaccess "github.com/router-for-me/CLIProxyAPI/v7/sdk/access"
 	cliproxyauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
 	"github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/usage"
 	"github.com/tidwall/gjson"
@@ -30,9 +32,12 @@ type UsageReporter struct {
 	authIndex    string
 	authType     string
 	apiKey       string
+	apiKeyName   string
 	source       string
 	reasoning    string
 	serviceTier  string
+	fallbackFrom string
+	cacheStatus  string
 	requestedAt  time.Time
 	ttftMu       sync.RWMutex
 	ttft         time.Duration
@@ -67,11 +72,14 @@ func NewUsageReporter(ctx context.Context, provider, model string, auth *cliprox
 		alias:       strings.TrimSpace(alias),
 		requestedAt: time.Now(),
 		apiKey:      apiKey,
+		apiKeyName:  APIKeyNameFromContext(ctx),
 		source:      resolveUsageSource(auth, apiKey),
 		authType:    resolveUsageAuthType(auth),
 		reasoning:   usage.ReasoningEffortFromContext(ctx),
 		serviceTier: usage.ServiceTierFromContext(ctx),
 	}
+	reporter.fallbackFrom = usage.FallbackFromContext(ctx)
+	reporter.cacheStatus = usage.CacheStatusFromContext(ctx)
 	if auth != nil {
 		reporter.authID = auth.ID
 		reporter.authIndex = auth.EnsureIndex()
@@ -266,6 +274,7 @@ func (r *UsageReporter) buildRecordForModel(model string, detail usage.Detail, f
 		Alias:           r.alias,
 		Source:          r.source,
 		APIKey:          r.apiKey,
+		APIKeyName:      r.apiKeyName,
 		AuthID:          r.authID,
 		AuthIndex:       r.authIndex,
 		AuthType:        r.authType,
@@ -277,9 +286,58 @@ func (r *UsageReporter) buildRecordForModel(model string, detail usage.Detail, f
 		Failed:          failed,
 		Fail:            fail,
 		Detail:          detail,
+		CostUSD:         usageCost(lookupModelPricing(r.provider, r.alias, model), detail),
+		FallbackFrom:    r.fallbackFrom,
+		CacheStatus:     r.cacheStatus,
 	}
 }
 
+// lookupModelPricing resolves prices for the client-facing alias first so
+// config overrides win, then for the upstream model.
+func lookupModelPricing(provider string, models ...string) *registry.ModelPricing {
+	for _, model := range models {
+		model = strings.TrimSpace(model)
+		if model == "" {
+			continue
+		}
+		if info := registry.LookupModelInfo(model, provider); info != nil && !info.Pricing.IsZero() {
+			return info.Pricing
+		}
+	}
+	return nil
+}
+
+// usageCost splits the token detail into disjoint buckets before pricing.
+// Claude reports input without cache tokens; OpenAI and Gemini include cached
+// tokens in input. Reasoning is billed separately only when it is not already
+// part of the output count, which the total reveals.
+func usageCost(pricing *registry.ModelPricing, detail usage.Detail) float64 {
+	if pricing.IsZero() {
+		return 0
+	}
+	input := detail.InputTokens
+	output := detail.OutputTokens
+	reasoning := detail.ReasoningTokens
+	var cacheRead, cacheWrite int64
+	if detail.CacheReadTokens > 0 || detail.CacheCreationTokens > 0 {
+		cacheRead = detail.CacheReadTokens
+		cacheWrite = detail.CacheCreationTokens
+	} else {
+		cacheRead = detail.CachedTokens
+		input -= cacheRead
+		if input < 0 {
+			input = 0
+		}
+	}
+	if reasoning > 0 && detail.TotalTokens < detail.InputTokens+detail.OutputTokens+reasoning {
+		output -= reasoning
+		if output < 0 {
+			output = 0
+		}
+	}
+	return pricing.Cost(input, output, reasoning, cacheRead, cacheWrite)
+}
+
 func extractServiceTierFromPayload(payload []byte) string {
 	if len(payload) == 0 {
 		return usage.DefaultServiceTier
@@ -401,6 +459,26 @@ func APIKeyFromContext(ctx context.Context) string {
 	return ""
 }
 
+// APIKeyNameFromContext returns the client key label recorded by the access provider.
+func APIKeyNameFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	ginCtx, ok := ctx.Value("gin").(*gin.Context)
+	if !ok || ginCtx == nil {
+		return ""
+	}
+	v, exists := ginCtx.Get("accessMetadata")
+	if !exists {
+		return ""
+	}
+	metadata, ok := v.(map[string]string)
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(metadata[sdkaccess.MetadataKeyName])
+}
+
 func resolveUsageSource(auth *cliproxyauth.Auth, ctxAPIKey string) string {
 	if auth != nil {
 		provider := strings.TrimSpace(auth.Provider)
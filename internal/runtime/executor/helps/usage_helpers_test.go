@@ -3,11 +3,13 @@ package helps
 import (
 	"context"
 	"io"
+	"math"
 	"net/http"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/registry"
 	"github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/usage"
 )
 
@@ -315,3 +317,55 @@ type TestUsageExecutor struct{}
 func (TestUsageExecutor) Identifier() string {
 	return "test-provider"
 }
+
+func TestUsageCostSplitsProviderTokenSemantics(t *testing.T) {
+	pricing := &registry.ModelPricing{Input: 1, Output: 10, CacheRead: 0.1, CacheWrite: 2, Reasoning: 20}
+	cases := []struct {
+		name   string
+		detail usage.Detail
+		want   float64
+	}{
+		{
+			// OpenAI: input includes cached tokens, output includes reasoning.
+			name:   "openai",
+			detail: usage.Detail{InputTokens: 1_000_000, CachedTokens: 400_000, OutputTokens: 300_000, ReasoningTokens: 100_000, TotalTokens: 1_300_000},
+			want:   0.6 + 0.04 + 2 + 2,
+		},
+		{
+			// Claude: cache reads and writes are reported next to input.
+			name:   "claude",
+			detail: usage.Detail{InputTokens: 100_000, OutputTokens: 100_000, CacheReadTokens: 1_000_000, CacheCreationTokens: 500_000, CachedTokens: 1_000_000, TotalTokens: 1_700_000},
+			want:   0.1 + 1 + 0.1 + 1,
+		},
+		{
+			// Gemini: thoughts are counted next to candidates in the total.
+			name:   "gemini",
+			detail: usage.Detail{InputTokens: 1_000_000, OutputTokens: 100_000, ReasoningTokens: 100_000, TotalTokens: 1_200_000},
+			want:   1 + 1 + 2,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := usageCost(pricing, tc.detail); math.Abs(got-tc.want) > 1e-9 {
+				t.Fatalf("cost = %v, want %v", got, tc.want)
+			}
+		})
+	}
+	if got := usageCost(nil, usage.Detail{InputTokens: 10}); got != 0 {
+		t.Fatalf("cost without pricing = %v", got)
+	}
+}
+
+func TestBuildRecordUsesRegisteredModelPricing(t *testing.T) {
+	reg := registry.GetGlobalRegistry()
+	reg.RegisterClient("usage-cost-test", "claude", []*registry.ModelInfo{
+		{ID: "priced-alias", Pricing: &registry.ModelPricing{Input: 4, Output: 8}},
+	})
+	t.Cleanup(func() { reg.UnregisterClient("usage-cost-test") })
+
+	reporter := &UsageReporter{provider: "claude", model: "upstream-model", alias: "priced-alias"}
+	record := reporter.buildRecord(usage.Detail{InputTokens: 500_000, OutputTokens: 250_000, TotalTokens: 750_000}, false)
+	if math.Abs(record.CostUSD-4) > 1e-9 {
+		t.Fatalf("CostUSD = %v, want 4", record.CostUSD)
+	}
+}
@@ -0,0 +1,379 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/runtime/executor/helps"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/thinking"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/util"
+	cliproxyauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
+	cliproxyexecutor "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/executor"
+	"github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/usage"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+const (
+	openAIEmbeddingSourceFormat   = "openai-embedding"
+	openAICompatEmbeddingsPath    = "/embeddings"
+	geminiBatchEmbedContentAction = "batchEmbedContents"
+	vertexEmbeddingPredictAction  = "predict"
+)
+
+// isOpenAIEmbeddingRequest reports whether the request was issued by the
+// OpenAI-compatible /v1/embeddings frontend.
+func isOpenAIEmbeddingRequest(opts cliproxyexecutor.Options) bool {
+	return strings.EqualFold(strings.TrimSpace(opts.SourceFormat.String()), openAIEmbeddingSourceFormat)
+}
+
+// openAIEmbeddingInputs extracts the list of texts from an OpenAI embeddings
+// request. The input may be a single string or an array of strings; token
+// arrays are rejected because Google upstreams only accept text.
+func openAIEmbeddingInputs(payload []byte) ([]string, error) {
+	input := gjson.GetBytes(payload, "input")
+	if !input.Exists() {
+		return nil, statusErr{code: http.StatusBadRequest, msg: "input is required"}
+	}
+	if input.Type == gjson.String {
+		return []string{input.String()}, nil
+	}
+	if !input.IsArray() {
+		return nil, statusErr{code: http.StatusBadRequest, msg: "input must be a string or an array of strings"}
+	}
+	items := input.Array()
+	if len(items) == 0 {
+		return nil, statusErr{code: http.StatusBadRequest, msg: "input must not be empty"}
+	}
+	texts := make([]string, 0, len(items))
+	for _, item := range items {
+		if item.Type != gjson.String {
+			return nil, statusErr{code: http.StatusBadRequest, msg: "token array inputs are not supported by this provider"}
+		}
+		texts = append(texts, item.String())
+	}
+	return texts, nil
+}
+
+// buildGeminiBatchEmbedRequest converts an OpenAI embeddings request into a
+// Gemini batchEmbedContents payload.
+func buildGeminiBatchEmbedRequest(model string, payload []byte) ([]byte, error) {
+	texts, err := openAIEmbeddingInputs(payload)
+	if err != nil {
+		return nil, err
+	}
+	dimensions := gjson.GetBytes(payload, "dimensions").Int()
+	taskType := strings.TrimSpace(gjson.GetBytes(payload, "task_type").String())
+
+	out := []byte(`{"requests":[]}`)
+	for _, text := range texts {
+		entry := []byte(`{}`)
+		entry, _ = sjson.SetBytes(entry, "model", "models/"+model)
+		entry, _ = sjson.SetBytes(entry, "content.parts.0.text", text)
+		if dimensions > 0 {
+			entry, _ = sjson.SetBytes(entry, "outputDimensionality", dimensions)
+		}
+		if taskType != "" {
+			entry, _ = sjson.SetBytes(entry, "taskType", taskType)
+		}
+		out, _ = sjson.SetRawBytes(out, "requests.-1", entry)
+	}
+	return out, nil
+}
+
+// buildVertexEmbedPredictRequest converts an OpenAI embeddings request into a
+// Vertex AI text embedding predict payload.
+func buildVertexEmbedPredictRequest(payload []byte) ([]byte, error) {
+	texts, err := openAIEmbeddingInputs(payload)
+	if err != nil {
+		return nil, err
+	}
+	taskType := strings.TrimSpace(gjson.GetBytes(payload, "task_type").String())
+
+	out := []byte(`{"instances":[]}`)
+	for _, text := range texts {
+		instance := []byte(`{}`)
+		instance, _ = sjson.SetBytes(instance, "content", text)
+		if taskType != "" {
+			instance, _ = sjson.SetBytes(instance, "task_type", taskType)
+		}
+		out, _ = sjson.SetRawBytes(out, "instances.-1", instance)
+	}
+	if dimensions := gjson.GetBytes(payload, "dimensions").Int(); dimensions > 0 {
+		out, _ = sjson.SetBytes(out, "parameters.outputDimensionality", dimensions)
+	}
+	return out, nil
+}
+
+// convertGeminiBatchEmbedResponse renders a batchEmbedContents response as an
+// OpenAI embeddings list.
+func convertGeminiBatchEmbedResponse(model string, originalPayload []byte, data []byte) ([]byte, usage.Detail) {
+	vectors := gjson.GetBytes(data, "embeddings").Array()
+	values := make([]gjson.Result, 0, len(vectors))
+	for _, vector := range vectors {
+		values = append(values, vector.Get("values"))
+	}
+	detail := helps.ParseGeminiUsage(data)
+	return buildOpenAIEmbeddingsResponse(model, originalPayload, values, detail.InputTokens), detail
+}
+
+// convertVertexEmbedPredictResponse renders a Vertex AI predict response as an
+// OpenAI embeddings list. Token statistics are summed across predictions.
+func convertVertexEmbedPredictResponse(model string, originalPayload []byte, data []byte) ([]byte, usage.Detail) {
+	predictions := gjson.GetBytes(data, "predictions").Array()
+	values := make([]gjson.Result, 0, len(predictions))
+	var promptTokens int64
+	for _, prediction := range predictions {
+		values = append(values, prediction.Get("embeddings.values"))
+		promptTokens += prediction.Get("embeddings.statistics.token_count").Int()
+	}
+	detail := usage.Detail{InputTokens: promptTokens, TotalTokens: promptTokens}
+	return buildOpenAIEmbeddingsResponse(model, originalPayload, values, promptTokens), detail
+}
+
+func buildOpenAIEmbeddingsResponse(model string, originalPayload []byte, vectors []gjson.Result, promptTokens int64) []byte {
+	encodeBase64 := strings.EqualFold(strings.TrimSpace(gjson.GetBytes(originalPayload, "encoding_format").String()), "base64")
+
+	out := []byte(`{"object":"list","data":[]}`)
+	for i, vector := range vectors {
+		entry := []byte(`{"object":"embedding"}`)
+		entry, _ = sjson.SetBytes(entry, "index", i)
+		if encodeBase64 {
+			entry, _ = sjson.SetBytes(entry, "embedding", encodeEmbeddingBase64(vector))
+		} else {
+			raw := vector.Raw
+			if raw == "" {
+				raw = "[]"
+			}
+			entry, _ = sjson.SetRawBytes(entry, "embedding", []byte(raw))
+		}
+		out, _ = sjson.SetRawBytes(out, "data.-1", entry)
+	}
+	out, _ = sjson.SetBytes(out, "model", model)
+	out, _ = sjson.SetBytes(out, "usage.prompt_tokens", promptTokens)
+	out, _ = sjson.SetBytes(out, "usage.total_tokens", promptTokens)
+	return out
+}
+
+// encodeEmbeddingBase64 matches the OpenAI base64 encoding: little-endian
+// float32 values.
+func encodeEmbeddingBase64(vector gjson.Result) string {
+	values := vector.Array()
+	buf := make([]byte, 4*len(values))
+	for i, value := range values {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(float32(value.Float())))
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+// embeddingsUpstreamRequest describes a single non-streaming embeddings call.
+type embeddingsUpstreamRequest struct {
+	provider string
+	url      string
+	body     []byte
+	// prepare sets provider-specific authentication headers.
+	prepare func(*http.Request)
+}
+
+// doEmbeddingsRequest sends an embeddings request upstream and returns the raw
+// response body, recording request/response logs the same way as other calls.
+func doEmbeddingsRequest(ctx context.Context, cfg *config.Config, auth *cliproxyauth.Auth, reporter *helps.UsageReporter, upstream embeddingsUpstreamRequest) ([]byte, http.Header, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, upstream.url, bytes.NewReader(upstream.body))
+	if err != nil {
+		return nil, nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if upstream.prepare != nil {
+		upstream.prepare(httpReq)
+	}
+	var attrs map[string]string
+	if auth != nil {
+		attrs = auth.Attributes
+	}
+	util.ApplyCustomHeadersFromAttrs(httpReq, attrs)
+
+	var authID, authLabel, authType, authValue string
+	if auth != nil {
+		authID = auth.ID
+		authLabel = auth.Label
+		authType, authValue = auth.AccountInfo()
+	}
+	helps.RecordAPIRequest(ctx, cfg, helps.UpstreamRequestLog{
+		URL:       upstream.url,
+		Method:    http.MethodPost,
+		Headers:   httpReq.Header.Clone(),
+		Body:      upstream.body,
+		Provider:  upstream.provider,
+		AuthID:    authID,
+		AuthLabel: authLabel,
+		AuthType:  authType,
+		AuthValue: authValue,
+	})
+
+	httpClient := helps.NewProxyAwareHTTPClient(ctx, cfg, auth, 0)
+	httpClient = reporter.TrackHTTPClient(httpClient)
+	httpResp, err := httpClient.Do(httpReq)
+	if err != nil {
+		helps.RecordAPIResponseError(ctx, cfg, err)
+		return nil, nil, err
+	}
+	defer func() {
+		if errClose := httpResp.Body.Close(); errClose != nil {
+			log.Errorf("%s executor: close embeddings response body error: %v", upstream.provider, errClose)
+		}
+	}()
+	helps.RecordAPIResponseMetadata(ctx, cfg, httpResp.StatusCode, httpResp.Header.Clone())
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		b, _ := io.ReadAll(httpResp.Body)
+		helps.AppendAPIResponseChunk(ctx, cfg, b)
+		helps.LogWithRequestID(ctx).Debugf("request error, error status: %d, error message: %s", httpResp.StatusCode, helps.SummarizeErrorBody(httpResp.Header.Get("Content-Type"), b))
+		return nil, nil, statusErr{code: httpResp.StatusCode, msg: string(b)}
+	}
+	data, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		helps.RecordAPIResponseError(ctx, cfg, err)
+		return nil, nil, err
+	}
+	helps.AppendAPIResponseChunk(ctx, cfg, data)
+	return data, httpResp.Header.Clone(), nil
+}
+
+// executeEmbeddings serves OpenAI embeddings requests through the Gemini
+// batchEmbedContents API.
+func (e *GeminiExecutor) executeEmbeddings(ctx context.Context, auth *cliproxyauth.Auth, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (resp cliproxyexecutor.Response, err error) {
+	baseModel := thinking.ParseSuffix(req.Model).ModelName
+
+	reporter := helps.NewExecutorUsageReporter(ctx, e, baseModel, auth)
+	defer reporter.TrackFailure(ctx, &err)
+
+	body, err := buildGeminiBatchEmbedRequest(baseModel, req.Payload)
+	if err != nil {
+		return resp, err
+	}
+
+	apiKey, bearer := geminiCreds(auth)
+	url := fmt.Sprintf("%s/%s/models/%s:%s", resolveGeminiBaseURL(auth), glAPIVersion, baseModel, geminiBatchEmbedContentAction)
+	data, respHeaders, err := doEmbeddingsRequest(ctx, e.cfg, auth, reporter, embeddingsUpstreamRequest{
+		provider: e.Identifier(),
+		url:      url,
+		body:     body,
+		prepare: func(httpReq *http.Request) {
+			if apiKey != "" {
+				httpReq.Header.Set("x-goog-api-key", apiKey)
+			} else if bearer != "" {
+				httpReq.Header.Set("Authorization", "Bearer "+bearer)
+			}
+		},
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	out, detail := convertGeminiBatchEmbedResponse(req.Model, req.Payload, data)
+	reporter.Publish(ctx, detail)
+	reporter.EnsurePublished(ctx)
+	return cliproxyexecutor.Response{Payload: out, Headers: respHeaders}, nil
+}
+
+// executeEmbeddings serves OpenAI embeddings requests through the Vertex AI
+// predict endpoint, using either an API key or service account credentials.
+func (e *GeminiVertexExecutor) executeEmbeddings(ctx context.Context, auth *cliproxyauth.Auth, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (resp cliproxyexecutor.Response, err error) {
+	baseModel := thinking.ParseSuffix(req.Model).ModelName
+
+	reporter := helps.NewExecutorUsageReporter(ctx, e, baseModel, auth)
+	defer reporter.TrackFailure(ctx, &err)
+
+	body, err := buildVertexEmbedPredictRequest(req.Payload)
+	if err != nil {
+		return resp, err
+	}
+
+	var url, authHeader, authValue string
+	apiKey, baseURL := vertexAPICreds(auth)
+	if apiKey != "" {
+		if baseURL == "" {
+			baseURL = "https://aiplatform.googleapis.com"
+		}
+		url = fmt.Sprintf("%s/%s/publishers/google/models/%s:%s", baseURL, vertexAPIVersion, baseModel, vertexEmbeddingPredictAction)
+		authHeader, authValue = "x-goog-api-key", apiKey
+	} else {
+		projectID, location, saJSON, errCreds := vertexCreds(auth)
+		if errCreds != nil {
+			return resp, errCreds
+		}
+		url = fmt.Sprintf("%s/%s/projects/%s/locations/%s/publishers/google/models/%s:%s", vertexBaseURL(location), vertexAPIVersion, projectID, location, baseModel, vertexEmbeddingPredictAction)
+		token, errTok := vertexAccessToken(ctx, e.cfg, auth, saJSON)
+		if errTok != nil {
+			log.Errorf("vertex executor: access token error: %v", errTok)
+			return resp, statusErr{code: http.StatusInternalServerError, msg: "internal server error"}
+		}
+		if token != "" {
+			authHeader, authValue = "Authorization", "Bearer "+token
+		}
+	}
+
+	data, respHeaders, err := doEmbeddingsRequest(ctx, e.cfg, auth, reporter, embeddingsUpstreamRequest{
+		provider: e.Identifier(),
+		url:      url,
+		body:     body,
+		prepare: func(httpReq *http.Request) {
+			if authHeader != "" {
+				httpReq.Header.Set(authHeader, authValue)
+			}
+		},
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	out, detail := convertVertexEmbedPredictResponse(req.Model, req.Payload, data)
+	reporter.Publish(ctx, detail)
+	reporter.EnsurePublished(ctx)
+	return cliproxyexecutor.Response{Payload: out, Headers: respHeaders}, nil
+}
+
+// executeEmbeddings forwards OpenAI embeddings requests to the compatible
+// provider's /embeddings endpoint with the upstream model name applied.
+func (e *OpenAICompatExecutor) executeEmbeddings(ctx context.Context, auth *cliproxyauth.Auth, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (resp cliproxyexecutor.Response, err error) {
+	baseModel := thinking.ParseSuffix(req.Model).ModelName
+
+	reporter := helps.NewExecutorUsageReporter(ctx, e, baseModel, auth)
+	defer reporter.TrackFailure(ctx, &err)
+
+	baseURL, apiKey := e.resolveCredentials(auth)
+	if baseURL == "" {
+		err = statusErr{code: http.StatusUnauthorized, msg: "missing provider baseURL"}
+		return resp, err
+	}
+
+	body, _ := sjson.SetBytes(req.Payload, "model", baseModel)
+	url := strings.TrimSuffix(baseURL, "/") + openAICompatEmbeddingsPath
+
+	data, respHeaders, err := doEmbeddingsRequest(ctx, e.cfg, auth, reporter, embeddingsUpstreamRequest{
+		provider: e.Identifier(),
+		url:      url,
+		body:     body,
+		prepare: func(httpReq *http.Request) {
+			if apiKey != "" {
+				httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+			}
+			applyOpenAICompatUserAgent(httpReq, opts.Headers)
+		},
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	reporter.Publish(ctx, helps.ParseOpenAIUsage(data))
+	reporter.EnsurePublished(ctx)
+	return cliproxyexecutor.Response{Payload: data, Headers: respHeaders}, nil
+}
@@ -0,0 +1,184 @@
+package executor
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	cliproxyauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
+	cliproxyexecutor "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/executor"
+	sdktranslator "github.com/router-for-me/CLIProxyAPI/v7/sdk/translator"
+	"github.com/tidwall/gjson"
+)
+
+func TestGeminiExecutorEmbeddingsUsesBatchEmbedContents(t *testing.T) {
+	var gotPath, gotKey string
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotKey = r.Header.Get("x-goog-api-key")
+		gotBody, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"embeddings":[{"values":[0.1,0.2]},{"values":[0.3,0.4]}]}`))
+	}))
+	defer server.Close()
+
+	exec := NewGeminiExecutor(&config.Config{})
+	auth := &cliproxyauth.Auth{Attributes: map[string]string{
+		"api_key":  "test-key",
+		"base_url": server.URL,
+	}}
+	resp, err := exec.Execute(context.Background(), auth, cliproxyexecutor.Request{
+		Model:   "gemini-embedding-001",
+		Payload: []byte(`{"model":"gemini-embedding-001","input":["a","b"],"dimensions":256}`),
+	}, cliproxyexecutor.Options{SourceFormat: sdktranslator.FromString("openai-embedding")})
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if gotPath != "/v1beta/models/gemini-embedding-001:batchEmbedContents" {
+		t.Fatalf("path = %q", gotPath)
+	}
+	if gotKey != "test-key" {
+		t.Fatalf("api key header = %q", gotKey)
+	}
+	if got := gjson.GetBytes(gotBody, "requests.#").Int(); got != 2 {
+		t.Fatalf("requests = %d, want 2; body=%s", got, gotBody)
+	}
+	if got := gjson.GetBytes(gotBody, "requests.1.content.parts.0.text").String(); got != "b" {
+		t.Fatalf("second text = %q", got)
+	}
+	if got := gjson.GetBytes(gotBody, "requests.0.model").String(); got != "models/gemini-embedding-001" {
+		t.Fatalf("request model = %q", got)
+	}
+	if got := gjson.GetBytes(gotBody, "requests.0.outputDimensionality").Int(); got != 256 {
+		t.Fatalf("outputDimensionality = %d", got)
+	}
+
+	if got := gjson.GetBytes(resp.Payload, "object").String(); got != "list" {
+		t.Fatalf("object = %q; payload=%s", got, resp.Payload)
+	}
+	if got := gjson.GetBytes(resp.Payload, "data.1.index").Int(); got != 1 {
+		t.Fatalf("data.1.index = %d", got)
+	}
+	if got := gjson.GetBytes(resp.Payload, "data.1.embedding").Raw; got != "[0.3,0.4]" {
+		t.Fatalf("data.1.embedding = %s", got)
+	}
+}
+
+func TestGeminiExecutorEmbeddingsRejectsTokenInput(t *testing.T) {
+	exec := NewGeminiExecutor(&config.Config{})
+	auth := &cliproxyauth.Auth{Attributes: map[string]string{"api_key": "test-key"}}
+	_, err := exec.Execute(context.Background(), auth, cliproxyexecutor.Request{
+		Model:   "gemini-embedding-001",
+		Payload: []byte(`{"model":"gemini-embedding-001","input":[[1,2,3]]}`),
+	}, cliproxyexecutor.Options{SourceFormat: sdktranslator.FromString("openai-embedding")})
+	if err == nil {
+		t.Fatal("expected error for token array input")
+	}
+	status, ok := err.(statusErr)
+	if !ok || status.StatusCode() != http.StatusBadRequest {
+		t.Fatalf("error = %#v, want 400 statusErr", err)
+	}
+}
+
+func TestGeminiVertexExecutorEmbeddingsUsesPredict(t *testing.T) {
+	var gotPath string
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotBody, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"predictions":[{"embeddings":{"values":[1,2],"statistics":{"token_count":3}}},{"embeddings":{"values":[3,4],"statistics":{"token_count":4}}}]}`))
+	}))
+	defer server.Close()
+
+	exec := NewGeminiVertexExecutor(&config.Config{})
+	auth := &cliproxyauth.Auth{Attributes: map[string]string{
+		"api_key":  "vertex-key",
+		"base_url": server.URL,
+	}}
+	resp, err := exec.Execute(context.Background(), auth, cliproxyexecutor.Request{
+		Model:   "text-embedding-005",
+		Payload: []byte(`{"model":"text-embedding-005","input":["x","y"],"task_type":"RETRIEVAL_QUERY"}`),
+	}, cliproxyexecutor.Options{SourceFormat: sdktranslator.FromString("openai-embedding")})
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if gotPath != "/v1/publishers/google/models/text-embedding-005:predict" {
+		t.Fatalf("path = %q", gotPath)
+	}
+	if got := gjson.GetBytes(gotBody, "instances.1.content").String(); got != "y" {
+		t.Fatalf("instances.1.content = %q; body=%s", got, gotBody)
+	}
+	if got := gjson.GetBytes(gotBody, "instances.0.task_type").String(); got != "RETRIEVAL_QUERY" {
+		t.Fatalf("task_type = %q", got)
+	}
+	if got := gjson.GetBytes(resp.Payload, "usage.prompt_tokens").Int(); got != 7 {
+		t.Fatalf("usage.prompt_tokens = %d; payload=%s", got, resp.Payload)
+	}
+	if got := gjson.GetBytes(resp.Payload, "data.0.embedding").Raw; got != "[1,2]" {
+		t.Fatalf("data.0.embedding = %s", got)
+	}
+}
+
+func TestOpenAICompatExecutorEmbeddingsPassthrough(t *testing.T) {
+	var gotPath, gotAuth string
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		gotBody, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object":"list","data":[{"object":"embedding","index":0,"embedding":[0.5]}],"model":"upstream-embed","usage":{"prompt_tokens":2,"total_tokens":2}}`))
+	}))
+	defer server.Close()
+
+	exec := NewOpenAICompatExecutor("openai-compatibility", &config.Config{})
+	auth := &cliproxyauth.Auth{Attributes: map[string]string{
+		"base_url": server.URL + "/v1",
+		"api_key":  "test",
+	}}
+	resp, err := exec.Execute(context.Background(), auth, cliproxyexecutor.Request{
+		Model:   "upstream-embed",
+		Payload: []byte(`{"model":"alias-embed","input":"hello"}`),
+	}, cliproxyexecutor.Options{SourceFormat: sdktranslator.FromString("openai-embedding")})
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if gotPath != "/v1/embeddings" {
+		t.Fatalf("path = %q", gotPath)
+	}
+	if gotAuth != "Bearer test" {
+		t.Fatalf("authorization = %q", gotAuth)
+	}
+	if got := gjson.GetBytes(gotBody, "model").String(); got != "upstream-embed" {
+		t.Fatalf("model = %q", got)
+	}
+	if got := gjson.GetBytes(resp.Payload, "data.0.embedding").Raw; got != "[0.5]" {
+		t.Fatalf("payload = %s", resp.Payload)
+	}
+}
+
+func TestBuildOpenAIEmbeddingsResponseBase64(t *testing.T) {
+	vectors := []gjson.Result{gjson.Parse(`[1.5,-2]`)}
+	out := buildOpenAIEmbeddingsResponse("m", []byte(`{"encoding_format":"base64"}`), vectors, 0)
+	encoded := gjson.GetBytes(out, "data.0.embedding").String()
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("decode base64: %v", err)
+	}
+	if len(raw) != 8 {
+		t.Fatalf("decoded length = %d, want 8", len(raw))
+	}
+	first := math.Float32frombits(binary.LittleEndian.Uint32(raw[0:4]))
+	second := math.Float32frombits(binary.LittleEndian.Uint32(raw[4:8]))
+	if first != 1.5 || second != -2 {
+		t.Fatalf("decoded = [%v %v]", first, second)
+	}
+}
@@ -0,0 +1,338 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	kimiauth "github.com/router-for-me/CLIProxyAPI/v7/internal/auth/kimi"
+	cliproxyauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	codexUsagePath              = "/wham/usage"
+	geminiCLIRetrieveQuotaPath  = "retrieveUserQuota"
+	antigravityAvailableModels  = "/v1internal:fetchAvailableModels"
+	kimiUsagesPath              = "/v1/usages"
+	quotaPollResponseLimitBytes = 1 << 20
+	// quotaPollTokenSkew is how long a stored access token must stay valid to be used for polling.
+	quotaPollTokenSkew = time.Minute
+)
+
+// doQuotaRequest executes a quota lookup and returns the response body and headers.
+func doQuotaRequest(client *http.Client, req *http.Request) ([]byte, http.Header, error) {
+	resp, errDo := client.Do(req)
+	if errDo != nil {
+		return nil, nil, errDo
+	}
+	defer func() {
+		if errClose := resp.Body.Close(); errClose != nil {
+			log.Debugf("quota poll: close response body error: %v", errClose)
+		}
+	}()
+	body, errRead := io.ReadAll(io.LimitReader(resp.Body, quotaPollResponseLimitBytes))
+	if errRead != nil {
+		return nil, nil, errRead
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, nil, statusErr{code: resp.StatusCode, msg: string(body)}
+	}
+	return body, resp.Header, nil
+}
+
+// PollQuota reads the ChatGPT rate-limit windows of a Codex OAuth credential.
+// API key credentials do not expose quota and are skipped.
+func (e *CodexExecutor) PollQuota(ctx context.Context, auth *cliproxyauth.Auth) (*cliproxyauth.QuotaSnapshot, error) {
+	if auth == nil || auth.Attributes["api_key"] != "" {
+		return nil, nil
+	}
+	token, baseURL := codexCreds(auth)
+	if strings.TrimSpace(token) == "" {
+		return nil, nil
+	}
+	if baseURL == "" {
+		baseURL = "https://chatgpt.com/backend-api/codex"
+	}
+	usageURL := strings.TrimSuffix(strings.TrimSuffix(baseURL, "/"), "/codex") + codexUsagePath
+	req, errReq := http.NewRequestWithContext(ctx, http.MethodGet, usageURL, nil)
+	if errReq != nil {
+		return nil, errReq
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", codexUserAgent)
+	req.Header.Set("Originator", codexOriginator)
+	if accountID, ok := auth.Metadata["account_id"].(string); ok && accountID != "" {
+		req.Header.Set("Chatgpt-Account-Id", accountID)
+	}
+	body, headers, errDo := doQuotaRequest(newHTTPClient(ctx, e.cfg, auth, 0), req)
+	if errDo != nil {
+		return nil, errDo
+	}
+	now := time.Now()
+	windows := parseCodexUsageWindows(body, now)
+	if len(windows) == 0 {
+		windows = parseCodexRateLimitHeaders(headers, now)
+	}
+	return &cliproxyauth.QuotaSnapshot{Windows: windows}, nil
+}
+
+// PollQuota delegates quota polling to the HTTP executor.
+func (e *CodexAutoExecutor) PollQuota(ctx context.Context, auth *cliproxyauth.Auth) (*cliproxyauth.QuotaSnapshot, error) {
+	if e == nil || e.httpExec == nil {
+		return nil, fmt.Errorf("codex auto executor: http executor is nil")
+	}
+	return e.httpExec.PollQuota(ctx, auth)
+}
+
+// parseCodexUsageWindows reads the primary and secondary windows of a usage response.
+func parseCodexUsageWindows(body []byte, now time.Time) []cliproxyauth.QuotaWindow {
+	var windows []cliproxyauth.QuotaWindow
+	for _, name := range []string{"primary", "secondary"} {
+		window := gjson.GetBytes(body, "rate_limit."+name+"_window")
+		if !window.IsObject() || !window.Get("used_percent").Exists() {
+			continue
+		}
+		var resetAt time.Time
+		if at := window.Get("reset_at").Int(); at > 0 {
+			resetAt = time.Unix(at, 0)
+		} else if after := window.Get("reset_after_seconds").Int(); after > 0 {
+			resetAt = now.Add(time.Duration(after) * time.Second)
+		}
+		windows = append(windows, cliproxyauth.QuotaWindow{
+			Name:              name,
+			RemainingFraction: remainingFromUsedPercent(window.Get("used_percent").Float()),
+			ResetAt:           resetAt,
+		})
+	}
+	return windows
+}
+
+// parseCodexRateLimitHeaders reads the x-codex-{primary,secondary}-* response headers.
+func parseCodexRateLimitHeaders(headers http.Header, now time.Time) []cliproxyauth.QuotaWindow {
+	var windows []cliproxyauth.QuotaWindow
+	for _, name := range []string{"primary", "secondary"} {
+		rawUsed := strings.TrimSpace(headers.Get("X-Codex-" + name + "-Used-Percent"))
+		used, errParse := strconv.ParseFloat(rawUsed, 64)
+		if rawUsed == "" || errParse != nil {
+			continue
+		}
+		window := cliproxyauth.QuotaWindow{Name: name, RemainingFraction: remainingFromUsedPercent(used)}
+		if after, errAfter := strconv.ParseInt(strings.TrimSpace(headers.Get("X-Codex-"+name+"-Reset-After-Seconds")), 10, 64); errAfter == nil && after > 0 {
+			window.ResetAt = now.Add(time.Duration(after) * time.Second)
+		}
+		windows = append(windows, window)
+	}
+	return windows
+}
+
+func remainingFromUsedPercent(used float64) float64 {
+	return clampQuotaFraction(1 - used/100)
+}
+
+func clampQuotaFraction(fraction float64) float64 {
+	if math.IsNaN(fraction) {
+		return 0
+	}
+	return math.Max(0, math.Min(1, fraction))
+}
+
+// PollQuota reads the per-model request buckets of a Gemini CLI project. Polling only
+// uses the stored access token: a token refreshed here would live on the poller's copy
+// of the auth and be lost, so expired credentials are skipped until they are refreshed.
+func (e *GeminiCLIExecutor) PollQuota(ctx context.Context, auth *cliproxyauth.Auth) (*cliproxyauth.QuotaSnapshot, error) {
+	projectID := resolveGeminiProjectID(auth)
+	if projectID == "" {
+		return nil, nil
+	}
+	_, tok := geminiCLIStoredToken(geminiOAuthMetadata(auth))
+	if tok.AccessToken == "" || (!tok.Expiry.IsZero() && tok.Expiry.Before(time.Now().Add(quotaPollTokenSkew))) {
+		return nil, nil
+	}
+
+	payload, errMarshal := json.Marshal(map[string]string{"project": projectID})
+	if errMarshal != nil {
+		return nil, errMarshal
+	}
+	url := fmt.Sprintf("%s/%s:%s", codeAssistEndpoint, codeAssistVersion, geminiCLIRetrieveQuotaPath)
+	req, errReq := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if errReq != nil {
+		return nil, errReq
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+tok.AccessToken)
+	req.Header.Set("Accept", "application/json")
+	applyGeminiCLIHeaders(req, "")
+	body, _, errDo := doQuotaRequest(newHTTPClient(ctx, e.cfg, auth, 0), req)
+	if errDo != nil {
+		return nil, errDo
+	}
+	return &cliproxyauth.QuotaSnapshot{Windows: parseGeminiCLIQuotaBuckets(body)}, nil
+}
+
+// parseGeminiCLIQuotaBuckets reads the buckets of a retrieveUserQuota response.
+func parseGeminiCLIQuotaBuckets(body []byte) []cliproxyauth.QuotaWindow {
+	var windows []cliproxyauth.QuotaWindow
+	for _, bucket := range gjson.GetBytes(body, "buckets").Array() {
+		model := strings.TrimSpace(bucket.Get("modelId").String())
+		if model == "" || !bucket.Get("remainingFraction").Exists() {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSpace(bucket.Get("tokenType").String()))
+		if name == "" {
+			name = "requests"
+		}
+		windows = append(windows, cliproxyauth.QuotaWindow{
+			Name:              name,
+			Model:             model,
+			RemainingFraction: clampQuotaFraction(bucket.Get("remainingFraction").Float()),
+			ResetAt:           parseQuotaResetTime(bucket.Get("resetTime").String()),
+		})
+	}
+	return windows
+}
+
+// PollQuota reads the per-model quota reported alongside the Antigravity model list.
+// Like Gemini CLI, only a valid stored access token is used; expired credentials are skipped.
+func (e *AntigravityExecutor) PollQuota(ctx context.Context, auth *cliproxyauth.Auth) (*cliproxyauth.QuotaSnapshot, error) {
+	if auth == nil {
+		return nil, nil
+	}
+	token := metaStringValue(auth.Metadata, "access_token")
+	if token == "" || !tokenExpiry(auth.Metadata).After(time.Now().Add(quotaPollTokenSkew)) {
+		return nil, nil
+	}
+	body := map[string]string{}
+	if projectID := antigravityProjectIDFromAuth(auth); projectID != "" {
+		body["project"] = projectID
+	}
+	payload, errMarshal := json.Marshal(body)
+	if errMarshal != nil {
+		return nil, errMarshal
+	}
+	url := strings.TrimSuffix(antigravityLoadCodeAssistBaseURL(auth), "/") + antigravityAvailableModels
+	req, errReq := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if errReq != nil {
+		return nil, errReq
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", resolveUserAgent(auth))
+	respBody, _, errDo := doQuotaRequest(newAntigravityHTTPClient(ctx, e.cfg, auth, 0), req)
+	if errDo != nil {
+		return nil, errDo
+	}
+	return &cliproxyauth.QuotaSnapshot{Windows: parseAntigravityModelQuotas(respBody)}, nil
+}
+
+// parseAntigravityModelQuotas reads models.*.quotaInfo of a fetchAvailableModels
+// response. Upstream omits remainingFraction once a model is exhausted.
+func parseAntigravityModelQuotas(body []byte) []cliproxyauth.QuotaWindow {
+	var windows []cliproxyauth.QuotaWindow
+	gjson.GetBytes(body, "models").ForEach(func(key, value gjson.Result) bool {
+		quota := value.Get("quotaInfo")
+		if !quota.IsObject() {
+			return true
+		}
+		model := strings.TrimSpace(key.String())
+		if model == "" {
+			return true
+		}
+		windows = append(windows, cliproxyauth.QuotaWindow{
+			Name:              "requests",
+			Model:             model,
+			RemainingFraction: clampQuotaFraction(quota.Get("remainingFraction").Float()),
+			ResetAt:           parseQuotaResetTime(quota.Get("resetTime").String()),
+		})
+		return true
+	})
+	return windows
+}
+
+// PollQuota reads the coding plan usage of a Kimi credential.
+func (e *KimiExecutor) PollQuota(ctx context.Context, auth *cliproxyauth.Auth) (*cliproxyauth.QuotaSnapshot, error) {
+	token := kimiCreds(auth)
+	if strings.TrimSpace(token) == "" {
+		return nil, nil
+	}
+	req, errReq := http.NewRequestWithContext(ctx, http.MethodGet, kimiauth.KimiAPIBaseURL+kimiUsagesPath, nil)
+	if errReq != nil {
+		return nil, errReq
+	}
+	applyKimiHeadersWithAuth(req, token, false, auth)
+	body, _, errDo := doQuotaRequest(newHTTPClient(ctx, e.cfg, auth, 0), req)
+	if errDo != nil {
+		return nil, errDo
+	}
+	return &cliproxyauth.QuotaSnapshot{Windows: parseKimiUsageWindows(body)}, nil
+}
+
+// parseKimiUsageWindows reads the overall usage and the rolling limits of a usages response.
+func parseKimiUsageWindows(body []byte) []cliproxyauth.QuotaWindow {
+	var windows []cliproxyauth.QuotaWindow
+	if window, ok := kimiUsageWindow("usage", gjson.GetBytes(body, "usage")); ok {
+		windows = append(windows, window)
+	}
+	for _, limit := range gjson.GetBytes(body, "limits").Array() {
+		if window, ok := kimiUsageWindow(kimiLimitWindowName(limit.Get("window")), limit.Get("detail")); ok {
+			windows = append(windows, window)
+		}
+	}
+	return windows
+}
+
+func kimiUsageWindow(name string, detail gjson.Result) (cliproxyauth.QuotaWindow, bool) {
+	limit := detail.Get("limit").Float()
+	if !detail.IsObject() || limit <= 0 {
+		return cliproxyauth.QuotaWindow{}, false
+	}
+	remaining := limit - detail.Get("used").Float()
+	if value := detail.Get("remaining"); value.Exists() {
+		remaining = value.Float()
+	}
+	return cliproxyauth.QuotaWindow{
+		Name:              name,
+		RemainingFraction: clampQuotaFraction(remaining / limit),
+		ResetAt:           parseQuotaResetTime(detail.Get("resetTime").String()),
+	}, true
+}
+
+func kimiLimitWindowName(window gjson.Result) string {
+	duration := window.Get("duration").Int()
+	if duration <= 0 {
+		return "limit"
+	}
+	unit := strings.ToUpper(window.Get("timeUnit").String())
+	switch {
+	case strings.Contains(unit, "MINUTE"):
+		return fmt.Sprintf("%dm", duration)
+	case strings.Contains(unit, "HOUR"):
+		return fmt.Sprintf("%dh", duration)
+	case strings.Contains(unit, "DAY"):
+		return fmt.Sprintf("%dd", duration)
+	default:
+		return fmt.Sprintf("%ds", duration)
+	}
+}
+
+func parseQuotaResetTime(raw string) time.Time {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}
+	}
+	parsed, errParse := time.Parse(time.RFC3339Nano, raw)
+	if errParse != nil {
+		return time.Time{}
+	}
+	return parsed
+}
@@ -0,0 +1,113 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	cliproxyauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
+)
+
+func TestParseCodexUsageWindows(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	body := []byte(`{"plan_type":"plus","rate_limit":{"primary_window":{"used_percent":25,"reset_after_seconds":600},"secondary_window":{"used_percent":100,"reset_at":1700086400}}}`)
+
+	windows := parseCodexUsageWindows(body, now)
+	if len(windows) != 2 {
+		t.Fatalf("windows = %+v, want 2", windows)
+	}
+	if windows[0].Name != "primary" || windows[0].RemainingFraction != 0.75 || !windows[0].ResetAt.Equal(now.Add(10*time.Minute)) {
+		t.Fatalf("primary = %+v", windows[0])
+	}
+	if windows[1].Name != "secondary" || windows[1].RemainingFraction != 0 || windows[1].ResetAt.Unix() != 1700086400 {
+		t.Fatalf("secondary = %+v", windows[1])
+	}
+}
+
+func TestParseCodexRateLimitHeaders(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	headers := http.Header{}
+	headers.Set("X-Codex-Primary-Used-Percent", "90")
+	headers.Set("X-Codex-Primary-Reset-After-Seconds", "120")
+
+	windows := parseCodexRateLimitHeaders(headers, now)
+	if len(windows) != 1 {
+		t.Fatalf("windows = %+v, want only primary", windows)
+	}
+	if got := windows[0].RemainingFraction; got < 0.0999 || got > 0.1001 {
+		t.Fatalf("remaining = %v, want 0.1", got)
+	}
+	if !windows[0].ResetAt.Equal(now.Add(2 * time.Minute)) {
+		t.Fatalf("reset = %v", windows[0].ResetAt)
+	}
+}
+
+func TestParseGeminiCLIQuotaBuckets(t *testing.T) {
+	body := []byte(`{"buckets":[{"modelId":"gemini-2.5-pro","tokenType":"REQUESTS","remainingFraction":0.4,"resetTime":"2026-01-02T03:04:05Z"},{"modelId":"gemini-2.5-flash"}]}`)
+
+	windows := parseGeminiCLIQuotaBuckets(body)
+	if len(windows) != 1 {
+		t.Fatalf("windows = %+v, want 1", windows)
+	}
+	want := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if w := windows[0]; w.Name != "requests" || w.Model != "gemini-2.5-pro" || w.RemainingFraction != 0.4 || !w.ResetAt.Equal(want) {
+		t.Fatalf("window = %+v", w)
+	}
+}
+
+func TestParseAntigravityModelQuotas_ExhaustedModelOmitsFraction(t *testing.T) {
+	body := []byte(`{"models":{"claude-sonnet-4-5":{"quotaInfo":{"resetTime":"2026-01-02T03:04:05Z"}},"gemini-3-pro":{"quotaInfo":{"remainingFraction":1}},"no-quota":{}}}`)
+
+	windows := parseAntigravityModelQuotas(body)
+	if len(windows) != 2 {
+		t.Fatalf("windows = %+v, want 2", windows)
+	}
+	byModel := map[string]float64{}
+	for _, w := range windows {
+		byModel[w.Model] = w.RemainingFraction
+	}
+	if byModel["claude-sonnet-4-5"] != 0 || byModel["gemini-3-pro"] != 1 {
+		t.Fatalf("fractions = %v", byModel)
+	}
+}
+
+func TestParseKimiUsageWindows(t *testing.T) {
+	body := []byte(`{"usage":{"limit":"100","used":"40","resetTime":"2026-01-02T03:04:05Z"},"limits":[{"window":{"duration":300,"timeUnit":"TIME_UNIT_MINUTE"},"detail":{"limit":"50","remaining":"5"}}]}`)
+
+	windows := parseKimiUsageWindows(body)
+	if len(windows) != 2 {
+		t.Fatalf("windows = %+v, want 2", windows)
+	}
+	if w := windows[0]; w.Name != "usage" || w.RemainingFraction != 0.6 || w.ResetAt.IsZero() {
+		t.Fatalf("usage window = %+v", w)
+	}
+	if w := windows[1]; w.Name != "300m" || w.RemainingFraction != 0.1 {
+		t.Fatalf("limit window = %+v", w)
+	}
+}
+
+func TestPollQuota_SkipsExpiredOAuthTokens(t *testing.T) {
+	expired := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	geminiAuth := &cliproxyauth.Auth{ID: "gemini-cli-expired", Metadata: map[string]any{
+		"project_id": "project-1",
+		"token":      map[string]any{"access_token": "stale", "refresh_token": "refresh", "expiry": expired},
+	}}
+	snapshot, err := (&GeminiCLIExecutor{}).PollQuota(context.Background(), geminiAuth)
+	if snapshot != nil || err != nil {
+		t.Fatalf("gemini-cli PollQuota = %+v, %v; want skipped", snapshot, err)
+	}
+	if token := geminiAuth.Metadata["token"].(map[string]any)["access_token"]; token != "stale" {
+		t.Fatalf("gemini-cli token changed to %v on the polled copy", token)
+	}
+
+	antigravityAuth := &cliproxyauth.Auth{ID: "antigravity-expired", Metadata: map[string]any{
+		"access_token":  "stale",
+		"refresh_token": "refresh",
+		"expired":       expired,
+	}}
+	snapshot, err = (&AntigravityExecutor{}).PollQuota(context.Background(), antigravityAuth)
+	if snapshot != nil || err != nil {
+		t.Fatalf("antigravity PollQuota = %+v, %v; want skipped", snapshot, err)
+	}
+}
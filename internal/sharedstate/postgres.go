@@ -0,0 +1,243 @@
+package sharedstate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/home"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultPostgresTable = "shared_state"
+	postgresPurgeEvery   = time.Minute
+	// postgresLive restricts queries to keys that have not expired.
+	postgresLive = "(expires_at IS NULL OR expires_at > NOW())"
+)
+
+// PostgresStoreConfig captures the connection settings for the Postgres store.
+type PostgresStoreConfig struct {
+	DSN    string
+	Schema string
+	Table  string
+}
+
+// PostgresStore keeps shared state in a PostgreSQL table. Expired rows are
+// ignored by every query and purged in the background.
+type PostgresStore struct {
+	db        *sql.DB
+	cfg       PostgresStoreConfig
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewPostgresStore connects to PostgreSQL and ensures the state table exists.
+func NewPostgresStore(ctx context.Context, cfg PostgresStoreConfig) (*PostgresStore, error) {
+	cfg.DSN = strings.TrimSpace(cfg.DSN)
+	if cfg.DSN == "" {
+		return nil, fmt.Errorf("shared state: postgres DSN is required")
+	}
+	cfg.Schema = strings.TrimSpace(cfg.Schema)
+	cfg.Table = strings.TrimSpace(cfg.Table)
+	if cfg.Table == "" {
+		cfg.Table = defaultPostgresTable
+	}
+	db, err := sql.Open("pgx", cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("shared state: open database connection: %w", err)
+	}
+	if err = db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("shared state: ping database: %w", err)
+	}
+	store := &PostgresStore{db: db, cfg: cfg, stop: make(chan struct{})}
+	if err = store.ensureSchema(ctx); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	go store.purgeLoop()
+	return store, nil
+}
+
+func (s *PostgresStore) ensureSchema(ctx context.Context) error {
+	if s.cfg.Schema != "" {
+		if _, err := s.db.ExecContext(ctx, fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", quoteIdentifier(s.cfg.Schema))); err != nil {
+			return fmt.Errorf("shared state: create schema: %w", err)
+		}
+	}
+	if _, err := s.db.ExecContext(ctx, fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			key TEXT PRIMARY KEY,
+			value BYTEA NOT NULL,
+			expires_at TIMESTAMPTZ
+		)
+	`, s.tableName())); err != nil {
+		return fmt.Errorf("shared state: create table: %w", err)
+	}
+	index := quoteIdentifier(s.cfg.Table + "_expires_at_idx")
+	if _, err := s.db.ExecContext(ctx, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (expires_at)", index, s.tableName())); err != nil {
+		return fmt.Errorf("shared state: create expiry index: %w", err)
+	}
+	return nil
+}
+
+// KVGet returns the value stored at key.
+func (s *PostgresStore) KVGet(ctx context.Context, key string) ([]byte, bool, error) {
+	var value []byte
+	query := fmt.Sprintf("SELECT value FROM %s WHERE key = $1 AND %s", s.tableName(), postgresLive)
+	err := s.db.QueryRowContext(ctx, query, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// KVSet stores value at key and reports whether it was written under the NX/XX conditions.
+// Expiry is computed by the database clock so replicas with skewed clocks agree.
+func (s *PostgresStore) KVSet(ctx context.Context, key string, value []byte, opts home.KVSetOptions) (bool, error) {
+	if opts.NX && opts.XX {
+		return false, fmt.Errorf("shared state: NX and XX are mutually exclusive")
+	}
+	ttl := opts.EX
+	if opts.PX > 0 {
+		ttl = opts.PX
+	}
+	expires := "CASE WHEN $3::BIGINT > 0 THEN NOW() + $3::BIGINT * INTERVAL '1 millisecond' END"
+	var query string
+	switch {
+	case opts.XX:
+		query = fmt.Sprintf("UPDATE %s SET value = $2, expires_at = %s WHERE key = $1 AND %s", s.tableName(), expires, postgresLive)
+	case opts.NX:
+		query = fmt.Sprintf(`
+			INSERT INTO %s AS t (key, value, expires_at) VALUES ($1, $2, %s)
+			ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at
+			WHERE t.expires_at IS NOT NULL AND t.expires_at <= NOW()
+		`, s.tableName(), expires)
+	default:
+		query = fmt.Sprintf(`
+			INSERT INTO %s (key, value, expires_at) VALUES ($1, $2, %s)
+			ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at
+		`, s.tableName(), expires)
+	}
+	result, err := s.db.ExecContext(ctx, query, key, value, ttl.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// KVSetNX stores value at key only when the key does not exist.
+func (s *PostgresStore) KVSetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	return s.KVSet(ctx, key, value, home.KVSetOptions{NX: true, EX: ttl})
+}
+
+// KVDel removes keys and returns how many were live.
+func (s *PostgresStore) KVDel(ctx context.Context, keys ...string) (int64, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	placeholders := make([]string, len(keys))
+	args := make([]any, len(keys))
+	for i, key := range keys {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = key
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE key IN (%s) AND %s", s.tableName(), strings.Join(placeholders, ", "), postgresLive)
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// KVExpire resets the time to live of key. A non-positive ttl deletes it.
+func (s *PostgresStore) KVExpire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	if ttl <= 0 {
+		deleted, err := s.KVDel(ctx, key)
+		return deleted > 0, err
+	}
+	query := fmt.Sprintf("UPDATE %s SET expires_at = NOW() + $2::BIGINT * INTERVAL '1 millisecond' WHERE key = $1 AND %s", s.tableName(), postgresLive)
+	result, err := s.db.ExecContext(ctx, query, key, ttl.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// KVScan returns every live key starting with prefix together with its value.
+func (s *PostgresStore) KVScan(ctx context.Context, prefix string) (map[string][]byte, error) {
+	query := fmt.Sprintf(`SELECT key, value FROM %s WHERE key LIKE $1 ESCAPE '\' AND %s`, s.tableName(), postgresLive)
+	rows, err := s.db.QueryContext(ctx, query, escapeLike(prefix)+"%")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make(map[string][]byte)
+	for rows.Next() {
+		var (
+			key   string
+			value []byte
+		)
+		if err = rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		out[key] = value
+	}
+	return out, rows.Err()
+}
+
+// Close stops the purge loop and releases the connection pool.
+func (s *PostgresStore) Close() error {
+	s.closeOnce.Do(func() { close(s.stop) })
+	return s.db.Close()
+}
+
+func (s *PostgresStore) purgeLoop() {
+	ticker := time.NewTicker(postgresPurgeEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		query := fmt.Sprintf("DELETE FROM %s WHERE expires_at IS NOT NULL AND expires_at <= NOW()", s.tableName())
+		if _, err := s.db.ExecContext(ctx, query); err != nil {
+			log.Warnf("shared state: purge expired keys: %v", err)
+		}
+		cancel()
+	}
+}
+
+func (s *PostgresStore) tableName() string {
+	if s.cfg.Schema == "" {
+		return quoteIdentifier(s.cfg.Table)
+	}
+	return quoteIdentifier(s.cfg.Schema) + "." + quoteIdentifier(s.cfg.Table)
+}
+
+func quoteIdentifier(identifier string) string {
+	return "\"" + strings.ReplaceAll(identifier, "\"", "\"\"") + "\""
+}
+
+func escapeLike(pattern string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(pattern)
+}
@@ -0,0 +1,144 @@
+package sharedstate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/home"
+)
+
+// redisScanBatch bounds the keys requested per SCAN and MGET round trip.
+const redisScanBatch = 256
+
+// RedisStore keeps shared state in a Redis-protocol server.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore connects to the server at rawURL (redis:// or rediss://).
+func NewRedisStore(ctx context.Context, rawURL string) (*RedisStore, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return nil, fmt.Errorf("shared state: redis URL is required")
+	}
+	options, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("shared state: parse redis URL: %w", err)
+	}
+	return newRedisStore(ctx, options)
+}
+
+func newRedisStore(ctx context.Context, options *redis.Options) (*RedisStore, error) {
+	options.ContextTimeoutEnabled = true
+	client := redis.NewClient(options)
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("shared state: ping redis: %w", err)
+	}
+	return &RedisStore{client: client}, nil
+}
+
+// KVGet returns the value stored at key.
+func (s *RedisStore) KVGet(ctx context.Context, key string) ([]byte, bool, error) {
+	raw, err := s.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return raw, true, nil
+}
+
+// KVSet stores value at key and reports whether it was written under the NX/XX conditions.
+func (s *RedisStore) KVSet(ctx context.Context, key string, value []byte, opts home.KVSetOptions) (bool, error) {
+	if opts.NX && opts.XX {
+		return false, fmt.Errorf("shared state: NX and XX are mutually exclusive")
+	}
+	args := redis.SetArgs{TTL: opts.EX}
+	if opts.PX > 0 {
+		args.TTL = opts.PX
+	}
+	switch {
+	case opts.NX:
+		args.Mode = "NX"
+	case opts.XX:
+		args.Mode = "XX"
+	}
+	err := s.client.SetArgs(ctx, key, value, args).Err()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// KVSetNX stores value at key only when the key does not exist.
+func (s *RedisStore) KVSetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	return s.KVSet(ctx, key, value, home.KVSetOptions{NX: true, EX: ttl})
+}
+
+// KVDel removes keys and returns how many existed.
+func (s *RedisStore) KVDel(ctx context.Context, keys ...string) (int64, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	return s.client.Del(ctx, keys...).Result()
+}
+
+// KVExpire resets the time to live of key.
+func (s *RedisStore) KVExpire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return s.client.Expire(ctx, key, ttl).Result()
+}
+
+// KVScan returns every key starting with prefix together with its value.
+func (s *RedisStore) KVScan(ctx context.Context, prefix string) (map[string][]byte, error) {
+	out := make(map[string][]byte)
+	match := escapeRedisGlob(prefix) + "*"
+	var cursor uint64
+	for {
+		keys, next, err := s.client.Scan(ctx, cursor, match, redisScanBatch).Result()
+		if err != nil {
+			return nil, err
+		}
+		if len(keys) > 0 {
+			values, errGet := s.client.MGet(ctx, keys...).Result()
+			if errGet != nil {
+				return nil, errGet
+			}
+			for i, value := range values {
+				// Keys expiring between SCAN and MGET come back as nil.
+				if text, ok := value.(string); ok {
+					out[keys[i]] = []byte(text)
+				}
+			}
+		}
+		if next == 0 {
+			return out, nil
+		}
+		cursor = next
+	}
+}
+
+// Close releases the connection pool.
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
+
+func escapeRedisGlob(pattern string) string {
+	var b strings.Builder
+	for _, r := range pattern {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
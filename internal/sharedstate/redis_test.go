@@ -0,0 +1,327 @@
+package sharedstate
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/home"
+)
+
+// fakeRedis is a minimal in-memory Redis-protocol server covering the commands
+// used by RedisStore.
+type fakeRedis struct {
+	mu      sync.Mutex
+	values  map[string]string
+	expires map[string]time.Time
+}
+
+func startFakeRedis(t *testing.T) string {
+	t.Helper()
+	listener, errListen := net.Listen("tcp", "127.0.0.1:0")
+	if errListen != nil {
+		t.Fatalf("listen: %v", errListen)
+	}
+	server := &fakeRedis{values: make(map[string]string), expires: make(map[string]time.Time)}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			conn, errAccept := listener.Accept()
+			if errAccept != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	t.Cleanup(func() {
+		_ = listener.Close()
+		<-done
+	})
+	return listener.Addr().String()
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	reader := bufio.NewReader(conn)
+	for {
+		args, errRead := readCommand(reader)
+		if errRead != nil {
+			return
+		}
+		if _, errWrite := io.WriteString(conn, f.handle(args)); errWrite != nil {
+			return
+		}
+	}
+}
+
+func (f *fakeRedis) liveLocked(key string) (string, bool) {
+	value, ok := f.values[key]
+	if !ok {
+		return "", false
+	}
+	if expires, has := f.expires[key]; has && !time.Now().Before(expires) {
+		delete(f.values, key)
+		delete(f.expires, key)
+		return "", false
+	}
+	return value, true
+}
+
+func (f *fakeRedis) handle(args []string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "HELLO":
+		return "-ERR unknown command 'HELLO'\r\n"
+	case "CLIENT", "SELECT":
+		return "+OK\r\n"
+	case "GET":
+		if value, ok := f.liveLocked(args[1]); ok {
+			return bulk(value)
+		}
+		return "$-1\r\n"
+	case "SET":
+		key, value := args[1], args[2]
+		var ttl time.Duration
+		nx, xx := false, false
+		for i := 3; i < len(args); i++ {
+			switch strings.ToUpper(args[i]) {
+			case "EX":
+				n, _ := strconv.Atoi(args[i+1])
+				ttl = time.Duration(n) * time.Second
+				i++
+			case "PX":
+				n, _ := strconv.Atoi(args[i+1])
+				ttl = time.Duration(n) * time.Millisecond
+				i++
+			case "NX":
+				nx = true
+			case "XX":
+				xx = true
+			}
+		}
+		_, exists := f.liveLocked(key)
+		if (nx && exists) || (xx && !exists) {
+			return "$-1\r\n"
+		}
+		f.values[key] = value
+		delete(f.expires, key)
+		if ttl > 0 {
+			f.expires[key] = time.Now().Add(ttl)
+		}
+		return "+OK\r\n"
+	case "DEL":
+		deleted := 0
+		for _, key := range args[1:] {
+			if _, ok := f.liveLocked(key); ok {
+				delete(f.values, key)
+				delete(f.expires, key)
+				deleted++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", deleted)
+	case "EXPIRE":
+		if _, ok := f.liveLocked(args[1]); !ok {
+			return ":0\r\n"
+		}
+		n, _ := strconv.Atoi(args[2])
+		f.expires[args[1]] = time.Now().Add(time.Duration(n) * time.Second)
+		return ":1\r\n"
+	case "SCAN":
+		pattern := "*"
+		for i := 2; i+1 < len(args); i += 2 {
+			if strings.ToUpper(args[i]) == "MATCH" {
+				pattern = args[i+1]
+			}
+		}
+		var keys []string
+		for key := range f.values {
+			if _, ok := f.liveLocked(key); !ok {
+				continue
+			}
+			if matched, _ := path.Match(pattern, key); matched {
+				keys = append(keys, key)
+			}
+		}
+		var b strings.Builder
+		b.WriteString("*2\r\n")
+		b.WriteString(bulk("0"))
+		fmt.Fprintf(&b, "*%d\r\n", len(keys))
+		for _, key := range keys {
+			b.WriteString(bulk(key))
+		}
+		return b.String()
+	case "MGET":
+		var b strings.Builder
+		fmt.Fprintf(&b, "*%d\r\n", len(args)-1)
+		for _, key := range args[1:] {
+			if value, ok := f.liveLocked(key); ok {
+				b.WriteString(bulk(value))
+			} else {
+				b.WriteString("$-1\r\n")
+			}
+		}
+		return b.String()
+	}
+	return fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+}
+
+func bulk(value string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, errRead := reader.ReadString('\n')
+	if errRead != nil {
+		return nil, errRead
+	}
+	count, errCount := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(line), "*"))
+	if errCount != nil {
+		return nil, errCount
+	}
+	args := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		header, errHeader := reader.ReadString('\n')
+		if errHeader != nil {
+			return nil, errHeader
+		}
+		size, errSize := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(header), "$"))
+		if errSize != nil {
+			return nil, errSize
+		}
+		payload := make([]byte, size+2)
+		if _, errFull := io.ReadFull(reader, payload); errFull != nil {
+			return nil, errFull
+		}
+		args = append(args, string(payload[:size]))
+	}
+	return args, nil
+}
+
+func newTestRedisStore(t *testing.T) *RedisStore {
+	t.Helper()
+	store, err := newRedisStore(context.Background(), &redis.Options{
+		Addr:            startFakeRedis(t),
+		Protocol:        2,
+		DisableIdentity: true,
+		MaxRetries:      -1,
+	})
+	if err != nil {
+		t.Fatalf("newRedisStore: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestRedisStore_KVOperations(t *testing.T) {
+	ctx := context.Background()
+	store := newTestRedisStore(t)
+
+	if _, found, err := store.KVGet(ctx, "missing"); err != nil || found {
+		t.Fatalf("KVGet missing = %v, %v", found, err)
+	}
+	if written, err := store.KVSet(ctx, "cpa:a", []byte("1"), home.KVSetOptions{EX: time.Minute}); err != nil || !written {
+		t.Fatalf("KVSet = %v, %v", written, err)
+	}
+	if written, err := store.KVSetNX(ctx, "cpa:a", []byte("2"), time.Minute); err != nil || written {
+		t.Fatalf("KVSetNX on existing key = %v, %v", written, err)
+	}
+	if written, err := store.KVSet(ctx, "cpa:missing", []byte("x"), home.KVSetOptions{XX: true}); err != nil || written {
+		t.Fatalf("KVSet XX on missing key = %v, %v", written, err)
+	}
+	if value, found, err := store.KVGet(ctx, "cpa:a"); err != nil || !found || string(value) != "1" {
+		t.Fatalf("KVGet = %q, %v, %v", value, found, err)
+	}
+	if _, err := store.KVSet(ctx, "cpa:b", []byte("2"), home.KVSetOptions{}); err != nil {
+		t.Fatalf("KVSet b: %v", err)
+	}
+	if _, err := store.KVSet(ctx, "other:c", []byte("3"), home.KVSetOptions{}); err != nil {
+		t.Fatalf("KVSet c: %v", err)
+	}
+
+	entries, err := store.KVScan(ctx, "cpa:")
+	if err != nil {
+		t.Fatalf("KVScan: %v", err)
+	}
+	if len(entries) != 2 || string(entries["cpa:a"]) != "1" || string(entries["cpa:b"]) != "2" {
+		t.Fatalf("KVScan = %v", entries)
+	}
+
+	if ok, err := store.KVExpire(ctx, "cpa:b", time.Minute); err != nil || !ok {
+		t.Fatalf("KVExpire = %v, %v", ok, err)
+	}
+	if deleted, err := store.KVDel(ctx, "cpa:a", "cpa:missing"); err != nil || deleted != 1 {
+		t.Fatalf("KVDel = %d, %v", deleted, err)
+	}
+	if _, found, _ := store.KVGet(ctx, "cpa:a"); found {
+		t.Fatal("deleted key still present")
+	}
+}
+
+func TestRedisStore_KeysExpire(t *testing.T) {
+	ctx := context.Background()
+	store := newTestRedisStore(t)
+
+	if _, err := store.KVSet(ctx, "short", []byte("v"), home.KVSetOptions{PX: 20 * time.Millisecond}); err != nil {
+		t.Fatalf("KVSet: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if _, found, err := store.KVGet(ctx, "short"); err != nil || found {
+		t.Fatalf("expired key found = %v, %v", found, err)
+	}
+	if written, err := store.KVSetNX(ctx, "short", []byte("again"), time.Minute); err != nil || !written {
+		t.Fatalf("KVSetNX after expiry = %v, %v", written, err)
+	}
+}
+
+func TestApply_NamespacesKeysWithPrefix(t *testing.T) {
+	addr := startFakeRedis(t)
+	t.Cleanup(Close)
+
+	err := Apply(config.SharedStateConfig{
+		Enable:    true,
+		RedisURL:  "redis://" + addr + "/0?protocol=2",
+		KeyPrefix: "prod:",
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	store := Current()
+	if store == nil {
+		t.Fatal("expected a shared store after Apply")
+	}
+	ctx := context.Background()
+	if _, err = store.KVSet(ctx, "cpa:x", []byte("1"), home.KVSetOptions{}); err != nil {
+		t.Fatalf("KVSet: %v", err)
+	}
+	entries, err := store.KVScan(ctx, "cpa:")
+	if err != nil || len(entries) != 1 || string(entries["cpa:x"]) != "1" {
+		t.Fatalf("KVScan = %v, %v", entries, err)
+	}
+
+	raw, err := NewRedisStore(ctx, "redis://"+addr+"/0?protocol=2")
+	if err != nil {
+		t.Fatalf("NewRedisStore: %v", err)
+	}
+	defer func() { _ = raw.Close() }()
+	if _, found, _ := raw.KVGet(ctx, "prod:cpa:x"); !found {
+		t.Fatal("prefixed key not stored under the namespace")
+	}
+
+	if err = Apply(config.SharedStateConfig{}); err != nil || Current() != nil {
+		t.Fatalf("disable: store %v, err %v", Current(), err)
+	}
+}
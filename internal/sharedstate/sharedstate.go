@@ -0,0 +1,205 @@
+// Package sharedstate provides the key-value store that replicas use to share
+// request-time state such as model cooldowns, session affinity bindings and
+// signature caches when the Home control plane is not connected.
+package sharedstate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/home"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// BackendRedis stores state in a Redis-protocol server.
+	BackendRedis = "redis"
+	// BackendPostgres stores state in a PostgreSQL table.
+	BackendPostgres = "postgres"
+
+	openStoreTimeout = 15 * time.Second
+)
+
+// Store is a key-value store with expiring keys. Its KV methods match the Home
+// KV client so caches can use either one through the same interface.
+type Store interface {
+	KVGet(ctx context.Context, key string) ([]byte, bool, error)
+	KVSet(ctx context.Context, key string, value []byte, opts home.KVSetOptions) (bool, error)
+	KVSetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	KVDel(ctx context.Context, keys ...string) (int64, error)
+	KVExpire(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// KVScan returns every live key starting with prefix together with its value.
+	KVScan(ctx context.Context, prefix string) (map[string][]byte, error)
+	Close() error
+}
+
+// KV is the key-value subset shared by the Home KV client and Store.
+type KV interface {
+	KVGet(ctx context.Context, key string) ([]byte, bool, error)
+	KVSet(ctx context.Context, key string, value []byte, opts home.KVSetOptions) (bool, error)
+	KVDel(ctx context.Context, keys ...string) (int64, error)
+	KVExpire(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+type storeHolder struct {
+	store Store
+}
+
+var (
+	applyMu    sync.Mutex
+	currentCfg config.SharedStateConfig
+	current    atomic.Pointer[storeHolder]
+)
+
+// Current returns the active shared store, or nil when shared state is disabled.
+func Current() Store {
+	if holder := current.Load(); holder != nil {
+		return holder.store
+	}
+	return nil
+}
+
+// CurrentKV resolves the key-value store caches share state through. The Home KV
+// client takes precedence when the Home control plane is configured, reporting
+// its connection error if it is unavailable; otherwise the shared store is used.
+// shared is false when neither is configured.
+func CurrentKV() (kv KV, shared bool, err error) {
+	if client, homeMode, errClient := home.CurrentKVClient(); homeMode {
+		if errClient != nil {
+			return nil, true, errClient
+		}
+		return client, true, nil
+	}
+	if store := Current(); store != nil {
+		return store, true, nil
+	}
+	return nil, false, nil
+}
+
+// SetCurrent installs store as the active shared store, closing the previous one.
+// A nil store disables shared state.
+func SetCurrent(store Store) {
+	var holder *storeHolder
+	if store != nil {
+		holder = &storeHolder{store: store}
+	}
+	if previous := current.Swap(holder); previous != nil && previous.store != nil && previous.store != store {
+		if errClose := previous.store.Close(); errClose != nil {
+			log.Warnf("shared state: close previous store: %v", errClose)
+		}
+	}
+}
+
+// Apply (re)configures the shared store. The store is only reopened when the
+// connection settings change.
+func Apply(cfg config.SharedStateConfig) error {
+	cfg.Backend = strings.ToLower(strings.TrimSpace(cfg.Backend))
+	if cfg.Backend == "" {
+		cfg.Backend = BackendRedis
+	}
+
+	applyMu.Lock()
+	defer applyMu.Unlock()
+	if cfg.Enable && Current() != nil && sameStorage(currentCfg, cfg) {
+		currentCfg = cfg
+		return nil
+	}
+	SetCurrent(nil)
+	currentCfg = config.SharedStateConfig{}
+	if !cfg.Enable {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), openStoreTimeout)
+	defer cancel()
+	store, err := openStore(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if prefix := strings.TrimSpace(cfg.KeyPrefix); prefix != "" {
+		store = &prefixedStore{Store: store, prefix: prefix}
+	}
+	SetCurrent(store)
+	currentCfg = cfg
+	log.Infof("shared state enabled (backend=%s)", cfg.Backend)
+	return nil
+}
+
+// Close releases the active shared store.
+func Close() {
+	applyMu.Lock()
+	defer applyMu.Unlock()
+	SetCurrent(nil)
+	currentCfg = config.SharedStateConfig{}
+}
+
+func sameStorage(a, b config.SharedStateConfig) bool {
+	return a.Backend == b.Backend &&
+		strings.TrimSpace(a.RedisURL) == strings.TrimSpace(b.RedisURL) &&
+		strings.TrimSpace(a.PostgresDSN) == strings.TrimSpace(b.PostgresDSN) &&
+		strings.TrimSpace(a.PostgresSchema) == strings.TrimSpace(b.PostgresSchema) &&
+		strings.TrimSpace(a.PostgresTable) == strings.TrimSpace(b.PostgresTable) &&
+		strings.TrimSpace(a.KeyPrefix) == strings.TrimSpace(b.KeyPrefix)
+}
+
+func openStore(ctx context.Context, cfg config.SharedStateConfig) (Store, error) {
+	switch cfg.Backend {
+	case BackendRedis:
+		return NewRedisStore(ctx, cfg.RedisURL)
+	case BackendPostgres:
+		return NewPostgresStore(ctx, PostgresStoreConfig{
+			DSN:    cfg.PostgresDSN,
+			Schema: cfg.PostgresSchema,
+			Table:  cfg.PostgresTable,
+		})
+	default:
+		return nil, fmt.Errorf("shared state: unsupported backend %q", cfg.Backend)
+	}
+}
+
+// prefixedStore namespaces every key of the wrapped store.
+type prefixedStore struct {
+	Store
+	prefix string
+}
+
+func (s *prefixedStore) KVGet(ctx context.Context, key string) ([]byte, bool, error) {
+	return s.Store.KVGet(ctx, s.prefix+key)
+}
+
+func (s *prefixedStore) KVSet(ctx context.Context, key string, value []byte, opts home.KVSetOptions) (bool, error) {
+	return s.Store.KVSet(ctx, s.prefix+key, value, opts)
+}
+
+func (s *prefixedStore) KVSetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	return s.Store.KVSetNX(ctx, s.prefix+key, value, ttl)
+}
+
+func (s *prefixedStore) KVDel(ctx context.Context, keys ...string) (int64, error) {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = s.prefix + key
+	}
+	return s.Store.KVDel(ctx, prefixed...)
+}
+
+func (s *prefixedStore) KVExpire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return s.Store.KVExpire(ctx, s.prefix+key, ttl)
+}
+
+func (s *prefixedStore) KVScan(ctx context.Context, prefix string) (map[string][]byte, error) {
+	entries, err := s.Store.KVScan(ctx, s.prefix+prefix)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string][]byte, len(entries))
+	for key, value := range entries {
+		out[strings.TrimPrefix(key, s.prefix)] = value
+	}
+	return out, nil
+}
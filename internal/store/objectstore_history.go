@@ -0,0 +1,133 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/confighistory"
+)
+
+const objectStoreConfigHistoryPrefix = "config-history"
+
+// ConfigHistory returns the config history backend kept under the config-history/ prefix of the
+// bucket, shared by every replica using it.
+func (s *ObjectTokenStore) ConfigHistory() confighistory.Backend {
+	if s == nil || s.client == nil {
+		return nil
+	}
+	return objectConfigHistory{store: s}
+}
+
+// objectConfigHistory stores each config revision as <id>.yaml with <id>.json metadata. The
+// metadata is written last, so a revision is only listed once its content is in the bucket.
+type objectConfigHistory struct {
+	store *ObjectTokenStore
+}
+
+func (h objectConfigHistory) key(id int64, suffix string) string {
+	return fmt.Sprintf("%s/%06d%s", objectStoreConfigHistoryPrefix, id, suffix)
+}
+
+func (h objectConfigHistory) ListRevisions(ctx context.Context) ([]confighistory.Revision, error) {
+	prefix := h.store.prefixedKey(objectStoreConfigHistoryPrefix + "/")
+	objectCh := h.store.client.ListObjects(ctx, h.store.cfg.Bucket, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	})
+	var revisions []confighistory.Revision
+	for object := range objectCh {
+		if object.Err != nil {
+			return nil, fmt.Errorf("object store: list config history: %w", object.Err)
+		}
+		name := strings.TrimPrefix(object.Key, prefix)
+		if !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		if _, errID := strconv.ParseInt(strings.TrimSuffix(name, ".json"), 10, 64); errID != nil {
+			continue
+		}
+		data, errRead := h.read(ctx, object.Key)
+		if errRead != nil {
+			if isObjectNotFound(errRead) {
+				continue
+			}
+			return nil, fmt.Errorf("object store: read config revision %s: %w", object.Key, errRead)
+		}
+		var rev confighistory.Revision
+		if json.Unmarshal(data, &rev) != nil || rev.ID <= 0 {
+			continue
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, nil
+}
+
+func (h objectConfigHistory) GetRevision(ctx context.Context, id int64) (confighistory.Revision, []byte, error) {
+	var rev confighistory.Revision
+	meta, err := h.read(ctx, h.store.prefixedKey(h.key(id, ".json")))
+	if err != nil {
+		if isObjectNotFound(err) {
+			return rev, nil, confighistory.ErrNotFound
+		}
+		return rev, nil, fmt.Errorf("object store: read config revision: %w", err)
+	}
+	if err = json.Unmarshal(meta, &rev); err != nil {
+		return rev, nil, fmt.Errorf("object store: decode config revision %d: %w", id, err)
+	}
+	data, err := h.read(ctx, h.store.prefixedKey(h.key(id, ".yaml")))
+	if err != nil {
+		if isObjectNotFound(err) {
+			return rev, nil, confighistory.ErrNotFound
+		}
+		return rev, nil, fmt.Errorf("object store: read config revision: %w", err)
+	}
+	return rev, data, nil
+}
+
+// PutRevision checks the metadata object before writing. Object stores offer no portable
+// create-only write, so two replicas recording the same ID at the same moment may still race;
+// the later write wins.
+func (h objectConfigHistory) PutRevision(ctx context.Context, rev confighistory.Revision, data []byte) error {
+	metaKey := h.key(rev.ID, ".json")
+	_, err := h.store.client.StatObject(ctx, h.store.cfg.Bucket, h.store.prefixedKey(metaKey), minio.StatObjectOptions{})
+	switch {
+	case err == nil:
+		return confighistory.ErrRevisionExists
+	case !isObjectNotFound(err):
+		return fmt.Errorf("object store: stat config revision: %w", err)
+	}
+	meta, err := json.Marshal(rev)
+	if err != nil {
+		return fmt.Errorf("object store: encode config revision: %w", err)
+	}
+	if err = h.store.putObject(ctx, h.key(rev.ID, ".yaml"), data, "application/x-yaml"); err != nil {
+		return err
+	}
+	return h.store.putObject(ctx, metaKey, meta, "application/json")
+}
+
+func (h objectConfigHistory) DeleteRevisions(ctx context.Context, ids ...int64) error {
+	for _, id := range ids {
+		if err := h.store.deleteObject(ctx, h.key(id, ".json")); err != nil {
+			return err
+		}
+		if err := h.store.deleteObject(ctx, h.key(id, ".yaml")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h objectConfigHistory) read(ctx context.Context, key string) ([]byte, error) {
+	object, err := h.store.client.GetObject(ctx, h.store.cfg.Bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+	return io.ReadAll(object)
+}
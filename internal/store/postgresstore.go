@@ -14,15 +14,19 @@ import (
 	"time"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/authcrypt"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/misc"
 	cliproxyauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
 	log "github.com/sirupsen/logrus"
 )
 
 const (
-	defaultConfigTable = "config_store"
-	defaultAuthTable   = "auth_store"
-	defaultConfigKey   = "config"
+	defaultConfigTable        = "config_store"
+	defaultAuthTable          = "auth_store"
+	defaultConfigHistoryTable = "config_history"
+	defaultConfigKey          = "config"
+	// defaultNotifyChannel is the LISTEN/NOTIFY channel used to broadcast row changes to replicas.
+	defaultNotifyChannel = "cliproxy_store_changes"
 )
 
 // PostgresStoreConfig captures configuration required to initialize a Postgres-backed store.
@@ -32,6 +36,11 @@ type PostgresStoreConfig struct {
 	ConfigTable string
 	AuthTable   string
 	SpoolDir    string
+	// ConfigHistoryTable holds the config revisions recorded by the management API.
+	ConfigHistoryTable string
+	// NotifyChannel is the channel on which row changes are published; replicas sharing the
+	// database listen on it to mirror each other's updates.
+	NotifyChannel string
 }
 
 // PostgresStore persists configuration and authentication metadata using PostgreSQL as backend
@@ -43,6 +52,12 @@ type PostgresStore struct {
 	configPath string
 	authDir    string
 	mu         sync.Mutex
+	// revisions holds the auth row revisions last mirrored to or written from this replica, keyed
+	// by relative auth ID. It is guarded by mu.
+	revisions map[string]int64
+	// configRevision is the config row revision last mirrored to or written from this replica,
+	// zero when none is known. It is guarded by mu.
+	configRevision int64
 }
 
 // NewPostgresStore establishes a connection to PostgreSQL and prepares the local workspace.
@@ -58,6 +73,12 @@ func NewPostgresStore(ctx context.Context, cfg PostgresStoreConfig) (*PostgresSt
 	if cfg.AuthTable == "" {
 		cfg.AuthTable = defaultAuthTable
 	}
+	if cfg.ConfigHistoryTable == "" {
+		cfg.ConfigHistoryTable = defaultConfigHistoryTable
+	}
+	if cfg.NotifyChannel == "" {
+		cfg.NotifyChannel = defaultNotifyChannel
+	}
 
 	spoolRoot := strings.TrimSpace(cfg.SpoolDir)
 	if spoolRoot == "" {
@@ -95,6 +116,7 @@ func NewPostgresStore(ctx context.Context, cfg PostgresStoreConfig) (*PostgresSt
 		spoolRoot:  absSpool,
 		configPath: filepath.Join(configDir, "config.yaml"),
 		authDir:    authDir,
+		revisions:  make(map[string]int64),
 	}
 	return store, nil
 }
@@ -140,7 +162,18 @@ func (s *PostgresStore) EnsureSchema(ctx context.Context) error {
 	`, authTable)); err != nil {
 		return fmt.Errorf("postgres store: create auth table: %w", err)
 	}
-	return nil
+	if err := s.ensureConfigHistoryTable(ctx); err != nil {
+		return err
+	}
+	// Tables created by earlier versions lack the revision column used to detect concurrent writes.
+	for _, table := range []struct{ name, kind string }{{configTable, "config"}, {authTable, "auth"}} {
+		if _, err := s.db.ExecContext(ctx, fmt.Sprintf(
+			"ALTER TABLE %s ADD COLUMN IF NOT EXISTS revision BIGINT NOT NULL DEFAULT 1", table.name,
+		)); err != nil {
+			return fmt.Errorf("postgres store: add %s revision column: %w", table.kind, err)
+		}
+	}
+	return s.ensureChangeTriggers(ctx)
 }
 
 // Bootstrap synchronizes configuration and auth records between PostgreSQL and the local workspace.
@@ -224,21 +257,28 @@ func (s *PostgresStore) Save(ctx context.Context, auth *cliproxyauth.Auth) (stri
 		if err = auth.Storage.SaveTokenToFile(path); err != nil {
 			return "", err
 		}
+		if err = authcrypt.SealFile(path); err != nil {
+			return "", fmt.Errorf("postgres store: encrypt auth file: %w", err)
+		}
 	case auth.Metadata != nil:
 		auth.Metadata["disabled"] = auth.Disabled
 		raw, errMarshal := json.Marshal(auth.Metadata)
 		if errMarshal != nil {
 			return "", fmt.Errorf("postgres store: marshal metadata: %w", errMarshal)
 		}
+		sealed, errSeal := authcrypt.Seal(raw)
+		if errSeal != nil {
+			return "", fmt.Errorf("postgres store: encrypt auth file: %w", errSeal)
+		}
 		if existing, errRead := os.ReadFile(path); errRead == nil {
-			if jsonEqual(existing, raw) {
+			if plain, errOpen := authcrypt.Open(existing); errOpen == nil && jsonEqual(plain, raw) && !authcrypt.NeedsSeal(existing) {
 				return path, nil
 			}
 		} else if errRead != nil && !errors.Is(errRead, fs.ErrNotExist) {
 			return "", fmt.Errorf("postgres store: read existing metadata: %w", errRead)
 		}
 		tmp := path + ".tmp"
-		if errWrite := os.WriteFile(tmp, raw, 0o600); errWrite != nil {
+		if errWrite := os.WriteFile(tmp, sealed, 0o600); errWrite != nil {
 			return "", fmt.Errorf("postgres store: write temp auth file: %w", errWrite)
 		}
 		if errRename := os.Rename(tmp, path); errRename != nil {
@@ -292,8 +332,13 @@ func (s *PostgresStore) List(ctx context.Context) ([]*cliproxyauth.Auth, error)
 			log.WithError(errPath).Warnf("postgres store: skipping auth %s outside spool", id)
 			continue
 		}
+		plain, errOpen := authcrypt.Open([]byte(payload))
+		if errOpen != nil {
+			log.WithError(errOpen).Warnf("postgres store: skipping auth %s that cannot be decrypted", id)
+			continue
+		}
 		metadata := make(map[string]any)
-		if err = json.Unmarshal([]byte(payload), &metadata); err != nil {
+		if err = json.Unmarshal(plain, &metadata); err != nil {
 			log.WithError(err).Warnf("postgres store: skipping auth %s with invalid json", id)
 			continue
 		}
@@ -406,9 +451,12 @@ func (s *PostgresStore) PersistConfig(ctx context.Context) error {
 
 // syncConfigFromDatabase writes the database-stored config to disk or seeds the database from template.
 func (s *PostgresStore) syncConfigFromDatabase(ctx context.Context, exampleConfigPath string) error {
-	query := fmt.Sprintf("SELECT content FROM %s WHERE id = $1", s.fullTableName(s.cfg.ConfigTable))
-	var content string
-	err := s.db.QueryRowContext(ctx, query, defaultConfigKey).Scan(&content)
+	query := fmt.Sprintf("SELECT content, revision FROM %s WHERE id = $1", s.fullTableName(s.cfg.ConfigTable))
+	var (
+		content  string
+		revision int64
+	)
+	err := s.db.QueryRowContext(ctx, query, defaultConfigKey).Scan(&content, &revision)
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
 		if _, errStat := os.Stat(s.configPath); errors.Is(errStat, fs.ErrNotExist) {
@@ -442,13 +490,14 @@ func (s *PostgresStore) syncConfigFromDatabase(ctx context.Context, exampleConfi
 		if err = os.WriteFile(s.configPath, []byte(normalized), 0o600); err != nil {
 			return fmt.Errorf("postgres store: write config to spool: %w", err)
 		}
+		s.configRevision = revision
 	}
 	return nil
 }
 
 // syncAuthFromDatabase populates the local auth directory from PostgreSQL data.
 func (s *PostgresStore) syncAuthFromDatabase(ctx context.Context) error {
-	query := fmt.Sprintf("SELECT id, content FROM %s", s.fullTableName(s.cfg.AuthTable))
+	query := fmt.Sprintf("SELECT id, content, revision FROM %s", s.fullTableName(s.cfg.AuthTable))
 	rows, err := s.db.QueryContext(ctx, query)
 	if err != nil {
 		return fmt.Errorf("postgres store: load auth from database: %w", err)
@@ -462,12 +511,17 @@ func (s *PostgresStore) syncAuthFromDatabase(ctx context.Context) error {
 		return fmt.Errorf("postgres store: recreate auth directory: %w", err)
 	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.revisions)
+
 	for rows.Next() {
 		var (
-			id      string
-			payload string
+			id       string
+			payload  string
+			revision int64
 		)
-		if err = rows.Scan(&id, &payload); err != nil {
+		if err = rows.Scan(&id, &payload, &revision); err != nil {
 			return fmt.Errorf("postgres store: scan auth row: %w", err)
 		}
 		path, errPath := s.absoluteAuthPath(id)
@@ -481,6 +535,7 @@ func (s *PostgresStore) syncAuthFromDatabase(ctx context.Context) error {
 		if err = os.WriteFile(path, []byte(payload), 0o600); err != nil {
 			return fmt.Errorf("postgres store: write auth file: %w", err)
 		}
+		s.revisions[id] = revision
 	}
 	if err = rows.Err(); err != nil {
 		return fmt.Errorf("postgres store: iterate auth rows: %w", err)
@@ -489,6 +544,9 @@ func (s *PostgresStore) syncAuthFromDatabase(ctx context.Context) error {
 }
 
 func (s *PostgresStore) syncAuthFile(ctx context.Context, relID, path string) error {
+	if errSeal := authcrypt.SealFile(path); errSeal != nil {
+		return fmt.Errorf("postgres store: encrypt auth file: %w", errSeal)
+	}
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
@@ -499,7 +557,7 @@ func (s *PostgresStore) syncAuthFile(ctx context.Context, relID, path string) er
 	if len(data) == 0 {
 		return s.deleteAuthRecord(ctx, relID)
 	}
-	return s.persistAuth(ctx, relID, data)
+	return s.persistAuth(ctx, relID, path, data)
 }
 
 func (s *PostgresStore) upsertAuthRecord(ctx context.Context, relID, path string) error {
@@ -510,20 +568,61 @@ func (s *PostgresStore) upsertAuthRecord(ctx context.Context, relID, path string
 	if len(data) == 0 {
 		return s.deleteAuthRecord(ctx, relID)
 	}
-	return s.persistAuth(ctx, relID, data)
+	return s.persistAuth(ctx, relID, path, data)
 }
 
-func (s *PostgresStore) persistAuth(ctx context.Context, relID string, data []byte) error {
-	jsonPayload := json.RawMessage(data)
-	query := fmt.Sprintf(`
-		INSERT INTO %s (id, content, created_at, updated_at)
-		VALUES ($1, $2, NOW(), NOW())
-		ON CONFLICT (id)
-		DO UPDATE SET content = EXCLUDED.content, updated_at = NOW()
-	`, s.fullTableName(s.cfg.AuthTable))
-	if _, err := s.db.ExecContext(ctx, query, relID, jsonPayload); err != nil {
-		return fmt.Errorf("postgres store: upsert auth record: %w", err)
+// persistAuth writes an auth record, bumping its revision. When another replica updated the row
+// since this replica last saw it, the record holding the later token expiry wins so a stale
+// replica cannot roll back a concurrently refreshed token; a losing local file is replaced with
+// the stored record. Callers must hold s.mu.
+func (s *PostgresStore) persistAuth(ctx context.Context, relID, path string, data []byte) error {
+	table := s.fullTableName(s.cfg.AuthTable)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("postgres store: begin auth transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		current  string
+		revision int64
+	)
+	err = tx.QueryRowContext(ctx, fmt.Sprintf("SELECT content, revision FROM %s WHERE id = $1 FOR UPDATE", table), relID).Scan(&current, &revision)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		query := fmt.Sprintf(`
+			INSERT INTO %s AS t (id, content, revision, created_at, updated_at)
+			VALUES ($1, $2, 1, NOW(), NOW())
+			ON CONFLICT (id)
+			DO UPDATE SET content = EXCLUDED.content, revision = t.revision + 1, updated_at = NOW()
+			RETURNING revision
+		`, table)
+		if err = tx.QueryRowContext(ctx, query, relID, json.RawMessage(data)).Scan(&revision); err != nil {
+			return fmt.Errorf("postgres store: insert auth record: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("postgres store: load auth record: %w", err)
+	case jsonEqual([]byte(current), data):
+		s.revisions[relID] = revision
+		return nil
+	default:
+		if s.keepStoredAuthLocked(relID, revision, data, []byte(current)) {
+			log.Warnf("postgres store: auth %s was updated by another replica with a fresher token, keeping revision %d", relID, revision)
+			if err = writeSpoolFile(path, []byte(current)); err != nil {
+				return fmt.Errorf("postgres store: write auth file: %w", err)
+			}
+			s.revisions[relID] = revision
+			return nil
+		}
+		query := fmt.Sprintf("UPDATE %s SET content = $2, revision = revision + 1, updated_at = NOW() WHERE id = $1 RETURNING revision", table)
+		if err = tx.QueryRowContext(ctx, query, relID, json.RawMessage(data)).Scan(&revision); err != nil {
+			return fmt.Errorf("postgres store: update auth record: %w", err)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("postgres store: commit auth record: %w", err)
 	}
+	s.revisions[relID] = revision
 	return nil
 }
 
@@ -532,20 +631,57 @@ func (s *PostgresStore) deleteAuthRecord(ctx context.Context, relID string) erro
 	if _, err := s.db.ExecContext(ctx, query, relID); err != nil {
 		return fmt.Errorf("postgres store: delete auth record: %w", err)
 	}
+	delete(s.revisions, relID)
 	return nil
 }
 
+// persistConfig writes the config record, bumping its revision. The local file reflects an
+// explicit edit, so it replaces a revision written concurrently by another replica, which is
+// logged. Callers must hold s.mu.
 func (s *PostgresStore) persistConfig(ctx context.Context, data []byte) error {
-	query := fmt.Sprintf(`
-		INSERT INTO %s (id, content, created_at, updated_at)
-		VALUES ($1, $2, NOW(), NOW())
-		ON CONFLICT (id)
-		DO UPDATE SET content = EXCLUDED.content, updated_at = NOW()
-	`, s.fullTableName(s.cfg.ConfigTable))
+	table := s.fullTableName(s.cfg.ConfigTable)
 	normalized := normalizeLineEndings(string(data))
-	if _, err := s.db.ExecContext(ctx, query, defaultConfigKey, normalized); err != nil {
-		return fmt.Errorf("postgres store: upsert config: %w", err)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("postgres store: begin config transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		current  string
+		revision int64
+	)
+	err = tx.QueryRowContext(ctx, fmt.Sprintf("SELECT content, revision FROM %s WHERE id = $1 FOR UPDATE", table), defaultConfigKey).Scan(&current, &revision)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		query := fmt.Sprintf(`
+			INSERT INTO %s AS t (id, content, revision, created_at, updated_at)
+			VALUES ($1, $2, 1, NOW(), NOW())
+			ON CONFLICT (id)
+			DO UPDATE SET content = EXCLUDED.content, revision = t.revision + 1, updated_at = NOW()
+			RETURNING revision
+		`, table)
+		if err = tx.QueryRowContext(ctx, query, defaultConfigKey, normalized).Scan(&revision); err != nil {
+			return fmt.Errorf("postgres store: insert config: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("postgres store: load config: %w", err)
+	case current == normalized:
+		s.configRevision = revision
+		return nil
+	default:
+		if s.configRevision != revision {
+			log.Warnf("postgres store: config revision %d written by another replica is replaced by a local edit", revision)
+		}
+		query := fmt.Sprintf("UPDATE %s SET content = $2, revision = revision + 1, updated_at = NOW() WHERE id = $1 RETURNING revision", table)
+		if err = tx.QueryRowContext(ctx, query, defaultConfigKey, normalized).Scan(&revision); err != nil {
+			return fmt.Errorf("postgres store: update config: %w", err)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("postgres store: commit config: %w", err)
 	}
+	s.configRevision = revision
 	return nil
 }
 
@@ -554,6 +690,7 @@ func (s *PostgresStore) deleteConfigRecord(ctx context.Context) error {
 	if _, err := s.db.ExecContext(ctx, query, defaultConfigKey); err != nil {
 		return fmt.Errorf("postgres store: delete config: %w", err)
 	}
+	s.configRevision = 0
 	return nil
 }
 
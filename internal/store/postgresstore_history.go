@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/confighistory"
+)
+
+// ConfigHistory returns the config history backend kept in the config history table, shared by
+// every replica using the database.
+func (s *PostgresStore) ConfigHistory() confighistory.Backend {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	return postgresConfigHistory{store: s}
+}
+
+func (s *PostgresStore) ensureConfigHistoryTable(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id BIGINT PRIMARY KEY,
+			created_at TIMESTAMPTZ NOT NULL,
+			key_name TEXT NOT NULL DEFAULT '',
+			source TEXT NOT NULL DEFAULT '',
+			size INTEGER NOT NULL,
+			sha256 TEXT NOT NULL,
+			content TEXT NOT NULL
+		)
+	`, s.fullTableName(s.cfg.ConfigHistoryTable))); err != nil {
+		return fmt.Errorf("postgres store: create config history table: %w", err)
+	}
+	return nil
+}
+
+// postgresConfigHistory stores config revisions as rows keyed by revision ID.
+type postgresConfigHistory struct {
+	store *PostgresStore
+}
+
+func (h postgresConfigHistory) table() string {
+	return h.store.fullTableName(h.store.cfg.ConfigHistoryTable)
+}
+
+func (h postgresConfigHistory) ListRevisions(ctx context.Context) ([]confighistory.Revision, error) {
+	query := fmt.Sprintf("SELECT id, created_at, key_name, source, size, sha256 FROM %s", h.table())
+	rows, err := h.store.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("postgres store: list config history: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []confighistory.Revision
+	for rows.Next() {
+		var rev confighistory.Revision
+		if err = rows.Scan(&rev.ID, &rev.Timestamp, &rev.Key, &rev.Source, &rev.Size, &rev.SHA256); err != nil {
+			return nil, fmt.Errorf("postgres store: scan config history: %w", err)
+		}
+		rev.Timestamp = rev.Timestamp.UTC()
+		revisions = append(revisions, rev)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres store: iterate config history: %w", err)
+	}
+	return revisions, nil
+}
+
+func (h postgresConfigHistory) GetRevision(ctx context.Context, id int64) (confighistory.Revision, []byte, error) {
+	query := fmt.Sprintf("SELECT id, created_at, key_name, source, size, sha256, content FROM %s WHERE id = $1", h.table())
+	var (
+		rev     confighistory.Revision
+		content string
+	)
+	err := h.store.db.QueryRowContext(ctx, query, id).Scan(&rev.ID, &rev.Timestamp, &rev.Key, &rev.Source, &rev.Size, &rev.SHA256, &content)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return confighistory.Revision{}, nil, confighistory.ErrNotFound
+		}
+		return confighistory.Revision{}, nil, fmt.Errorf("postgres store: load config revision: %w", err)
+	}
+	rev.Timestamp = rev.Timestamp.UTC()
+	return rev, []byte(content), nil
+}
+
+func (h postgresConfigHistory) PutRevision(ctx context.Context, rev confighistory.Revision, data []byte) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (id, created_at, key_name, source, size, sha256, content)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO NOTHING
+	`, h.table())
+	result, err := h.store.db.ExecContext(ctx, query, rev.ID, rev.Timestamp, rev.Key, rev.Source, rev.Size, rev.SHA256, string(data))
+	if err != nil {
+		return fmt.Errorf("postgres store: insert config revision: %w", err)
+	}
+	if inserted, errRows := result.RowsAffected(); errRows == nil && inserted == 0 {
+		return confighistory.ErrRevisionExists
+	}
+	return nil
+}
+
+func (h postgresConfigHistory) DeleteRevisions(ctx context.Context, ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = ANY($1)", h.table())
+	if _, err := h.store.db.ExecContext(ctx, query, ids); err != nil {
+		return fmt.Errorf("postgres store: delete config revisions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,350 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/authcrypt"
+	cliproxyauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	changeListenerMinBackoff = time.Second
+	changeListenerMaxBackoff = 30 * time.Second
+)
+
+// changeNotification is the payload published by the store tables' change triggers.
+type changeNotification struct {
+	Schema string `json:"schema"`
+	Table  string `json:"table"`
+	Op     string `json:"op"`
+	ID     string `json:"id"`
+}
+
+// ensureChangeTriggers installs the triggers that publish row changes on the notify channel.
+// Replicas starting concurrently serialize on an advisory lock while replacing the triggers.
+func (s *PostgresStore) ensureChangeTriggers(ctx context.Context) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("postgres store: begin trigger transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err = tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", "cliproxy-store-triggers"); err != nil {
+		return fmt.Errorf("postgres store: lock triggers: %w", err)
+	}
+	function := s.fullTableName("cliproxy_store_notify")
+	if _, err = tx.ExecContext(ctx, fmt.Sprintf(`
+		CREATE OR REPLACE FUNCTION %s() RETURNS trigger AS $$
+		DECLARE
+			record_id TEXT;
+		BEGIN
+			IF TG_OP = 'DELETE' THEN
+				record_id := OLD.id;
+			ELSE
+				record_id := NEW.id;
+			END IF;
+			PERFORM pg_notify(TG_ARGV[0], json_build_object(
+				'schema', TG_TABLE_SCHEMA, 'table', TG_TABLE_NAME, 'op', TG_OP, 'id', record_id
+			)::text);
+			RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql
+	`, function)); err != nil {
+		return fmt.Errorf("postgres store: create notify function: %w", err)
+	}
+	channel := "'" + strings.ReplaceAll(s.cfg.NotifyChannel, "'", "''") + "'"
+	for _, table := range []string{s.cfg.ConfigTable, s.cfg.AuthTable} {
+		trigger := quoteIdentifier(table + "_notify")
+		fullTable := s.fullTableName(table)
+		if _, err = tx.ExecContext(ctx, fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s", trigger, fullTable)); err != nil {
+			return fmt.Errorf("postgres store: drop %s trigger: %w", table, err)
+		}
+		if _, err = tx.ExecContext(ctx, fmt.Sprintf(
+			"CREATE TRIGGER %s AFTER INSERT OR UPDATE OR DELETE ON %s FOR EACH ROW EXECUTE FUNCTION %s(%s)",
+			trigger, fullTable, function, channel,
+		)); err != nil {
+			return fmt.Errorf("postgres store: create %s trigger: %w", table, err)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("postgres store: commit triggers: %w", err)
+	}
+	return nil
+}
+
+// StartChangeListener mirrors changes made by other replicas sharing the database into the local
+// workspace, where the watcher picks them up like any local file change. It listens on the notify
+// channel in the background, reconnecting with backoff and resynchronizing after each connect so
+// missed notifications are recovered. The listener stops when ctx is cancelled.
+func (s *PostgresStore) StartChangeListener(ctx context.Context) {
+	if s == nil || s.db == nil {
+		return
+	}
+	go s.runChangeListener(ctx)
+}
+
+func (s *PostgresStore) runChangeListener(ctx context.Context) {
+	backoff := changeListenerMinBackoff
+	for {
+		connected, err := s.listenForChanges(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			backoff = changeListenerMinBackoff
+		}
+		log.WithError(err).Warnf("postgres store: change listener disconnected, retrying in %s", backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if !connected {
+			backoff = min(backoff*2, changeListenerMaxBackoff)
+		}
+	}
+}
+
+// listenForChanges holds one listening connection until it fails. It reports whether the
+// connection was established.
+func (s *PostgresStore) listenForChanges(ctx context.Context) (bool, error) {
+	conn, err := pgx.Connect(ctx, s.cfg.DSN)
+	if err != nil {
+		return false, fmt.Errorf("connect: %w", err)
+	}
+	defer func() { _ = conn.Close(context.Background()) }()
+
+	if _, err = conn.Exec(ctx, "LISTEN "+quoteIdentifier(s.cfg.NotifyChannel)); err != nil {
+		return false, fmt.Errorf("listen: %w", err)
+	}
+	if err = s.resyncFromDatabase(ctx); err != nil {
+		log.WithError(err).Warn("postgres store: resync after listen failed")
+	}
+	for {
+		notification, errWait := conn.WaitForNotification(ctx)
+		if errWait != nil {
+			return true, errWait
+		}
+		if errApply := s.handleChangeNotification(ctx, notification.Payload); errApply != nil {
+			log.WithError(errApply).Warnf("postgres store: failed to apply change notification %s", notification.Payload)
+		}
+	}
+}
+
+func (s *PostgresStore) handleChangeNotification(ctx context.Context, payload string) error {
+	var change changeNotification
+	if err := json.Unmarshal([]byte(payload), &change); err != nil {
+		return fmt.Errorf("decode notification: %w", err)
+	}
+	if schema := strings.TrimSpace(s.cfg.Schema); schema != "" && change.Schema != schema {
+		return nil
+	}
+	switch change.Table {
+	case s.cfg.AuthTable:
+		return s.syncAuthRecord(ctx, change.ID)
+	case s.cfg.ConfigTable:
+		if change.ID != defaultConfigKey {
+			return nil
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.syncConfigRecordLocked(ctx)
+	}
+	return nil
+}
+
+// syncAuthRecord mirrors the current database state of one auth record to the local workspace.
+func (s *PostgresStore) syncAuthRecord(ctx context.Context, relID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := fmt.Sprintf("SELECT content, revision FROM %s WHERE id = $1", s.fullTableName(s.cfg.AuthTable))
+	var (
+		content  string
+		revision int64
+	)
+	err := s.db.QueryRowContext(ctx, query, relID).Scan(&content, &revision)
+	if errors.Is(err, sql.ErrNoRows) {
+		return s.applyRemoteAuthDeleteLocked(relID)
+	}
+	if err != nil {
+		return fmt.Errorf("load auth %s: %w", relID, err)
+	}
+	return s.applyRemoteAuthLocked(relID, []byte(content), revision)
+}
+
+// resyncFromDatabase reconciles the local workspace with every auth record and the config row.
+func (s *PostgresStore) resyncFromDatabase(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := fmt.Sprintf("SELECT id, content, revision FROM %s", s.fullTableName(s.cfg.AuthTable))
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("load auth: %w", err)
+	}
+	defer rows.Close()
+
+	seen := make(map[string]struct{})
+	for rows.Next() {
+		var (
+			id       string
+			content  string
+			revision int64
+		)
+		if err = rows.Scan(&id, &content, &revision); err != nil {
+			return fmt.Errorf("scan auth row: %w", err)
+		}
+		seen[id] = struct{}{}
+		if errApply := s.applyRemoteAuthLocked(id, []byte(content), revision); errApply != nil {
+			log.WithError(errApply).Warnf("postgres store: failed to mirror auth %s", id)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("iterate auth rows: %w", err)
+	}
+	for id := range s.revisions {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		if errApply := s.applyRemoteAuthDeleteLocked(id); errApply != nil {
+			log.WithError(errApply).Warnf("postgres store: failed to remove auth %s", id)
+		}
+	}
+	return s.syncConfigRecordLocked(ctx)
+}
+
+// applyRemoteAuthLocked writes a database record to its spool file unless this replica already
+// mirrored or wrote that revision, so local edits not yet persisted are not clobbered by echoes
+// of this replica's own writes.
+func (s *PostgresStore) applyRemoteAuthLocked(relID string, content []byte, revision int64) error {
+	if known, ok := s.revisions[relID]; ok && known == revision {
+		return nil
+	}
+	path, err := s.absoluteAuthPath(relID)
+	if err != nil {
+		return err
+	}
+	if existing, errRead := os.ReadFile(path); errRead == nil && jsonEqual(existing, content) {
+		s.revisions[relID] = revision
+		return nil
+	}
+	if err = writeSpoolFile(path, content); err != nil {
+		return fmt.Errorf("write auth file: %w", err)
+	}
+	s.revisions[relID] = revision
+	log.Debugf("postgres store: mirrored auth %s revision %d from database", relID, revision)
+	return nil
+}
+
+// applyRemoteAuthDeleteLocked removes the spool file of a deleted record. Files this replica
+// never mirrored from the database are left alone, as they may still be awaiting persistence.
+func (s *PostgresStore) applyRemoteAuthDeleteLocked(relID string) error {
+	if _, ok := s.revisions[relID]; !ok {
+		return nil
+	}
+	delete(s.revisions, relID)
+	path, err := s.absoluteAuthPath(relID)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("remove auth file: %w", err)
+	}
+	log.Debugf("postgres store: removed auth %s deleted in database", relID)
+	return nil
+}
+
+// syncConfigRecordLocked mirrors the database config row to the local workspace.
+func (s *PostgresStore) syncConfigRecordLocked(ctx context.Context) error {
+	query := fmt.Sprintf("SELECT content, revision FROM %s WHERE id = $1", s.fullTableName(s.cfg.ConfigTable))
+	var (
+		content  string
+		revision int64
+	)
+	err := s.db.QueryRowContext(ctx, query, defaultConfigKey).Scan(&content, &revision)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	return s.applyRemoteConfigLocked(content, revision)
+}
+
+// applyRemoteConfigLocked writes the database config to the spool file unless this replica
+// already mirrored or wrote that revision, mirroring applyRemoteAuthLocked.
+func (s *PostgresStore) applyRemoteConfigLocked(content string, revision int64) error {
+	if s.configRevision == revision {
+		return nil
+	}
+	normalized := normalizeLineEndings(content)
+	if existing, errRead := os.ReadFile(s.configPath); errRead == nil && normalizeLineEndings(string(existing)) == normalized {
+		s.configRevision = revision
+		return nil
+	}
+	if err := writeSpoolFile(s.configPath, []byte(normalized)); err != nil {
+		return fmt.Errorf("write config file: %w", err)
+	}
+	s.configRevision = revision
+	log.Debugf("postgres store: mirrored config revision %d from database", revision)
+	return nil
+}
+
+// keepStoredAuthLocked reports whether persistAuth must keep the stored auth record instead of
+// writing the incoming one. Only a revision this replica has not seen, which means another
+// replica wrote the row concurrently, is contested, and preferIncomingAuth settles it.
+func (s *PostgresStore) keepStoredAuthLocked(relID string, revision int64, incoming, stored []byte) bool {
+	if known, ok := s.revisions[relID]; ok && known == revision {
+		return false
+	}
+	return !preferIncomingAuth(incoming, stored)
+}
+
+// preferIncomingAuth decides a write conflict between an incoming auth record and the one stored
+// by another replica. The incoming record loses only when both carry a token expiry and the
+// stored token expires later, which is the signature of a concurrent refresh on another replica.
+func preferIncomingAuth(incoming, stored []byte) bool {
+	incomingExpiry, okIncoming := authRecordExpiry(incoming)
+	storedExpiry, okStored := authRecordExpiry(stored)
+	if !okIncoming || !okStored {
+		return true
+	}
+	return !incomingExpiry.Before(storedExpiry)
+}
+
+func authRecordExpiry(data []byte) (time.Time, bool) {
+	plain, err := authcrypt.Open(data)
+	if err != nil {
+		return time.Time{}, false
+	}
+	metadata := make(map[string]any)
+	if err = json.Unmarshal(plain, &metadata); err != nil {
+		return time.Time{}, false
+	}
+	return (&cliproxyauth.Auth{Metadata: metadata}).ExpirationTime()
+}
+
+// writeSpoolFile replaces a mirrored file through a temporary file so the watcher never observes
+// a partial write.
+func writeSpoolFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
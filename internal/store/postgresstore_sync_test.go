@@ -0,0 +1,148 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPreferIncomingAuth(t *testing.T) {
+	older := []byte(`{"type":"codex","expired":"2026-01-01T10:00:00Z"}`)
+	newer := []byte(`{"type":"codex","expired":"2026-01-01T11:00:00Z"}`)
+	noExpiry := []byte(`{"type":"vertex","project_id":"p"}`)
+
+	if preferIncomingAuth(older, newer) {
+		t.Fatal("a stale token must not replace a fresher stored token")
+	}
+	if !preferIncomingAuth(newer, older) {
+		t.Fatal("a fresher token should replace the stored token")
+	}
+	if !preferIncomingAuth(newer, newer) {
+		t.Fatal("equal expiry should keep the incoming write")
+	}
+	if !preferIncomingAuth(noExpiry, newer) {
+		t.Fatal("records without expiry should fall back to last writer wins")
+	}
+}
+
+func TestApplyRemoteAuthSkipsKnownRevisions(t *testing.T) {
+	authDir := t.TempDir()
+	s := &PostgresStore{authDir: authDir, revisions: make(map[string]int64)}
+	path := filepath.Join(authDir, "a.json")
+
+	if err := s.applyRemoteAuthLocked("a.json", []byte(`{"type":"claude","v":1}`), 1); err != nil {
+		t.Fatalf("apply revision 1: %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != `{"type":"claude","v":1}` {
+		t.Fatalf("mirrored file = %s, %v", data, err)
+	}
+
+	// A local edit awaiting persistence must survive the echo of a revision already mirrored.
+	if err := os.WriteFile(path, []byte(`{"type":"claude","v":2}`), 0o600); err != nil {
+		t.Fatalf("local edit: %v", err)
+	}
+	if err := s.applyRemoteAuthLocked("a.json", []byte(`{"type":"claude","v":1}`), 1); err != nil {
+		t.Fatalf("apply known revision: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != `{"type":"claude","v":2}` {
+		t.Fatalf("known revision overwrote local edit: %s", data)
+	}
+
+	if err := s.applyRemoteAuthLocked("a.json", []byte(`{"type":"claude","v":3}`), 2); err != nil {
+		t.Fatalf("apply revision 2: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != `{"type":"claude","v":3}` {
+		t.Fatalf("new revision not mirrored: %s", data)
+	}
+}
+
+func TestApplyRemoteAuthDeleteOnlyRemovesMirroredFiles(t *testing.T) {
+	authDir := t.TempDir()
+	s := &PostgresStore{authDir: authDir, revisions: map[string]int64{"mirrored.json": 4}}
+	for _, name := range []string{"mirrored.json", "local.json"} {
+		if err := os.WriteFile(filepath.Join(authDir, name), []byte(`{"type":"gemini"}`), 0o600); err != nil {
+			t.Fatalf("seed %s: %v", name, err)
+		}
+	}
+
+	for _, id := range []string{"mirrored.json", "local.json"} {
+		if err := s.applyRemoteAuthDeleteLocked(id); err != nil {
+			t.Fatalf("delete %s: %v", id, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(authDir, "mirrored.json")); !os.IsNotExist(err) {
+		t.Fatalf("mirrored file not removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(authDir, "local.json")); err != nil {
+		t.Fatalf("unmirrored local file removed: %v", err)
+	}
+	if _, ok := s.revisions["mirrored.json"]; ok {
+		t.Fatal("revision of deleted record still tracked")
+	}
+}
+
+func TestHandleChangeNotificationIgnoresOtherSchemas(t *testing.T) {
+	s := &PostgresStore{
+		cfg:       PostgresStoreConfig{Schema: "tenant_a", AuthTable: defaultAuthTable, ConfigTable: defaultConfigTable},
+		revisions: make(map[string]int64),
+	}
+	payload := `{"schema":"tenant_b","table":"auth_store","op":"UPDATE","id":"a.json"}`
+	if err := s.handleChangeNotification(context.Background(), payload); err != nil {
+		t.Fatalf("notification for another schema: %v", err)
+	}
+	if err := s.handleChangeNotification(context.Background(), "not json"); err == nil {
+		t.Fatal("expected an error for a malformed payload")
+	}
+}
+
+func TestApplyRemoteConfigSkipsKnownRevisions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	s := &PostgresStore{configPath: path, revisions: make(map[string]int64)}
+
+	if err := s.applyRemoteConfigLocked("port: 1\r\n", 1); err != nil {
+		t.Fatalf("apply revision 1: %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "port: 1\n" {
+		t.Fatalf("mirrored config = %q, %v", data, err)
+	}
+
+	// A local edit awaiting persistence must survive the echo of a revision already mirrored.
+	if err := os.WriteFile(path, []byte("port: 2\n"), 0o600); err != nil {
+		t.Fatalf("local edit: %v", err)
+	}
+	if err := s.applyRemoteConfigLocked("port: 1\n", 1); err != nil {
+		t.Fatalf("apply known revision: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "port: 2\n" {
+		t.Fatalf("known revision overwrote local edit: %q", data)
+	}
+
+	if err := s.applyRemoteConfigLocked("port: 3\n", 2); err != nil {
+		t.Fatalf("apply revision 2: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "port: 3\n" || s.configRevision != 2 {
+		t.Fatalf("new revision not mirrored: %q, revision %d", data, s.configRevision)
+	}
+}
+
+// The transactional part of persistAuth needs a live PostgreSQL server, which the unit tests do
+// not have; the conflict decision it takes under the row lock is covered here instead.
+func TestKeepStoredAuthOnConcurrentRefresh(t *testing.T) {
+	stale := []byte(`{"type":"codex","expired":"2026-01-01T10:00:00Z"}`)
+	refreshed := []byte(`{"type":"codex","expired":"2026-01-01T11:00:00Z"}`)
+	s := &PostgresStore{revisions: map[string]int64{"a.json": 3}}
+
+	if !s.keepStoredAuthLocked("a.json", 4, stale, refreshed) {
+		t.Fatal("a stale write must not replace a token refreshed by another replica")
+	}
+	if s.keepStoredAuthLocked("a.json", 4, refreshed, stale) {
+		t.Fatal("a fresher write should replace the concurrently stored record")
+	}
+	if s.keepStoredAuthLocked("a.json", 3, stale, refreshed) {
+		t.Fatal("a revision this replica already saw is not a conflict")
+	}
+	if !s.keepStoredAuthLocked("b.json", 1, stale, refreshed) {
+		t.Fatal("a record never seen locally should be contested like a concurrent write")
+	}
+}
@@ -0,0 +1,127 @@
+// Package tracing records request spans across the proxy pipeline with the
+// OpenTelemetry SDK and exports them over OTLP/HTTP. It also reads and writes
+// W3C traceparent headers so traces continue across process boundaries.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanKind is the OpenTelemetry span kind.
+type SpanKind = trace.SpanKind
+
+const (
+	SpanKindInternal = trace.SpanKindInternal
+	SpanKindServer   = trace.SpanKindServer
+	SpanKindClient   = trace.SpanKindClient
+)
+
+// StatusCode is the OpenTelemetry span status code.
+type StatusCode = codes.Code
+
+const (
+	StatusUnset = codes.Unset
+	StatusOK    = codes.Ok
+	StatusError = codes.Error
+)
+
+// Attribute is a key/value pair attached to a span or event.
+type Attribute = attribute.KeyValue
+
+// String returns a string attribute.
+func String(key, value string) Attribute { return attribute.String(key, value) }
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attribute { return attribute.Int(key, value) }
+
+// Int64 returns an integer attribute.
+func Int64(key string, value int64) Attribute { return attribute.Int64(key, value) }
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) Attribute { return attribute.Bool(key, value) }
+
+// Float64 returns a floating point attribute.
+func Float64(key string, value float64) Attribute { return attribute.Float64(key, value) }
+
+// Span is one timed operation. A nil *Span is valid and ignores every call,
+// which is what Start returns while tracing is disabled.
+type Span struct {
+	span trace.Span
+}
+
+// SpanContext returns the ids of the span.
+func (s *Span) SpanContext() trace.SpanContext {
+	if s == nil {
+		return trace.SpanContext{}
+	}
+	return s.span.SpanContext()
+}
+
+// IsRecording reports whether the span will be exported.
+func (s *Span) IsRecording() bool { return s != nil && s.span.IsRecording() }
+
+// SetAttributes adds attributes, replacing earlier values with the same key.
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil || len(attrs) == 0 {
+		return
+	}
+	s.span.SetAttributes(attrs...)
+}
+
+// AddEvent records a named event at the current time.
+func (s *Span) AddEvent(name string, attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.span.AddEvent(name, trace.WithAttributes(attrs...))
+}
+
+// SetStatus sets the span status.
+func (s *Span) SetStatus(code StatusCode, message string) {
+	if s == nil {
+		return
+	}
+	s.span.SetStatus(code, message)
+}
+
+// RecordError records err as an exception event and marks the span failed.
+// A nil error is ignored.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End finishes the span and queues it for export. Later calls are ignored.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.span.End()
+}
+
+// SpanFromContext returns the active span in ctx, or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span := trace.SpanFromContext(ctx)
+	if !span.SpanContext().IsValid() {
+		return nil
+	}
+	return &Span{span: span}
+}
+
+// ContextWithSpan returns a copy of ctx carrying span as the active span.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	if span == nil {
+		return ctx
+	}
+	return trace.ContextWithSpan(ctx, span.span)
+}
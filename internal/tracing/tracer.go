@@ -0,0 +1,250 @@
+package tracing
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/buildinfo"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ExporterOTLP sends spans to an OTLP/HTTP collector.
+	ExporterOTLP = "otlp"
+	// ExporterStdout writes spans to standard output as JSON lines.
+	ExporterStdout = "stdout"
+	// ExporterFile appends spans to a file as JSON lines.
+	ExporterFile = "file"
+
+	// DefaultOTLPEndpoint is the traces URL of a collector running on the same host.
+	DefaultOTLPEndpoint = "http://127.0.0.1:4318/v1/traces"
+	// DefaultServiceName is reported as the service.name resource attribute.
+	DefaultServiceName = "cli-proxy-api"
+
+	// TraceparentHeader is the W3C trace context header name.
+	TraceparentHeader = "traceparent"
+
+	scopeName = "github.com/router-for-me/CLIProxyAPI"
+
+	queueSize     = 4096
+	batchSize     = 512
+	flushInterval = 5 * time.Second
+	exportTimeout = 10 * time.Second
+)
+
+// propagator reads and writes W3C traceparent headers.
+var propagator = propagation.TraceContext{}
+
+func init() {
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		log.Warnf("tracing: %v", err)
+	}))
+}
+
+// Tracer creates spans on an OpenTelemetry tracer provider built from the
+// tracing config.
+type Tracer struct {
+	mu        sync.Mutex
+	cfg       config.TracingConfig
+	enabled   atomic.Bool
+	propagate atomic.Bool
+	ratio     atomic.Uint64
+	provider  atomic.Pointer[sdktrace.TracerProvider]
+}
+
+// NewTracer returns a disabled tracer. Call Apply to enable it.
+func NewTracer() *Tracer {
+	return &Tracer{}
+}
+
+var defaultTracer = NewTracer()
+
+// Default returns the process-wide tracer.
+func Default() *Tracer { return defaultTracer }
+
+// Enabled reports whether the default tracer records spans.
+func Enabled() bool { return defaultTracer.Enabled() }
+
+// Start opens an internal span on the default tracer.
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	return defaultTracer.Start(ctx, name, SpanKindInternal, attrs...)
+}
+
+// StartKind opens a span of the given kind on the default tracer.
+func StartKind(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, *Span) {
+	return defaultTracer.Start(ctx, name, kind, attrs...)
+}
+
+// Enabled reports whether spans are being recorded.
+func (t *Tracer) Enabled() bool { return t != nil && t.enabled.Load() }
+
+// Propagating reports whether traceparent headers are sent upstream.
+func (t *Tracer) Propagating() bool { return t.Enabled() && t.propagate.Load() }
+
+// Apply (re)configures the tracer. The provider is only rebuilt when the
+// exporter settings change; spans queued for a replaced provider are flushed
+// first.
+func (t *Tracer) Apply(cfg config.TracingConfig) error {
+	if t == nil {
+		return nil
+	}
+	cfg = normalizeConfig(cfg)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !cfg.Enable {
+		t.enabled.Store(false)
+		t.cfg = cfg
+		t.swapProvider(nil)
+		return nil
+	}
+	t.propagate.Store(cfg.Propagate)
+	t.ratio.Store(math.Float64bits(cfg.SampleRatio))
+	current := t.provider.Load()
+	if current != nil && sameExporterConfig(t.cfg, cfg) {
+		t.cfg = cfg
+		t.enabled.Store(true)
+		return nil
+	}
+	exporter, err := newExporter(cfg)
+	if err != nil {
+		return err
+	}
+	t.cfg = cfg
+	t.swapProvider(t.newProvider(cfg, exporter))
+	t.enabled.Store(true)
+	log.Infof("tracing enabled (exporter=%s)", cfg.Exporter)
+	return nil
+}
+
+// Shutdown flushes queued spans and stops the exporter.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	t.enabled.Store(false)
+	t.cfg = config.TracingConfig{}
+	provider := t.provider.Swap(nil)
+	t.mu.Unlock()
+	if provider == nil {
+		return nil
+	}
+	return provider.Shutdown(ctx)
+}
+
+func (t *Tracer) newProvider(cfg config.TracingConfig, exporter sdktrace.SpanExporter) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithResource(newResource(cfg)),
+		sdktrace.WithSampler(sdktrace.ParentBased(ratioSampler{tracer: t})),
+		sdktrace.WithBatcher(exporter,
+			sdktrace.WithMaxQueueSize(queueSize),
+			sdktrace.WithMaxExportBatchSize(batchSize),
+			sdktrace.WithBatchTimeout(flushInterval),
+			sdktrace.WithExportTimeout(exportTimeout),
+		),
+	)
+}
+
+func (t *Tracer) swapProvider(next *sdktrace.TracerProvider) {
+	previous := t.provider.Swap(next)
+	if previous == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+		defer cancel()
+		if err := previous.Shutdown(ctx); err != nil {
+			log.Warnf("tracing: flush previous exporter: %v", err)
+		}
+	}()
+}
+
+// Start opens a span as a child of the span or remote parent in ctx and
+// returns a context carrying it. While the tracer is disabled it returns ctx
+// unchanged and a nil span.
+func (t *Tracer) Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, *Span) {
+	if !t.Enabled() {
+		return ctx, nil
+	}
+	provider := t.provider.Load()
+	if provider == nil {
+		return ctx, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, span := provider.Tracer(scopeName, trace.WithInstrumentationVersion(buildinfo.Version)).
+		Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+	return ctx, &Span{span: span}
+}
+
+// ratioSampler samples new traces at the tracer's current ratio, so a config
+// reload can change it without rebuilding the provider. The decision is made on
+// the trace ID, so every process sharing a trace agrees on it.
+type ratioSampler struct {
+	tracer *Tracer
+}
+
+func (s ratioSampler) ShouldSample(params sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	ratio := math.Float64frombits(s.tracer.ratio.Load())
+	return sdktrace.TraceIDRatioBased(ratio).ShouldSample(params)
+}
+
+func (s ratioSampler) Description() string { return "TraceIDRatioBased{tracing.sample-ratio}" }
+
+// Extract returns ctx carrying the remote parent from a traceparent header, if present and valid.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	if header == nil {
+		return ctx
+	}
+	return propagator.Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// Inject writes the traceparent of the active span in ctx to header when
+// upstream propagation is enabled.
+func Inject(ctx context.Context, header http.Header) {
+	if header == nil || !defaultTracer.Propagating() {
+		return
+	}
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+func normalizeConfig(cfg config.TracingConfig) config.TracingConfig {
+	cfg.Exporter = strings.ToLower(strings.TrimSpace(cfg.Exporter))
+	if cfg.Exporter == "" {
+		cfg.Exporter = ExporterOTLP
+	}
+	cfg.Endpoint = strings.TrimSpace(cfg.Endpoint)
+	if cfg.Exporter == ExporterOTLP && cfg.Endpoint == "" {
+		cfg.Endpoint = DefaultOTLPEndpoint
+	}
+	cfg.File = strings.TrimSpace(cfg.File)
+	cfg.ServiceName = strings.TrimSpace(cfg.ServiceName)
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = DefaultServiceName
+	}
+	if cfg.SampleRatio <= 0 || cfg.SampleRatio > 1 {
+		cfg.SampleRatio = 1
+	}
+	return cfg
+}
+
+func sameExporterConfig(a, b config.TracingConfig) bool {
+	return a.Exporter == b.Exporter &&
+		a.Endpoint == b.Endpoint &&
+		a.File == b.File &&
+		a.ServiceName == b.ServiceName &&
+		reflect.DeepEqual(a.Headers, b.Headers)
+}
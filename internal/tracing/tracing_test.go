@@ -0,0 +1,222 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestTracer(t *testing.T, ratio float64) (*Tracer, *tracetest.InMemoryExporter) {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	tracer := NewTracer()
+	tracer.cfg = normalizeConfig(config.TracingConfig{Enable: true, SampleRatio: ratio})
+	tracer.ratio.Store(math.Float64bits(tracer.cfg.SampleRatio))
+	tracer.provider.Store(sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.ParentBased(ratioSampler{tracer: tracer})),
+		sdktrace.WithSyncer(exporter),
+	))
+	tracer.enabled.Store(true)
+	return tracer, exporter
+}
+
+func TestTracerStartBuildsParentChain(t *testing.T) {
+	tracer, exporter := newTestTracer(t, 1)
+	defer func() { _ = tracer.Shutdown(context.Background()) }()
+
+	ctx, root := tracer.Start(context.Background(), "root", SpanKindServer, String("a", "b"))
+	_, child := tracer.Start(ctx, "child", SpanKindInternal)
+	child.RecordError(errors.New("boom"))
+	child.End()
+	child.End()
+	root.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans, want 2", len(spans))
+	}
+	gotChild, gotRoot := spans[0], spans[1]
+	if gotChild.SpanContext.TraceID() != gotRoot.SpanContext.TraceID() {
+		t.Fatal("child does not share the root trace id")
+	}
+	if gotChild.Parent.SpanID() != gotRoot.SpanContext.SpanID() {
+		t.Fatal("child parent is not the root span")
+	}
+	if gotChild.Status.Code != StatusError || len(gotChild.Events) != 1 {
+		t.Fatalf("child error not recorded: status=%v events=%d", gotChild.Status.Code, len(gotChild.Events))
+	}
+}
+
+func TestTracerContinuesRemoteParent(t *testing.T) {
+	tracer, _ := newTestTracer(t, 1)
+	defer func() { _ = tracer.Shutdown(context.Background()) }()
+
+	header := http.Header{}
+	header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	_, span := tracer.Start(Extract(context.Background(), header), "server", SpanKindServer)
+	if span.SpanContext().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatal("remote trace id not continued")
+	}
+	if span.IsRecording() {
+		t.Fatal("span should follow the unsampled remote parent")
+	}
+}
+
+func TestTracerDisabledReturnsNilSpan(t *testing.T) {
+	tracer := NewTracer()
+	ctx := context.Background()
+	gotCtx, span := tracer.Start(ctx, "noop", SpanKindInternal)
+	if span != nil || gotCtx != ctx {
+		t.Fatal("disabled tracer should return the context unchanged and a nil span")
+	}
+	span.SetAttributes(String("k", "v"))
+	span.RecordError(errors.New("ignored"))
+	span.End()
+}
+
+func TestTracerSampleRatio(t *testing.T) {
+	tracer, _ := newTestTracer(t, 0.5)
+	defer func() { _ = tracer.Shutdown(context.Background()) }()
+
+	sampler := ratioSampler{tracer: tracer}
+	low := trace.TraceID{}
+	high := trace.TraceID{8: 0xff, 9: 0xff, 10: 0xff, 11: 0xff, 12: 0xff, 13: 0xff, 14: 0xff, 15: 0xff}
+	if sampler.ShouldSample(sdktrace.SamplingParameters{TraceID: low}).Decision != sdktrace.RecordAndSample {
+		t.Fatal("low trace id should be sampled at ratio 0.5")
+	}
+	if sampler.ShouldSample(sdktrace.SamplingParameters{TraceID: high}).Decision == sdktrace.RecordAndSample {
+		t.Fatal("high trace id should not be sampled at ratio 0.5")
+	}
+}
+
+func TestOTLPExporterPostsSpans(t *testing.T) {
+	var body []byte
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tracer := NewTracer()
+	if err := tracer.Apply(config.TracingConfig{
+		Enable:      true,
+		Endpoint:    server.URL + "/v1/traces",
+		Headers:     map[string]string{"Authorization": "Bearer token"},
+		ServiceName: "test-service",
+	}); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	_, span := tracer.Start(context.Background(), "op", SpanKindClient, Int("retry.attempt", 2), Bool("stream", true))
+	span.End()
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	if auth != "Bearer token" {
+		t.Fatalf("Authorization header = %q", auth)
+	}
+	var payload coltracepb.ExportTraceServiceRequest
+	if err := proto.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	resourceSpans := payload.GetResourceSpans()
+	if len(resourceSpans) != 1 {
+		t.Fatalf("resource spans = %d, want 1", len(resourceSpans))
+	}
+	var serviceName string
+	for _, attr := range resourceSpans[0].GetResource().GetAttributes() {
+		if attr.GetKey() == "service.name" {
+			serviceName = attr.GetValue().GetStringValue()
+		}
+	}
+	if serviceName != "test-service" {
+		t.Fatalf("service.name = %q, want test-service", serviceName)
+	}
+	spans := resourceSpans[0].GetScopeSpans()[0].GetSpans()
+	if len(spans) != 1 || spans[0].GetName() != "op" || spans[0].GetAttributes()[0].GetValue().GetIntValue() != 2 {
+		t.Fatalf("unexpected spans: %v", spans)
+	}
+}
+
+func TestFileExporterAppendsLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces", "spans.jsonl")
+	tracer := NewTracer()
+	if err := tracer.Apply(config.TracingConfig{Enable: true, Exporter: "file", File: path}); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	_, span := tracer.Start(context.Background(), "op", SpanKindInternal)
+	span.End()
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read trace file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], `"Name":"op"`) {
+		t.Fatalf("unexpected trace file contents: %s", data)
+	}
+}
+
+func TestApplyRejectsUnknownExporter(t *testing.T) {
+	tracer := NewTracer()
+	if err := tracer.Apply(config.TracingConfig{Enable: true, Exporter: "zipkin"}); err == nil {
+		t.Fatal("expected error for unknown exporter")
+	}
+	if tracer.Enabled() {
+		t.Fatal("tracer should stay disabled after a failed Apply")
+	}
+}
+
+func TestTransportInjectsTraceparent(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+	if err := Default().Apply(config.TracingConfig{Enable: true, Exporter: "file", File: path, Propagate: true}); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	defer func() { _ = Default().Shutdown(context.Background()) }()
+
+	ctx, parent := Start(context.Background(), "parent")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/v1/models", nil)
+	resp, err := (&http.Client{Transport: WrapTransport(nil)}).Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	_, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	parent.End()
+
+	sc := trace.SpanContextFromContext(Extract(context.Background(), received))
+	if !sc.IsValid() {
+		t.Fatalf("upstream did not receive a valid traceparent: %q", received.Get(TraceparentHeader))
+	}
+	if sc.TraceID() != parent.SpanContext().TraceID() || sc.SpanID() == parent.SpanContext().SpanID() {
+		t.Fatal("traceparent should carry the client span of the parent trace")
+	}
+	if req.Header.Get(TraceparentHeader) != "" {
+		t.Fatal("transport must not modify the caller's request headers")
+	}
+}
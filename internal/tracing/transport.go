@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"io"
+	"net/http"
+	"sync"
+)
+
+// transport opens a client span for each upstream request and, when enabled,
+// forwards the trace context in a traceparent header.
+type transport struct {
+	base http.RoundTripper
+}
+
+// WrapTransport returns base wrapped with client spans. A nil base uses http.DefaultTransport.
+func WrapTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if _, ok := base.(*transport); ok {
+		return base
+	}
+	return &transport{base: base}
+}
+
+// RoundTrip implements http.RoundTripper. The span stays open until the
+// response body is fully read or closed so streamed responses are covered.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := StartKind(req.Context(), "upstream "+req.Method, SpanKindClient,
+		String("http.request.method", req.Method),
+		String("server.address", req.URL.Host),
+		String("url.path", req.URL.Path),
+	)
+	if span == nil {
+		return t.base.RoundTrip(req)
+	}
+	if defaultTracer.Propagating() {
+		req = req.Clone(ctx)
+		Inject(ctx, req.Header)
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.End()
+		return resp, err
+	}
+	span.SetAttributes(Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(StatusError, resp.Status)
+	}
+	if resp.Body == nil || resp.Body == http.NoBody || resp.StatusCode == http.StatusSwitchingProtocols {
+		span.End()
+		return resp, nil
+	}
+	resp.Body = &spanBody{ReadCloser: resp.Body, span: span}
+	return resp, nil
+}
+
+// spanBody ends its span once the body is drained or closed.
+type spanBody struct {
+	io.ReadCloser
+	span *Span
+	once sync.Once
+}
+
+func (b *spanBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.finish()
+	} else if err != nil {
+		b.span.RecordError(err)
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *spanBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.finish()
+	return err
+}
+
+func (b *spanBody) finish() {
+	b.once.Do(b.span.End)
+}
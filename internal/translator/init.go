@@ -26,6 +26,7 @@ import (
 	_ "github.com/router-for-me/CLIProxyAPI/v7/internal/translator/openai/claude"
 	_ "github.com/router-for-me/CLIProxyAPI/v7/internal/translator/openai/gemini"
 	_ "github.com/router-for-me/CLIProxyAPI/v7/internal/translator/openai/gemini-cli"
+	_ "github.com/router-for-me/CLIProxyAPI/v7/internal/translator/openai/ollama"
 	_ "github.com/router-for-me/CLIProxyAPI/v7/internal/translator/openai/openai/chat-completions"
 	_ "github.com/router-for-me/CLIProxyAPI/v7/internal/translator/openai/openai/responses"
 
@@ -0,0 +1,256 @@
+// Package ollama provides translation between the Ollama API and the OpenAI Chat Completions API.
+// It converts /api/chat and /api/generate requests into Chat Completions requests and turns
+// Chat Completions responses back into Ollama NDJSON messages, so Ollama clients can reach
+// every upstream that already speaks the OpenAI format.
+package ollama
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// ConvertOllamaRequestToOpenAI parses an Ollama /api/chat or /api/generate request and
+// transforms it into OpenAI Chat Completions format. Generate requests are recognised by
+// the presence of "prompt" without "messages" and become a system plus user message pair.
+func ConvertOllamaRequestToOpenAI(modelName string, inputRawJSON []byte, stream bool) []byte {
+	root := gjson.ParseBytes(inputRawJSON)
+	out := []byte(`{"model":"","messages":[]}`)
+	out, _ = sjson.SetBytes(out, "model", modelName)
+
+	if IsGenerateRequest(inputRawJSON) {
+		out = appendGenerateMessages(out, root)
+	} else {
+		out = appendChatMessages(out, root.Get("messages"))
+	}
+
+	out = applyOptions(out, root.Get("options"))
+
+	// format: "json" or a JSON schema object
+	if format := root.Get("format"); format.Exists() {
+		switch {
+		case format.IsObject():
+			out, _ = sjson.SetBytes(out, "response_format.type", "json_schema")
+			out, _ = sjson.SetBytes(out, "response_format.json_schema.name", "response")
+			out, _ = sjson.SetRawBytes(out, "response_format.json_schema.schema", []byte(format.Raw))
+		case strings.EqualFold(format.String(), "json"):
+			out, _ = sjson.SetBytes(out, "response_format.type", "json_object")
+		}
+	}
+
+	// Tools already use the OpenAI function declaration shape.
+	if tools := root.Get("tools"); tools.IsArray() && len(tools.Array()) > 0 {
+		out, _ = sjson.SetRawBytes(out, "tools", []byte(tools.Raw))
+	}
+
+	// think: true/false or a level name
+	if think := root.Get("think"); think.Exists() {
+		switch think.Type {
+		case gjson.True:
+			out, _ = sjson.SetBytes(out, "reasoning_effort", "medium")
+		case gjson.False:
+			out, _ = sjson.SetBytes(out, "reasoning_effort", "none")
+		case gjson.String:
+			if level := strings.ToLower(strings.TrimSpace(think.String())); level != "" {
+				out, _ = sjson.SetBytes(out, "reasoning_effort", level)
+			}
+		}
+	}
+
+	out, _ = sjson.SetBytes(out, "stream", stream)
+	if stream {
+		out, _ = sjson.SetBytes(out, "stream_options.include_usage", true)
+	}
+	return out
+}
+
+// IsGenerateRequest reports whether rawJSON is an Ollama /api/generate request.
+func IsGenerateRequest(rawJSON []byte) bool {
+	return !gjson.GetBytes(rawJSON, "messages").Exists() && gjson.GetBytes(rawJSON, "prompt").Exists()
+}
+
+// IsStreamRequest reports whether an Ollama request asks for a streamed response.
+// Ollama streams unless the client explicitly sends "stream": false.
+func IsStreamRequest(rawJSON []byte) bool {
+	return gjson.GetBytes(rawJSON, "stream").Type != gjson.False
+}
+
+func appendGenerateMessages(out []byte, root gjson.Result) []byte {
+	if system := root.Get("system").String(); system != "" {
+		out, _ = sjson.SetRawBytes(out, "messages.-1", buildTextMessage("system", system))
+	}
+	user := []byte(`{"role":"user"}`)
+	user = setMessageContent(user, root.Get("prompt").String(), root.Get("images"))
+	out, _ = sjson.SetRawBytes(out, "messages.-1", user)
+	return out
+}
+
+func appendChatMessages(out []byte, messages gjson.Result) []byte {
+	if !messages.IsArray() {
+		return out
+	}
+	// Ollama tool calls carry no ids; generate them and hand them to the tool
+	// results that follow, matching by function name when available.
+	var pending []pendingToolCall
+	callCount := 0
+	messages.ForEach(func(_, message gjson.Result) bool {
+		role := message.Get("role").String()
+		content := message.Get("content").String()
+
+		if role == "tool" {
+			msg := []byte(`{"role":"tool","tool_call_id":"","content":""}`)
+			id := message.Get("tool_call_id").String()
+			if id == "" {
+				id, pending = takeToolCallID(pending, message.Get("tool_name").String())
+			}
+			if id == "" {
+				id = fmt.Sprintf("call_%d", callCount)
+				callCount++
+			}
+			msg, _ = sjson.SetBytes(msg, "tool_call_id", id)
+			msg, _ = sjson.SetBytes(msg, "content", content)
+			out, _ = sjson.SetRawBytes(out, "messages.-1", msg)
+			return true
+		}
+
+		msg := []byte(`{"role":""}`)
+		msg, _ = sjson.SetBytes(msg, "role", role)
+		msg = setMessageContent(msg, content, message.Get("images"))
+		if role == "assistant" {
+			if thinking := message.Get("thinking").String(); thinking != "" {
+				msg, _ = sjson.SetBytes(msg, "reasoning_content", thinking)
+			}
+			if toolCalls := message.Get("tool_calls"); toolCalls.IsArray() {
+				pending = pending[:0]
+				toolCalls.ForEach(func(_, toolCall gjson.Result) bool {
+					id := toolCall.Get("id").String()
+					if id == "" {
+						id = fmt.Sprintf("call_%d", callCount)
+						callCount++
+					}
+					name := toolCall.Get("function.name").String()
+					call := []byte(`{"id":"","type":"function","function":{"name":"","arguments":""}}`)
+					call, _ = sjson.SetBytes(call, "id", id)
+					call, _ = sjson.SetBytes(call, "function.name", name)
+					call, _ = sjson.SetBytes(call, "function.arguments", toolArguments(toolCall.Get("function.arguments")))
+					msg, _ = sjson.SetRawBytes(msg, "tool_calls.-1", call)
+					pending = append(pending, pendingToolCall{id: id, name: name})
+					return true
+				})
+			}
+		}
+		out, _ = sjson.SetRawBytes(out, "messages.-1", msg)
+		return true
+	})
+	return out
+}
+
+type pendingToolCall struct {
+	id   string
+	name string
+}
+
+// takeToolCallID removes and returns the id of the first pending call named name,
+// falling back to the oldest pending call when the name is empty or unknown.
+func takeToolCallID(pending []pendingToolCall, name string) (string, []pendingToolCall) {
+	if len(pending) == 0 {
+		return "", pending
+	}
+	idx := 0
+	if name != "" {
+		for i, call := range pending {
+			if call.name == name {
+				idx = i
+				break
+			}
+		}
+	}
+	id := pending[idx].id
+	return id, append(pending[:idx], pending[idx+1:]...)
+}
+
+// toolArguments returns the JSON string form OpenAI expects for function arguments.
+func toolArguments(args gjson.Result) string {
+	switch {
+	case !args.Exists() || args.Type == gjson.Null:
+		return "{}"
+	case args.Type == gjson.String:
+		return args.String()
+	default:
+		return args.Raw
+	}
+}
+
+func buildTextMessage(role, text string) []byte {
+	msg := []byte(`{"role":"","content":""}`)
+	msg, _ = sjson.SetBytes(msg, "role", role)
+	msg, _ = sjson.SetBytes(msg, "content", text)
+	return msg
+}
+
+// setMessageContent stores text as a plain string, or as a content part array when
+// the Ollama message also carries base64 images.
+func setMessageContent(msg []byte, text string, images gjson.Result) []byte {
+	if !images.IsArray() || len(images.Array()) == 0 {
+		msg, _ = sjson.SetBytes(msg, "content", text)
+		return msg
+	}
+	msg, _ = sjson.SetRawBytes(msg, "content", []byte(`[]`))
+	if text != "" {
+		part := []byte(`{"type":"text","text":""}`)
+		part, _ = sjson.SetBytes(part, "text", text)
+		msg, _ = sjson.SetRawBytes(msg, "content.-1", part)
+	}
+	images.ForEach(func(_, image gjson.Result) bool {
+		data := strings.TrimSpace(image.String())
+		if data == "" {
+			return true
+		}
+		if !strings.HasPrefix(data, "data:") {
+			data = "data:" + imageMimeType(data) + ";base64," + data
+		}
+		part := []byte(`{"type":"image_url","image_url":{"url":""}}`)
+		part, _ = sjson.SetBytes(part, "image_url.url", data)
+		msg, _ = sjson.SetRawBytes(msg, "content.-1", part)
+		return true
+	})
+	return msg
+}
+
+// imageMimeType guesses the media type of base64 image data from its leading bytes.
+func imageMimeType(b64 string) string {
+	switch {
+	case strings.HasPrefix(b64, "/9j/"):
+		return "image/jpeg"
+	case strings.HasPrefix(b64, "R0lGOD"):
+		return "image/gif"
+	case strings.HasPrefix(b64, "UklGR"):
+		return "image/webp"
+	default:
+		return "image/png"
+	}
+}
+
+// applyOptions maps Ollama runtime options onto their Chat Completions equivalents.
+func applyOptions(out []byte, options gjson.Result) []byte {
+	if !options.IsObject() {
+		return out
+	}
+	for _, key := range []string{"temperature", "top_p", "frequency_penalty", "presence_penalty"} {
+		if value := options.Get(key); value.Exists() {
+			out, _ = sjson.SetBytes(out, key, value.Float())
+		}
+	}
+	if seed := options.Get("seed"); seed.Exists() {
+		out, _ = sjson.SetBytes(out, "seed", seed.Int())
+	}
+	if numPredict := options.Get("num_predict"); numPredict.Exists() && numPredict.Int() > 0 {
+		out, _ = sjson.SetBytes(out, "max_tokens", numPredict.Int())
+	}
+	if stop := options.Get("stop"); stop.Exists() {
+		out, _ = sjson.SetRawBytes(out, "stop", []byte(stop.Raw))
+	}
+	return out
+}
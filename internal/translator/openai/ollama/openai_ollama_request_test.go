@@ -0,0 +1,90 @@
+package ollama
+
+import (
+	"testing"
+
+	"github.com/tidwall/gjson"
+)
+
+func TestConvertOllamaChatRequestToOpenAI(t *testing.T) {
+	input := []byte(`{
+		"model": "gpt-5",
+		"messages": [
+			{"role": "system", "content": "be brief"},
+			{"role": "user", "content": "what is in this picture?", "images": ["iVBORw0KGgo="]},
+			{"role": "assistant", "content": "", "tool_calls": [
+				{"function": {"name": "get_weather", "arguments": {"city": "Paris"}}},
+				{"function": {"name": "get_time", "arguments": {"tz": "CET"}}}
+			]},
+			{"role": "tool", "tool_name": "get_time", "content": "12:00"},
+			{"role": "tool", "content": "sunny"}
+		],
+		"tools": [{"type": "function", "function": {"name": "get_weather", "parameters": {"type": "object"}}}],
+		"options": {"temperature": 0.2, "num_predict": 64, "stop": ["\n\n"], "seed": 7},
+		"format": "json",
+		"think": false
+	}`)
+
+	out := gjson.ParseBytes(ConvertOllamaRequestToOpenAI("gpt-5", input, true))
+
+	if out.Get("model").String() != "gpt-5" || !out.Get("stream").Bool() || !out.Get("stream_options.include_usage").Bool() {
+		t.Fatalf("unexpected envelope: %s", out.Raw)
+	}
+	if out.Get("temperature").Float() != 0.2 || out.Get("max_tokens").Int() != 64 || out.Get("seed").Int() != 7 {
+		t.Fatalf("options not mapped: %s", out.Raw)
+	}
+	if out.Get("stop.0").String() != "\n\n" {
+		t.Fatalf("stop not mapped: %s", out.Raw)
+	}
+	if out.Get("response_format.type").String() != "json_object" {
+		t.Fatalf("format not mapped: %s", out.Raw)
+	}
+	if out.Get("reasoning_effort").String() != "none" {
+		t.Fatalf("think not mapped: %s", out.Raw)
+	}
+	if out.Get("tools.0.function.name").String() != "get_weather" {
+		t.Fatalf("tools not copied: %s", out.Raw)
+	}
+
+	messages := out.Get("messages").Array()
+	if len(messages) != 5 {
+		t.Fatalf("got %d messages, want 5: %s", len(messages), out.Raw)
+	}
+	if got := messages[1].Get("content.1.image_url.url").String(); got != "data:image/png;base64,iVBORw0KGgo=" {
+		t.Fatalf("image not converted: %q", got)
+	}
+	calls := messages[2].Get("tool_calls").Array()
+	if len(calls) != 2 || calls[0].Get("function.arguments").String() != `{"city": "Paris"}` {
+		t.Fatalf("tool calls not converted: %s", messages[2].Raw)
+	}
+	if messages[3].Get("tool_call_id").String() != calls[1].Get("id").String() {
+		t.Fatalf("named tool result not matched: %s", messages[3].Raw)
+	}
+	if messages[4].Get("tool_call_id").String() != calls[0].Get("id").String() {
+		t.Fatalf("unnamed tool result not matched to remaining call: %s", messages[4].Raw)
+	}
+}
+
+func TestConvertOllamaGenerateRequestToOpenAI(t *testing.T) {
+	input := []byte(`{"model":"claude-sonnet-4","system":"sys","prompt":"hello","stream":false,"format":{"type":"object"}}`)
+	if !IsGenerateRequest(input) || IsStreamRequest(input) {
+		t.Fatal("expected a non-streaming generate request")
+	}
+
+	out := gjson.ParseBytes(ConvertOllamaRequestToOpenAI("claude-sonnet-4", input, false))
+	if out.Get("messages.0.role").String() != "system" || out.Get("messages.1.content").String() != "hello" {
+		t.Fatalf("generate prompt not converted: %s", out.Raw)
+	}
+	if out.Get("stream").Bool() || out.Get("stream_options").Exists() {
+		t.Fatalf("unexpected stream settings: %s", out.Raw)
+	}
+	if out.Get("response_format.json_schema.schema.type").String() != "object" {
+		t.Fatalf("schema format not mapped: %s", out.Raw)
+	}
+}
+
+func TestIsStreamRequestDefaultsToTrue(t *testing.T) {
+	if !IsStreamRequest([]byte(`{"model":"m","messages":[]}`)) {
+		t.Fatal("Ollama requests stream unless stream is false")
+	}
+}
@@ -0,0 +1,233 @@
+package ollama
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+var dataTag = []byte("data:")
+
+// ConvertOpenAIResponseToOllamaParams holds the state carried across streamed chunks.
+type ConvertOpenAIResponseToOllamaParams struct {
+	Model            string
+	Generate         bool
+	DoneReason       string
+	PromptTokens     int64
+	CompletionTokens int64
+	Started          time.Time
+	ToolCalls        map[int]*ToolCallAccumulator
+	Finished         bool
+}
+
+// ToolCallAccumulator collects a streamed tool call until its arguments are complete.
+// Ollama sends each tool call as one message, unlike the incremental OpenAI deltas.
+type ToolCallAccumulator struct {
+	Name      string
+	Arguments strings.Builder
+}
+
+// ConvertOpenAIResponseToOllama converts one OpenAI Chat Completions streaming chunk into
+// zero or more Ollama NDJSON messages. Buffered tool calls and the final "done" message are
+// emitted when the "[DONE]" marker arrives.
+//
+// Parameters:
+//   - ctx: The context for the request
+//   - modelName: The upstream model name, used when the original request has none
+//   - originalRequestRawJSON: The Ollama request, used to pick the chat or generate shape
+//   - rawJSON: The raw OpenAI chunk, with or without the SSE "data:" prefix
+//   - param: A pointer to the conversion state
+//
+// Returns:
+//   - [][]byte: Ollama JSON messages without trailing newlines
+func ConvertOpenAIResponseToOllama(_ context.Context, modelName string, originalRequestRawJSON, _, rawJSON []byte, param *any) [][]byte {
+	if *param == nil {
+		*param = newOllamaParams(modelName, originalRequestRawJSON)
+	}
+	state := (*param).(*ConvertOpenAIResponseToOllamaParams)
+
+	rawJSON = bytes.TrimSpace(rawJSON)
+	if bytes.HasPrefix(rawJSON, dataTag) {
+		rawJSON = bytes.TrimSpace(rawJSON[len(dataTag):])
+	}
+	if len(rawJSON) == 0 {
+		return nil
+	}
+	if bytes.Equal(rawJSON, []byte("[DONE]")) {
+		return finishOllamaStream(state)
+	}
+	if state.Finished || !gjson.ValidBytes(rawJSON) {
+		return nil
+	}
+
+	root := gjson.ParseBytes(rawJSON)
+	if usage := root.Get("usage"); usage.IsObject() {
+		state.PromptTokens = usage.Get("prompt_tokens").Int()
+		state.CompletionTokens = usage.Get("completion_tokens").Int()
+	}
+
+	var outputs [][]byte
+	root.Get("choices").ForEach(func(_, choice gjson.Result) bool {
+		delta := choice.Get("delta")
+		content := delta.Get("content").String()
+		thinking := delta.Get("reasoning_content").String()
+		if thinking == "" {
+			thinking = delta.Get("reasoning").String()
+		}
+		if content != "" || thinking != "" {
+			outputs = append(outputs, state.message(content, thinking, nil))
+		}
+		delta.Get("tool_calls").ForEach(func(_, toolCall gjson.Result) bool {
+			index := int(toolCall.Get("index").Int())
+			acc, ok := state.ToolCalls[index]
+			if !ok {
+				acc = &ToolCallAccumulator{}
+				state.ToolCalls[index] = acc
+			}
+			if name := toolCall.Get("function.name").String(); name != "" {
+				acc.Name = name
+			}
+			acc.Arguments.WriteString(toolCall.Get("function.arguments").String())
+			return true
+		})
+		if reason := choice.Get("finish_reason").String(); reason != "" {
+			state.DoneReason = reason
+		}
+		return true
+	})
+	return outputs
+}
+
+// ConvertOpenAIResponseToOllamaNonStream converts a complete OpenAI Chat Completions response
+// into a single Ollama chat or generate response.
+//
+// Parameters:
+//   - ctx: The context for the request
+//   - modelName: The upstream model name, used when the original request has none
+//   - originalRequestRawJSON: The Ollama request, used to pick the chat or generate shape
+//   - rawJSON: The OpenAI response body
+//   - param: Unused
+//
+// Returns:
+//   - []byte: The Ollama JSON response
+func ConvertOpenAIResponseToOllamaNonStream(_ context.Context, modelName string, originalRequestRawJSON, _, rawJSON []byte, _ *any) []byte {
+	state := newOllamaParams(modelName, originalRequestRawJSON)
+	root := gjson.ParseBytes(rawJSON)
+	message := root.Get("choices.0.message")
+
+	thinking := message.Get("reasoning_content").String()
+	if thinking == "" {
+		thinking = message.Get("reasoning").String()
+	}
+	var toolCalls [][]byte
+	message.Get("tool_calls").ForEach(func(_, toolCall gjson.Result) bool {
+		toolCalls = append(toolCalls, buildOllamaToolCall(toolCall.Get("function.name").String(), toolCall.Get("function.arguments").String()))
+		return true
+	})
+
+	out := state.message(message.Get("content").String(), thinking, toolCalls)
+	state.DoneReason = root.Get("choices.0.finish_reason").String()
+	state.PromptTokens = root.Get("usage.prompt_tokens").Int()
+	state.CompletionTokens = root.Get("usage.completion_tokens").Int()
+	return state.markDone(out, false)
+}
+
+func newOllamaParams(modelName string, originalRequestRawJSON []byte) *ConvertOpenAIResponseToOllamaParams {
+	model := gjson.GetBytes(originalRequestRawJSON, "model").String()
+	if model == "" {
+		model = modelName
+	}
+	return &ConvertOpenAIResponseToOllamaParams{
+		Model:     model,
+		Generate:  IsGenerateRequest(originalRequestRawJSON),
+		Started:   time.Now(),
+		ToolCalls: make(map[int]*ToolCallAccumulator),
+	}
+}
+
+func finishOllamaStream(state *ConvertOpenAIResponseToOllamaParams) [][]byte {
+	if state.Finished {
+		return nil
+	}
+	state.Finished = true
+
+	var outputs [][]byte
+	if len(state.ToolCalls) > 0 && !state.Generate {
+		indexes := make([]int, 0, len(state.ToolCalls))
+		for index := range state.ToolCalls {
+			indexes = append(indexes, index)
+		}
+		sort.Ints(indexes)
+		toolCalls := make([][]byte, 0, len(indexes))
+		for _, index := range indexes {
+			acc := state.ToolCalls[index]
+			toolCalls = append(toolCalls, buildOllamaToolCall(acc.Name, acc.Arguments.String()))
+		}
+		outputs = append(outputs, state.message("", "", toolCalls))
+	}
+	return append(outputs, state.markDone(state.message("", "", nil), true))
+}
+
+// message builds one Ollama message in the chat or generate shape with done set to false.
+func (s *ConvertOpenAIResponseToOllamaParams) message(content, thinking string, toolCalls [][]byte) []byte {
+	out := []byte(`{"model":"","created_at":""}`)
+	out, _ = sjson.SetBytes(out, "model", s.Model)
+	out, _ = sjson.SetBytes(out, "created_at", time.Now().UTC().Format(time.RFC3339Nano))
+	if s.Generate {
+		out, _ = sjson.SetBytes(out, "response", content)
+		if thinking != "" {
+			out, _ = sjson.SetBytes(out, "thinking", thinking)
+		}
+	} else {
+		out, _ = sjson.SetRawBytes(out, "message", []byte(`{"role":"assistant","content":""}`))
+		out, _ = sjson.SetBytes(out, "message.content", content)
+		if thinking != "" {
+			out, _ = sjson.SetBytes(out, "message.thinking", thinking)
+		}
+		for _, toolCall := range toolCalls {
+			out, _ = sjson.SetRawBytes(out, "message.tool_calls.-1", toolCall)
+		}
+	}
+	out, _ = sjson.SetBytes(out, "done", false)
+	return out
+}
+
+// markDone turns a message into the final one carrying the stop reason and token counts.
+func (s *ConvertOpenAIResponseToOllamaParams) markDone(out []byte, withDuration bool) []byte {
+	out, _ = sjson.SetBytes(out, "done", true)
+	out, _ = sjson.SetBytes(out, "done_reason", ollamaDoneReason(s.DoneReason))
+	if withDuration {
+		out, _ = sjson.SetBytes(out, "total_duration", time.Since(s.Started).Nanoseconds())
+	}
+	out, _ = sjson.SetBytes(out, "prompt_eval_count", s.PromptTokens)
+	out, _ = sjson.SetBytes(out, "eval_count", s.CompletionTokens)
+	return out
+}
+
+// ollamaDoneReason maps an OpenAI finish_reason onto the values Ollama reports.
+func ollamaDoneReason(reason string) string {
+	switch reason {
+	case "length":
+		return "length"
+	case "", "stop", "tool_calls", "function_call":
+		return "stop"
+	default:
+		return reason
+	}
+}
+
+// buildOllamaToolCall converts OpenAI string arguments into the object Ollama expects.
+func buildOllamaToolCall(name, arguments string) []byte {
+	call := []byte(`{"function":{"name":"","arguments":{}}}`)
+	call, _ = sjson.SetBytes(call, "function.name", name)
+	arguments = strings.TrimSpace(arguments)
+	if arguments != "" && gjson.Valid(arguments) && gjson.Parse(arguments).IsObject() {
+		call, _ = sjson.SetRawBytes(call, "function.arguments", []byte(arguments))
+	}
+	return call
+}
@@ -0,0 +1,87 @@
+package ollama
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tidwall/gjson"
+)
+
+func runStream(originalReq string, chunks ...string) []gjson.Result {
+	var param any
+	var lines []gjson.Result
+	for _, chunk := range append(chunks, "[DONE]") {
+		for _, out := range ConvertOpenAIResponseToOllama(context.Background(), "upstream", []byte(originalReq), nil, []byte("data: "+chunk), &param) {
+			lines = append(lines, gjson.ParseBytes(out))
+		}
+	}
+	return lines
+}
+
+func TestConvertOpenAIStreamToOllamaChat(t *testing.T) {
+	lines := runStream(`{"model":"gpt-5","messages":[]}`,
+		`{"choices":[{"index":0,"delta":{"role":"assistant","reasoning_content":"hmm"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":"Hel"}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","function":{"name":"get_weather","arguments":"{\"city\":"}}]}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Paris\"}"}}]}}]}`,
+		`{"choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
+		`{"choices":[],"usage":{"prompt_tokens":11,"completion_tokens":5}}`,
+	)
+
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4", len(lines))
+	}
+	if lines[0].Get("message.thinking").String() != "hmm" || lines[0].Get("model").String() != "gpt-5" {
+		t.Fatalf("unexpected thinking line: %s", lines[0].Raw)
+	}
+	if lines[1].Get("message.content").String() != "Hel" || lines[1].Get("done").Bool() {
+		t.Fatalf("unexpected content line: %s", lines[1].Raw)
+	}
+	if lines[2].Get("message.tool_calls.0.function.arguments.city").String() != "Paris" {
+		t.Fatalf("tool call not assembled: %s", lines[2].Raw)
+	}
+	final := lines[3]
+	if !final.Get("done").Bool() || final.Get("done_reason").String() != "stop" {
+		t.Fatalf("unexpected final line: %s", final.Raw)
+	}
+	if final.Get("prompt_eval_count").Int() != 11 || final.Get("eval_count").Int() != 5 {
+		t.Fatalf("usage not reported: %s", final.Raw)
+	}
+
+	var param any
+	_ = ConvertOpenAIResponseToOllama(context.Background(), "m", nil, nil, []byte("[DONE]"), &param)
+	if out := ConvertOpenAIResponseToOllama(context.Background(), "m", nil, nil, []byte("[DONE]"), &param); len(out) != 0 {
+		t.Fatal("done message must only be emitted once")
+	}
+}
+
+func TestConvertOpenAIStreamToOllamaGenerate(t *testing.T) {
+	lines := runStream(`{"model":"gemini-2.5-pro","prompt":"hi"}`,
+		`{"choices":[{"index":0,"delta":{"content":"Hello"}}]}`,
+		`{"choices":[{"index":0,"delta":{},"finish_reason":"length"}],"usage":{"prompt_tokens":2,"completion_tokens":1}}`,
+	)
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	if lines[0].Get("response").String() != "Hello" || lines[0].Get("message").Exists() {
+		t.Fatalf("unexpected generate line: %s", lines[0].Raw)
+	}
+	if lines[1].Get("done_reason").String() != "length" || lines[1].Get("eval_count").Int() != 1 {
+		t.Fatalf("unexpected final line: %s", lines[1].Raw)
+	}
+}
+
+func TestConvertOpenAIResponseToOllamaNonStream(t *testing.T) {
+	resp := []byte(`{"choices":[{"message":{"role":"assistant","content":"","tool_calls":[{"id":"c1","type":"function","function":{"name":"f","arguments":"{\"a\":1}"}}]},"finish_reason":"tool_calls"}],"usage":{"prompt_tokens":3,"completion_tokens":4}}`)
+	out := gjson.ParseBytes(ConvertOpenAIResponseToOllamaNonStream(context.Background(), "upstream", []byte(`{"model":"gpt-5","messages":[]}`), nil, resp, nil))
+
+	if !out.Get("done").Bool() || out.Get("done_reason").String() != "stop" {
+		t.Fatalf("unexpected done fields: %s", out.Raw)
+	}
+	if out.Get("message.tool_calls.0.function.arguments.a").Int() != 1 {
+		t.Fatalf("tool call not converted: %s", out.Raw)
+	}
+	if out.Get("prompt_eval_count").Int() != 3 || out.Get("eval_count").Int() != 4 {
+		t.Fatalf("usage not converted: %s", out.Raw)
+	}
+}
@@ -199,6 +199,9 @@ func (m authTabModel) renderContent() string {
 		if disabled {
 			statusIcon = lipgloss.NewStyle().Foreground(colorMuted).Render("○")
 			statusText = T("status_disabled")
+		} else if health, ok := f["health"].(map[string]any); ok && getBool(health, "quarantined") {
+			statusIcon = errorStyle.Render("●")
+			statusText = T("status_quarantined")
 		}
 
 		cursor := "  "
@@ -306,11 +309,47 @@ func (m authTabModel) renderDetail(f map[string]any) string {
 		sb.WriteString(line)
 		sb.WriteString("\n")
 	}
+	if health := healthSummary(f); health != "" {
+		sb.WriteString(fmt.Sprintf("    │ %s %s\n",
+			labelStyle.Render(fmt.Sprintf("%-12s:", "Health")),
+			valueStyle.Render(health)))
+	}
 
 	sb.WriteString("    └─────────────────────────────────────────────\n")
 	return sb.String()
 }
 
+// healthSummary renders the probe history of an auth file, oldest probe first,
+// followed by the consecutive failure count. Files no probe applies to are shown
+// as not probeable.
+func healthSummary(f map[string]any) string {
+	health, ok := f["health"].(map[string]any)
+	if !ok {
+		return ""
+	}
+	if getBool(health, "not_probeable") {
+		return T("not_probeable")
+	}
+	history, _ := health["history"].([]any)
+	var marks strings.Builder
+	for _, item := range history {
+		probe, okProbe := item.(map[string]any)
+		if !okProbe {
+			continue
+		}
+		if getBool(probe, "success") {
+			marks.WriteString("✓")
+		} else {
+			marks.WriteString("✗")
+		}
+	}
+	summary := fmt.Sprintf("%s failures=%d", marks.String(), int(getFloat(health, "consecutive_failures")))
+	if getBool(health, "quarantined") {
+		summary += " (" + T("status_quarantined") + ")"
+	}
+	return strings.TrimSpace(summary)
+}
+
 // getAnyString converts any value to its string representation.
 func getAnyString(m map[string]any, key string) string {
 	v, ok := m[key]
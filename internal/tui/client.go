@@ -9,6 +9,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
 )
 
 // Client wraps HTTP calls to the management API.
@@ -336,6 +338,34 @@ func extractList(wrapper map[string]any, key string) ([]map[string]any, error) {
 	return result, nil
 }
 
+// ManagementIdentity describes the management key used by the client.
+type ManagementIdentity struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// GetManagementIdentity fetches the name and scopes of the configured management key.
+func (c *Client) GetManagementIdentity() (*ManagementIdentity, error) {
+	data, err := c.get("/v0/management/whoami")
+	if err != nil {
+		return nil, err
+	}
+	var identity ManagementIdentity
+	if err := json.Unmarshal(data, &identity); err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// Allows reports whether the key may read a route group guarded by scope.
+// A nil identity (servers without /whoami) is treated as unrestricted.
+func (i *ManagementIdentity) Allows(scope string, readScopes ...string) bool {
+	if i == nil {
+		return true
+	}
+	return config.ManagementScopeAllows(i.Scopes, true, scope, readScopes...)
+}
+
 // GetDebug fetches the current debug setting.
 func (c *Client) GetDebug() (bool, error) {
 	wrapper, err := c.getJSON("/v0/management/debug")
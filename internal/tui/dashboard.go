@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
 )
 
 // dashboardModel displays server info, stats cards, and config overview.
@@ -24,12 +25,14 @@ type dashboardModel struct {
 	lastConfig    map[string]any
 	lastAuthFiles []map[string]any
 	lastAPIKeys   []string
+	lastIdentity  *ManagementIdentity
 }
 
 type dashboardDataMsg struct {
 	config    map[string]any
 	authFiles []map[string]any
 	apiKeys   []string
+	identity  *ManagementIdentity
 	err       error
 }
 
@@ -44,9 +47,21 @@ func (m dashboardModel) Init() tea.Cmd {
 }
 
 func (m dashboardModel) fetchData() tea.Msg {
-	cfg, cfgErr := m.client.GetConfig()
-	authFiles, authErr := m.client.GetAuthFiles()
-	apiKeys, keysErr := m.client.GetAPIKeys()
+	// Scoped management keys only see the sections they may read.
+	identity, _ := m.client.GetManagementIdentity()
+	var (
+		cfg                      map[string]any
+		authFiles                []map[string]any
+		apiKeys                  []string
+		cfgErr, authErr, keysErr error
+	)
+	if identity.Allows(config.ManagementScopeConfigAdmin, config.ManagementScopeReadOnly) {
+		cfg, cfgErr = m.client.GetConfig()
+		apiKeys, keysErr = m.client.GetAPIKeys()
+	}
+	if identity.Allows(config.ManagementScopeAuthFiles, config.ManagementScopeReadOnly) {
+		authFiles, authErr = m.client.GetAuthFiles()
+	}
 
 	var err error
 	for _, e := range []error{cfgErr, authErr, keysErr} {
@@ -55,14 +70,14 @@ func (m dashboardModel) fetchData() tea.Msg {
 			break
 		}
 	}
-	return dashboardDataMsg{config: cfg, authFiles: authFiles, apiKeys: apiKeys, err: err}
+	return dashboardDataMsg{config: cfg, authFiles: authFiles, apiKeys: apiKeys, identity: identity, err: err}
 }
 
 func (m dashboardModel) Update(msg tea.Msg) (dashboardModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case localeChangedMsg:
 		// Re-render immediately with cached data using new locale
-		m.content = m.renderDashboard(m.lastConfig, m.lastAuthFiles, m.lastAPIKeys)
+		m.content = m.renderDashboard(m.lastConfig, m.lastAuthFiles, m.lastAPIKeys, m.lastIdentity)
 		m.viewport.SetContent(m.content)
 		// Also fetch fresh data in background
 		return m, m.fetchData
@@ -77,8 +92,9 @@ func (m dashboardModel) Update(msg tea.Msg) (dashboardModel, tea.Cmd) {
 			m.lastConfig = msg.config
 			m.lastAuthFiles = msg.authFiles
 			m.lastAPIKeys = msg.apiKeys
+			m.lastIdentity = msg.identity
 
-			m.content = m.renderDashboard(msg.config, msg.authFiles, msg.apiKeys)
+			m.content = m.renderDashboard(msg.config, msg.authFiles, msg.apiKeys, msg.identity)
 		}
 		m.viewport.SetContent(m.content)
 		return m, nil
@@ -117,7 +133,7 @@ func (m dashboardModel) View() string {
 	return m.viewport.View()
 }
 
-func (m dashboardModel) renderDashboard(cfg map[string]any, authFiles []map[string]any, apiKeys []string) string {
+func (m dashboardModel) renderDashboard(cfg map[string]any, authFiles []map[string]any, apiKeys []string, identity *ManagementIdentity) string {
 	var sb strings.Builder
 
 	sb.WriteString(titleStyle.Render(T("dashboard_title")))
@@ -129,7 +145,11 @@ func (m dashboardModel) renderDashboard(cfg map[string]any, authFiles []map[stri
 	connStyle := lipgloss.NewStyle().Bold(true).Foreground(colorSuccess)
 	sb.WriteString(connStyle.Render(T("connected")))
 	sb.WriteString(fmt.Sprintf("  %s", m.client.baseURL))
-	sb.WriteString("\n\n")
+	sb.WriteString("\n")
+	if identity != nil {
+		sb.WriteString(formatKV(T("current_key"), fmt.Sprintf("%s (%s)", identity.Name, strings.Join(identity.Scopes, ", "))))
+	}
+	sb.WriteString("\n")
 
 	// ━━━ Stats Cards ━━━
 	cardWidth := 25
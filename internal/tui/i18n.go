@@ -99,6 +99,7 @@ var zhStrings = map[string]string{
 	"dashboard_help":   " [r] 刷新 • [↑↓] 滚动",
 	"connected":        "● 已连接",
 	"mgmt_keys":        "管理密钥",
+	"current_key":      "当前密钥",
 	"auth_files_label": "认证文件",
 	"active_suffix":    "活跃",
 	"total_requests":   "请求",
@@ -134,17 +135,19 @@ var zhStrings = map[string]string{
 	"section_other":     "其他",
 
 	// ── Auth Files ──
-	"auth_title":      "🔑 认证文件",
-	"auth_help1":      " [↑↓/jk] 导航 • [Enter] 展开 • [e] 启用/停用 • [d] 删除 • [r] 刷新",
-	"auth_help2":      " [1] 编辑 prefix • [2] 编辑 proxy_url • [3] 编辑 priority",
-	"no_auth_files":   "  无认证文件",
-	"confirm_delete":  "⚠ 删除 %s? [y/n]",
-	"deleted":         "已删除 %s",
-	"enabled":         "已启用",
-	"disabled":        "已停用",
-	"updated_field":   "已更新 %s 的 %s",
-	"status_active":   "活跃",
-	"status_disabled": "已停用",
+	"auth_title":         "🔑 认证文件",
+	"auth_help1":         " [↑↓/jk] 导航 • [Enter] 展开 • [e] 启用/停用 • [d] 删除 • [r] 刷新",
+	"auth_help2":         " [1] 编辑 prefix • [2] 编辑 proxy_url • [3] 编辑 priority",
+	"no_auth_files":      "  无认证文件",
+	"confirm_delete":     "⚠ 删除 %s? [y/n]",
+	"deleted":            "已删除 %s",
+	"enabled":            "已启用",
+	"disabled":           "已停用",
+	"updated_field":      "已更新 %s 的 %s",
+	"status_active":      "活跃",
+	"status_disabled":    "已停用",
+	"status_quarantined": "已隔离",
+	"not_probeable":      "无法探测",
 
 	// ── API Keys ──
 	"keys_title":         "🔐 API 密钥",
@@ -250,6 +253,7 @@ var enStrings = map[string]string{
 	"dashboard_help":   " [r] Refresh • [↑↓] Scroll",
 	"connected":        "● Connected",
 	"mgmt_keys":        "Mgmt Keys",
+	"current_key":      "Current key",
 	"auth_files_label": "Auth Files",
 	"active_suffix":    "active",
 	"total_requests":   "Requests",
@@ -285,17 +289,19 @@ var enStrings = map[string]string{
 	"section_other":     "Other",
 
 	// ── Auth Files ──
-	"auth_title":      "🔑 Auth Files",
-	"auth_help1":      " [↑↓/jk] Navigate • [Enter] Expand • [e] Enable/Disable • [d] Delete • [r] Refresh",
-	"auth_help2":      " [1] Edit prefix • [2] Edit proxy_url • [3] Edit priority",
-	"no_auth_files":   "  No auth files found",
-	"confirm_delete":  "⚠ Delete %s? [y/n]",
-	"deleted":         "Deleted %s",
-	"enabled":         "Enabled",
-	"disabled":        "Disabled",
-	"updated_field":   "Updated %s on %s",
-	"status_active":   "active",
-	"status_disabled": "disabled",
+	"auth_title":         "🔑 Auth Files",
+	"auth_help1":         " [↑↓/jk] Navigate • [Enter] Expand • [e] Enable/Disable • [d] Delete • [r] Refresh",
+	"auth_help2":         " [1] Edit prefix • [2] Edit proxy_url • [3] Edit priority",
+	"no_auth_files":      "  No auth files found",
+	"confirm_delete":     "⚠ Delete %s? [y/n]",
+	"deleted":            "Deleted %s",
+	"enabled":            "Enabled",
+	"disabled":           "Disabled",
+	"updated_field":      "Updated %s on %s",
+	"status_active":      "active",
+	"status_disabled":    "disabled",
+	"status_quarantined": "quarantined",
+	"not_probeable":      "not probeable",
 
 	// ── API Keys ──
 	"keys_title":         "🔐 API Keys",
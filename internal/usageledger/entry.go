@@ -0,0 +1,77 @@
+// Package usageledger persists usage records so token and request counts can
+// be aggregated over arbitrary time ranges after restarts.
+package usageledger
+
+import (
+	"strings"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	coreusage "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/usage"
+)
+
+// Entry is a single persisted usage record. Client keys are stored by label so
+// raw secrets never reach the ledger.
+type Entry struct {
+	Timestamp       time.Time `json:"timestamp"`
+	ClientKey       string    `json:"client_key,omitempty"`
+	Provider        string    `json:"provider,omitempty"`
+	ExecutorType    string    `json:"executor_type,omitempty"`
+	Model           string    `json:"model,omitempty"`
+	Alias           string    `json:"alias,omitempty"`
+	AuthIndex       string    `json:"auth_index,omitempty"`
+	AuthType        string    `json:"auth_type,omitempty"`
+	Failed          bool      `json:"failed,omitempty"`
+	StatusCode      int       `json:"status_code,omitempty"`
+	LatencyMs       int64     `json:"latency_ms"`
+	TTFTMs          int64     `json:"ttft_ms,omitempty"`
+	InputTokens     int64     `json:"input_tokens"`
+	OutputTokens    int64     `json:"output_tokens"`
+	ReasoningTokens int64     `json:"reasoning_tokens,omitempty"`
+	CachedTokens    int64     `json:"cached_tokens,omitempty"`
+	TotalTokens     int64     `json:"total_tokens"`
+	CostUSD         float64   `json:"cost_usd,omitempty"`
+	FallbackFrom    string    `json:"fallback_from,omitempty"`
+}
+
+// EntryFromRecord converts a runtime usage record into a ledger entry.
+func EntryFromRecord(record coreusage.Record) Entry {
+	timestamp := record.RequestedAt
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	clientKey := strings.TrimSpace(record.APIKeyName)
+	if clientKey == "" {
+		clientKey = config.MaskClientAPIKey(record.APIKey)
+	}
+	detail := record.Detail
+	total := detail.TotalTokens
+	if total == 0 {
+		total = detail.InputTokens + detail.OutputTokens + detail.ReasoningTokens
+	}
+	cached := detail.CachedTokens
+	if cached == 0 {
+		cached = detail.CacheReadTokens
+	}
+	return Entry{
+		Timestamp:       timestamp.UTC(),
+		ClientKey:       clientKey,
+		Provider:        strings.TrimSpace(record.Provider),
+		ExecutorType:    strings.TrimSpace(record.ExecutorType),
+		Model:           strings.TrimSpace(record.Model),
+		Alias:           strings.TrimSpace(record.Alias),
+		AuthIndex:       strings.TrimSpace(record.AuthIndex),
+		AuthType:        strings.TrimSpace(record.AuthType),
+		Failed:          record.Failed,
+		StatusCode:      record.Fail.StatusCode,
+		LatencyMs:       record.Latency.Milliseconds(),
+		TTFTMs:          record.TTFT.Milliseconds(),
+		InputTokens:     detail.InputTokens,
+		OutputTokens:    detail.OutputTokens,
+		ReasoningTokens: detail.ReasoningTokens,
+		CachedTokens:    cached,
+		TotalTokens:     total,
+		CostUSD:         record.CostUSD,
+		FallbackFrom:    strings.TrimSpace(record.FallbackFrom),
+	}
+}
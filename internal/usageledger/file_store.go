@@ -0,0 +1,169 @@
+package usageledger
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	fileStorePrefix = "usage-"
+	fileStoreSuffix = ".jsonl"
+	fileStoreLayout = "2006-01-02"
+)
+
+// FileStore appends entries as JSON lines to one file per UTC day.
+type FileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileStore prepares dir for ledger files.
+func NewFileStore(dir string) (*FileStore, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return nil, fmt.Errorf("usage ledger: file store directory is required")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("usage ledger: create directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) pathForDay(day time.Time) string {
+	return filepath.Join(s.dir, fileStorePrefix+day.UTC().Format(fileStoreLayout)+fileStoreSuffix)
+}
+
+// Append writes entries to the daily files matching their timestamps.
+func (s *FileStore) Append(_ context.Context, entries []Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	byPath := make(map[string][]Entry)
+	var order []string
+	for _, entry := range entries {
+		path := s.pathForDay(entry.Timestamp)
+		if _, ok := byPath[path]; !ok {
+			order = append(order, path)
+		}
+		byPath[path] = append(byPath[path], entry)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, path := range order {
+		if err := appendEntries(path, byPath[path]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendEntries(path string, entries []Entry) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("usage ledger: open %s: %w", filepath.Base(path), err)
+	}
+	writer := bufio.NewWriter(f)
+	encoder := json.NewEncoder(writer)
+	for _, entry := range entries {
+		if err = encoder.Encode(entry); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("usage ledger: encode entry: %w", err)
+		}
+	}
+	if err = writer.Flush(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("usage ledger: write %s: %w", filepath.Base(path), err)
+	}
+	return f.Close()
+}
+
+// Aggregate scans the daily files covering the query range.
+func (s *FileStore) Aggregate(ctx context.Context, q Query) ([]Aggregate, error) {
+	if err := q.Normalize(); err != nil {
+		return nil, err
+	}
+	agg := newAggregator(q)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	day := truncateBucket(q.From, IntervalDay)
+	for day.Before(q.To) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := scanEntries(s.pathForDay(day), agg.add); err != nil {
+			return nil, err
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return agg.result(), nil
+}
+
+func scanEntries(path string, fn func(Entry)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("usage ledger: open %s: %w", filepath.Base(path), err)
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var entry Entry
+		if err = json.Unmarshal(line, &entry); err != nil {
+			// A torn final line after a crash must not break reporting.
+			log.Debugf("usage ledger: skip malformed line in %s: %v", filepath.Base(path), err)
+			continue
+		}
+		fn(entry)
+	}
+	if err = scanner.Err(); err != nil {
+		return fmt.Errorf("usage ledger: read %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// Prune deletes daily files that end before the cutoff.
+func (s *FileStore) Prune(_ context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dirEntries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("usage ledger: read directory: %w", err)
+	}
+	cutoff := truncateBucket(before, IntervalDay)
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if dirEntry.IsDir() || !strings.HasPrefix(name, fileStorePrefix) || !strings.HasSuffix(name, fileStoreSuffix) {
+			continue
+		}
+		day, errParse := time.Parse(fileStoreLayout, strings.TrimSuffix(strings.TrimPrefix(name, fileStorePrefix), fileStoreSuffix))
+		if errParse != nil || !day.Before(cutoff) {
+			continue
+		}
+		if errRemove := os.Remove(filepath.Join(s.dir, name)); errRemove != nil && !os.IsNotExist(errRemove) {
+			return fmt.Errorf("usage ledger: remove %s: %w", name, errRemove)
+		}
+	}
+	return nil
+}
+
+// Close is a no-op; files are opened per append.
+func (s *FileStore) Close() error { return nil }
@@ -0,0 +1,106 @@
+package usageledger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileStoreAggregate(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	day1 := time.Date(2026, 3, 2, 10, 30, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	entries := []Entry{
+		{Timestamp: day1, ClientKey: "team-a", Provider: "claude", Model: "claude-sonnet-4", InputTokens: 10, OutputTokens: 5, TotalTokens: 15, CostUSD: 0.25},
+		{Timestamp: day1.Add(time.Minute), ClientKey: "team-a", Provider: "claude", Model: "claude-sonnet-4", TotalTokens: 20, Failed: true, CostUSD: 0.5},
+		{Timestamp: day1, ClientKey: "team-b", Provider: "gemini", Model: "gemini-2.5-pro", TotalTokens: 7},
+		{Timestamp: day2, ClientKey: "team-a", Provider: "claude", Model: "claude-sonnet-4", TotalTokens: 100},
+	}
+	if err = store.Append(context.Background(), entries); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	rows, err := store.Aggregate(context.Background(), Query{
+		From:     day1.Add(-time.Hour),
+		To:       day2.Add(time.Hour),
+		Interval: IntervalDay,
+		GroupBy:  []string{DimensionClientKey},
+		Provider: "claude",
+	})
+	if err != nil {
+		t.Fatalf("Aggregate: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("rows = %#v", rows)
+	}
+	first := rows[0]
+	if first.Bucket == nil || !first.Bucket.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("bucket = %v", first.Bucket)
+	}
+	if first.ClientKey != "team-a" || first.Requests != 2 || first.Failed != 1 || first.TotalTokens != 35 || first.CostUSD != 0.75 {
+		t.Fatalf("first row = %#v", first)
+	}
+	if rows[1].TotalTokens != 100 {
+		t.Fatalf("second row = %#v", rows[1])
+	}
+
+	totals, err := store.Aggregate(context.Background(), Query{From: day1.Add(-time.Hour), To: day2.Add(time.Hour), GroupBy: []string{"model"}})
+	if err != nil {
+		t.Fatalf("Aggregate totals: %v", err)
+	}
+	if len(totals) != 2 || totals[0].Model != "claude-sonnet-4" || totals[0].Requests != 3 || totals[0].Bucket != nil {
+		t.Fatalf("totals = %#v", totals)
+	}
+}
+
+func TestFileStoreToleratesTornLines(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	ts := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	if err = store.Append(context.Background(), []Entry{{Timestamp: ts, TotalTokens: 3}}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	f, err := os.OpenFile(store.pathForDay(ts), os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	_, _ = f.WriteString(`{"timestamp":"2026-03-02T00:0`)
+	_ = f.Close()
+
+	rows, err := store.Aggregate(context.Background(), Query{From: ts, To: ts.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("Aggregate: %v", err)
+	}
+	if len(rows) != 1 || rows[0].TotalTokens != 3 {
+		t.Fatalf("rows = %#v", rows)
+	}
+}
+
+func TestFileStorePrune(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	old := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	recent := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	if err = store.Append(context.Background(), []Entry{{Timestamp: old}, {Timestamp: recent}}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if err = store.Prune(context.Background(), time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "usage-*.jsonl"))
+	if len(files) != 1 || !strings.HasSuffix(files[0], "usage-2026-01-10.jsonl") {
+		t.Fatalf("remaining files = %v", files)
+	}
+}
@@ -0,0 +1,258 @@
+package usageledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/util"
+	coreusage "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/usage"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// BackendFile stores entries as daily JSONL files.
+	BackendFile = "file"
+	// BackendPostgres stores entries in a PostgreSQL table.
+	BackendPostgres = "postgres"
+
+	defaultDirName   = "usage-ledger"
+	queueSize        = 4096
+	flushBatchSize   = 256
+	flushInterval    = time.Second
+	pruneInterval    = time.Hour
+	openStoreTimeout = 15 * time.Second
+)
+
+// ErrDisabled is returned by queries when the ledger is not enabled.
+var ErrDisabled = errors.New("usage ledger is disabled")
+
+func init() {
+	coreusage.RegisterPlugin(defaultLedger)
+}
+
+// Ledger buffers usage records and writes them to the configured store.
+type Ledger struct {
+	mu        sync.RWMutex
+	cfg       config.UsageLedgerConfig
+	store     Store
+	queue     chan Entry
+	stop      chan struct{}
+	done      chan struct{}
+	retention time.Duration
+}
+
+// NewLedger returns a disabled ledger. Call Apply to enable it.
+func NewLedger() *Ledger { return &Ledger{} }
+
+var defaultLedger = NewLedger()
+
+// Default returns the process-wide ledger registered as a usage plugin.
+func Default() *Ledger { return defaultLedger }
+
+// Enabled reports whether records are currently being persisted.
+func (l *Ledger) Enabled() bool {
+	if l == nil {
+		return false
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.store != nil
+}
+
+// Apply (re)configures the ledger. The store is only reopened when the storage settings change.
+func (l *Ledger) Apply(cfg config.UsageLedgerConfig) error {
+	if l == nil {
+		return nil
+	}
+	cfg.Backend = strings.ToLower(strings.TrimSpace(cfg.Backend))
+	if cfg.Backend == "" {
+		cfg.Backend = BackendFile
+	}
+
+	l.mu.Lock()
+	if l.store != nil && cfg.Enable && sameStorage(l.cfg, cfg) {
+		l.cfg = cfg
+		l.retention = retentionFor(cfg)
+		l.mu.Unlock()
+		return nil
+	}
+	l.mu.Unlock()
+
+	l.Close()
+	if !cfg.Enable {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), openStoreTimeout)
+	defer cancel()
+	store, err := openStore(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.cfg = cfg
+	l.store = store
+	l.retention = retentionFor(cfg)
+	l.queue = make(chan Entry, queueSize)
+	l.stop = make(chan struct{})
+	l.done = make(chan struct{})
+	go l.run(store, l.queue, l.stop, l.done)
+	l.mu.Unlock()
+	log.Infof("usage ledger enabled (backend=%s)", cfg.Backend)
+	return nil
+}
+
+func sameStorage(a, b config.UsageLedgerConfig) bool {
+	return a.Backend == b.Backend &&
+		strings.TrimSpace(a.Dir) == strings.TrimSpace(b.Dir) &&
+		strings.TrimSpace(a.PostgresDSN) == strings.TrimSpace(b.PostgresDSN) &&
+		strings.TrimSpace(a.PostgresSchema) == strings.TrimSpace(b.PostgresSchema) &&
+		strings.TrimSpace(a.PostgresTable) == strings.TrimSpace(b.PostgresTable)
+}
+
+func retentionFor(cfg config.UsageLedgerConfig) time.Duration {
+	if cfg.RetentionDays <= 0 {
+		return 0
+	}
+	return time.Duration(cfg.RetentionDays) * 24 * time.Hour
+}
+
+func openStore(ctx context.Context, cfg config.UsageLedgerConfig) (Store, error) {
+	switch cfg.Backend {
+	case BackendFile:
+		dir := strings.TrimSpace(cfg.Dir)
+		if dir == "" {
+			base := util.WritablePath()
+			if base == "" {
+				base = "."
+			}
+			dir = filepath.Join(base, defaultDirName)
+		}
+		return NewFileStore(dir)
+	case BackendPostgres:
+		return NewPostgresStore(ctx, PostgresStoreConfig{
+			DSN:    cfg.PostgresDSN,
+			Schema: cfg.PostgresSchema,
+			Table:  cfg.PostgresTable,
+		})
+	default:
+		return nil, fmt.Errorf("usage ledger: unsupported backend %q", cfg.Backend)
+	}
+}
+
+// HandleUsage implements usage.Plugin. Records are dropped rather than
+// blocking the usage dispatcher when the write queue is full.
+func (l *Ledger) HandleUsage(_ context.Context, record coreusage.Record) {
+	if l == nil {
+		return
+	}
+	l.mu.RLock()
+	queue := l.queue
+	l.mu.RUnlock()
+	if queue == nil {
+		return
+	}
+	select {
+	case queue <- EntryFromRecord(record):
+	default:
+		log.Warn("usage ledger: write queue full, dropping record")
+	}
+}
+
+// Aggregate runs an aggregate query against the active store.
+func (l *Ledger) Aggregate(ctx context.Context, q Query) ([]Aggregate, error) {
+	if l == nil {
+		return nil, ErrDisabled
+	}
+	l.mu.RLock()
+	store := l.store
+	l.mu.RUnlock()
+	if store == nil {
+		return nil, ErrDisabled
+	}
+	return store.Aggregate(ctx, q)
+}
+
+// Close flushes pending entries and releases the store.
+func (l *Ledger) Close() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	store, stop, done := l.store, l.stop, l.done
+	l.store, l.queue, l.stop, l.done = nil, nil, nil, nil
+	l.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+	if store != nil {
+		if err := store.Close(); err != nil {
+			log.Warnf("usage ledger: close store: %v", err)
+		}
+	}
+}
+
+func (l *Ledger) run(store Store, queue <-chan Entry, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	var lastPrune time.Time
+	batch := make([]Entry, 0, flushBatchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := store.Append(ctx, batch); err != nil {
+			log.Errorf("usage ledger: append %d entries: %v", len(batch), err)
+		}
+		cancel()
+		batch = batch[:0]
+	}
+	prune := func() {
+		l.mu.RLock()
+		retention := l.retention
+		l.mu.RUnlock()
+		if retention <= 0 || time.Since(lastPrune) < pruneInterval {
+			return
+		}
+		lastPrune = time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := store.Prune(ctx, lastPrune.Add(-retention)); err != nil {
+			log.Warnf("usage ledger: prune: %v", err)
+		}
+		cancel()
+	}
+
+	for {
+		select {
+		case entry := <-queue:
+			batch = append(batch, entry)
+			if len(batch) >= flushBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+			prune()
+		case <-stop:
+			for {
+				select {
+				case entry := <-queue:
+					batch = append(batch, entry)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package usageledger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	coreusage "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/usage"
+)
+
+func TestLedgerPersistsRecordsOnClose(t *testing.T) {
+	dir := t.TempDir()
+	ledger := NewLedger()
+	if _, err := ledger.Aggregate(context.Background(), Query{}); err != ErrDisabled {
+		t.Fatalf("disabled aggregate error = %v", err)
+	}
+	if err := ledger.Apply(config.UsageLedgerConfig{Enable: true, Dir: dir}); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	now := time.Now().UTC()
+	ledger.HandleUsage(context.Background(), coreusage.Record{
+		Provider:    "gemini",
+		Model:       "gemini-2.5-pro",
+		APIKey:      "sk-secret-value-1234",
+		RequestedAt: now,
+		Detail:      coreusage.Detail{InputTokens: 4, OutputTokens: 6},
+	})
+	ledger.Close()
+
+	if err := ledger.Apply(config.UsageLedgerConfig{Enable: true, Dir: dir}); err != nil {
+		t.Fatalf("re-Apply: %v", err)
+	}
+	defer ledger.Close()
+	rows, err := ledger.Aggregate(context.Background(), Query{From: now.Add(-time.Minute), To: now.Add(time.Minute), GroupBy: []string{DimensionClientKey}})
+	if err != nil {
+		t.Fatalf("Aggregate: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("rows = %#v", rows)
+	}
+	if rows[0].ClientKey != "sk-s...1234" {
+		t.Fatalf("client key should be masked, got %q", rows[0].ClientKey)
+	}
+	if rows[0].TotalTokens != 10 {
+		t.Fatalf("total tokens = %d", rows[0].TotalTokens)
+	}
+}
+
+func TestEntryFromRecordPrefersKeyName(t *testing.T) {
+	entry := EntryFromRecord(coreusage.Record{APIKey: "secret", APIKeyName: "team-a"})
+	if entry.ClientKey != "team-a" {
+		t.Fatalf("client key = %q", entry.ClientKey)
+	}
+	if entry.Timestamp.IsZero() {
+		t.Fatal("timestamp should default to now")
+	}
+}
+
+func TestEntryFromRecordKeepsFallbackOrigin(t *testing.T) {
+	entry := EntryFromRecord(coreusage.Record{Model: "gemini-2.5-pro", Alias: "gemini-2.5-pro", FallbackFrom: "claude-opus"})
+	if entry.FallbackFrom != "claude-opus" {
+		t.Fatalf("fallback from = %q", entry.FallbackFrom)
+	}
+}
@@ -0,0 +1,255 @@
+package usageledger
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+const defaultPostgresTable = "usage_ledger"
+
+// PostgresStoreConfig captures the connection settings for the Postgres ledger.
+type PostgresStoreConfig struct {
+	DSN    string
+	Schema string
+	Table  string
+}
+
+// PostgresStore persists ledger entries in a PostgreSQL table and aggregates with SQL.
+type PostgresStore struct {
+	db  *sql.DB
+	cfg PostgresStoreConfig
+}
+
+// NewPostgresStore connects to PostgreSQL and ensures the ledger table exists.
+func NewPostgresStore(ctx context.Context, cfg PostgresStoreConfig) (*PostgresStore, error) {
+	cfg.DSN = strings.TrimSpace(cfg.DSN)
+	if cfg.DSN == "" {
+		return nil, fmt.Errorf("usage ledger: postgres DSN is required")
+	}
+	cfg.Schema = strings.TrimSpace(cfg.Schema)
+	cfg.Table = strings.TrimSpace(cfg.Table)
+	if cfg.Table == "" {
+		cfg.Table = defaultPostgresTable
+	}
+	db, err := sql.Open("pgx", cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("usage ledger: open database connection: %w", err)
+	}
+	if err = db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("usage ledger: ping database: %w", err)
+	}
+	store := &PostgresStore{db: db, cfg: cfg}
+	if err = store.ensureSchema(ctx); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *PostgresStore) ensureSchema(ctx context.Context) error {
+	if s.cfg.Schema != "" {
+		if _, err := s.db.ExecContext(ctx, fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", quoteIdentifier(s.cfg.Schema))); err != nil {
+			return fmt.Errorf("usage ledger: create schema: %w", err)
+		}
+	}
+	table := s.tableName()
+	if _, err := s.db.ExecContext(ctx, fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id BIGSERIAL PRIMARY KEY,
+			ts TIMESTAMPTZ NOT NULL,
+			client_key TEXT NOT NULL DEFAULT '',
+			provider TEXT NOT NULL DEFAULT '',
+			executor_type TEXT NOT NULL DEFAULT '',
+			model TEXT NOT NULL DEFAULT '',
+			alias TEXT NOT NULL DEFAULT '',
+			auth_index TEXT NOT NULL DEFAULT '',
+			auth_type TEXT NOT NULL DEFAULT '',
+			failed BOOLEAN NOT NULL DEFAULT FALSE,
+			status_code INTEGER NOT NULL DEFAULT 0,
+			latency_ms BIGINT NOT NULL DEFAULT 0,
+			ttft_ms BIGINT NOT NULL DEFAULT 0,
+			input_tokens BIGINT NOT NULL DEFAULT 0,
+			output_tokens BIGINT NOT NULL DEFAULT 0,
+			reasoning_tokens BIGINT NOT NULL DEFAULT 0,
+			cached_tokens BIGINT NOT NULL DEFAULT 0,
+			total_tokens BIGINT NOT NULL DEFAULT 0,
+			cost_usd DOUBLE PRECISION NOT NULL DEFAULT 0,
+			fallback_from TEXT NOT NULL DEFAULT ''
+		)
+	`, table)); err != nil {
+		return fmt.Errorf("usage ledger: create table: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS cost_usd DOUBLE PRECISION NOT NULL DEFAULT 0", table)); err != nil {
+		return fmt.Errorf("usage ledger: add cost column: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS fallback_from TEXT NOT NULL DEFAULT ''", table)); err != nil {
+		return fmt.Errorf("usage ledger: add fallback column: %w", err)
+	}
+	index := quoteIdentifier(s.cfg.Table + "_ts_idx")
+	if _, err := s.db.ExecContext(ctx, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (ts)", index, table)); err != nil {
+		return fmt.Errorf("usage ledger: create index: %w", err)
+	}
+	return nil
+}
+
+// Append inserts entries in a single transaction.
+func (s *PostgresStore) Append(ctx context.Context, entries []Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("usage ledger: begin transaction: %w", err)
+	}
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf(`
+		INSERT INTO %s (ts, client_key, provider, executor_type, model, alias, auth_index, auth_type,
+			failed, status_code, latency_ms, ttft_ms, input_tokens, output_tokens, reasoning_tokens, cached_tokens, total_tokens, cost_usd, fallback_from)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+	`, s.tableName()))
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("usage ledger: prepare insert: %w", err)
+	}
+	defer func() { _ = stmt.Close() }()
+	for _, e := range entries {
+		if _, err = stmt.ExecContext(ctx, e.Timestamp, e.ClientKey, e.Provider, e.ExecutorType, e.Model, e.Alias, e.AuthIndex, e.AuthType,
+			e.Failed, e.StatusCode, e.LatencyMs, e.TTFTMs, e.InputTokens, e.OutputTokens, e.ReasoningTokens, e.CachedTokens, e.TotalTokens, e.CostUSD, e.FallbackFrom); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("usage ledger: insert entry: %w", err)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("usage ledger: commit: %w", err)
+	}
+	return nil
+}
+
+// Aggregate groups entries with date_trunc and GROUP BY on the requested dimensions.
+func (s *PostgresStore) Aggregate(ctx context.Context, q Query) ([]Aggregate, error) {
+	if err := q.Normalize(); err != nil {
+		return nil, err
+	}
+	query, args := buildPostgresAggregateQuery(s.tableName(), q)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("usage ledger: aggregate: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []Aggregate
+	for rows.Next() {
+		var row Aggregate
+		var bucket sql.NullTime
+		dims := make([]string, len(q.GroupBy))
+		dest := []any{&bucket}
+		for i := range dims {
+			dest = append(dest, &dims[i])
+		}
+		dest = append(dest, &row.Requests, &row.Failed, &row.InputTokens, &row.OutputTokens, &row.ReasoningTokens, &row.CachedTokens, &row.TotalTokens, &row.CostUSD)
+		if err = rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("usage ledger: scan aggregate: %w", err)
+		}
+		if bucket.Valid {
+			ts := bucket.Time.UTC()
+			row.Bucket = &ts
+		}
+		for i, dim := range q.GroupBy {
+			switch dim {
+			case DimensionClientKey:
+				row.ClientKey = dims[i]
+			case DimensionProvider:
+				row.Provider = dims[i]
+			case DimensionAuthIndex:
+				row.AuthIndex = dims[i]
+			case DimensionModel:
+				row.Model = dims[i]
+			}
+		}
+		out = append(out, row)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("usage ledger: iterate aggregate: %w", err)
+	}
+	sortAggregates(out)
+	return out, nil
+}
+
+// buildPostgresAggregateQuery renders the aggregate SQL. Dimensions and intervals
+// are validated by Query.Normalize, so only column names from a fixed set are interpolated.
+func buildPostgresAggregateQuery(table string, q Query) (string, []any) {
+	bucketExpr := "NULL::timestamptz"
+	if q.Interval != IntervalNone {
+		bucketExpr = fmt.Sprintf("date_trunc('%s', ts AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'", q.Interval)
+	}
+	selectCols := []string{bucketExpr + " AS bucket"}
+	groupCols := []string{"bucket"}
+	for _, dim := range q.GroupBy {
+		selectCols = append(selectCols, dim)
+		groupCols = append(groupCols, dim)
+	}
+	selectCols = append(selectCols,
+		"COUNT(*)",
+		"COUNT(*) FILTER (WHERE failed)",
+		"COALESCE(SUM(input_tokens), 0)",
+		"COALESCE(SUM(output_tokens), 0)",
+		"COALESCE(SUM(reasoning_tokens), 0)",
+		"COALESCE(SUM(cached_tokens), 0)",
+		"COALESCE(SUM(total_tokens), 0)",
+		"COALESCE(SUM(cost_usd), 0)",
+	)
+
+	where := []string{"ts >= $1", "ts < $2"}
+	args := []any{q.From, q.To}
+	for _, filter := range []struct {
+		column string
+		value  string
+	}{
+		{DimensionClientKey, q.ClientKey},
+		{DimensionProvider, q.Provider},
+		{DimensionAuthIndex, q.AuthIndex},
+		{DimensionModel, q.Model},
+	} {
+		if filter.value == "" {
+			continue
+		}
+		args = append(args, filter.value)
+		where = append(where, fmt.Sprintf("%s = $%d", filter.column, len(args)))
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s GROUP BY %s",
+		strings.Join(selectCols, ", "), table, strings.Join(where, " AND "), strings.Join(groupCols, ", "))
+	return query, args
+}
+
+// Prune deletes entries recorded before the cutoff.
+func (s *PostgresStore) Prune(ctx context.Context, before time.Time) error {
+	if _, err := s.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE ts < $1", s.tableName()), before); err != nil {
+		return fmt.Errorf("usage ledger: prune: %w", err)
+	}
+	return nil
+}
+
+// Close releases the database connection.
+func (s *PostgresStore) Close() error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+func (s *PostgresStore) tableName() string {
+	if s.cfg.Schema == "" {
+		return quoteIdentifier(s.cfg.Table)
+	}
+	return quoteIdentifier(s.cfg.Schema) + "." + quoteIdentifier(s.cfg.Table)
+}
+
+func quoteIdentifier(identifier string) string {
+	return "\"" + strings.ReplaceAll(identifier, "\"", "\"\"") + "\""
+}
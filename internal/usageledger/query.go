@@ -0,0 +1,214 @@
+package usageledger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Supported grouping dimensions.
+const (
+	DimensionClientKey = "client_key"
+	DimensionProvider  = "provider"
+	DimensionAuthIndex = "auth_index"
+	DimensionModel     = "model"
+)
+
+// Supported time bucket intervals.
+const (
+	IntervalNone  = ""
+	IntervalHour  = "hour"
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+// Query selects and groups ledger entries. From is inclusive and To is exclusive.
+type Query struct {
+	From     time.Time
+	To       time.Time
+	Interval string
+	GroupBy  []string
+
+	ClientKey string
+	Provider  string
+	AuthIndex string
+	Model     string
+}
+
+// Aggregate is one row of an aggregate query result.
+type Aggregate struct {
+	Bucket          *time.Time `json:"bucket,omitempty"`
+	ClientKey       string     `json:"client_key,omitempty"`
+	Provider        string     `json:"provider,omitempty"`
+	AuthIndex       string     `json:"auth_index,omitempty"`
+	Model           string     `json:"model,omitempty"`
+	Requests        int64      `json:"requests"`
+	Failed          int64      `json:"failed"`
+	InputTokens     int64      `json:"input_tokens"`
+	OutputTokens    int64      `json:"output_tokens"`
+	ReasoningTokens int64      `json:"reasoning_tokens"`
+	CachedTokens    int64      `json:"cached_tokens"`
+	TotalTokens     int64      `json:"total_tokens"`
+	CostUSD         float64    `json:"cost_usd"`
+}
+
+// Normalize validates the query and fills defaults.
+func (q *Query) Normalize() error {
+	q.Interval = strings.ToLower(strings.TrimSpace(q.Interval))
+	switch q.Interval {
+	case IntervalNone, IntervalHour, IntervalDay, IntervalWeek, IntervalMonth:
+	default:
+		return fmt.Errorf("unsupported interval %q", q.Interval)
+	}
+	seen := make(map[string]struct{}, len(q.GroupBy))
+	groupBy := make([]string, 0, len(q.GroupBy))
+	for _, dim := range q.GroupBy {
+		dim = strings.ToLower(strings.TrimSpace(dim))
+		if dim == "" {
+			continue
+		}
+		switch dim {
+		case DimensionClientKey, DimensionProvider, DimensionAuthIndex, DimensionModel:
+		default:
+			return fmt.Errorf("unsupported group_by dimension %q", dim)
+		}
+		if _, ok := seen[dim]; ok {
+			continue
+		}
+		seen[dim] = struct{}{}
+		groupBy = append(groupBy, dim)
+	}
+	q.GroupBy = groupBy
+	if q.To.IsZero() {
+		q.To = time.Now().UTC()
+	}
+	if q.From.IsZero() {
+		q.From = q.To.Add(-7 * 24 * time.Hour)
+	}
+	if !q.From.Before(q.To) {
+		return fmt.Errorf("from must be before to")
+	}
+	return nil
+}
+
+func (q *Query) matches(entry Entry) bool {
+	if entry.Timestamp.Before(q.From) || !entry.Timestamp.Before(q.To) {
+		return false
+	}
+	if q.ClientKey != "" && entry.ClientKey != q.ClientKey {
+		return false
+	}
+	if q.Provider != "" && entry.Provider != q.Provider {
+		return false
+	}
+	if q.AuthIndex != "" && entry.AuthIndex != q.AuthIndex {
+		return false
+	}
+	if q.Model != "" && entry.Model != q.Model {
+		return false
+	}
+	return true
+}
+
+func (q *Query) groups(dim string) bool {
+	for _, candidate := range q.GroupBy {
+		if candidate == dim {
+			return true
+		}
+	}
+	return false
+}
+
+// truncateBucket returns the start of the UTC bucket containing ts.
+func truncateBucket(ts time.Time, interval string) time.Time {
+	ts = ts.UTC()
+	switch interval {
+	case IntervalHour:
+		return ts.Truncate(time.Hour)
+	case IntervalDay:
+		return time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, time.UTC)
+	case IntervalWeek:
+		day := time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, time.UTC)
+		offset := (int(day.Weekday()) + 6) % 7 // weeks start on Monday
+		return day.AddDate(0, 0, -offset)
+	case IntervalMonth:
+		return time.Date(ts.Year(), ts.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Time{}
+	}
+}
+
+// aggregator accumulates entries in memory for backends without native grouping.
+type aggregator struct {
+	query Query
+	rows  map[string]*Aggregate
+}
+
+func newAggregator(q Query) *aggregator {
+	return &aggregator{query: q, rows: make(map[string]*Aggregate)}
+}
+
+func (a *aggregator) add(entry Entry) {
+	if !a.query.matches(entry) {
+		return
+	}
+	row := Aggregate{}
+	if a.query.Interval != IntervalNone {
+		bucket := truncateBucket(entry.Timestamp, a.query.Interval)
+		row.Bucket = &bucket
+	}
+	if a.query.groups(DimensionClientKey) {
+		row.ClientKey = entry.ClientKey
+	}
+	if a.query.groups(DimensionProvider) {
+		row.Provider = entry.Provider
+	}
+	if a.query.groups(DimensionAuthIndex) {
+		row.AuthIndex = entry.AuthIndex
+	}
+	if a.query.groups(DimensionModel) {
+		row.Model = entry.Model
+	}
+	key := aggregateKey(row)
+	existing, ok := a.rows[key]
+	if !ok {
+		existing = &row
+		a.rows[key] = existing
+	}
+	existing.Requests++
+	if entry.Failed {
+		existing.Failed++
+	}
+	existing.InputTokens += entry.InputTokens
+	existing.OutputTokens += entry.OutputTokens
+	existing.ReasoningTokens += entry.ReasoningTokens
+	existing.CachedTokens += entry.CachedTokens
+	existing.TotalTokens += entry.TotalTokens
+	existing.CostUSD += entry.CostUSD
+}
+
+func (a *aggregator) result() []Aggregate {
+	out := make([]Aggregate, 0, len(a.rows))
+	for _, row := range a.rows {
+		out = append(out, *row)
+	}
+	sortAggregates(out)
+	return out
+}
+
+func aggregateKey(row Aggregate) string {
+	bucket := ""
+	if row.Bucket != nil {
+		bucket = row.Bucket.Format(time.RFC3339)
+	}
+	return strings.Join([]string{bucket, row.ClientKey, row.Provider, row.AuthIndex, row.Model}, "\x00")
+}
+
+// sortAggregates orders rows by bucket and then by the grouped dimensions.
+func sortAggregates(rows []Aggregate) {
+	sort.Slice(rows, func(i, j int) bool {
+		return aggregateKey(rows[i]) < aggregateKey(rows[j])
+	})
+}
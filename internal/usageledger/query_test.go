@@ -0,0 +1,54 @@
+package usageledger
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQueryNormalize(t *testing.T) {
+	q := Query{Interval: "Day", GroupBy: []string{"model", " MODEL ", "provider"}}
+	if err := q.Normalize(); err != nil {
+		t.Fatalf("Normalize: %v", err)
+	}
+	if q.Interval != IntervalDay || len(q.GroupBy) != 2 {
+		t.Fatalf("query = %#v", q)
+	}
+	if q.To.Sub(q.From) != 7*24*time.Hour {
+		t.Fatalf("default range = %v", q.To.Sub(q.From))
+	}
+
+	for _, bad := range []Query{
+		{Interval: "minute"},
+		{GroupBy: []string{"api_key"}},
+		{From: time.Unix(10, 0), To: time.Unix(5, 0)},
+	} {
+		if err := bad.Normalize(); err == nil {
+			t.Fatalf("expected error for %#v", bad)
+		}
+	}
+}
+
+func TestTruncateBucketWeekStartsMonday(t *testing.T) {
+	sunday := time.Date(2026, 3, 8, 15, 0, 0, 0, time.UTC)
+	if got := truncateBucket(sunday, IntervalWeek); !got.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("week bucket = %v", got)
+	}
+}
+
+func TestBuildPostgresAggregateQuery(t *testing.T) {
+	q := Query{Interval: IntervalHour, GroupBy: []string{DimensionClientKey, DimensionModel}, Provider: "claude"}
+	if err := q.Normalize(); err != nil {
+		t.Fatalf("Normalize: %v", err)
+	}
+	query, args := buildPostgresAggregateQuery(`"usage_ledger"`, q)
+	if !strings.Contains(query, "date_trunc('hour'") {
+		t.Fatalf("missing bucket expression: %s", query)
+	}
+	if !strings.Contains(query, "GROUP BY bucket, client_key, model") {
+		t.Fatalf("unexpected grouping: %s", query)
+	}
+	if !strings.Contains(query, "provider = $3") || len(args) != 3 {
+		t.Fatalf("unexpected filter: %s %v", query, args)
+	}
+}
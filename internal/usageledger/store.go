@@ -0,0 +1,18 @@
+package usageledger
+
+import (
+	"context"
+	"time"
+)
+
+// Store persists ledger entries and answers aggregate queries.
+type Store interface {
+	// Append durably writes the entries.
+	Append(ctx context.Context, entries []Entry) error
+	// Aggregate groups stored entries according to the query.
+	Aggregate(ctx context.Context, q Query) ([]Aggregate, error)
+	// Prune removes entries recorded before the cutoff.
+	Prune(ctx context.Context, before time.Time) error
+	// Close releases backend resources.
+	Close() error
+}
@@ -13,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/authcrypt"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/redisqueue"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/util"
@@ -108,7 +109,7 @@ func (w *Watcher) reloadClients(rescanAuth bool, affectedOAuthProviders []string
 						// Parse and cache auth content for future diff comparisons (debug only).
 						if cacheAuthContents {
 							var auth coreauth.Auth
-							if errParse := json.Unmarshal(data, &auth); errParse == nil {
+							if plain, errOpen := authcrypt.Open(data); errOpen == nil && json.Unmarshal(plain, &auth) == nil {
 								newAuthContents[normalizedPath] = &auth
 							}
 						}
@@ -179,6 +180,12 @@ func (w *Watcher) addOrUpdateClientLocked(path string) {
 	curHash := hex.EncodeToString(sum[:])
 	normalized := w.normalizeAuthPath(path)
 
+	data, errOpen := authcrypt.Open(data)
+	if errOpen != nil {
+		log.Errorf("failed to decrypt auth file %s: %v", filepath.Base(path), errOpen)
+		return
+	}
+
 	// Parse new auth content for diff comparison
 	var newAuth coreauth.Auth
 	if errParse := json.Unmarshal(data, &newAuth); errParse != nil {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
@@ -33,6 +34,54 @@ func BuildConfigChangeDetails(oldCfg, newCfg *config.Config) []string {
 	if strings.TrimSpace(oldCfg.Pprof.Addr) != strings.TrimSpace(newCfg.Pprof.Addr) {
 		changes = append(changes, fmt.Sprintf("pprof.addr: %s -> %s", strings.TrimSpace(oldCfg.Pprof.Addr), strings.TrimSpace(newCfg.Pprof.Addr)))
 	}
+	if oldCfg.Metrics.Enable != newCfg.Metrics.Enable {
+		changes = append(changes, fmt.Sprintf("metrics.enable: %t -> %t", oldCfg.Metrics.Enable, newCfg.Metrics.Enable))
+	}
+	if strings.TrimSpace(oldCfg.Metrics.Addr) != strings.TrimSpace(newCfg.Metrics.Addr) {
+		changes = append(changes, fmt.Sprintf("metrics.addr: %s -> %s", strings.TrimSpace(oldCfg.Metrics.Addr), strings.TrimSpace(newCfg.Metrics.Addr)))
+	}
+	if oldCfg.QuotaPolling.Enable != newCfg.QuotaPolling.Enable {
+		changes = append(changes, fmt.Sprintf("quota-polling.enable: %t -> %t", oldCfg.QuotaPolling.Enable, newCfg.QuotaPolling.Enable))
+	}
+	if strings.TrimSpace(oldCfg.QuotaPolling.Interval) != strings.TrimSpace(newCfg.QuotaPolling.Interval) {
+		changes = append(changes, fmt.Sprintf("quota-polling.interval: %s -> %s", strings.TrimSpace(oldCfg.QuotaPolling.Interval), strings.TrimSpace(newCfg.QuotaPolling.Interval)))
+	}
+	if oldCfg.QuotaPolling.LowThreshold != newCfg.QuotaPolling.LowThreshold {
+		changes = append(changes, fmt.Sprintf("quota-polling.low-threshold: %g -> %g", oldCfg.QuotaPolling.LowThreshold, newCfg.QuotaPolling.LowThreshold))
+	}
+	if oldCfg.ResponseCache.Enable != newCfg.ResponseCache.Enable {
+		changes = append(changes, fmt.Sprintf("response-cache.enable: %t -> %t", oldCfg.ResponseCache.Enable, newCfg.ResponseCache.Enable))
+	}
+	if oldCfg.ResponseCache.TTLSeconds != newCfg.ResponseCache.TTLSeconds {
+		changes = append(changes, fmt.Sprintf("response-cache.ttl-seconds: %d -> %d", oldCfg.ResponseCache.TTLSeconds, newCfg.ResponseCache.TTLSeconds))
+	}
+	if oldCfg.ResponseCache.MaxEntries != newCfg.ResponseCache.MaxEntries || oldCfg.ResponseCache.MaxSizeMB != newCfg.ResponseCache.MaxSizeMB || oldCfg.ResponseCache.MaxEntrySizeKB != newCfg.ResponseCache.MaxEntrySizeKB {
+		changes = append(changes, "response-cache: limits updated")
+	}
+	if oldCfg.HealthCheck.Enable != newCfg.HealthCheck.Enable {
+		changes = append(changes, fmt.Sprintf("health-check.enable: %t -> %t", oldCfg.HealthCheck.Enable, newCfg.HealthCheck.Enable))
+	}
+	if strings.TrimSpace(oldCfg.HealthCheck.Interval) != strings.TrimSpace(newCfg.HealthCheck.Interval) {
+		changes = append(changes, fmt.Sprintf("health-check.interval: %s -> %s", strings.TrimSpace(oldCfg.HealthCheck.Interval), strings.TrimSpace(newCfg.HealthCheck.Interval)))
+	}
+	if strings.TrimSpace(oldCfg.HealthCheck.Timeout) != strings.TrimSpace(newCfg.HealthCheck.Timeout) {
+		changes = append(changes, fmt.Sprintf("health-check.timeout: %s -> %s", strings.TrimSpace(oldCfg.HealthCheck.Timeout), strings.TrimSpace(newCfg.HealthCheck.Timeout)))
+	}
+	if oldCfg.HealthCheck.FailureThreshold != newCfg.HealthCheck.FailureThreshold {
+		changes = append(changes, fmt.Sprintf("health-check.failure-threshold: %d -> %d", oldCfg.HealthCheck.FailureThreshold, newCfg.HealthCheck.FailureThreshold))
+	}
+	if oldCfg.Tracing.Enable != newCfg.Tracing.Enable {
+		changes = append(changes, fmt.Sprintf("tracing.enable: %t -> %t", oldCfg.Tracing.Enable, newCfg.Tracing.Enable))
+	}
+	if strings.TrimSpace(oldCfg.Tracing.Exporter) != strings.TrimSpace(newCfg.Tracing.Exporter) {
+		changes = append(changes, fmt.Sprintf("tracing.exporter: %s -> %s", strings.TrimSpace(oldCfg.Tracing.Exporter), strings.TrimSpace(newCfg.Tracing.Exporter)))
+	}
+	if oldCfg.Tracing.SampleRatio != newCfg.Tracing.SampleRatio {
+		changes = append(changes, fmt.Sprintf("tracing.sample-ratio: %g -> %g", oldCfg.Tracing.SampleRatio, newCfg.Tracing.SampleRatio))
+	}
+	if oldCfg.Tracing.Propagate != newCfg.Tracing.Propagate {
+		changes = append(changes, fmt.Sprintf("tracing.propagate: %t -> %t", oldCfg.Tracing.Propagate, newCfg.Tracing.Propagate))
+	}
 	if oldCfg.LoggingToFile != newCfg.LoggingToFile {
 		changes = append(changes, fmt.Sprintf("logging-to-file: %t -> %t", oldCfg.LoggingToFile, newCfg.LoggingToFile))
 	}
@@ -113,6 +162,11 @@ func BuildConfigChangeDetails(oldCfg, newCfg *config.Config) []string {
 	} else if !reflect.DeepEqual(trimStrings(oldCfg.APIKeys), trimStrings(newCfg.APIKeys)) {
 		changes = append(changes, "api-keys: values updated (count unchanged, redacted)")
 	}
+	if len(oldCfg.ClientAPIKeys) != len(newCfg.ClientAPIKeys) {
+		changes = append(changes, fmt.Sprintf("client-api-keys count: %d -> %d", len(oldCfg.ClientAPIKeys), len(newCfg.ClientAPIKeys)))
+	} else if !reflect.DeepEqual(oldCfg.ClientAPIKeys, newCfg.ClientAPIKeys) {
+		changes = append(changes, "client-api-keys: entries updated (count unchanged, redacted)")
+	}
 	if len(oldCfg.GeminiKey) != len(newCfg.GeminiKey) {
 		changes = append(changes, fmt.Sprintf("gemini-api-key count: %d -> %d", len(oldCfg.GeminiKey), len(newCfg.GeminiKey)))
 	} else {
@@ -237,6 +291,14 @@ func BuildConfigChangeDetails(oldCfg, newCfg *config.Config) []string {
 	if entries, _ := DiffOAuthModelAliasChanges(oldCfg.OAuthModelAlias, newCfg.OAuthModelAlias); len(entries) > 0 {
 		changes = append(changes, entries...)
 	}
+	if !reflect.DeepEqual(oldCfg.RoutingRules, newCfg.RoutingRules) {
+		changes = append(changes, fmt.Sprintf("routing-rules: updated (%d -> %d rules)", len(oldCfg.RoutingRules), len(newCfg.RoutingRules)))
+	}
+	if !reflect.DeepEqual(oldCfg.ModelFallbacks, newCfg.ModelFallbacks) {
+		changes = append(changes, fmt.Sprintf("model-fallbacks: updated (%d -> %d chains)", len(oldCfg.ModelFallbacks), len(newCfg.ModelFallbacks)))
+	}
+
+	changes = append(changes, diffPluginsConfig(oldCfg.Plugins, newCfg.Plugins)...)
 
 	// Remote management (never print the key)
 	if oldCfg.RemoteManagement.AllowRemote != newCfg.RemoteManagement.AllowRemote {
@@ -248,6 +310,18 @@ func BuildConfigChangeDetails(oldCfg, newCfg *config.Config) []string {
 	if oldCfg.RemoteManagement.DisableAutoUpdatePanel != newCfg.RemoteManagement.DisableAutoUpdatePanel {
 		changes = append(changes, fmt.Sprintf("remote-management.disable-auto-update-panel: %t -> %t", oldCfg.RemoteManagement.DisableAutoUpdatePanel, newCfg.RemoteManagement.DisableAutoUpdatePanel))
 	}
+	if oldCfg.RemoteManagement.DisableAuditLog != newCfg.RemoteManagement.DisableAuditLog {
+		changes = append(changes, fmt.Sprintf("remote-management.disable-audit-log: %t -> %t", oldCfg.RemoteManagement.DisableAuditLog, newCfg.RemoteManagement.DisableAuditLog))
+	}
+	if oldAuditFile, newAuditFile := strings.TrimSpace(oldCfg.RemoteManagement.AuditLogFile), strings.TrimSpace(newCfg.RemoteManagement.AuditLogFile); oldAuditFile != newAuditFile {
+		changes = append(changes, fmt.Sprintf("remote-management.audit-log-file: %s -> %s", oldAuditFile, newAuditFile))
+	}
+	if oldCfg.RemoteManagement.DisableConfigHistory != newCfg.RemoteManagement.DisableConfigHistory {
+		changes = append(changes, fmt.Sprintf("remote-management.disable-config-history: %t -> %t", oldCfg.RemoteManagement.DisableConfigHistory, newCfg.RemoteManagement.DisableConfigHistory))
+	}
+	if oldCfg.RemoteManagement.ConfigHistoryMaxRevisions != newCfg.RemoteManagement.ConfigHistoryMaxRevisions {
+		changes = append(changes, fmt.Sprintf("remote-management.config-history-max-revisions: %d -> %d", oldCfg.RemoteManagement.ConfigHistoryMaxRevisions, newCfg.RemoteManagement.ConfigHistoryMaxRevisions))
+	}
 	oldPanelRepo := strings.TrimSpace(oldCfg.RemoteManagement.PanelGitHubRepository)
 	newPanelRepo := strings.TrimSpace(newCfg.RemoteManagement.PanelGitHubRepository)
 	if oldPanelRepo != newPanelRepo {
@@ -263,6 +337,9 @@ func BuildConfigChangeDetails(oldCfg, newCfg *config.Config) []string {
 			changes = append(changes, "remote-management.secret-key: updated")
 		}
 	}
+	if !managementKeyScopesEqual(oldCfg.RemoteManagement.Keys, newCfg.RemoteManagement.Keys) {
+		changes = append(changes, fmt.Sprintf("remote-management.keys: updated (%d -> %d keys)", len(oldCfg.RemoteManagement.Keys), len(newCfg.RemoteManagement.Keys)))
+	}
 
 	// OpenAI compatibility providers (summarized)
 	if compat := DiffOpenAICompatibility(oldCfg.OpenAICompatibility, newCfg.OpenAICompatibility); len(compat) > 0 {
@@ -307,6 +384,51 @@ func BuildConfigChangeDetails(oldCfg, newCfg *config.Config) []string {
 		}
 	}
 
+	// AWS Bedrock credentials
+	if len(oldCfg.BedrockKey) != len(newCfg.BedrockKey) {
+		changes = append(changes, fmt.Sprintf("bedrock-api-key count: %d -> %d", len(oldCfg.BedrockKey), len(newCfg.BedrockKey)))
+	} else {
+		for i := range oldCfg.BedrockKey {
+			o := oldCfg.BedrockKey[i]
+			n := newCfg.BedrockKey[i]
+			if strings.TrimSpace(o.Region) != strings.TrimSpace(n.Region) {
+				changes = append(changes, fmt.Sprintf("bedrock[%d].region: %s -> %s", i, strings.TrimSpace(o.Region), strings.TrimSpace(n.Region)))
+			}
+			if strings.TrimSpace(o.API) != strings.TrimSpace(n.API) {
+				changes = append(changes, fmt.Sprintf("bedrock[%d].api: %s -> %s", i, strings.TrimSpace(o.API), strings.TrimSpace(n.API)))
+			}
+			if strings.TrimSpace(o.BaseURL) != strings.TrimSpace(n.BaseURL) {
+				changes = append(changes, fmt.Sprintf("bedrock[%d].base-url: %s -> %s", i, strings.TrimSpace(o.BaseURL), strings.TrimSpace(n.BaseURL)))
+			}
+			if strings.TrimSpace(o.ProxyURL) != strings.TrimSpace(n.ProxyURL) {
+				changes = append(changes, fmt.Sprintf("bedrock[%d].proxy-url: %s -> %s", i, formatProxyURL(o.ProxyURL), formatProxyURL(n.ProxyURL)))
+			}
+			if strings.TrimSpace(o.Prefix) != strings.TrimSpace(n.Prefix) {
+				changes = append(changes, fmt.Sprintf("bedrock[%d].prefix: %s -> %s", i, strings.TrimSpace(o.Prefix), strings.TrimSpace(n.Prefix)))
+			}
+			if o.Priority != n.Priority {
+				changes = append(changes, fmt.Sprintf("bedrock[%d].priority: %d -> %d", i, o.Priority, n.Priority))
+			}
+			if strings.TrimSpace(o.AccessKeyID) != strings.TrimSpace(n.AccessKeyID) || strings.TrimSpace(o.SecretAccessKey) != strings.TrimSpace(n.SecretAccessKey) || strings.TrimSpace(o.SessionToken) != strings.TrimSpace(n.SessionToken) {
+				changes = append(changes, fmt.Sprintf("bedrock[%d].credentials: updated", i))
+			}
+			if ComputeBedrockModelsHash(o.Models) != ComputeBedrockModelsHash(n.Models) {
+				changes = append(changes, fmt.Sprintf("bedrock[%d].models: updated (%d -> %d entries)", i, len(o.Models), len(n.Models)))
+			}
+			oldExcluded := SummarizeExcludedModels(o.ExcludedModels)
+			newExcluded := SummarizeExcludedModels(n.ExcludedModels)
+			if oldExcluded.hash != newExcluded.hash {
+				changes = append(changes, fmt.Sprintf("bedrock[%d].excluded-models: updated (%d -> %d entries)", i, oldExcluded.count, newExcluded.count))
+			}
+			if !equalStringMap(o.Headers, n.Headers) {
+				changes = append(changes, fmt.Sprintf("bedrock[%d].headers: updated", i))
+			}
+			if o.DisableCooling != n.DisableCooling {
+				changes = append(changes, fmt.Sprintf("bedrock[%d].disable-cooling: %t -> %t", i, o.DisableCooling, n.DisableCooling))
+			}
+		}
+	}
+
 	return changes
 }
 
@@ -380,3 +502,61 @@ func formatProxyURL(raw string) string {
 	}
 	return scheme + "://" + host
 }
+
+// managementKeyScopesEqual compares management keys by name and scopes only;
+// plaintext keys are re-hashed on every load, so hashes are not comparable.
+func managementKeyScopesEqual(a, b []config.ManagementKey) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || !reflect.DeepEqual(a[i].Scopes, b[i].Scopes) {
+			return false
+		}
+	}
+	return true
+}
+
+// diffPluginsConfig summarizes plugin loading and per-plugin enable/priority
+// changes. Plugin-owned settings may hold secrets and are not printed.
+func diffPluginsConfig(oldPlugins, newPlugins config.PluginsConfig) []string {
+	var changes []string
+	if oldPlugins.Enabled != newPlugins.Enabled {
+		changes = append(changes, fmt.Sprintf("plugins.enabled: %t -> %t", oldPlugins.Enabled, newPlugins.Enabled))
+	}
+	if strings.TrimSpace(oldPlugins.Dir) != strings.TrimSpace(newPlugins.Dir) {
+		changes = append(changes, fmt.Sprintf("plugins.dir: %s -> %s", strings.TrimSpace(oldPlugins.Dir), strings.TrimSpace(newPlugins.Dir)))
+	}
+	ids := make([]string, 0, len(oldPlugins.Configs)+len(newPlugins.Configs))
+	for id := range oldPlugins.Configs {
+		ids = append(ids, id)
+	}
+	for id := range newPlugins.Configs {
+		if _, ok := oldPlugins.Configs[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		oldItem, hadOld := oldPlugins.Configs[id]
+		newItem, hasNew := newPlugins.Configs[id]
+		switch {
+		case !hadOld:
+			changes = append(changes, fmt.Sprintf("plugins.configs.%s: added (enabled=%t)", id, pluginEnabled(newItem)))
+		case !hasNew:
+			changes = append(changes, fmt.Sprintf("plugins.configs.%s: removed", id))
+		default:
+			if pluginEnabled(oldItem) != pluginEnabled(newItem) {
+				changes = append(changes, fmt.Sprintf("plugins.configs.%s.enabled: %t -> %t", id, pluginEnabled(oldItem), pluginEnabled(newItem)))
+			}
+			if oldItem.Priority != newItem.Priority {
+				changes = append(changes, fmt.Sprintf("plugins.configs.%s.priority: %d -> %d", id, oldItem.Priority, newItem.Priority))
+			}
+		}
+	}
+	return changes
+}
+
+func pluginEnabled(item config.PluginInstanceConfig) bool {
+	return item.Enabled != nil && *item.Enabled
+}
@@ -5,12 +5,13 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/watcher/diff"
 	coreauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
 )
 
 // ConfigSynthesizer generates Auth entries from configuration API keys.
-// It handles Gemini, Claude, Codex, OpenAI-compat, and Vertex-compat providers.
+// It handles Gemini, Claude, Codex, OpenAI-compat, Vertex-compat, and Bedrock providers.
 type ConfigSynthesizer struct{}
 
 // NewConfigSynthesizer creates a new ConfigSynthesizer instance.
@@ -35,6 +36,8 @@ func (s *ConfigSynthesizer) Synthesize(ctx *SynthesisContext) ([]*coreauth.Auth,
 	out = append(out, s.synthesizeOpenAICompat(ctx)...)
 	// Vertex-compat
 	out = append(out, s.synthesizeVertexCompat(ctx)...)
+	// AWS Bedrock
+	out = append(out, s.synthesizeBedrockKeys(ctx)...)
 
 	return out, nil
 }
@@ -364,3 +367,73 @@ func (s *ConfigSynthesizer) synthesizeVertexCompat(ctx *SynthesisContext) []*cor
 	}
 	return out
 }
+
+// synthesizeBedrockKeys creates Auth entries for AWS Bedrock credentials.
+func (s *ConfigSynthesizer) synthesizeBedrockKeys(ctx *SynthesisContext) []*coreauth.Auth {
+	cfg := ctx.Config
+	now := ctx.Now
+	idGen := ctx.IDGenerator
+
+	out := make([]*coreauth.Auth, 0, len(cfg.BedrockKey))
+	for i := range cfg.BedrockKey {
+		bk := cfg.BedrockKey[i]
+		accessKey := strings.TrimSpace(bk.AccessKeyID)
+		secretKey := strings.TrimSpace(bk.SecretAccessKey)
+		if accessKey == "" || secretKey == "" {
+			continue
+		}
+		region := strings.TrimSpace(bk.Region)
+		if region == "" {
+			region = config.DefaultBedrockRegion
+		}
+		api := strings.TrimSpace(bk.API)
+		if api == "" {
+			api = config.BedrockAPIConverse
+		}
+		prefix := strings.TrimSpace(bk.Prefix)
+		base := strings.TrimSpace(bk.BaseURL)
+		id, token := idGen.Next("bedrock:apikey", accessKey, region, base)
+		attrs := map[string]string{
+			"source":            fmt.Sprintf("config:bedrock[%s]", token),
+			"api_key":           accessKey,
+			"secret_access_key": secretKey,
+			"region":            region,
+			"api":               api,
+		}
+		if sessionToken := strings.TrimSpace(bk.SessionToken); sessionToken != "" {
+			attrs["session_token"] = sessionToken
+		}
+		metadata := map[string]any{}
+		if bk.DisableCooling {
+			metadata["disable_cooling"] = true
+		}
+		if bk.Priority != 0 {
+			attrs["priority"] = strconv.Itoa(bk.Priority)
+		}
+		if base != "" {
+			attrs["base_url"] = base
+		}
+		if hash := diff.ComputeBedrockModelsHash(bk.Models); hash != "" {
+			attrs["models_hash"] = hash
+		}
+		addConfigHeadersToAttrs(bk.Headers, attrs)
+		a := &coreauth.Auth{
+			ID:         id,
+			Provider:   "bedrock",
+			Label:      "bedrock-" + region,
+			Prefix:     prefix,
+			Status:     coreauth.StatusActive,
+			ProxyURL:   strings.TrimSpace(bk.ProxyURL),
+			Attributes: attrs,
+			Metadata:   metadata,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		ApplyAuthExcludedModelsMeta(a, cfg, bk.ExcludedModels, "apikey")
+		if len(a.Metadata) == 0 {
+			a.Metadata = nil
+		}
+		out = append(out, a)
+	}
+	return out
+}
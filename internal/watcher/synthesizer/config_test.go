@@ -435,6 +435,59 @@ func TestConfigSynthesizer_VertexCompat(t *testing.T) {
 	}
 }
 
+func TestConfigSynthesizer_BedrockKeys(t *testing.T) {
+	synth := NewConfigSynthesizer()
+	ctx := &SynthesisContext{
+		Config: &config.Config{
+			BedrockKey: []config.BedrockKey{
+				{AccessKeyID: "", SecretAccessKey: "secret"},
+				{
+					AccessKeyID:     "AKIA123",
+					SecretAccessKey: "secret",
+					SessionToken:    "token",
+					Region:          "eu-west-1",
+					API:             "invoke",
+					Priority:        2,
+					DisableCooling:  true,
+					Models:          []config.BedrockModel{{Name: "eu.anthropic.claude-sonnet-4-20250514-v1:0", Alias: "sonnet"}},
+				},
+			},
+		},
+		Now:         time.Now(),
+		IDGenerator: NewStableIDGenerator(),
+	}
+
+	auths, err := synth.Synthesize(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(auths) != 1 {
+		t.Fatalf("expected 1 auth, got %d", len(auths))
+	}
+	a := auths[0]
+	if a.Provider != "bedrock" || a.Label != "bedrock-eu-west-1" {
+		t.Errorf("unexpected provider/label: %s/%s", a.Provider, a.Label)
+	}
+	for key, want := range map[string]string{
+		"api_key":           "AKIA123",
+		"secret_access_key": "secret",
+		"session_token":     "token",
+		"region":            "eu-west-1",
+		"api":               "invoke",
+		"priority":          "2",
+	} {
+		if got := a.Attributes[key]; got != want {
+			t.Errorf("attribute %s = %q, want %q", key, got, want)
+		}
+	}
+	if a.Attributes["models_hash"] == "" {
+		t.Error("expected models_hash attribute")
+	}
+	if a.Metadata["disable_cooling"] != true {
+		t.Errorf("expected disable_cooling metadata, got %v", a.Metadata)
+	}
+}
+
 func TestConfigSynthesizer_VertexCompat_SkipsEmptyAndHeaders(t *testing.T) {
 	synth := NewConfigSynthesizer()
 	ctx := &SynthesisContext{
@@ -13,6 +13,7 @@ const (
 	AuthErrorCodeNoCredentials     AuthErrorCode = "no_credentials"
 	AuthErrorCodeInvalidCredential AuthErrorCode = "invalid_credential"
 	AuthErrorCodeNotHandled        AuthErrorCode = "not_handled"
+	AuthErrorCodeForbidden         AuthErrorCode = "forbidden"
 	AuthErrorCodeInternal          AuthErrorCode = "internal_error"
 )
 
@@ -70,6 +71,16 @@ func NewInvalidCredentialError() *AuthError {
 	return newAuthError(AuthErrorCodeInvalidCredential, "Invalid API key", http.StatusUnauthorized, nil)
 }
 
+// NewForbiddenError reports a recognised credential that is not allowed to make the request,
+// for example because it is disabled, expired or restricted to other endpoints.
+func NewForbiddenError(message string) *AuthError {
+	normalizedMessage := strings.TrimSpace(message)
+	if normalizedMessage == "" {
+		normalizedMessage = "API key is not allowed to access this resource"
+	}
+	return newAuthError(AuthErrorCodeForbidden, normalizedMessage, http.StatusForbidden, nil)
+}
+
 func NewNotHandledError() *AuthError {
 	return newAuthError(AuthErrorCodeNotHandled, "authentication provider did not handle request", 0, nil)
 }
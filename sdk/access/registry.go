@@ -20,6 +20,15 @@ type Result struct {
 	Metadata  map[string]string
 }
 
+const (
+	// MetadataKeySource names where the credential was read from (e.g. "authorization").
+	MetadataKeySource = "source"
+	// MetadataKeyName carries the human-readable label of the matched client key.
+	MetadataKeyName = "name"
+	// MetadataKeyModels carries the comma-separated model globs the client key may use.
+	MetadataKeyModels = "models"
+)
+
 var (
 	registryMu        sync.RWMutex
 	registry          = make(map[string]Provider)
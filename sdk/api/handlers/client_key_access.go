@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/interfaces"
+	sdkaccess "github.com/router-for-me/CLIProxyAPI/v7/sdk/access"
+)
+
+// clientKeyModelPatterns returns the model globs attached to the authenticated
+// client key, or nil when the key is unrestricted.
+func clientKeyModelPatterns(ctx context.Context) []string {
+	if ctx == nil {
+		return nil
+	}
+	ginCtx, ok := ctx.Value("gin").(*gin.Context)
+	if !ok || ginCtx == nil {
+		return nil
+	}
+	raw, exists := ginCtx.Get("accessMetadata")
+	if !exists {
+		return nil
+	}
+	metadata, ok := raw.(map[string]string)
+	if !ok {
+		return nil
+	}
+	value := strings.TrimSpace(metadata[sdkaccess.MetadataKeyModels])
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// clientKeyModelError rejects requests for models outside the client key allowlist.
+func clientKeyModelError(ctx context.Context, modelName string) *interfaces.ErrorMessage {
+	patterns := clientKeyModelPatterns(ctx)
+	if len(patterns) == 0 {
+		return nil
+	}
+	if config.MatchClientAPIKeyPatterns(patterns, modelName) {
+		return nil
+	}
+	return &interfaces.ErrorMessage{
+		StatusCode: http.StatusForbidden,
+		Error:      fmt.Errorf("API key is not allowed to use model %s", strings.TrimSpace(modelName)),
+	}
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	sdkaccess "github.com/router-for-me/CLIProxyAPI/v7/sdk/access"
+)
+
+func TestClientKeyModelError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginCtx.Set("accessMetadata", map[string]string{sdkaccess.MetadataKeyModels: "gemini-*,claude-sonnet-*"})
+	ctx := context.WithValue(context.Background(), "gin", ginCtx)
+
+	if errMsg := clientKeyModelError(ctx, "gemini-2.5-pro"); errMsg != nil {
+		t.Fatalf("allowed model rejected: %v", errMsg.Error)
+	}
+	errMsg := clientKeyModelError(ctx, "gpt-5")
+	if errMsg == nil {
+		t.Fatal("expected disallowed model to be rejected")
+	}
+	if errMsg.StatusCode != http.StatusForbidden {
+		t.Fatalf("status = %d", errMsg.StatusCode)
+	}
+	if errMsg := clientKeyModelError(context.Background(), "gpt-5"); errMsg != nil {
+		t.Fatal("requests without client key metadata should not be restricted")
+	}
+}
@@ -0,0 +1,159 @@
+package gemini
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/sdk/api/handlers"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// embeddingsHandlerType matches the source format used by the OpenAI
+// embeddings frontend; native Gemini embedding calls are converted to it so
+// that every embedding-capable provider can serve them.
+const embeddingsHandlerType = "openai-embedding"
+
+// embedRequestGroup is one upstream OpenAI embeddings call covering the batch
+// entries at positions, which share a task type and output dimensionality.
+type embedRequestGroup struct {
+	body      []byte
+	positions []int
+}
+
+// handleEmbedContent serves models/{model}:embedContent and
+// models/{model}:batchEmbedContents by converting the request to the OpenAI
+// embeddings shape, executing it through the auth manager and converting the
+// result back to the Gemini response format. Batch entries with different
+// task types or output dimensionalities are sent as separate upstream calls.
+//
+// Parameters:
+//   - c: The Gin context for the request
+//   - modelName: The name of the embedding model
+//   - rawJSON: The raw JSON request body
+//   - batch: Whether the request uses the batchEmbedContents shape
+func (h *GeminiAPIHandler) handleEmbedContent(c *gin.Context, modelName string, rawJSON []byte, batch bool) {
+	groups, total, ok := convertGeminiEmbedRequestToOpenAI(modelName, rawJSON, batch)
+	if !ok {
+		c.JSON(http.StatusBadRequest, handlers.ErrorResponse{
+			Error: handlers.ErrorDetail{
+				Message: "Invalid request: content with text parts is required",
+				Type:    "invalid_request_error",
+			},
+		})
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	cliCtx, cliCancel := h.GetContextWithCancel(h, c, context.Background())
+	values := make([]string, total)
+	var upstreamHeaders http.Header
+	for _, group := range groups {
+		resp, headers, errMsg := h.ExecuteWithAuthManager(cliCtx, embeddingsHandlerType, modelName, group.body, "")
+		if errMsg != nil {
+			h.WriteErrorResponse(c, errMsg)
+			cliCancel(errMsg.Error)
+			return
+		}
+		upstreamHeaders = headers
+		for i, embedding := range openAIEmbeddingValues(resp, len(group.positions)) {
+			values[group.positions[i]] = embedding
+		}
+	}
+	handlers.WriteUpstreamHeaders(c.Writer.Header(), upstreamHeaders)
+	_, _ = c.Writer.Write(buildGeminiEmbeddingsResponse(values, batch))
+	cliCancel()
+}
+
+// convertGeminiEmbedRequestToOpenAI builds OpenAI embeddings requests from an
+// embedContent or batchEmbedContents payload. Each content becomes one input
+// whose text is the concatenation of its text parts; entries are grouped by
+// task type and output dimensionality, one request per group. It also returns
+// the number of entries.
+func convertGeminiEmbedRequestToOpenAI(modelName string, rawJSON []byte, batch bool) ([]embedRequestGroup, int, bool) {
+	root := gjson.ParseBytes(rawJSON)
+	entries := []gjson.Result{root}
+	if batch {
+		entries = root.Get("requests").Array()
+	}
+	if len(entries) == 0 {
+		return nil, 0, false
+	}
+
+	model := strings.TrimPrefix(modelName, "models/")
+	var groups []embedRequestGroup
+	groupIndex := make(map[string]int)
+	for position, entry := range entries {
+		var text strings.Builder
+		for _, part := range entry.Get("content.parts").Array() {
+			text.WriteString(part.Get("text").String())
+		}
+		if text.Len() == 0 {
+			return nil, 0, false
+		}
+		dimensions := entry.Get("outputDimensionality").Int()
+		taskType := strings.TrimSpace(entry.Get("taskType").String())
+		key := taskType + "\x00" + strconv.FormatInt(dimensions, 10)
+		index, exists := groupIndex[key]
+		if !exists {
+			body := []byte(`{"input":[]}`)
+			body, _ = sjson.SetBytes(body, "model", model)
+			if dimensions > 0 {
+				body, _ = sjson.SetBytes(body, "dimensions", dimensions)
+			}
+			if taskType != "" {
+				body, _ = sjson.SetBytes(body, "task_type", taskType)
+			}
+			index = len(groups)
+			groupIndex[key] = index
+			groups = append(groups, embedRequestGroup{body: body})
+		}
+		groups[index].body, _ = sjson.SetBytes(groups[index].body, "input.-1", text.String())
+		groups[index].positions = append(groups[index].positions, position)
+	}
+	return groups, len(entries), true
+}
+
+// openAIEmbeddingValues returns the raw embedding arrays of an OpenAI
+// embeddings list ordered by input index.
+func openAIEmbeddingValues(resp []byte, count int) []string {
+	values := make([]string, count)
+	for position, item := range gjson.GetBytes(resp, "data").Array() {
+		index := position
+		if item.Get("index").Exists() {
+			index = int(item.Get("index").Int())
+		}
+		if index < 0 || index >= count || !item.Get("embedding").IsArray() {
+			continue
+		}
+		values[index] = item.Get("embedding").Raw
+	}
+	return values
+}
+
+// buildGeminiEmbeddingsResponse renders embedding arrays in the embedContent
+// ({"embedding":{...}}) or batchEmbedContents ({"embeddings":[...]}) shape.
+func buildGeminiEmbeddingsResponse(values []string, batch bool) []byte {
+	if !batch {
+		raw := "[]"
+		if len(values) > 0 && values[0] != "" {
+			raw = values[0]
+		}
+		out, _ := sjson.SetRawBytes([]byte(`{"embedding":{}}`), "embedding.values", []byte(raw))
+		return out
+	}
+
+	out := []byte(`{"embeddings":[]}`)
+	for _, value := range values {
+		raw := "[]"
+		if value != "" {
+			raw = value
+		}
+		entry, _ := sjson.SetRawBytes([]byte(`{}`), "values", []byte(raw))
+		out, _ = sjson.SetRawBytes(out, "embeddings.-1", entry)
+	}
+	return out
+}
@@ -0,0 +1,70 @@
+package gemini
+
+import (
+	"testing"
+
+	"github.com/tidwall/gjson"
+)
+
+func TestConvertGeminiEmbedRequestToOpenAI(t *testing.T) {
+	groups, total, ok := convertGeminiEmbedRequestToOpenAI("models/gemini-embedding-001", []byte(`{"content":{"parts":[{"text":"foo "},{"text":"bar"}]},"taskType":"RETRIEVAL_DOCUMENT","outputDimensionality":128}`), false)
+	if !ok || total != 1 || len(groups) != 1 {
+		t.Fatalf("single request = %d groups, %d entries, ok=%v", len(groups), total, ok)
+	}
+	single := groups[0].body
+	if got := gjson.GetBytes(single, "model").String(); got != "gemini-embedding-001" {
+		t.Fatalf("model = %q", got)
+	}
+	if got := gjson.GetBytes(single, "input.0").String(); got != "foo bar" {
+		t.Fatalf("input.0 = %q", got)
+	}
+	if got := gjson.GetBytes(single, "dimensions").Int(); got != 128 {
+		t.Fatalf("dimensions = %d", got)
+	}
+	if got := gjson.GetBytes(single, "task_type").String(); got != "RETRIEVAL_DOCUMENT" {
+		t.Fatalf("task_type = %q", got)
+	}
+
+	groups, total, ok = convertGeminiEmbedRequestToOpenAI("gemini-embedding-001", []byte(`{"requests":[{"content":{"parts":[{"text":"a"}]}},{"content":{"parts":[{"text":"b"}]}}]}`), true)
+	if !ok || total != 2 || len(groups) != 1 {
+		t.Fatalf("batch request = %d groups, %d entries, ok=%v", len(groups), total, ok)
+	}
+	if got := gjson.GetBytes(groups[0].body, "input.#").Int(); got != 2 {
+		t.Fatalf("input count = %d", got)
+	}
+
+	if _, _, ok = convertGeminiEmbedRequestToOpenAI("m", []byte(`{"content":{"parts":[]}}`), false); ok {
+		t.Fatal("expected empty content to be rejected")
+	}
+}
+
+func TestConvertGeminiEmbedRequestToOpenAI_SplitsMixedBatchSettings(t *testing.T) {
+	groups, total, ok := convertGeminiEmbedRequestToOpenAI("gemini-embedding-001", []byte(`{"requests":[
+		{"content":{"parts":[{"text":"a"}]},"taskType":"RETRIEVAL_QUERY","outputDimensionality":256},
+		{"content":{"parts":[{"text":"b"}]},"taskType":"RETRIEVAL_DOCUMENT","outputDimensionality":768},
+		{"content":{"parts":[{"text":"c"}]},"taskType":"RETRIEVAL_QUERY","outputDimensionality":256}
+	]}`), true)
+	if !ok || total != 3 || len(groups) != 2 {
+		t.Fatalf("mixed batch = %d groups, %d entries, ok=%v", len(groups), total, ok)
+	}
+	if got := gjson.GetBytes(groups[0].body, "dimensions").Int(); got != 256 || len(groups[0].positions) != 2 || groups[0].positions[1] != 2 {
+		t.Fatalf("first group = %s positions %v", groups[0].body, groups[0].positions)
+	}
+	if got := gjson.GetBytes(groups[1].body, "task_type").String(); got != "RETRIEVAL_DOCUMENT" || groups[1].positions[0] != 1 {
+		t.Fatalf("second group = %s positions %v", groups[1].body, groups[1].positions)
+	}
+}
+
+func TestBuildGeminiEmbeddingsResponse(t *testing.T) {
+	values := openAIEmbeddingValues([]byte(`{"object":"list","data":[{"index":1,"embedding":[3]},{"index":0,"embedding":[1,2]}]}`), 2)
+
+	single := buildGeminiEmbeddingsResponse(values, false)
+	if got := gjson.GetBytes(single, "embedding.values").Raw; got != "[1,2]" {
+		t.Fatalf("embedding.values = %s", got)
+	}
+
+	batch := buildGeminiEmbeddingsResponse(values, true)
+	if got := gjson.GetBytes(batch, "embeddings.1.values").Raw; got != "[3]" {
+		t.Fatalf("embeddings.1.values = %s", got)
+	}
+}
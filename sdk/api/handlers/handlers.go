@@ -18,7 +18,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/interfaces"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/logging"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/responsecache"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/thinking"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/tracing"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/util"
 	coreauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
 	coreexecutor "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/executor"
@@ -532,6 +534,9 @@ func (h *BaseAPIHandler) GetContextWithCancel(handler interfaces.APIHandler, c *
 			parentCtx = logging.WithRequestID(parentCtx, requestID)
 		}
 	}
+	if requestCtx != nil && tracing.SpanFromContext(parentCtx) == nil {
+		parentCtx = tracing.ContextWithSpan(parentCtx, tracing.SpanFromContext(requestCtx))
+	}
 	newCtx, cancel := context.WithCancel(parentCtx)
 
 	endpoint := ""
@@ -707,9 +712,16 @@ func (h *BaseAPIHandler) executeWithAuthManager(ctx context.Context, handlerType
 }
 
 func (h *BaseAPIHandler) executeWithAuthManagerFormats(ctx context.Context, entryProtocol, exitProtocol, modelName string, rawJSON []byte, alt string, allowImageModel bool, execOptions modelExecutionOptions) ([]byte, http.Header, *interfaces.ErrorMessage) {
+	ctx, span := startHandlerSpan(ctx, "handler.execute", entryProtocol, modelName)
+	defer span.End()
 	originalRequestedModel := modelName
-	routeDecision := h.applyModelRouter(ctx, entryProtocol, modelName, rawJSON, false, execOptions)
+	if !execOptions.InternalSource {
+		if errMsg := clientKeyModelError(ctx, modelName); errMsg != nil {
+			return nil, nil, errMsg
+		}
+	}
 	responseProtocol := modelExecutionResponseProtocol(entryProtocol, exitProtocol)
+	routeDecision := h.applyModelRouter(ctx, entryProtocol, modelName, rawJSON, false, execOptions)
 	if routeDecision.ExecutorPluginID != "" {
 		return h.executeWithPluginExecutor(ctx, entryProtocol, responseProtocol, modelName, originalRequestedModel, rawJSON, alt, routeDecision.ExecutorPluginID, execOptions)
 	}
@@ -719,6 +731,7 @@ func (h *BaseAPIHandler) executeWithAuthManagerFormats(ctx context.Context, entr
 	}
 	reqMeta := requestExecutionMetadata(ctx)
 	reqMeta[coreexecutor.RequestedModelMetadataKey] = originalRequestedModel
+	setRouteDecisionMetadata(reqMeta, routeDecision)
 	addModelExecutionSourceMetadata(reqMeta, execOptions.InternalSource)
 	setReasoningEffortMetadata(reqMeta, entryProtocol, normalizedModel, rawJSON)
 	setServiceTierMetadata(reqMeta, rawJSON)
@@ -743,9 +756,18 @@ func (h *BaseAPIHandler) executeWithAuthManagerFormats(ctx context.Context, entr
 	}
 	opts.Metadata = reqMeta
 	req, opts = h.applyRequestInterceptorsBeforeAuth(ctx, entryProtocol, originalRequestedModel, req, opts, execOptions.SkipInterceptorPluginID)
+	cacheKey := responseCacheKey(ctx, entryProtocol, responseProtocol, alt, providers, routeDecision, req, false, execOptions)
+	if cacheKey != "" {
+		if entry, ok := responsecache.Default().Get(cacheKey); ok {
+			publishResponseCacheHit(ctx, originalRequestedModel)
+			return cloneBytes(entry.Body), cloneHeader(entry.Headers), nil
+		}
+		ctx = coreusage.WithCacheStatus(ctx, coreusage.CacheStatusMiss)
+	}
 	resp, err := h.AuthManager.Execute(ctx, providers, req, opts)
 	if err != nil {
 		err = enrichAuthSelectionError(err, providers, normalizedModel)
+		span.RecordError(err)
 		status := http.StatusInternalServerError
 		if se, ok := err.(interface{ StatusCode() int }); ok && se != nil {
 			if code := se.StatusCode(); code > 0 {
@@ -764,6 +786,9 @@ func (h *BaseAPIHandler) executeWithAuthManagerFormats(ctx context.Context, entr
 	rawResponseHeaders := cloneHeader(resp.Headers)
 	responseHeaders := downstreamHeadersFromExecutor(rawResponseHeaders, PassthroughHeadersEnabled(h.Cfg))
 	body, responseHeaders := h.applyResponseInterceptors(ctx, responseProtocol, normalizedModel, originalRequestedModel, executedOpts, rawResponseHeaders, responseHeaders, executedOpts.OriginalRequest, executedReq.Payload, resp.Payload, http.StatusOK, execOptions.SkipInterceptorPluginID)
+	if cacheKey != "" && len(body) > 0 {
+		responsecache.Default().Put(cacheKey, &responsecache.Entry{Body: cloneBytes(body), Headers: cloneHeader(responseHeaders)})
+	}
 	return body, responseHeaders, nil
 }
 
@@ -774,7 +799,14 @@ func (h *BaseAPIHandler) ExecuteCountWithAuthManager(ctx context.Context, handle
 }
 
 func (h *BaseAPIHandler) executeCountWithAuthManager(ctx context.Context, handlerType, modelName string, rawJSON []byte, alt string, execOptions modelExecutionOptions) ([]byte, http.Header, *interfaces.ErrorMessage) {
+	ctx, span := startHandlerSpan(ctx, "handler.count_tokens", handlerType, modelName)
+	defer span.End()
 	originalRequestedModel := modelName
+	if !execOptions.InternalSource {
+		if errMsg := clientKeyModelError(ctx, modelName); errMsg != nil {
+			return nil, nil, errMsg
+		}
+	}
 	routeDecision := h.applyModelRouter(ctx, handlerType, modelName, rawJSON, false, execOptions)
 	if routeDecision.ExecutorPluginID != "" {
 		return h.countWithPluginExecutor(ctx, handlerType, modelName, originalRequestedModel, rawJSON, alt, routeDecision.ExecutorPluginID, execOptions)
@@ -785,6 +817,7 @@ func (h *BaseAPIHandler) executeCountWithAuthManager(ctx context.Context, handle
 	}
 	reqMeta := requestExecutionMetadata(ctx)
 	reqMeta[coreexecutor.RequestedModelMetadataKey] = originalRequestedModel
+	setRouteDecisionMetadata(reqMeta, routeDecision)
 	setReasoningEffortMetadata(reqMeta, handlerType, normalizedModel, rawJSON)
 	setServiceTierMetadata(reqMeta, rawJSON)
 	payload := rawJSON
@@ -810,6 +843,7 @@ func (h *BaseAPIHandler) executeCountWithAuthManager(ctx context.Context, handle
 	resp, err := h.AuthManager.ExecuteCount(ctx, providers, req, opts)
 	if err != nil {
 		err = enrichAuthSelectionError(err, providers, normalizedModel)
+		span.RecordError(err)
 		status := http.StatusInternalServerError
 		if se, ok := err.(interface{ StatusCode() int }); ok && se != nil {
 			if code := se.StatusCode(); code > 0 {
@@ -1094,9 +1128,21 @@ func (h *BaseAPIHandler) executeStreamWithAuthManager(ctx context.Context, handl
 }
 
 func (h *BaseAPIHandler) executeStreamWithAuthManagerFormats(ctx context.Context, entryProtocol, exitProtocol, modelName string, rawJSON []byte, alt string, allowImageModel bool, execOptions modelExecutionOptions) (<-chan []byte, http.Header, <-chan *interfaces.ErrorMessage) {
+	// The span covers stream setup up to the first upstream bytes; the server
+	// span stays open until the stream has been forwarded.
+	ctx, span := startHandlerSpan(ctx, "handler.execute_stream", entryProtocol, modelName)
+	defer span.End()
 	originalRequestedModel := modelName
-	routeDecision := h.applyModelRouter(ctx, entryProtocol, modelName, rawJSON, true, execOptions)
+	if !execOptions.InternalSource {
+		if errMsg := clientKeyModelError(ctx, modelName); errMsg != nil {
+			errChan := make(chan *interfaces.ErrorMessage, 1)
+			errChan <- errMsg
+			close(errChan)
+			return nil, nil, errChan
+		}
+	}
 	responseProtocol := modelExecutionResponseProtocol(entryProtocol, exitProtocol)
+	routeDecision := h.applyModelRouter(ctx, entryProtocol, modelName, rawJSON, true, execOptions)
 	if routeDecision.ExecutorPluginID != "" {
 		return h.streamWithPluginExecutor(ctx, entryProtocol, responseProtocol, modelName, originalRequestedModel, rawJSON, alt, routeDecision.ExecutorPluginID, execOptions)
 	}
@@ -1109,6 +1155,7 @@ func (h *BaseAPIHandler) executeStreamWithAuthManagerFormats(ctx context.Context
 	}
 	reqMeta := requestExecutionMetadata(ctx)
 	reqMeta[coreexecutor.RequestedModelMetadataKey] = originalRequestedModel
+	setRouteDecisionMetadata(reqMeta, routeDecision)
 	addModelExecutionSourceMetadata(reqMeta, execOptions.InternalSource)
 	setReasoningEffortMetadata(reqMeta, entryProtocol, normalizedModel, rawJSON)
 	setServiceTierMetadata(reqMeta, rawJSON)
@@ -1133,9 +1180,19 @@ func (h *BaseAPIHandler) executeStreamWithAuthManagerFormats(ctx context.Context
 	}
 	opts.Metadata = reqMeta
 	req, opts = h.applyRequestInterceptorsBeforeAuth(ctx, entryProtocol, originalRequestedModel, req, opts, execOptions.SkipInterceptorPluginID)
+	cacheKey := responseCacheKey(ctx, entryProtocol, responseProtocol, alt, providers, routeDecision, req, true, execOptions)
+	if cacheKey != "" {
+		if entry, ok := responsecache.Default().Get(cacheKey); ok {
+			publishResponseCacheHit(ctx, originalRequestedModel)
+			dataChan, errChan := replayCachedStream(ctx, entry)
+			return dataChan, cloneHeader(entry.Headers), errChan
+		}
+		ctx = coreusage.WithCacheStatus(ctx, coreusage.CacheStatusMiss)
+	}
 	streamResult, err := h.AuthManager.ExecuteStream(ctx, providers, req, opts)
 	if err != nil {
 		err = enrichAuthSelectionError(err, providers, normalizedModel)
+		span.RecordError(err)
 		errChan := make(chan *interfaces.ErrorMessage, 1)
 		status := http.StatusInternalServerError
 		if se, ok := err.(interface{ StatusCode() int }); ok && se != nil {
@@ -1386,6 +1443,10 @@ func (h *BaseAPIHandler) executeStreamWithAuthManagerFormats(ctx context.Context
 			return
 		}
 	}()
+	if cacheKey != "" {
+		cachedData, cachedErrs := teeStreamIntoResponseCache(ctx, cacheKey, upstreamHeaders, dataChan, errChan)
+		return cachedData, upstreamHeaders, cachedErrs
+	}
 	return dataChan, upstreamHeaders, errChan
 }
 
@@ -1436,7 +1497,8 @@ func (h *BaseAPIHandler) getRequestDetails(modelName string) (providers []string
 
 // providersForExecution resolves the providers and normalized model for a request. When a model
 // router selected a built-in provider, it skips model->provider resolution and uses the router's
-// provider (with an optional target model); otherwise it falls back to the registry-based path.
+// provider (with an optional target model). Config routing rules may pin a provider set or
+// rewrite the model; otherwise it falls back to the registry-based path.
 func (h *BaseAPIHandler) providersForExecution(modelName, originalRequestedModel string, allowImageModel bool, routeDecision modelRouteDecision) ([]string, string, *interfaces.ErrorMessage) {
 	if routeDecision.Provider != "" {
 		normalizedModel := originalRequestedModel
@@ -1448,6 +1510,15 @@ func (h *BaseAPIHandler) providersForExecution(modelName, originalRequestedModel
 		}
 		return []string{routeDecision.Provider}, normalizedModel, nil
 	}
+	if routeDecision.Model != "" {
+		modelName = routeDecision.Model
+	}
+	if len(routeDecision.Providers) > 0 {
+		if errMsg := h.validateImageOnlyModel(modelName, allowImageModel); errMsg != nil {
+			return nil, "", errMsg
+		}
+		return append([]string(nil), routeDecision.Providers...), modelName, nil
+	}
 	return h.getRequestDetailsWithOptions(modelName, allowImageModel)
 }
 
@@ -1495,6 +1566,11 @@ func (h *BaseAPIHandler) getRequestDetailsWithOptions(modelName string, allowIma
 	}
 
 	if len(providers) == 0 {
+		// A model without credentials of its own can still be served through its
+		// configured fallback chain; the auth manager resolves those providers.
+		if h != nil && h.AuthManager != nil && len(h.AuthManager.ModelFallbackChain(baseModel)) > 0 {
+			return nil, resolvedModelName, nil
+		}
 		return nil, "", &interfaces.ErrorMessage{StatusCode: http.StatusBadGateway, Error: fmt.Errorf("unknown provider for model %s", modelName)}
 	}
 
@@ -1715,6 +1791,10 @@ type modelRouteDecision struct {
 	ExecutorPluginID string
 	Provider         string
 	Model            string
+	// Providers, AuthPrefix and AuthIndexes are set by config routing rules.
+	Providers   []string
+	AuthPrefix  string
+	AuthIndexes []string
 }
 
 func routeModel(ctx context.Context, host PluginModelRouterHost, req pluginapi.ModelRouteRequest, skipPluginID string) (pluginapi.ModelRouteResponse, bool) {
@@ -1756,7 +1836,7 @@ func (h *BaseAPIHandler) applyModelRouter(ctx context.Context, handlerType, mode
 	var decision modelRouteDecision
 	host := h.modelRouterHost()
 	if host == nil || !modelRoutersEnabled(host, execOptions.SkipRouterPluginID) {
-		return decision
+		return h.applyRoutingRules(ctx, handlerType, modelName, rawJSON, execOptions)
 	}
 	meta := requestExecutionMetadata(ctx)
 	meta[coreexecutor.RequestedModelMetadataKey] = modelName
@@ -1771,7 +1851,7 @@ func (h *BaseAPIHandler) applyModelRouter(ctx context.Context, handlerType, mode
 		Metadata:       meta,
 	}, execOptions.SkipRouterPluginID)
 	if !ok || !resp.Handled {
-		return decision
+		return h.applyRoutingRules(ctx, handlerType, modelName, rawJSON, execOptions)
 	}
 	switch resp.TargetKind {
 	case pluginapi.ModelRouteTargetSelf, pluginapi.ModelRouteTargetExecutor:
@@ -1872,7 +1952,18 @@ func mergeRequestInterceptorHeaders(current, updates http.Header, clear []string
 	return out
 }
 
+// startHandlerSpan opens the span covering model routing, interceptors and the
+// conductor call for one client request.
+func startHandlerSpan(ctx context.Context, name, entryProtocol, model string) (context.Context, *tracing.Span) {
+	return tracing.Start(ctx, name,
+		tracing.String("request.model", model),
+		tracing.String("format.entry", entryProtocol),
+	)
+}
+
 func interceptRequestBeforeAuth(ctx context.Context, host PluginInterceptorHost, req pluginapi.RequestInterceptRequest, skipPluginID string) pluginapi.RequestInterceptResponse {
+	ctx, span := tracing.Start(ctx, "plugin.intercept_request_before_auth")
+	defer span.End()
 	if skipPluginID != "" {
 		if skipper, ok := host.(pluginInterceptorSkipHost); ok {
 			return skipper.InterceptRequestBeforeAuthExcept(ctx, req, skipPluginID)
@@ -1882,6 +1973,8 @@ func interceptRequestBeforeAuth(ctx context.Context, host PluginInterceptorHost,
 }
 
 func interceptRequestAfterAuth(ctx context.Context, host PluginInterceptorHost, req pluginapi.RequestInterceptRequest, skipPluginID string) pluginapi.RequestInterceptResponse {
+	ctx, span := tracing.Start(ctx, "plugin.intercept_request_after_auth")
+	defer span.End()
 	if skipPluginID != "" {
 		if skipper, ok := host.(pluginInterceptorSkipHost); ok {
 			return skipper.InterceptRequestAfterAuthExcept(ctx, req, skipPluginID)
@@ -1891,6 +1984,8 @@ func interceptRequestAfterAuth(ctx context.Context, host PluginInterceptorHost,
 }
 
 func interceptResponse(ctx context.Context, host PluginInterceptorHost, req pluginapi.ResponseInterceptRequest, skipPluginID string) pluginapi.ResponseInterceptResponse {
+	ctx, span := tracing.Start(ctx, "plugin.intercept_response")
+	defer span.End()
 	if skipPluginID != "" {
 		if skipper, ok := host.(pluginInterceptorSkipHost); ok {
 			return skipper.InterceptResponseExcept(ctx, req, skipPluginID)
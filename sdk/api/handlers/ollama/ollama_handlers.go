@@ -0,0 +1,365 @@
+// Package ollama provides HTTP handlers for the Ollama-compatible API endpoints.
+// Chat and generate requests are translated to OpenAI Chat Completions and executed
+// through the shared auth manager, so every configured credential is reachable from
+// Ollama clients. Responses are written back as Ollama JSON or NDJSON streams.
+package ollama
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/router-for-me/CLIProxyAPI/v7/internal/constant"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/interfaces"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/registry"
+	ollamaconverter "github.com/router-for-me/CLIProxyAPI/v7/internal/translator/openai/ollama"
+	"github.com/router-for-me/CLIProxyAPI/v7/sdk/api/handlers"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// ndjsonContentType is the media type Ollama uses for streamed responses.
+const ndjsonContentType = "application/x-ndjson"
+
+// OllamaAPIHandler contains the handlers for Ollama API endpoints.
+type OllamaAPIHandler struct {
+	*handlers.BaseAPIHandler
+}
+
+// NewOllamaAPIHandler creates a new Ollama API handlers instance.
+//
+// Parameters:
+//   - apiHandlers: The base API handlers instance
+//
+// Returns:
+//   - *OllamaAPIHandler: A new Ollama API handlers instance
+func NewOllamaAPIHandler(apiHandlers *handlers.BaseAPIHandler) *OllamaAPIHandler {
+	return &OllamaAPIHandler{
+		BaseAPIHandler: apiHandlers,
+	}
+}
+
+// HandlerType returns the identifier for this handler implementation.
+func (h *OllamaAPIHandler) HandlerType() string {
+	return Ollama
+}
+
+// Models returns the model metadata exposed to Ollama clients. Ollama requests run
+// through the OpenAI pipeline, so the OpenAI model list applies.
+func (h *OllamaAPIHandler) Models() []map[string]any {
+	modelRegistry := registry.GetGlobalRegistry()
+	return modelRegistry.GetAvailableModels(OpenAI)
+}
+
+// Chat handles the /api/chat endpoint.
+func (h *OllamaAPIHandler) Chat(c *gin.Context) {
+	h.handleCompletion(c)
+}
+
+// Generate handles the /api/generate endpoint.
+func (h *OllamaAPIHandler) Generate(c *gin.Context) {
+	h.handleCompletion(c)
+}
+
+// Tags handles the /api/tags endpoint, listing the available models in Ollama format.
+func (h *OllamaAPIHandler) Tags(c *gin.Context) {
+	allModels := h.Models()
+	sort.SliceStable(allModels, func(i, j int) bool {
+		return fmt.Sprint(allModels[i]["id"]) < fmt.Sprint(allModels[j]["id"])
+	})
+	models := make([]gin.H, 0, len(allModels))
+	for _, model := range allModels {
+		id := fmt.Sprint(model["id"])
+		models = append(models, gin.H{
+			"name":        id,
+			"model":       id,
+			"modified_at": modifiedAt(model),
+			"size":        0,
+			"digest":      modelDigest(id),
+			"details":     modelDetails(model),
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"models": models})
+}
+
+// Show handles the /api/show endpoint, describing a single model.
+func (h *OllamaAPIHandler) Show(c *gin.Context) {
+	rawJSON, err := handlers.ReadRequestBody(c)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+	name := gjson.GetBytes(rawJSON, "model").String()
+	if name == "" {
+		name = gjson.GetBytes(rawJSON, "name").String()
+	}
+	name = normalizeModelName(name)
+	if name == "" {
+		writeError(c, http.StatusBadRequest, "model is required")
+		return
+	}
+
+	for _, model := range h.Models() {
+		if fmt.Sprint(model["id"]) != name {
+			continue
+		}
+		details := modelDetails(model)
+		family := details["family"].(string)
+		modelInfo := gin.H{
+			"general.architecture": family,
+			"general.basename":     name,
+		}
+		if contextLength, ok := model["context_length"]; ok {
+			modelInfo[family+".context_length"] = contextLength
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"modelfile":    "FROM " + name + "\n",
+			"parameters":   "",
+			"template":     "{{ .Prompt }}",
+			"details":      details,
+			"model_info":   modelInfo,
+			"capabilities": []string{"completion", "tools"},
+			"modified_at":  modifiedAt(model),
+		})
+		return
+	}
+	writeError(c, http.StatusNotFound, fmt.Sprintf("model '%s' not found", name))
+}
+
+// handleCompletion serves /api/chat and /api/generate. Both share one conversion path;
+// the converter picks the response shape from the original request.
+func (h *OllamaAPIHandler) handleCompletion(c *gin.Context) {
+	rawJSON, err := handlers.ReadRequestBody(c)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+	modelName := normalizeModelName(gjson.GetBytes(rawJSON, "model").String())
+	if modelName == "" {
+		writeError(c, http.StatusBadRequest, "model is required")
+		return
+	}
+	// Keep the client's model name in responses even when ":latest" was stripped.
+	rawJSON, _ = sjson.SetBytes(rawJSON, "model", modelName)
+
+	stream := ollamaconverter.IsStreamRequest(rawJSON)
+	chatJSON := ollamaconverter.ConvertOllamaRequestToOpenAI(modelName, rawJSON, stream)
+	if stream {
+		h.handleStreamingResponse(c, rawJSON, chatJSON, modelName)
+	} else {
+		h.handleNonStreamingResponse(c, rawJSON, chatJSON, modelName)
+	}
+}
+
+// handleNonStreamingResponse executes the converted request and writes a single Ollama response.
+func (h *OllamaAPIHandler) handleNonStreamingResponse(c *gin.Context, rawJSON, chatJSON []byte, modelName string) {
+	c.Header("Content-Type", "application/json")
+
+	cliCtx, cliCancel := h.GetContextWithCancel(h, c, context.Background())
+	stopKeepAlive := h.StartNonStreamingKeepAlive(c, cliCtx)
+	resp, upstreamHeaders, errMsg := h.ExecuteWithAuthManager(cliCtx, OpenAI, modelName, chatJSON, "")
+	stopKeepAlive()
+	if errMsg != nil {
+		h.writeErrorMessage(c, errMsg)
+		cliCancel(errMsg.Error)
+		return
+	}
+	handlers.WriteUpstreamHeaders(c.Writer.Header(), upstreamHeaders)
+	out := ollamaconverter.ConvertOpenAIResponseToOllamaNonStream(cliCtx, modelName, rawJSON, chatJSON, resp, nil)
+	_, _ = c.Writer.Write(out)
+	cliCancel()
+}
+
+// handleStreamingResponse executes the converted request and streams Ollama NDJSON messages.
+func (h *OllamaAPIHandler) handleStreamingResponse(c *gin.Context, rawJSON, chatJSON []byte, modelName string) {
+	flusher, ok := c.Writer.(http.Flusher)
+	if !ok {
+		writeError(c, http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+
+	cliCtx, cliCancel := h.GetContextWithCancel(h, c, context.Background())
+	dataChan, upstreamHeaders, errChan := h.ExecuteStreamWithAuthManager(cliCtx, OpenAI, modelName, chatJSON, "")
+
+	var param any
+	convert := func(chunk []byte) [][]byte {
+		return ollamaconverter.ConvertOpenAIResponseToOllama(cliCtx, modelName, rawJSON, chatJSON, chunk, &param)
+	}
+	writeLines := func(lines [][]byte) {
+		for _, line := range lines {
+			_, _ = c.Writer.Write(line)
+			_, _ = c.Writer.Write([]byte("\n"))
+		}
+	}
+	setHeaders := func() {
+		c.Header("Content-Type", ndjsonContentType)
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		handlers.WriteUpstreamHeaders(c.Writer.Header(), upstreamHeaders)
+	}
+
+	// Peek at the first chunk to determine success or failure before setting headers.
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			cliCancel(c.Request.Context().Err())
+			return
+		case errMsg, ok := <-errChan:
+			if !ok {
+				errChan = nil
+				continue
+			}
+			h.writeErrorMessage(c, errMsg)
+			if errMsg != nil {
+				cliCancel(errMsg.Error)
+			} else {
+				cliCancel(nil)
+			}
+			return
+		case chunk, ok := <-dataChan:
+			setHeaders()
+			if !ok {
+				writeLines(convert([]byte("[DONE]")))
+				flusher.Flush()
+				cliCancel(nil)
+				return
+			}
+			writeLines(convert(chunk))
+			flusher.Flush()
+
+			done := make(chan struct{})
+			var doneOnce sync.Once
+			stop := func() { doneOnce.Do(func() { close(done) }) }
+
+			// Convert on a separate goroutine so the converter state is only touched in order.
+			convertedChan := make(chan []byte)
+			go func() {
+				defer close(convertedChan)
+				for {
+					select {
+					case <-done:
+						return
+					case chunk, ok := <-dataChan:
+						if !ok {
+							return
+						}
+						for _, line := range convert(chunk) {
+							select {
+							case <-done:
+								return
+							case convertedChan <- line:
+							}
+						}
+					}
+				}
+			}()
+
+			disableKeepAlive := time.Duration(0)
+			h.ForwardStream(c, flusher, func(err error) {
+				stop()
+				cliCancel(err)
+			}, convertedChan, errChan, handlers.StreamForwardOptions{
+				// NDJSON has no comment syntax for heartbeats.
+				KeepAliveInterval: &disableKeepAlive,
+				WriteChunk: func(line []byte) {
+					writeLines([][]byte{line})
+				},
+				WriteTerminalError: func(errMsg *interfaces.ErrorMessage) {
+					_, text := errorStatusText(errMsg)
+					line, _ := sjson.SetBytes([]byte(`{}`), "error", text)
+					writeLines([][]byte{line})
+				},
+				WriteDone: func() {
+					writeLines(convert([]byte("[DONE]")))
+				},
+			})
+			return
+		}
+	}
+}
+
+// writeErrorMessage writes an upstream error using Ollama's {"error": "..."} body.
+func (h *OllamaAPIHandler) writeErrorMessage(c *gin.Context, msg *interfaces.ErrorMessage) {
+	if msg != nil && msg.Addon != nil && handlers.PassthroughHeadersEnabled(h.Cfg) {
+		for key, values := range msg.Addon {
+			if len(values) == 0 {
+				continue
+			}
+			c.Writer.Header().Del(key)
+			for _, value := range values {
+				c.Writer.Header().Add(key, value)
+			}
+		}
+	}
+	status, text := errorStatusText(msg)
+	writeError(c, status, text)
+}
+
+func errorStatusText(msg *interfaces.ErrorMessage) (int, string) {
+	status := http.StatusInternalServerError
+	if msg != nil && msg.StatusCode > 0 {
+		status = msg.StatusCode
+	}
+	text := http.StatusText(status)
+	if msg != nil && msg.Error != nil {
+		if v := strings.TrimSpace(msg.Error.Error()); v != "" {
+			text = v
+		}
+	}
+	// Upstream errors often arrive as OpenAI-style JSON; surface just the message.
+	if message := gjson.Get(text, "error.message"); gjson.Valid(text) && message.Exists() {
+		text = message.String()
+	}
+	return status, text
+}
+
+func writeError(c *gin.Context, status int, text string) {
+	c.JSON(status, gin.H{"error": text})
+}
+
+// normalizeModelName drops the implicit ":latest" tag Ollama clients append.
+func normalizeModelName(name string) string {
+	return strings.TrimSuffix(strings.TrimSpace(name), ":latest")
+}
+
+// modelDigest derives a stable digest for a model id; Ollama clients use it as a cache key.
+func modelDigest(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:])
+}
+
+func modifiedAt(model map[string]any) string {
+	var created int64
+	switch v := model["created"].(type) {
+	case int64:
+		created = v
+	case int:
+		created = int64(v)
+	}
+	if created <= 0 {
+		return time.Unix(0, 0).UTC().Format(time.RFC3339)
+	}
+	return time.Unix(created, 0).UTC().Format(time.RFC3339)
+}
+
+func modelDetails(model map[string]any) gin.H {
+	family, _ := model["owned_by"].(string)
+	if family == "" {
+		family = "unknown"
+	}
+	return gin.H{
+		"parent_model":       "",
+		"format":             "",
+		"family":             family,
+		"families":           []string{family},
+		"parameter_size":     "",
+		"quantization_level": "",
+	}
+}
@@ -0,0 +1,162 @@
+package ollama
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/registry"
+	apihandlers "github.com/router-for-me/CLIProxyAPI/v7/sdk/api/handlers"
+	coreauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
+	coreexecutor "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/executor"
+	sdkconfig "github.com/router-for-me/CLIProxyAPI/v7/sdk/config"
+	"github.com/tidwall/gjson"
+)
+
+type ollamaCaptureExecutor struct {
+	sourceFormat string
+	payload      []byte
+}
+
+func (e *ollamaCaptureExecutor) Identifier() string { return "ollama-test" }
+
+func (e *ollamaCaptureExecutor) Execute(_ context.Context, _ *coreauth.Auth, req coreexecutor.Request, opts coreexecutor.Options) (coreexecutor.Response, error) {
+	e.sourceFormat = opts.SourceFormat.String()
+	e.payload = append([]byte(nil), req.Payload...)
+	return coreexecutor.Response{Payload: []byte(`{"choices":[{"message":{"role":"assistant","content":"hi there"},"finish_reason":"stop"}],"usage":{"prompt_tokens":3,"completion_tokens":2}}`)}, nil
+}
+
+func (e *ollamaCaptureExecutor) ExecuteStream(_ context.Context, _ *coreauth.Auth, req coreexecutor.Request, opts coreexecutor.Options) (*coreexecutor.StreamResult, error) {
+	e.sourceFormat = opts.SourceFormat.String()
+	e.payload = append([]byte(nil), req.Payload...)
+	chunks := make(chan coreexecutor.StreamChunk, 3)
+	chunks <- coreexecutor.StreamChunk{Payload: []byte(`{"choices":[{"index":0,"delta":{"content":"hi"}}]}`)}
+	chunks <- coreexecutor.StreamChunk{Payload: []byte(`{"choices":[{"index":0,"delta":{"content":" there"}}]}`)}
+	chunks <- coreexecutor.StreamChunk{Payload: []byte(`{"choices":[{"index":0,"delta":{},"finish_reason":"stop"}],"usage":{"prompt_tokens":3,"completion_tokens":2}}`)}
+	close(chunks)
+	return &coreexecutor.StreamResult{Chunks: chunks}, nil
+}
+
+func (e *ollamaCaptureExecutor) Refresh(_ context.Context, auth *coreauth.Auth) (*coreauth.Auth, error) {
+	return auth, nil
+}
+
+func (e *ollamaCaptureExecutor) CountTokens(context.Context, *coreauth.Auth, coreexecutor.Request, coreexecutor.Options) (coreexecutor.Response, error) {
+	return coreexecutor.Response{}, &coreauth.Error{Code: "not_implemented", Message: "CountTokens not implemented"}
+}
+
+func (e *ollamaCaptureExecutor) HttpRequest(context.Context, *coreauth.Auth, *http.Request) (*http.Response, error) {
+	return nil, &coreauth.Error{Code: "not_implemented", Message: "HttpRequest not implemented"}
+}
+
+func newOllamaTestHandler(t *testing.T, executor *ollamaCaptureExecutor) *OllamaAPIHandler {
+	t.Helper()
+
+	manager := coreauth.NewManager(nil, &coreauth.RoundRobinSelector{}, nil)
+	manager.RegisterExecutor(executor)
+	auth := &coreauth.Auth{ID: "ollama-test-auth", Provider: "ollama-test", Status: coreauth.StatusActive}
+	if _, errRegister := manager.Register(context.Background(), auth); errRegister != nil {
+		t.Fatalf("manager.Register: %v", errRegister)
+	}
+	registry.GetGlobalRegistry().RegisterClient(auth.ID, auth.Provider, []*registry.ModelInfo{{ID: "ollama-test-model", OwnedBy: "openai", ContextLength: 8192}})
+	t.Cleanup(func() {
+		registry.GetGlobalRegistry().UnregisterClient(auth.ID)
+	})
+
+	base := apihandlers.NewBaseAPIHandlers(&sdkconfig.SDKConfig{}, manager)
+	return NewOllamaAPIHandler(base)
+}
+
+func performRequest(t *testing.T, method, path, body string, handler gin.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Handle(method, path, handler)
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestChatNonStreamingRoutesThroughOpenAIPipeline(t *testing.T) {
+	executor := &ollamaCaptureExecutor{}
+	handler := newOllamaTestHandler(t, executor)
+
+	resp := performRequest(t, http.MethodPost, "/api/chat", `{"model":"ollama-test-model:latest","stream":false,"messages":[{"role":"user","content":"hello"}]}`, handler.Chat)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", resp.Code, resp.Body.String())
+	}
+	if executor.sourceFormat != "openai" {
+		t.Fatalf("source format = %q, want openai", executor.sourceFormat)
+	}
+	if got := gjson.GetBytes(executor.payload, "messages.0.content").String(); got != "hello" {
+		t.Fatalf("payload message = %q", got)
+	}
+	body := gjson.Parse(strings.TrimSpace(resp.Body.String()))
+	if body.Get("message.content").String() != "hi there" || !body.Get("done").Bool() {
+		t.Fatalf("unexpected body: %s", resp.Body.String())
+	}
+	if body.Get("model").String() != "ollama-test-model" || body.Get("eval_count").Int() != 2 {
+		t.Fatalf("unexpected body: %s", resp.Body.String())
+	}
+}
+
+func TestGenerateStreamsNDJSON(t *testing.T) {
+	executor := &ollamaCaptureExecutor{}
+	handler := newOllamaTestHandler(t, executor)
+
+	resp := performRequest(t, http.MethodPost, "/api/generate", `{"model":"ollama-test-model","prompt":"hello"}`, handler.Generate)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", resp.Code, resp.Body.String())
+	}
+	if got := resp.Header().Get("Content-Type"); got != ndjsonContentType {
+		t.Fatalf("content type = %q", got)
+	}
+	lines := strings.Split(strings.TrimSpace(resp.Body.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3: %s", len(lines), resp.Body.String())
+	}
+	if gjson.Get(lines[0], "response").String() != "hi" || gjson.Get(lines[1], "response").String() != " there" {
+		t.Fatalf("unexpected chunks: %s", resp.Body.String())
+	}
+	if !gjson.Get(lines[2], "done").Bool() || gjson.Get(lines[2], "prompt_eval_count").Int() != 3 {
+		t.Fatalf("unexpected final line: %s", lines[2])
+	}
+}
+
+func TestTagsAndShow(t *testing.T) {
+	handler := newOllamaTestHandler(t, &ollamaCaptureExecutor{})
+
+	tags := performRequest(t, http.MethodGet, "/api/tags", "", handler.Tags)
+	if tags.Code != http.StatusOK {
+		t.Fatalf("tags status = %d", tags.Code)
+	}
+	found := false
+	for _, model := range gjson.Get(tags.Body.String(), "models").Array() {
+		if model.Get("name").String() == "ollama-test-model" {
+			found = model.Get("details.family").String() == "openai" && len(model.Get("digest").String()) == 64
+		}
+	}
+	if !found {
+		t.Fatalf("model missing from tags: %s", tags.Body.String())
+	}
+
+	show := performRequest(t, http.MethodPost, "/api/show", `{"model":"ollama-test-model"}`, handler.Show)
+	if show.Code != http.StatusOK {
+		t.Fatalf("show status = %d, body = %s", show.Code, show.Body.String())
+	}
+	if gjson.Get(show.Body.String(), `model_info.openai\.context_length`).Int() != 8192 {
+		t.Fatalf("unexpected show body: %s", show.Body.String())
+	}
+
+	missing := performRequest(t, http.MethodPost, "/api/show", `{"model":"nope"}`, handler.Show)
+	if missing.Code != http.StatusNotFound || gjson.Get(missing.Body.String(), "error").String() != "model 'nope' not found" {
+		t.Fatalf("unexpected missing-model response: %d %s", missing.Code, missing.Body.String())
+	}
+}
@@ -0,0 +1,470 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/interfaces"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/registry"
+	"github.com/router-for-me/CLIProxyAPI/v7/sdk/api/handlers"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+const (
+	// audioHandlerType is the source format the openai-compatibility executor
+	// forwards to the upstream /audio/transcriptions and /audio/speech endpoints.
+	audioHandlerType        = "openai-audio"
+	geminiAudioHandlerType  = "gemini"
+	audioTranscriptionsPath = "/v1/audio/transcriptions"
+	audioSpeechPath         = "/v1/audio/speech"
+
+	// geminiSpeechSampleRate is the PCM sample rate Gemini TTS models return
+	// when the response MIME type does not state one.
+	geminiSpeechSampleRate = 24000
+)
+
+// openAISpeechVoices lists the OpenAI voice names. Gemini speech models use
+// their own voices, so these fall back to the model default.
+var openAISpeechVoices = map[string]struct{}{
+	"alloy": {}, "ash": {}, "ballad": {}, "coral": {}, "echo": {}, "fable": {},
+	"nova": {}, "onyx": {}, "sage": {}, "shimmer": {}, "verse": {},
+}
+
+// isGeminiTranscriptionModel reports whether model is a Gemini model that can
+// transcribe audio input.
+func isGeminiTranscriptionModel(model string) bool {
+	baseModel := imagesModelBase(model)
+	return strings.HasPrefix(baseModel, "gemini-") && !strings.Contains(baseModel, "-tts") && !strings.Contains(baseModel, "-image")
+}
+
+// isGeminiSpeechModel reports whether model is a Gemini text-to-speech model.
+func isGeminiSpeechModel(model string) bool {
+	baseModel := imagesModelBase(model)
+	return strings.HasPrefix(baseModel, "gemini-") && strings.Contains(baseModel, "-tts")
+}
+
+// isOpenAICompatAudioModel reports whether model is served by an
+// openai-compatibility provider, whose upstream is expected to offer the
+// OpenAI audio endpoints.
+func isOpenAICompatAudioModel(model string) bool {
+	model = strings.TrimSpace(model)
+	if model == "" {
+		return false
+	}
+	info := registry.LookupModelInfo(model)
+	return info != nil && info.Type == "openai-compatibility"
+}
+
+func writeAudioInvalidRequest(c *gin.Context, message string) {
+	c.JSON(http.StatusBadRequest, handlers.ErrorResponse{
+		Error: handlers.ErrorDetail{
+			Message: "Invalid request: " + message,
+			Type:    "invalid_request_error",
+		},
+	})
+}
+
+func normalizeTranscriptionResponseFormat(raw string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", "json":
+		return "json", true
+	case "text":
+		return "text", true
+	case "srt":
+		return "srt", true
+	case "vtt":
+		return "vtt", true
+	case "verbose_json":
+		return "verbose_json", true
+	default:
+		return "", false
+	}
+}
+
+// AudioTranscriptions handles the /v1/audio/transcriptions endpoint.
+// Gemini models receive the upload as an inline audio part of a
+// generateContent request; other models are forwarded to the upstream
+// transcription endpoint of their openai-compatibility provider.
+//
+// Parameters:
+//   - c: The Gin context containing the HTTP request and response
+func (h *OpenAIAPIHandler) AudioTranscriptions(c *gin.Context) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		writeAudioInvalidRequest(c, err.Error())
+		return
+	}
+
+	model := strings.TrimSpace(c.PostForm("model"))
+	if model == "" {
+		writeAudioInvalidRequest(c, "model is required")
+		return
+	}
+	files := form.File["file"]
+	if len(files) == 0 || files[0] == nil {
+		writeAudioInvalidRequest(c, "file is required")
+		return
+	}
+	responseFormat, ok := normalizeTranscriptionResponseFormat(c.PostForm("response_format"))
+	if !ok {
+		writeAudioInvalidRequest(c, fmt.Sprintf("unsupported response_format %q", c.PostForm("response_format")))
+		return
+	}
+
+	switch {
+	case isOpenAICompatAudioModel(model):
+		payload, contentType, errBuild := buildOpenAICompatImagesMultipartRequest(form, model, false)
+		if errBuild != nil {
+			writeAudioInvalidRequest(c, errBuild.Error())
+			return
+		}
+		c.Request.Header.Set("Content-Type", contentType)
+		h.forwardAudio(c, model, payload, transcriptionContentType(responseFormat))
+	case isGeminiTranscriptionModel(model):
+		audio, mimeType, errRead := readAudioUpload(files[0])
+		if errRead != nil {
+			writeAudioInvalidRequest(c, errRead.Error())
+			return
+		}
+		language := strings.TrimSpace(c.PostForm("language"))
+		geminiReq := buildGeminiTranscriptionRequest(audio, mimeType, c.PostForm("prompt"), language, responseFormat, c.PostForm("temperature"))
+		h.transcribeWithGemini(c, model, geminiReq, responseFormat, language)
+	default:
+		writeAudioInvalidRequest(c, fmt.Sprintf("model %s is not supported on %s. Use a Gemini model or a configured openai-compatibility model.", model, audioTranscriptionsPath))
+	}
+}
+
+// AudioSpeech handles the /v1/audio/speech endpoint.
+// Gemini TTS models return wav or raw pcm audio; other models are forwarded to
+// the upstream speech endpoint of their openai-compatibility provider.
+//
+// Parameters:
+//   - c: The Gin context containing the HTTP request and response
+func (h *OpenAIAPIHandler) AudioSpeech(c *gin.Context) {
+	rawJSON, err := handlers.ReadRequestBody(c)
+	if err != nil {
+		writeAudioInvalidRequest(c, err.Error())
+		return
+	}
+	if !json.Valid(rawJSON) {
+		writeAudioInvalidRequest(c, "body must be valid JSON")
+		return
+	}
+	model := strings.TrimSpace(gjson.GetBytes(rawJSON, "model").String())
+	if model == "" {
+		writeAudioInvalidRequest(c, "model is required")
+		return
+	}
+	input := gjson.GetBytes(rawJSON, "input").String()
+	if strings.TrimSpace(input) == "" {
+		writeAudioInvalidRequest(c, "input is required")
+		return
+	}
+
+	switch {
+	case isOpenAICompatAudioModel(model):
+		payload, _ := sjson.SetBytes(rawJSON, "model", model)
+		c.Request.Header.Set("Content-Type", "application/json")
+		h.forwardAudio(c, model, payload, speechContentType(gjson.GetBytes(rawJSON, "response_format").String()))
+	case isGeminiSpeechModel(model):
+		responseFormat := strings.ToLower(strings.TrimSpace(gjson.GetBytes(rawJSON, "response_format").String()))
+		if responseFormat == "" {
+			responseFormat = "wav"
+		}
+		if responseFormat != "wav" && responseFormat != "pcm" {
+			writeAudioInvalidRequest(c, fmt.Sprintf("model %s only supports response_format wav or pcm", model))
+			return
+		}
+		geminiReq := buildGeminiSpeechRequest(input, gjson.GetBytes(rawJSON, "voice").String(), gjson.GetBytes(rawJSON, "instructions").String())
+		h.synthesizeWithGemini(c, model, geminiReq, responseFormat)
+	default:
+		writeAudioInvalidRequest(c, fmt.Sprintf("model %s is not supported on %s. Use a Gemini TTS model or a configured openai-compatibility model.", model, audioSpeechPath))
+	}
+}
+
+// forwardAudio sends the request to an openai-compatibility upstream and
+// relays its response body unchanged. contentType is used unless upstream
+// headers are passed through.
+func (h *OpenAIAPIHandler) forwardAudio(c *gin.Context, model string, payload []byte, contentType string) {
+	cliCtx, cliCancel := h.GetContextWithCancel(h, c, context.Background())
+	resp, upstreamHeaders, errMsg := h.ExecuteWithAuthManager(cliCtx, audioHandlerType, model, payload, "")
+	if errMsg != nil {
+		h.WriteErrorResponse(c, errMsg)
+		cliCancel(errMsg.Error)
+		return
+	}
+	handlers.WriteUpstreamHeaders(c.Writer.Header(), upstreamHeaders)
+	if c.Writer.Header().Get("Content-Type") == "" {
+		c.Header("Content-Type", contentType)
+	}
+	_, _ = c.Writer.Write(resp)
+	cliCancel()
+}
+
+func (h *OpenAIAPIHandler) transcribeWithGemini(c *gin.Context, model string, geminiReq []byte, responseFormat string, language string) {
+	cliCtx, cliCancel := h.GetContextWithCancel(h, c, context.Background())
+	resp, _, errMsg := h.ExecuteWithAuthManager(cliCtx, geminiAudioHandlerType, model, geminiReq, "")
+	if errMsg != nil {
+		h.WriteErrorResponse(c, errMsg)
+		cliCancel(errMsg.Error)
+		return
+	}
+	contentType, body := buildTranscriptionResponse(resp, responseFormat, language)
+	c.Data(http.StatusOK, contentType, body)
+	cliCancel()
+}
+
+func (h *OpenAIAPIHandler) synthesizeWithGemini(c *gin.Context, model string, geminiReq []byte, responseFormat string) {
+	cliCtx, cliCancel := h.GetContextWithCancel(h, c, context.Background())
+	resp, _, errMsg := h.ExecuteWithAuthManager(cliCtx, geminiAudioHandlerType, model, geminiReq, "")
+	if errMsg != nil {
+		h.WriteErrorResponse(c, errMsg)
+		cliCancel(errMsg.Error)
+		return
+	}
+	pcm, sampleRate, err := extractGeminiSpeechAudio(resp)
+	if err != nil {
+		errMsg = &interfaces.ErrorMessage{StatusCode: http.StatusBadGateway, Error: err}
+		h.WriteErrorResponse(c, errMsg)
+		cliCancel(err)
+		return
+	}
+	if responseFormat == "pcm" {
+		c.Data(http.StatusOK, speechContentType(responseFormat), pcm)
+	} else {
+		c.Data(http.StatusOK, speechContentType(responseFormat), wrapPCMAsWAV(pcm, sampleRate))
+	}
+	cliCancel()
+}
+
+func transcriptionContentType(responseFormat string) string {
+	if responseFormat == "json" || responseFormat == "verbose_json" {
+		return "application/json"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+func speechContentType(responseFormat string) string {
+	switch strings.ToLower(strings.TrimSpace(responseFormat)) {
+	case "opus":
+		return "audio/ogg"
+	case "aac":
+		return "audio/aac"
+	case "flac":
+		return "audio/flac"
+	case "wav":
+		return "audio/wav"
+	case "pcm":
+		return "audio/pcm"
+	default:
+		return "audio/mpeg"
+	}
+}
+
+// readAudioUpload reads an uploaded audio file and resolves its MIME type from
+// the part header, then the file extension.
+func readAudioUpload(fileHeader *multipart.FileHeader) ([]byte, string, error) {
+	f, err := fileHeader.Open()
+	if err != nil {
+		return nil, "", fmt.Errorf("open upload file failed: %w", err)
+	}
+	defer func() {
+		if errClose := f.Close(); errClose != nil {
+			log.Errorf("openai audio: close upload file error: %v", errClose)
+		}
+	}()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, "", fmt.Errorf("read upload file failed: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, "", fmt.Errorf("file is empty")
+	}
+
+	mimeType := strings.TrimSpace(fileHeader.Header.Get("Content-Type"))
+	if mimeType == "" || mimeType == "application/octet-stream" {
+		mimeType = mime.TypeByExtension(strings.ToLower(filepath.Ext(fileHeader.Filename)))
+	}
+	if mediaType, _, errParse := mime.ParseMediaType(mimeType); errParse == nil {
+		mimeType = mediaType
+	}
+	if mimeType == "" || mimeType == "application/octet-stream" {
+		mimeType = "audio/mpeg"
+	}
+	return data, mimeType, nil
+}
+
+// buildGeminiTranscriptionRequest builds a generateContent request that asks
+// the model for a verbatim transcript of the inline audio. Subtitle formats
+// are produced by the model itself.
+func buildGeminiTranscriptionRequest(audio []byte, mimeType string, prompt string, language string, responseFormat string, temperature string) []byte {
+	instruction := "Transcribe the speech in the attached audio verbatim. Reply with the transcript only, without any commentary."
+	if language != "" {
+		instruction += " The spoken language is " + language + "."
+	}
+	switch responseFormat {
+	case "srt":
+		instruction += " Format the transcript as SubRip (SRT) subtitles with sequence numbers and HH:MM:SS,mmm --> HH:MM:SS,mmm timestamps."
+	case "vtt":
+		instruction += " Format the transcript as WebVTT subtitles starting with a WEBVTT line and using HH:MM:SS.mmm --> HH:MM:SS.mmm timestamps."
+	}
+	if prompt = strings.TrimSpace(prompt); prompt != "" {
+		instruction += " Use this context for spelling and style: " + prompt
+	}
+
+	req := []byte(`{"contents":[{"role":"user","parts":[{"text":""},{"inlineData":{"mimeType":"","data":""}}]}]}`)
+	req, _ = sjson.SetBytes(req, "contents.0.parts.0.text", instruction)
+	req, _ = sjson.SetBytes(req, "contents.0.parts.1.inlineData.mimeType", mimeType)
+	req, _ = sjson.SetBytes(req, "contents.0.parts.1.inlineData.data", base64.StdEncoding.EncodeToString(audio))
+	if value, err := strconv.ParseFloat(strings.TrimSpace(temperature), 64); err == nil {
+		req, _ = sjson.SetBytes(req, "generationConfig.temperature", value)
+	}
+	return req
+}
+
+// buildTranscriptionResponse renders a Gemini transcript in the requested
+// OpenAI transcription response format.
+func buildTranscriptionResponse(payload []byte, responseFormat string, language string) (string, []byte) {
+	text := geminiResponseText(payload)
+	switch responseFormat {
+	case "text":
+		return transcriptionContentType(responseFormat), []byte(text)
+	case "srt":
+		return transcriptionContentType(responseFormat), []byte(stripCodeFence(text))
+	case "vtt":
+		text = stripCodeFence(text)
+		if !strings.HasPrefix(text, "WEBVTT") {
+			text = "WEBVTT\n\n" + text
+		}
+		return transcriptionContentType(responseFormat), []byte(text)
+	}
+
+	out := []byte(`{"text":""}`)
+	if responseFormat == "verbose_json" {
+		out = []byte(`{"task":"transcribe","language":"","text":""}`)
+		out, _ = sjson.SetBytes(out, "language", language)
+	}
+	out, _ = sjson.SetBytes(out, "text", text)
+	if usage := gjson.GetBytes(payload, "usageMetadata"); usage.IsObject() {
+		out, _ = sjson.SetBytes(out, "usage.type", "tokens")
+		out, _ = sjson.SetBytes(out, "usage.input_tokens", usage.Get("promptTokenCount").Int())
+		out, _ = sjson.SetBytes(out, "usage.output_tokens", usage.Get("candidatesTokenCount").Int())
+		out, _ = sjson.SetBytes(out, "usage.total_tokens", usage.Get("totalTokenCount").Int())
+	}
+	return transcriptionContentType(responseFormat), out
+}
+
+// geminiResponseText joins the non-thought text parts of the first candidate.
+func geminiResponseText(payload []byte) string {
+	var texts []string
+	for _, part := range gjson.GetBytes(payload, "candidates.0.content.parts").Array() {
+		if part.Get("thought").Bool() {
+			continue
+		}
+		if text := part.Get("text").String(); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return strings.TrimSpace(strings.Join(texts, ""))
+}
+
+func stripCodeFence(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") {
+		return text
+	}
+	if idx := strings.Index(text, "\n"); idx >= 0 {
+		text = text[idx+1:]
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "```"))
+}
+
+// buildGeminiSpeechRequest builds a generateContent request for a Gemini TTS
+// model. OpenAI voice names are dropped so the model default voice is used.
+func buildGeminiSpeechRequest(input string, voice string, instructions string) []byte {
+	text := input
+	if instructions = strings.TrimSpace(instructions); instructions != "" {
+		text = instructions + ": " + input
+	}
+	req := []byte(`{"contents":[{"role":"user","parts":[{"text":""}]}],"generationConfig":{"responseModalities":["AUDIO"]}}`)
+	req, _ = sjson.SetBytes(req, "contents.0.parts.0.text", text)
+	voice = strings.TrimSpace(voice)
+	if _, isOpenAIVoice := openAISpeechVoices[strings.ToLower(voice)]; voice != "" && !isOpenAIVoice {
+		req, _ = sjson.SetBytes(req, "generationConfig.speechConfig.voiceConfig.prebuiltVoiceConfig.voiceName", voice)
+	}
+	return req
+}
+
+// extractGeminiSpeechAudio returns the 16-bit PCM audio of a Gemini TTS
+// response and its sample rate.
+func extractGeminiSpeechAudio(payload []byte) ([]byte, int, error) {
+	var pcm bytes.Buffer
+	sampleRate := geminiSpeechSampleRate
+	for _, part := range gjson.GetBytes(payload, "candidates.0.content.parts").Array() {
+		inline := part.Get("inlineData")
+		if !inline.Exists() {
+			inline = part.Get("inline_data")
+		}
+		data := inline.Get("data").String()
+		if data == "" {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return nil, 0, fmt.Errorf("upstream returned invalid audio data: %w", err)
+		}
+		pcm.Write(decoded)
+		mimeType := inline.Get("mimeType").String()
+		if mimeType == "" {
+			mimeType = inline.Get("mime_type").String()
+		}
+		if _, params, errParse := mime.ParseMediaType(mimeType); errParse == nil {
+			if rate, errRate := strconv.Atoi(params["rate"]); errRate == nil && rate > 0 {
+				sampleRate = rate
+			}
+		}
+	}
+	if pcm.Len() == 0 {
+		return nil, 0, fmt.Errorf("upstream did not return audio output")
+	}
+	return pcm.Bytes(), sampleRate, nil
+}
+
+// wrapPCMAsWAV prefixes mono 16-bit little-endian PCM samples with a WAV header.
+func wrapPCMAsWAV(pcm []byte, sampleRate int) []byte {
+	const (
+		channels      = 1
+		bitsPerSample = 16
+	)
+	blockAlign := channels * bitsPerSample / 8
+	var out bytes.Buffer
+	out.Grow(44 + len(pcm))
+	out.WriteString("RIFF")
+	_ = binary.Write(&out, binary.LittleEndian, uint32(36+len(pcm)))
+	out.WriteString("WAVEfmt ")
+	_ = binary.Write(&out, binary.LittleEndian, uint32(16))
+	_ = binary.Write(&out, binary.LittleEndian, uint16(1))
+	_ = binary.Write(&out, binary.LittleEndian, uint16(channels))
+	_ = binary.Write(&out, binary.LittleEndian, uint32(sampleRate))
+	_ = binary.Write(&out, binary.LittleEndian, uint32(sampleRate*blockAlign))
+	_ = binary.Write(&out, binary.LittleEndian, uint16(blockAlign))
+	_ = binary.Write(&out, binary.LittleEndian, uint16(bitsPerSample))
+	out.WriteString("data")
+	_ = binary.Write(&out, binary.LittleEndian, uint32(len(pcm)))
+	out.Write(pcm)
+	return out.Bytes()
+}
@@ -0,0 +1,96 @@
+package openai
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/tidwall/gjson"
+)
+
+func TestBuildGeminiTranscriptionRequestInlinesAudio(t *testing.T) {
+	req := buildGeminiTranscriptionRequest([]byte("abc"), "audio/wav", "CLIProxyAPI", "de", "srt", "0.2")
+
+	if got := gjson.GetBytes(req, "contents.0.parts.1.inlineData.mimeType").String(); got != "audio/wav" {
+		t.Fatalf("mimeType = %q, want audio/wav", got)
+	}
+	if got := gjson.GetBytes(req, "contents.0.parts.1.inlineData.data").String(); got != "YWJj" {
+		t.Fatalf("data = %q, want YWJj", got)
+	}
+	instruction := gjson.GetBytes(req, "contents.0.parts.0.text").String()
+	for _, want := range []string{"SubRip", "de", "CLIProxyAPI"} {
+		if !strings.Contains(instruction, want) {
+			t.Fatalf("instruction %q does not mention %q", instruction, want)
+		}
+	}
+	if got := gjson.GetBytes(req, "generationConfig.temperature").Float(); got != 0.2 {
+		t.Fatalf("temperature = %v, want 0.2", got)
+	}
+}
+
+func TestBuildTranscriptionResponseFormats(t *testing.T) {
+	payload := []byte(`{"candidates":[{"content":{"parts":[{"text":"thinking","thought":true},{"text":"hello world"}]}}],"usageMetadata":{"promptTokenCount":10,"candidatesTokenCount":2,"totalTokenCount":12}}`)
+
+	contentType, body := buildTranscriptionResponse(payload, "json", "")
+	if contentType != "application/json" || gjson.GetBytes(body, "text").String() != "hello world" {
+		t.Fatalf("json response = %s (%s)", body, contentType)
+	}
+	if got := gjson.GetBytes(body, "usage.total_tokens").Int(); got != 12 {
+		t.Fatalf("usage.total_tokens = %d, want 12", got)
+	}
+
+	contentType, body = buildTranscriptionResponse(payload, "text", "")
+	if !strings.HasPrefix(contentType, "text/plain") || string(body) != "hello world" {
+		t.Fatalf("text response = %q (%s)", body, contentType)
+	}
+
+	vtt := []byte(`{"candidates":[{"content":{"parts":[{"text":"` + "```vtt\\n00:00:00.000 --> 00:00:01.000\\nhello\\n```" + `"}]}}]}`)
+	_, body = buildTranscriptionResponse(vtt, "vtt", "")
+	if string(body) != "WEBVTT\n\n00:00:00.000 --> 00:00:01.000\nhello" {
+		t.Fatalf("vtt response = %q", body)
+	}
+}
+
+func TestBuildGeminiSpeechRequestKeepsGeminiVoicesOnly(t *testing.T) {
+	req := buildGeminiSpeechRequest("hi", "Kore", "Say cheerfully")
+	if got := gjson.GetBytes(req, "generationConfig.speechConfig.voiceConfig.prebuiltVoiceConfig.voiceName").String(); got != "Kore" {
+		t.Fatalf("voiceName = %q, want Kore", got)
+	}
+	if got := gjson.GetBytes(req, "contents.0.parts.0.text").String(); got != "Say cheerfully: hi" {
+		t.Fatalf("text = %q", got)
+	}
+	if gjson.GetBytes(buildGeminiSpeechRequest("hi", "alloy", ""), "generationConfig.speechConfig").Exists() {
+		t.Fatal("OpenAI voice was forwarded to Gemini")
+	}
+}
+
+func TestExtractGeminiSpeechAudioWrapsWAV(t *testing.T) {
+	payload := []byte(`{"candidates":[{"content":{"parts":[{"inlineData":{"mimeType":"audio/L16;codec=pcm;rate=16000","data":"AAEC"}}]}}]}`)
+	pcm, rate, err := extractGeminiSpeechAudio(payload)
+	if err != nil {
+		t.Fatalf("extractGeminiSpeechAudio() error = %v", err)
+	}
+	if rate != 16000 || len(pcm) != 3 {
+		t.Fatalf("rate = %d, len = %d, want 16000 and 3", rate, len(pcm))
+	}
+	wav := wrapPCMAsWAV(pcm, rate)
+	if string(wav[:4]) != "RIFF" || string(wav[8:12]) != "WAVE" || len(wav) != 47 {
+		t.Fatalf("wav header = %q, len %d", wav[:12], len(wav))
+	}
+	if got := binary.LittleEndian.Uint32(wav[24:28]); got != 16000 {
+		t.Fatalf("sample rate = %d, want 16000", got)
+	}
+
+	if _, _, err = extractGeminiSpeechAudio([]byte(`{"candidates":[{"content":{"parts":[{"text":"no audio"}]}}]}`)); err == nil {
+		t.Fatal("expected an error for a response without audio")
+	}
+}
+
+func TestAudioModelValidation(t *testing.T) {
+	if !isGeminiTranscriptionModel("gemini-2.5-flash") || isGeminiTranscriptionModel("gemini-2.5-flash-preview-tts") {
+		t.Fatal("unexpected Gemini transcription model detection")
+	}
+	if !isGeminiSpeechModel("gemini-2.5-flash-preview-tts") || isGeminiSpeechModel("gemini-2.5-flash") {
+		t.Fatal("unexpected Gemini speech model detection")
+	}
+}
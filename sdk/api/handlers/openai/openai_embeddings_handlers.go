@@ -0,0 +1,85 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/sdk/api/handlers"
+	"github.com/tidwall/gjson"
+)
+
+// embeddingsHandlerType is the source format executors use to recognise
+// OpenAI embeddings requests.
+const embeddingsHandlerType = "openai-embedding"
+
+// Embeddings handles the /v1/embeddings endpoint.
+// The request is routed through the auth manager like chat requests so key
+// rotation, cooldowns and usage accounting apply; executors translate it to
+// the upstream embedding API (Gemini batchEmbedContents, Vertex predict or an
+// OpenAI-compatible /embeddings endpoint).
+//
+// Parameters:
+//   - c: The Gin context containing the HTTP request and response
+func (h *OpenAIAPIHandler) Embeddings(c *gin.Context) {
+	rawJSON, err := handlers.ReadRequestBody(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, handlers.ErrorResponse{
+			Error: handlers.ErrorDetail{
+				Message: fmt.Sprintf("Invalid request: %v", err),
+				Type:    "invalid_request_error",
+			},
+		})
+		return
+	}
+	if errValidate := validateEmbeddingsRequest(rawJSON); errValidate != "" {
+		c.JSON(http.StatusBadRequest, handlers.ErrorResponse{
+			Error: handlers.ErrorDetail{
+				Message: "Invalid request: " + errValidate,
+				Type:    "invalid_request_error",
+			},
+		})
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	modelName := strings.TrimSpace(gjson.GetBytes(rawJSON, "model").String())
+	cliCtx, cliCancel := h.GetContextWithCancel(h, c, context.Background())
+	resp, upstreamHeaders, errMsg := h.ExecuteWithAuthManager(cliCtx, embeddingsHandlerType, modelName, rawJSON, "")
+	if errMsg != nil {
+		h.WriteErrorResponse(c, errMsg)
+		cliCancel(errMsg.Error)
+		return
+	}
+	handlers.WriteUpstreamHeaders(c.Writer.Header(), upstreamHeaders)
+	_, _ = c.Writer.Write(resp)
+	cliCancel()
+}
+
+// validateEmbeddingsRequest returns a non-empty message when the payload is not
+// a usable OpenAI embeddings request.
+func validateEmbeddingsRequest(rawJSON []byte) string {
+	if !json.Valid(rawJSON) {
+		return "body must be valid JSON"
+	}
+	if strings.TrimSpace(gjson.GetBytes(rawJSON, "model").String()) == "" {
+		return "model is required"
+	}
+	input := gjson.GetBytes(rawJSON, "input")
+	switch {
+	case !input.Exists():
+		return "input is required"
+	case input.Type == gjson.String:
+		return ""
+	case input.IsArray():
+		if len(input.Array()) == 0 {
+			return "input must not be empty"
+		}
+		return ""
+	default:
+		return "input must be a string or an array"
+	}
+}
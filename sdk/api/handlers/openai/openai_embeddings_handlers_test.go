@@ -0,0 +1,107 @@
+package openai
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/registry"
+	apihandlers "github.com/router-for-me/CLIProxyAPI/v7/sdk/api/handlers"
+	coreauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
+	coreexecutor "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/executor"
+	sdkconfig "github.com/router-for-me/CLIProxyAPI/v7/sdk/config"
+	"github.com/tidwall/gjson"
+)
+
+type embeddingsCaptureExecutor struct {
+	sourceFormat string
+	model        string
+	payload      []byte
+}
+
+func (e *embeddingsCaptureExecutor) Identifier() string { return "gemini" }
+
+func (e *embeddingsCaptureExecutor) Execute(_ context.Context, _ *coreauth.Auth, req coreexecutor.Request, opts coreexecutor.Options) (coreexecutor.Response, error) {
+	e.sourceFormat = opts.SourceFormat.String()
+	e.model = req.Model
+	e.payload = append([]byte(nil), req.Payload...)
+	return coreexecutor.Response{Payload: []byte(`{"object":"list","data":[{"object":"embedding","index":0,"embedding":[0.25]}],"model":"embed-test-model","usage":{"prompt_tokens":1,"total_tokens":1}}`)}, nil
+}
+
+func (e *embeddingsCaptureExecutor) ExecuteStream(context.Context, *coreauth.Auth, coreexecutor.Request, coreexecutor.Options) (*coreexecutor.StreamResult, error) {
+	return nil, &coreauth.Error{Code: "not_implemented", Message: "ExecuteStream not implemented"}
+}
+
+func (e *embeddingsCaptureExecutor) Refresh(_ context.Context, auth *coreauth.Auth) (*coreauth.Auth, error) {
+	return auth, nil
+}
+
+func (e *embeddingsCaptureExecutor) CountTokens(context.Context, *coreauth.Auth, coreexecutor.Request, coreexecutor.Options) (coreexecutor.Response, error) {
+	return coreexecutor.Response{}, &coreauth.Error{Code: "not_implemented", Message: "CountTokens not implemented"}
+}
+
+func (e *embeddingsCaptureExecutor) HttpRequest(context.Context, *coreauth.Auth, *http.Request) (*http.Response, error) {
+	return nil, &coreauth.Error{Code: "not_implemented", Message: "HttpRequest not implemented"}
+}
+
+func newEmbeddingsTestHandler(t *testing.T, executor *embeddingsCaptureExecutor) *OpenAIAPIHandler {
+	t.Helper()
+
+	manager := coreauth.NewManager(nil, &coreauth.RoundRobinSelector{}, nil)
+	manager.RegisterExecutor(executor)
+	auth := &coreauth.Auth{ID: "embeddings-test-auth", Provider: "gemini", Status: coreauth.StatusActive}
+	if _, errRegister := manager.Register(context.Background(), auth); errRegister != nil {
+		t.Fatalf("manager.Register: %v", errRegister)
+	}
+	registry.GetGlobalRegistry().RegisterClient(auth.ID, auth.Provider, []*registry.ModelInfo{{ID: "embed-test-model"}})
+	t.Cleanup(func() {
+		registry.GetGlobalRegistry().UnregisterClient(auth.ID)
+	})
+
+	base := apihandlers.NewBaseAPIHandlers(&sdkconfig.SDKConfig{}, manager)
+	return NewOpenAIAPIHandler(base)
+}
+
+func TestEmbeddingsRoutesThroughAuthManager(t *testing.T) {
+	executor := &embeddingsCaptureExecutor{}
+	handler := newEmbeddingsTestHandler(t, executor)
+
+	resp := performImagesEndpointRequest(t, "/v1/embeddings", "application/json", strings.NewReader(`{"model":"embed-test-model","input":["hello"]}`), handler.Embeddings)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", resp.Code, resp.Body.String())
+	}
+	if executor.sourceFormat != embeddingsHandlerType {
+		t.Fatalf("source format = %q, want %q", executor.sourceFormat, embeddingsHandlerType)
+	}
+	if executor.model != "embed-test-model" {
+		t.Fatalf("model = %q", executor.model)
+	}
+	if got := gjson.GetBytes(executor.payload, "input.0").String(); got != "hello" {
+		t.Fatalf("payload input = %q", got)
+	}
+	if got := gjson.Get(resp.Body.String(), "data.0.embedding.0").Float(); got != 0.25 {
+		t.Fatalf("embedding = %v, body = %s", got, resp.Body.String())
+	}
+}
+
+func TestEmbeddingsRejectsInvalidRequests(t *testing.T) {
+	handler := NewOpenAIAPIHandler(apihandlers.NewBaseAPIHandlers(&sdkconfig.SDKConfig{}, nil))
+	for name, body := range map[string]string{
+		"invalid json":  `{`,
+		"missing model": `{"input":"hi"}`,
+		"missing input": `{"model":"m"}`,
+		"empty input":   `{"model":"m","input":[]}`,
+		"object input":  `{"model":"m","input":{"text":"hi"}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp := performImagesEndpointRequest(t, "/v1/embeddings", "application/json", strings.NewReader(body), handler.Embeddings)
+			if resp.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, body = %s", resp.Code, resp.Body.String())
+			}
+			if got := gjson.Get(resp.Body.String(), "error.type").String(); got != "invalid_request_error" {
+				t.Fatalf("error.type = %q", got)
+			}
+		})
+	}
+}
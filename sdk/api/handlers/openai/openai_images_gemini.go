@@ -0,0 +1,218 @@
+package openai
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/sdk/api/handlers"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// geminiImagesHandlerType routes image requests through the native Gemini
+// request format so Gemini API keys, AI Studio and Vertex credentials can serve
+// them. The Vertex executor maps Imagen models onto the predict endpoint.
+const geminiImagesHandlerType = "gemini"
+
+const geminiImagesMaskInstruction = "The next image is an edit mask. Only change the regions of the previous image that are transparent in the mask."
+
+// isGeminiImagesModel reports whether model is a Gemini image-output model
+// (for example gemini-2.5-flash-image) or an Imagen model.
+func isGeminiImagesModel(model string) bool {
+	baseModel := imagesModelBase(model)
+	if isImagenImagesModel(model) {
+		return true
+	}
+	return strings.HasPrefix(baseModel, "gemini-") && strings.Contains(baseModel, "-image")
+}
+
+func isImagenImagesModel(model string) bool {
+	return strings.HasPrefix(imagesModelBase(model), "imagen-")
+}
+
+// geminiImagesInlineDataPart converts a base64 data URL into a Gemini inlineData part.
+func geminiImagesInlineDataPart(imageURL string) ([]byte, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(imageURL), "data:")
+	if !ok {
+		return nil, fmt.Errorf("input images must be base64 data URLs for Gemini image models")
+	}
+	meta, data, ok := strings.Cut(rest, ",")
+	if !ok || !strings.HasSuffix(strings.ToLower(meta), ";base64") || strings.TrimSpace(data) == "" {
+		return nil, fmt.Errorf("input image is not a base64 data URL")
+	}
+	mimeType := strings.TrimSpace(meta[:len(meta)-len(";base64")])
+	if mimeType == "" {
+		mimeType = "image/png"
+	}
+	part := []byte(`{"inlineData":{"mimeType":"","data":""}}`)
+	part, _ = sjson.SetBytes(part, "inlineData.mimeType", mimeType)
+	part, _ = sjson.SetBytes(part, "inlineData.data", strings.TrimSpace(data))
+	return part, nil
+}
+
+// buildGeminiImagesRequest builds a Gemini generateContent request for an
+// images generation or edit. Input images and the optional mask become inline
+// image parts after the prompt. Imagen models read the prompt, aspectRatio and
+// sampleCount fields when the executor converts the request to predict.
+func buildGeminiImagesRequest(model string, prompt string, images []string, mask string, aspectRatio string, n int64) ([]byte, error) {
+	req := []byte(`{"contents":[{"role":"user","parts":[]}]}`)
+	textPart := []byte(`{"text":""}`)
+	textPart, _ = sjson.SetBytes(textPart, "text", strings.TrimSpace(prompt))
+	req, _ = sjson.SetRawBytes(req, "contents.0.parts.-1", textPart)
+
+	if isImagenImagesModel(model) {
+		if aspectRatio != "" {
+			req, _ = sjson.SetBytes(req, "aspectRatio", aspectRatio)
+		}
+		if n > 0 {
+			req, _ = sjson.SetBytes(req, "sampleCount", n)
+		}
+		return req, nil
+	}
+
+	for _, img := range images {
+		if strings.TrimSpace(img) == "" {
+			continue
+		}
+		part, err := geminiImagesInlineDataPart(img)
+		if err != nil {
+			return nil, err
+		}
+		req, _ = sjson.SetRawBytes(req, "contents.0.parts.-1", part)
+	}
+	if strings.TrimSpace(mask) != "" {
+		part, err := geminiImagesInlineDataPart(mask)
+		if err != nil {
+			return nil, fmt.Errorf("mask: %w", err)
+		}
+		maskText := []byte(`{"text":""}`)
+		maskText, _ = sjson.SetBytes(maskText, "text", geminiImagesMaskInstruction)
+		req, _ = sjson.SetRawBytes(req, "contents.0.parts.-1", maskText)
+		req, _ = sjson.SetRawBytes(req, "contents.0.parts.-1", part)
+	}
+
+	req, _ = sjson.SetRawBytes(req, "generationConfig.responseModalities", []byte(`["TEXT","IMAGE"]`))
+	if aspectRatio != "" {
+		req, _ = sjson.SetBytes(req, "generationConfig.imageConfig.aspectRatio", aspectRatio)
+	}
+	return req, nil
+}
+
+// geminiImagesResponseToImagesAPI maps a Gemini generateContent response onto
+// the images JSON read by extractXAIImagesResponse. Text parts returned next to
+// the images are reported as the revised prompt.
+func geminiImagesResponseToImagesAPI(payload []byte) ([]byte, error) {
+	if !json.Valid(payload) {
+		return nil, fmt.Errorf("upstream returned invalid image response JSON")
+	}
+	root := gjson.ParseBytes(payload)
+	if wrapped := root.Get("response"); wrapped.IsObject() {
+		root = wrapped
+	}
+
+	out := []byte(`{"created":0,"data":[]}`)
+	out, _ = sjson.SetBytes(out, "created", time.Now().Unix())
+
+	var texts []string
+	images := 0
+	for _, candidate := range root.Get("candidates").Array() {
+		for _, part := range candidate.Get("content.parts").Array() {
+			if part.Get("thought").Bool() {
+				continue
+			}
+			inline := part.Get("inlineData")
+			if !inline.Exists() {
+				inline = part.Get("inline_data")
+			}
+			if data := strings.TrimSpace(inline.Get("data").String()); data != "" {
+				mimeType := strings.TrimSpace(inline.Get("mimeType").String())
+				if mimeType == "" {
+					mimeType = strings.TrimSpace(inline.Get("mime_type").String())
+				}
+				item := []byte(`{}`)
+				item, _ = sjson.SetBytes(item, "b64_json", data)
+				item, _ = sjson.SetBytes(item, "mime_type", mimeTypeFromOutputFormat(mimeType))
+				out, _ = sjson.SetRawBytes(out, "data.-1", item)
+				images++
+				continue
+			}
+			if text := strings.TrimSpace(part.Get("text").String()); text != "" {
+				texts = append(texts, text)
+			}
+		}
+	}
+
+	revisedPrompt := strings.Join(texts, "\n")
+	if images == 0 {
+		if reason := root.Get("promptFeedback.blockReason").String(); reason != "" {
+			return nil, fmt.Errorf("upstream did not return image output: prompt blocked (%s)", reason)
+		}
+		if revisedPrompt != "" {
+			return nil, fmt.Errorf("upstream did not return image output: %s", revisedPrompt)
+		}
+		return nil, fmt.Errorf("upstream did not return image output")
+	}
+	if revisedPrompt != "" {
+		for i := 0; i < images; i++ {
+			out, _ = sjson.SetBytes(out, fmt.Sprintf("data.%d.revised_prompt", i), revisedPrompt)
+		}
+	}
+
+	if usage := root.Get("usageMetadata"); usage.IsObject() {
+		usageJSON := []byte(`{}`)
+		usageJSON, _ = sjson.SetBytes(usageJSON, "input_tokens", usage.Get("promptTokenCount").Int())
+		usageJSON, _ = sjson.SetBytes(usageJSON, "output_tokens", usage.Get("candidatesTokenCount").Int())
+		usageJSON, _ = sjson.SetBytes(usageJSON, "total_tokens", usage.Get("totalTokenCount").Int())
+		out, _ = sjson.SetRawBytes(out, "usage", usageJSON)
+	}
+	return out, nil
+}
+
+func (h *OpenAIAPIHandler) handleGeminiImagesGenerations(c *gin.Context, rawJSON []byte, imageModel string, prompt string, responseFormat string, stream bool) {
+	aspectRatio := xaiImagesAspectRatio(gjson.GetBytes(rawJSON, "aspect_ratio").String(), "")
+	aspectRatio = xaiImagesAspectRatioFromSize(gjson.GetBytes(rawJSON, "size").String(), aspectRatio)
+	n := int64(0)
+	if v := gjson.GetBytes(rawJSON, "n"); v.Exists() && v.Type == gjson.Number {
+		n = v.Int()
+	}
+	geminiReq, err := buildGeminiImagesRequest(imageModel, prompt, nil, "", aspectRatio, n)
+	if err != nil {
+		writeGeminiImagesInvalidRequest(c, err)
+		return
+	}
+	h.handleGeminiImages(c, geminiReq, imageModel, responseFormat, "image_generation", stream)
+}
+
+func (h *OpenAIAPIHandler) handleGeminiImagesEdits(c *gin.Context, imageModel string, prompt string, images []string, mask string, size string, responseFormat string, stream bool) {
+	if isImagenImagesModel(imageModel) {
+		writeGeminiImagesInvalidRequest(c, fmt.Errorf("model %s does not support %s; use a Gemini image model", imageModel, imagesEditsPath))
+		return
+	}
+	geminiReq, err := buildGeminiImagesRequest(imageModel, prompt, images, mask, xaiImagesAspectRatioFromSize(size, ""), 0)
+	if err != nil {
+		writeGeminiImagesInvalidRequest(c, err)
+		return
+	}
+	h.handleGeminiImages(c, geminiReq, imageModel, responseFormat, "image_edit", stream)
+}
+
+func (h *OpenAIAPIHandler) handleGeminiImages(c *gin.Context, geminiReq []byte, imageModel string, responseFormat string, streamPrefix string, stream bool) {
+	if stream {
+		h.streamImagesWithModel(c, geminiReq, imageModel, geminiImagesHandlerType, responseFormat, streamPrefix, geminiImagesResponseToImagesAPI)
+		return
+	}
+	h.collectImagesWithModel(c, geminiReq, imageModel, geminiImagesHandlerType, responseFormat, geminiImagesResponseToImagesAPI)
+}
+
+func writeGeminiImagesInvalidRequest(c *gin.Context, err error) {
+	c.JSON(http.StatusBadRequest, handlers.ErrorResponse{
+		Error: handlers.ErrorDetail{
+			Message: fmt.Sprintf("Invalid request: %v", err),
+			Type:    "invalid_request_error",
+		},
+	})
+}
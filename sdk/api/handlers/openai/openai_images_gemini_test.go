@@ -0,0 +1,84 @@
+package openai
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tidwall/gjson"
+)
+
+func TestIsGeminiImagesModel(t *testing.T) {
+	for _, model := range []string{"gemini-2.5-flash-image", "gemini-3-pro-image-preview", "vertex/imagen-4.0-generate-001", "imagen-3.0-generate-002"} {
+		if !isGeminiImagesModel(model) || !isSupportedImagesModel(model) {
+			t.Fatalf("expected %s to be supported", model)
+		}
+	}
+	if isGeminiImagesModel("gemini-2.5-pro") {
+		t.Fatal("expected text-only Gemini model to be rejected")
+	}
+}
+
+func TestBuildGeminiImagesRequestMapsEditInputsToInlineParts(t *testing.T) {
+	req, err := buildGeminiImagesRequest("gemini-2.5-flash-image", "add a hat", []string{"data:image/jpeg;base64,AAA="}, "data:image/png;base64,BBB=", "16:9", 2)
+	if err != nil {
+		t.Fatalf("buildGeminiImagesRequest() error = %v", err)
+	}
+	parts := gjson.GetBytes(req, "contents.0.parts").Array()
+	if len(parts) != 4 {
+		t.Fatalf("parts = %s, want prompt, image, mask instruction and mask", gjson.GetBytes(req, "contents.0.parts").Raw)
+	}
+	if parts[0].Get("text").String() != "add a hat" {
+		t.Fatalf("prompt part = %s", parts[0].Raw)
+	}
+	if parts[1].Get("inlineData.mimeType").String() != "image/jpeg" || parts[1].Get("inlineData.data").String() != "AAA=" {
+		t.Fatalf("image part = %s", parts[1].Raw)
+	}
+	if parts[3].Get("inlineData.data").String() != "BBB=" {
+		t.Fatalf("mask part = %s", parts[3].Raw)
+	}
+	if got := gjson.GetBytes(req, "generationConfig.imageConfig.aspectRatio").String(); got != "16:9" {
+		t.Fatalf("aspectRatio = %q, want 16:9", got)
+	}
+	if gjson.GetBytes(req, "sampleCount").Exists() {
+		t.Fatal("sampleCount set for a Gemini model")
+	}
+
+	if _, err = buildGeminiImagesRequest("gemini-2.5-flash-image", "edit", []string{"https://example.com/a.png"}, "", "", 0); err == nil {
+		t.Fatal("expected remote image URL to be rejected")
+	}
+
+	imagenReq, err := buildGeminiImagesRequest("imagen-4.0-generate-001", "a cat", nil, "", "1:1", 3)
+	if err != nil {
+		t.Fatalf("buildGeminiImagesRequest(imagen) error = %v", err)
+	}
+	if gjson.GetBytes(imagenReq, "sampleCount").Int() != 3 || gjson.GetBytes(imagenReq, "aspectRatio").String() != "1:1" {
+		t.Fatalf("imagen request = %s", imagenReq)
+	}
+}
+
+func TestGeminiImagesResponseToImagesAPI(t *testing.T) {
+	payload := []byte(`{"candidates":[{"content":{"parts":[{"text":"a red hat","thought":true},{"text":"Here is your image"},{"inlineData":{"mimeType":"image/jpeg","data":"AAA="}}]}}],"usageMetadata":{"promptTokenCount":5,"candidatesTokenCount":7,"totalTokenCount":12}}`)
+
+	converted, err := geminiImagesResponseToImagesAPI(payload)
+	if err != nil {
+		t.Fatalf("geminiImagesResponseToImagesAPI() error = %v", err)
+	}
+	out, err := buildImagesAPIResponseFromXAI(converted, "url")
+	if err != nil {
+		t.Fatalf("buildImagesAPIResponseFromXAI() error = %v", err)
+	}
+	if got := gjson.GetBytes(out, "data.0.url").String(); got != "data:image/jpeg;base64,AAA=" {
+		t.Fatalf("data.0.url = %q", got)
+	}
+	if got := gjson.GetBytes(out, "data.0.revised_prompt").String(); got != "Here is your image" {
+		t.Fatalf("data.0.revised_prompt = %q", got)
+	}
+	if got := gjson.GetBytes(out, "usage.total_tokens").Int(); got != 12 {
+		t.Fatalf("usage.total_tokens = %d, want 12", got)
+	}
+
+	_, err = geminiImagesResponseToImagesAPI([]byte(`{"candidates":[{"content":{"parts":[{"text":"I can't draw that"}]}}]}`))
+	if err == nil || !strings.Contains(err.Error(), "I can't draw that") {
+		t.Fatalf("error = %v, want upstream text in error", err)
+	}
+}
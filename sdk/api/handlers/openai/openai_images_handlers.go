@@ -219,7 +219,7 @@ func isSupportedImagesModel(model string) bool {
 	if baseModel == defaultImagesToolModel {
 		return true
 	}
-	return isXAIImagesModel(model) || isOpenAICompatImagesModel(model)
+	return isXAIImagesModel(model) || isOpenAICompatImagesModel(model) || isGeminiImagesModel(model)
 }
 
 func isDefaultImagesToolModel(model string) bool {
@@ -242,7 +242,7 @@ func rejectUnsupportedImagesModel(c *gin.Context, model string) bool {
 
 	c.JSON(http.StatusBadRequest, handlers.ErrorResponse{
 		Error: handlers.ErrorDetail{
-			Message: fmt.Sprintf("Model %s is not supported on %s or %s. Use %s, %s, %s, or a configured openai-compatibility image model.", model, imagesGenerationsPath, imagesEditsPath, defaultImagesToolModel, defaultXAIImagesModel, xaiImagesQualityModel),
+			Message: fmt.Sprintf("Model %s is not supported on %s or %s. Use %s, %s, %s, a Gemini or Imagen image model, or a configured openai-compatibility image model.", model, imagesGenerationsPath, imagesEditsPath, defaultImagesToolModel, defaultXAIImagesModel, xaiImagesQualityModel),
 			Type:    "invalid_request_error",
 		},
 	})
@@ -642,6 +642,10 @@ func (h *OpenAIAPIHandler) ImagesGenerations(c *gin.Context) {
 		h.handleOpenAICompatImages(c, compatReq, imageModel, responseFormat, "image_generation", stream)
 		return
 	}
+	if isGeminiImagesModel(imageModel) {
+		h.handleGeminiImagesGenerations(c, rawJSON, imageModel, prompt, responseFormat, stream)
+		return
+	}
 
 	tool := []byte(`{"type":"image_generation","action":"generate"}`)
 	tool, _ = sjson.SetBytes(tool, "model", imageModel)
@@ -811,6 +815,24 @@ func (h *OpenAIAPIHandler) imagesEditsFromMultipart(c *gin.Context) {
 		h.handleOpenAICompatImages(c, compatReq, imageModel, responseFormat, "image_edit", stream)
 		return
 	}
+	if isGeminiImagesModel(imageModel) {
+		var mask string
+		if maskFiles := form.File["mask"]; len(maskFiles) > 0 && maskFiles[0] != nil {
+			dataURL, errMask := multipartFileToDataURL(maskFiles[0])
+			if errMask != nil {
+				c.JSON(http.StatusBadRequest, handlers.ErrorResponse{
+					Error: handlers.ErrorDetail{
+						Message: fmt.Sprintf("Invalid request: %v", errMask),
+						Type:    "invalid_request_error",
+					},
+				})
+				return
+			}
+			mask = dataURL
+		}
+		h.handleGeminiImagesEdits(c, imageModel, prompt, images, mask, c.PostForm("size"), responseFormat, stream)
+		return
+	}
 
 	var maskDataURL *string
 	if maskFiles := form.File["mask"]; len(maskFiles) > 0 && maskFiles[0] != nil {
@@ -940,6 +962,21 @@ func (h *OpenAIAPIHandler) imagesEditsFromJSON(c *gin.Context) {
 		h.handleOpenAICompatImages(c, compatReq, imageModel, responseFormat, "image_edit", stream)
 		return
 	}
+	if isGeminiImagesModel(imageModel) {
+		images := collectXAIImagesFromJSON(rawJSON)
+		if len(images) == 0 {
+			c.JSON(http.StatusBadRequest, handlers.ErrorResponse{
+				Error: handlers.ErrorDetail{
+					Message: "Invalid request: image is required",
+					Type:    "invalid_request_error",
+				},
+			})
+			return
+		}
+		mask := strings.TrimSpace(gjson.GetBytes(rawJSON, "mask.image_url").String())
+		h.handleGeminiImagesEdits(c, imageModel, prompt, images, mask, gjson.GetBytes(rawJSON, "size").String(), responseFormat, stream)
+		return
+	}
 
 	var images []string
 	imagesResult := gjson.GetBytes(rawJSON, "images")
@@ -1132,7 +1169,7 @@ func (h *OpenAIAPIHandler) handleOpenAICompatImages(c *gin.Context, compatReq []
 		h.streamOpenAICompatImages(c, compatReq, imageModel)
 		return
 	}
-	h.collectImagesWithModel(c, compatReq, imageModel, responseFormat)
+	h.collectImagesWithModel(c, compatReq, imageModel, xaiImagesHandlerType, responseFormat, nil)
 }
 
 func (h *OpenAIAPIHandler) handleRoutedImages(c *gin.Context, imageReq []byte, imageModel string, stream bool) {
@@ -1390,17 +1427,20 @@ func (h *OpenAIAPIHandler) streamOpenAICompatImages(c *gin.Context, compatReq []
 
 func (h *OpenAIAPIHandler) collectXAIImages(c *gin.Context, xaiReq []byte, responseFormat string) {
 	model := strings.TrimSpace(gjson.GetBytes(xaiReq, "model").String())
-	h.collectImagesWithModel(c, xaiReq, model, responseFormat)
+	h.collectImagesWithModel(c, xaiReq, model, xaiImagesHandlerType, responseFormat, nil)
 }
 
-func (h *OpenAIAPIHandler) collectImagesWithModel(c *gin.Context, imageReq []byte, model string, responseFormat string) {
+// collectImagesWithModel executes imageReq through handlerType. When normalize
+// is set it maps the upstream payload onto the images JSON read by
+// extractXAIImagesResponse.
+func (h *OpenAIAPIHandler) collectImagesWithModel(c *gin.Context, imageReq []byte, model string, handlerType string, responseFormat string, normalize func([]byte) ([]byte, error)) {
 	c.Header("Content-Type", "application/json")
 
 	cliCtx, cliCancel := h.GetContextWithCancel(h, c, context.Background())
 	stopKeepAlive := h.StartNonStreamingKeepAlive(c, cliCtx)
 
 	model = strings.TrimSpace(model)
-	resp, upstreamHeaders, errMsg := h.ExecuteWithAuthManager(cliCtx, xaiImagesHandlerType, model, imageReq, "")
+	resp, upstreamHeaders, errMsg := h.ExecuteWithAuthManager(cliCtx, handlerType, model, imageReq, "")
 	stopKeepAlive()
 	if errMsg != nil {
 		h.WriteErrorResponse(c, errMsg)
@@ -1412,7 +1452,14 @@ func (h *OpenAIAPIHandler) collectImagesWithModel(c *gin.Context, imageReq []byt
 		return
 	}
 
-	out, err := buildImagesAPIResponseFromXAI(resp, responseFormat)
+	var err error
+	if normalize != nil {
+		resp, err = normalize(resp)
+	}
+	var out []byte
+	if err == nil {
+		out, err = buildImagesAPIResponseFromXAI(resp, responseFormat)
+	}
 	if err != nil {
 		errMsg := &interfaces.ErrorMessage{StatusCode: http.StatusBadGateway, Error: err}
 		h.WriteErrorResponse(c, errMsg)
@@ -1427,10 +1474,10 @@ func (h *OpenAIAPIHandler) collectImagesWithModel(c *gin.Context, imageReq []byt
 
 func (h *OpenAIAPIHandler) streamXAIImages(c *gin.Context, xaiReq []byte, responseFormat string, streamPrefix string) {
 	model := strings.TrimSpace(gjson.GetBytes(xaiReq, "model").String())
-	h.streamImagesWithModel(c, xaiReq, model, responseFormat, streamPrefix)
+	h.streamImagesWithModel(c, xaiReq, model, xaiImagesHandlerType, responseFormat, streamPrefix, nil)
 }
 
-func (h *OpenAIAPIHandler) streamImagesWithModel(c *gin.Context, imageReq []byte, model string, responseFormat string, streamPrefix string) {
+func (h *OpenAIAPIHandler) streamImagesWithModel(c *gin.Context, imageReq []byte, model string, handlerType string, responseFormat string, streamPrefix string, normalize func([]byte) ([]byte, error)) {
 	flusher, ok := c.Writer.(http.Flusher)
 	if !ok {
 		c.JSON(http.StatusInternalServerError, handlers.ErrorResponse{
@@ -1451,7 +1498,7 @@ func (h *OpenAIAPIHandler) streamImagesWithModel(c *gin.Context, imageReq []byte
 	}
 	resultChan := make(chan imageStreamResult, 1)
 	go func() {
-		resp, upstreamHeaders, errMsg := h.ExecuteWithAuthManager(cliCtx, xaiImagesHandlerType, model, imageReq, "")
+		resp, upstreamHeaders, errMsg := h.ExecuteWithAuthManager(cliCtx, handlerType, model, imageReq, "")
 		resultChan <- imageStreamResult{resp: resp, upstreamHeaders: upstreamHeaders, errMsg: errMsg}
 	}()
 
@@ -1495,7 +1542,16 @@ func (h *OpenAIAPIHandler) streamImagesWithModel(c *gin.Context, imageReq []byte
 				return
 			}
 
-			results, _, usageRaw, err := extractXAIImagesResponse(result.resp)
+			payload := result.resp
+			var err error
+			if normalize != nil {
+				payload, err = normalize(payload)
+			}
+			var results []xaiImageResult
+			var usageRaw []byte
+			if err == nil {
+				results, _, usageRaw, err = extractXAIImagesResponse(payload)
+			}
 			if err != nil {
 				writeError(&interfaces.ErrorMessage{StatusCode: http.StatusBadGateway, Error: err})
 				return
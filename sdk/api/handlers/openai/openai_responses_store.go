@@ -0,0 +1,157 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/interfaces"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/responsestore"
+	"github.com/router-for-me/CLIProxyAPI/v7/sdk/api/handlers"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+)
+
+// responsesStoreCapture remembers the expanded input of a request so the
+// finished response can be stored under its id.
+type responsesStoreCapture struct {
+	store *responsestore.Store
+	owner string
+	model string
+	input []byte
+}
+
+func responsesStoreOwner(c *gin.Context) string {
+	if c == nil {
+		return ""
+	}
+	raw, _ := c.Get("userApiKey")
+	principal, _ := raw.(string)
+	return responsestore.OwnerKey(principal)
+}
+
+// prepareResponsesStore expands previous_response_id from the local store and
+// returns a capture for storing the response. Unknown ids are left for the
+// upstream to resolve. Both results are unchanged when the store is disabled.
+func prepareResponsesStore(c *gin.Context, rawJSON []byte) ([]byte, *responsesStoreCapture, *interfaces.ErrorMessage) {
+	store := responsestore.Default()
+	if !store.Enabled() {
+		return rawJSON, nil, nil
+	}
+	owner := responsesStoreOwner(c)
+	if prevID := strings.TrimSpace(gjson.GetBytes(rawJSON, "previous_response_id").String()); prevID != "" {
+		prev, err := store.Get(c.Request.Context(), prevID, owner)
+		if err != nil {
+			log.Warnf("responses store: load %s: %v", prevID, err)
+		}
+		if prev != nil {
+			expanded, errExpand := responsestore.ExpandRequest(rawJSON, prev)
+			if errExpand != nil {
+				return rawJSON, nil, &interfaces.ErrorMessage{
+					StatusCode: http.StatusBadRequest,
+					Error:      fmt.Errorf("invalid input: %w", errExpand),
+				}
+			}
+			rawJSON = expanded
+		}
+	}
+	if storeFlag := gjson.GetBytes(rawJSON, "store"); storeFlag.Exists() && storeFlag.Type == gjson.False {
+		return rawJSON, nil, nil
+	}
+	input, err := responsestore.NormalizeInput(gjson.GetBytes(rawJSON, "input"))
+	if err != nil {
+		return rawJSON, nil, nil
+	}
+	return rawJSON, &responsesStoreCapture{
+		store: store,
+		owner: owner,
+		model: gjson.GetBytes(rawJSON, "model").String(),
+		input: input,
+	}, nil
+}
+
+// prepareResponsesWebsocketStore applies the responses store to a websocket request.
+// A previous_response_id naming the last response of the upstream session is kept
+// for the upstream; only the stored input is expanded so the response can still be
+// continued over HTTP.
+func prepareResponsesWebsocketStore(c *gin.Context, rawJSON []byte, sessionResponseID string) ([]byte, *responsesStoreCapture, *interfaces.ErrorMessage) {
+	prevID := strings.TrimSpace(gjson.GetBytes(rawJSON, "previous_response_id").String())
+	if prevID == "" || prevID != strings.TrimSpace(sessionResponseID) {
+		return prepareResponsesStore(c, rawJSON)
+	}
+	_, capture, errMsg := prepareResponsesStore(c, rawJSON)
+	if errMsg != nil {
+		return rawJSON, nil, nil
+	}
+	return rawJSON, capture, nil
+}
+
+// save stores a completed response object.
+func (r *responsesStoreCapture) save(ctx context.Context, response []byte) {
+	if r == nil || len(response) == 0 {
+		return
+	}
+	id := strings.TrimSpace(gjson.GetBytes(response, "id").String())
+	if id == "" {
+		return
+	}
+	model := gjson.GetBytes(response, "model").String()
+	if model == "" {
+		model = r.model
+	}
+	if err := r.store.Put(ctx, &responsestore.Record{
+		ID:       id,
+		Owner:    r.owner,
+		Model:    model,
+		Input:    r.input,
+		Response: append([]byte(nil), response...),
+	}); err != nil {
+		log.Warnf("responses store: save %s: %v", id, err)
+	}
+}
+
+func writeResponseNotFound(c *gin.Context, id string) {
+	c.JSON(http.StatusNotFound, handlers.ErrorResponse{
+		Error: handlers.ErrorDetail{
+			Message: fmt.Sprintf("Response with id '%s' not found.", id),
+			Type:    "invalid_request_error",
+		},
+	})
+}
+
+// GetResponse handles GET /v1/responses/{id} from the local responses store.
+func (h *OpenAIResponsesAPIHandler) GetResponse(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	record, err := responsestore.Default().Get(c.Request.Context(), id, responsesStoreOwner(c))
+	if err != nil && !errors.Is(err, responsestore.ErrDisabled) {
+		c.JSON(http.StatusInternalServerError, handlers.ErrorResponse{
+			Error: handlers.ErrorDetail{Message: err.Error(), Type: "server_error"},
+		})
+		return
+	}
+	if record == nil {
+		writeResponseNotFound(c, id)
+		return
+	}
+	c.Data(http.StatusOK, "application/json", record.Response)
+}
+
+// DeleteResponse handles DELETE /v1/responses/{id} against the local responses store.
+func (h *OpenAIResponsesAPIHandler) DeleteResponse(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	deleted, err := responsestore.Default().Delete(c.Request.Context(), id, responsesStoreOwner(c))
+	if err != nil && !errors.Is(err, responsestore.ErrDisabled) {
+		c.JSON(http.StatusInternalServerError, handlers.ErrorResponse{
+			Error: handlers.ErrorDetail{Message: err.Error(), Type: "server_error"},
+		})
+		return
+	}
+	if !deleted {
+		writeResponseNotFound(c, id)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": id, "object": "response", "deleted": true})
+}
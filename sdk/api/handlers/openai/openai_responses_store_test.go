@@ -0,0 +1,171 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/registry"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/responsestore"
+	"github.com/router-for-me/CLIProxyAPI/v7/sdk/api/handlers"
+	coreauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
+	coreexecutor "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/executor"
+	sdkconfig "github.com/router-for-me/CLIProxyAPI/v7/sdk/config"
+	"github.com/tidwall/gjson"
+)
+
+type responsesStoreExecutor struct {
+	payloads [][]byte
+}
+
+func (e *responsesStoreExecutor) Identifier() string { return "responses-store-test" }
+
+func (e *responsesStoreExecutor) Execute(_ context.Context, _ *coreauth.Auth, req coreexecutor.Request, _ coreexecutor.Options) (coreexecutor.Response, error) {
+	e.payloads = append(e.payloads, append([]byte(nil), req.Payload...))
+	id := "resp_" + string(rune('0'+len(e.payloads)))
+	return coreexecutor.Response{Payload: []byte(`{"id":"` + id + `","object":"response","model":"store-test-model","output":[{"type":"message","role":"assistant","content":[{"type":"output_text","text":"answer"}]}]}`)}, nil
+}
+
+func (e *responsesStoreExecutor) ExecuteStream(context.Context, *coreauth.Auth, coreexecutor.Request, coreexecutor.Options) (*coreexecutor.StreamResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (e *responsesStoreExecutor) Refresh(_ context.Context, auth *coreauth.Auth) (*coreauth.Auth, error) {
+	return auth, nil
+}
+
+func (e *responsesStoreExecutor) CountTokens(context.Context, *coreauth.Auth, coreexecutor.Request, coreexecutor.Options) (coreexecutor.Response, error) {
+	return coreexecutor.Response{}, errors.New("not implemented")
+}
+
+func (e *responsesStoreExecutor) HttpRequest(context.Context, *coreauth.Auth, *http.Request) (*http.Response, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestResponsesStoreExpandsPreviousResponseID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	if err := responsestore.Default().Apply(config.ResponsesStoreConfig{Enable: true}); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	t.Cleanup(responsestore.Default().Close)
+
+	executor := &responsesStoreExecutor{}
+	manager := coreauth.NewManager(nil, nil, nil)
+	manager.RegisterExecutor(executor)
+	auth := &coreauth.Auth{ID: "responses-store-auth", Provider: executor.Identifier(), Status: coreauth.StatusActive}
+	if _, err := manager.Register(context.Background(), auth); err != nil {
+		t.Fatalf("Register auth: %v", err)
+	}
+	registry.GetGlobalRegistry().RegisterClient(auth.ID, auth.Provider, []*registry.ModelInfo{{ID: "store-test-model"}})
+	t.Cleanup(func() {
+		registry.GetGlobalRegistry().UnregisterClient(auth.ID)
+	})
+
+	h := NewOpenAIResponsesAPIHandler(handlers.NewBaseAPIHandlers(&sdkconfig.SDKConfig{}, manager))
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userApiKey", c.GetHeader("Authorization"))
+		c.Next()
+	})
+	router.POST("/v1/responses", h.Responses)
+	router.GET("/v1/responses/:id", h.GetResponse)
+	router.DELETE("/v1/responses/:id", h.DeleteResponse)
+	do := func(method, path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", key)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	if resp := do(http.MethodPost, "/v1/responses", "key-a", `{"model":"store-test-model","input":"first"}`); resp.Code != http.StatusOK {
+		t.Fatalf("first status = %d, body = %s", resp.Code, resp.Body.String())
+	}
+	if resp := do(http.MethodPost, "/v1/responses", "key-a", `{"model":"store-test-model","previous_response_id":"resp_1","input":"second"}`); resp.Code != http.StatusOK {
+		t.Fatalf("second status = %d, body = %s", resp.Code, resp.Body.String())
+	}
+	second := executor.payloads[1]
+	if gjson.GetBytes(second, "previous_response_id").Exists() {
+		t.Fatalf("previous_response_id forwarded upstream: %s", second)
+	}
+	if got := gjson.GetBytes(second, "input.#").Int(); got != 3 {
+		t.Fatalf("expanded input = %s", gjson.GetBytes(second, "input").Raw)
+	}
+
+	if resp := do(http.MethodGet, "/v1/responses/resp_2", "key-b", ""); resp.Code != http.StatusNotFound {
+		t.Fatalf("foreign GET status = %d", resp.Code)
+	}
+	resp := do(http.MethodGet, "/v1/responses/resp_2", "key-a", "")
+	if resp.Code != http.StatusOK || gjson.Get(resp.Body.String(), "id").String() != "resp_2" {
+		t.Fatalf("GET status = %d, body = %s", resp.Code, resp.Body.String())
+	}
+	resp = do(http.MethodDelete, "/v1/responses/resp_2", "key-a", "")
+	if resp.Code != http.StatusOK || !gjson.Get(resp.Body.String(), "deleted").Bool() {
+		t.Fatalf("DELETE status = %d, body = %s", resp.Code, resp.Body.String())
+	}
+	if resp = do(http.MethodGet, "/v1/responses/resp_2", "key-a", ""); resp.Code != http.StatusNotFound {
+		t.Fatalf("GET after delete status = %d", resp.Code)
+	}
+}
+
+func TestResponsesSSEFramerReportsCompletedResponse(t *testing.T) {
+	var completed []byte
+	framer := &responsesSSEFramer{onCompleted: func(response []byte) { completed = response }}
+	var out strings.Builder
+	framer.WriteChunk(&out, []byte("event: response.output_item.done\ndata: {\"type\":\"response.output_item.done\",\"output_index\":0,\"item\":{\"type\":\"message\",\"role\":\"assistant\"}}\n\n"))
+	framer.WriteChunk(&out, []byte("event: response.completed\ndata: {\"type\":\"response.completed\",\"response\":{\"id\":\"resp_s\",\"output\":[]}}\n\n"))
+
+	if got := gjson.GetBytes(completed, "id").String(); got != "resp_s" {
+		t.Fatalf("completed id = %q", got)
+	}
+	if got := gjson.GetBytes(completed, "output.0.role").String(); got != "assistant" {
+		t.Fatalf("completed output was not repaired: %s", completed)
+	}
+}
+
+func TestResponsesWebsocketStoreKeepsSessionPreviousResponseID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	if err := responsestore.Default().Apply(config.ResponsesStoreConfig{Enable: true}); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	t.Cleanup(responsestore.Default().Close)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/v1/responses", nil)
+	c.Set("userApiKey", "key-a")
+	if err := responsestore.Default().Put(context.Background(), &responsestore.Record{
+		ID:       "resp_ws",
+		Owner:    responsesStoreOwner(c),
+		Model:    "store-test-model",
+		Input:    []byte(`[{"type":"message","role":"user","content":[{"type":"input_text","text":"first"}]}]`),
+		Response: []byte(`{"id":"resp_ws","output":[{"type":"message","role":"assistant","content":[{"type":"output_text","text":"answer"}]}]}`),
+	}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	request := []byte(`{"model":"store-test-model","previous_response_id":"resp_ws","input":[{"type":"message","role":"user","content":[{"type":"input_text","text":"second"}]}]}`)
+
+	expanded, capture, errMsg := prepareResponsesWebsocketStore(c, request, "")
+	if errMsg != nil || capture == nil {
+		t.Fatalf("prepare for a new session: capture=%v err=%v", capture, errMsg)
+	}
+	if gjson.GetBytes(expanded, "previous_response_id").Exists() || gjson.GetBytes(expanded, "input.#").Int() != 3 {
+		t.Fatalf("stored response not expanded for a new session: %s", expanded)
+	}
+
+	kept, capture, errMsg := prepareResponsesWebsocketStore(c, request, "resp_ws")
+	if errMsg != nil || capture == nil {
+		t.Fatalf("prepare for the same session: capture=%v err=%v", capture, errMsg)
+	}
+	if string(kept) != string(request) {
+		t.Fatalf("session request rewritten: %s", kept)
+	}
+	if got := gjson.GetBytes(capture.input, "#").Int(); got != 3 {
+		t.Fatalf("captured input = %s, want the expanded transcript", capture.input)
+	}
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/interfaces"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/responsecache"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/runtime/executor/helps"
+	coreexecutor "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/executor"
+	coreusage "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/usage"
+)
+
+// responseCacheHeader lets a client skip the response cache for one request.
+const responseCacheHeader = "X-CLIProxy-Cache"
+
+// responseCacheKey returns the cache key of a routed request, or "" when the
+// cache is disabled, the request is internal or the client opted out. The key
+// covers the request as handed to the auth manager after model routing and the
+// before-auth interceptors, the resolved providers and auth scope, and the
+// calling client key, so tenants and differently routed keys never share entries.
+func responseCacheKey(ctx context.Context, entryProtocol, responseProtocol, alt string, providers []string, routeDecision modelRouteDecision, req coreexecutor.Request, stream bool, execOptions modelExecutionOptions) string {
+	if execOptions.InternalSource || !responsecache.Default().Enabled() {
+		return ""
+	}
+	if responseCacheBypassed(modelExecutionHeaders(ctx, execOptions.Headers)) {
+		return ""
+	}
+	sortedProviders := append([]string(nil), providers...)
+	sort.Strings(sortedProviders)
+	authIndexes := append([]string(nil), routeDecision.AuthIndexes...)
+	sort.Strings(authIndexes)
+	scope := strings.Join([]string{
+		entryProtocol + ">" + responseProtocol + "?" + alt,
+		strings.Join(sortedProviders, ","),
+		routeDecision.AuthPrefix,
+		strings.Join(authIndexes, ","),
+		responseCacheClientIdentity(ctx),
+	}, "\x00")
+	return responsecache.Key(scope, req.Model, stream, req.Payload)
+}
+
+// responseCacheClientIdentity hashes the calling client key so cached answers
+// stay private to the key that produced them.
+func responseCacheClientIdentity(ctx context.Context) string {
+	apiKey := helps.APIKeyFromContext(ctx)
+	if apiKey == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// responseCacheBypassed reports whether the request headers opt out of the cache.
+func responseCacheBypassed(headers http.Header) bool {
+	if headers == nil {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(headers.Get(responseCacheHeader))) {
+	case "bypass", "off", "no-cache", "false":
+		return true
+	}
+	for _, directive := range strings.Split(headers.Get("Cache-Control"), ",") {
+		switch strings.ToLower(strings.TrimSpace(directive)) {
+		case "no-cache", "no-store":
+			return true
+		}
+	}
+	return false
+}
+
+// publishResponseCacheHit records a request answered from the cache. No
+// provider was called, so the record carries no credential or token usage.
+func publishResponseCacheHit(ctx context.Context, modelName string) {
+	coreusage.PublishRecord(ctx, coreusage.Record{
+		Provider:    "response-cache",
+		Model:       modelName,
+		Alias:       modelName,
+		APIKey:      helps.APIKeyFromContext(ctx),
+		APIKeyName:  helps.APIKeyNameFromContext(ctx),
+		ServiceTier: coreusage.ServiceTierFromContext(ctx),
+		RequestedAt: time.Now(),
+		CacheStatus: coreusage.CacheStatusHit,
+	})
+}
+
+// replayCachedStream re-emits the cached SSE chunks of a streaming response.
+func replayCachedStream(ctx context.Context, entry *responsecache.Entry) (<-chan []byte, <-chan *interfaces.ErrorMessage) {
+	dataChan := make(chan []byte)
+	errChan := make(chan *interfaces.ErrorMessage)
+	go func() {
+		defer close(errChan)
+		defer close(dataChan)
+		for _, chunk := range entry.Chunks {
+			select {
+			case <-ctx.Done():
+				return
+			case dataChan <- cloneBytes(chunk):
+			}
+		}
+	}()
+	return dataChan, errChan
+}
+
+// teeStreamIntoResponseCache forwards a live stream unchanged and caches its
+// chunks once it completes without an error.
+func teeStreamIntoResponseCache(ctx context.Context, key string, headers http.Header, data <-chan []byte, errs <-chan *interfaces.ErrorMessage) (<-chan []byte, <-chan *interfaces.ErrorMessage) {
+	dataChan := make(chan []byte)
+	errChan := make(chan *interfaces.ErrorMessage, 1)
+	go func() {
+		defer close(errChan)
+		defer close(dataChan)
+		var chunks [][]byte
+		complete := true
+		for data != nil || errs != nil {
+			select {
+			case chunk, ok := <-data:
+				if !ok {
+					data = nil
+					continue
+				}
+				if !complete {
+					continue
+				}
+				chunks = append(chunks, cloneBytes(chunk))
+				select {
+				case <-ctx.Done():
+					complete = false
+				case dataChan <- chunk:
+				}
+			case errMsg, ok := <-errs:
+				if !ok {
+					errs = nil
+					continue
+				}
+				if !complete {
+					continue
+				}
+				complete = false
+				select {
+				case <-ctx.Done():
+				case errChan <- errMsg:
+				}
+			}
+		}
+		if complete && len(chunks) > 0 {
+			responsecache.Default().Put(key, &responsecache.Entry{Chunks: chunks, Headers: cloneHeader(headers)})
+		}
+	}()
+	return dataChan, errChan
+}
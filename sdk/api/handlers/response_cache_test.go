@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/registry"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/responsecache"
+	coreauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
+	coreexecutor "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/executor"
+	sdkconfig "github.com/router-for-me/CLIProxyAPI/v7/sdk/config"
+)
+
+type countingCacheExecutor struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (e *countingCacheExecutor) Identifier() string { return "cache-test" }
+
+func (e *countingCacheExecutor) count() {
+	e.mu.Lock()
+	e.calls++
+	e.mu.Unlock()
+}
+
+func (e *countingCacheExecutor) Calls() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.calls
+}
+
+func (e *countingCacheExecutor) Execute(context.Context, *coreauth.Auth, coreexecutor.Request, coreexecutor.Options) (coreexecutor.Response, error) {
+	e.count()
+	return coreexecutor.Response{Payload: []byte(`{"answer":42}`)}, nil
+}
+
+func (e *countingCacheExecutor) ExecuteStream(context.Context, *coreauth.Auth, coreexecutor.Request, coreexecutor.Options) (*coreexecutor.StreamResult, error) {
+	e.count()
+	ch := make(chan coreexecutor.StreamChunk, 2)
+	ch <- coreexecutor.StreamChunk{Payload: []byte("data: one\n\n")}
+	ch <- coreexecutor.StreamChunk{Payload: []byte("data: two\n\n")}
+	close(ch)
+	return &coreexecutor.StreamResult{Chunks: ch}, nil
+}
+
+func (e *countingCacheExecutor) Refresh(_ context.Context, auth *coreauth.Auth) (*coreauth.Auth, error) {
+	return auth, nil
+}
+
+func (e *countingCacheExecutor) CountTokens(context.Context, *coreauth.Auth, coreexecutor.Request, coreexecutor.Options) (coreexecutor.Response, error) {
+	return coreexecutor.Response{}, &coreauth.Error{Code: "not_implemented", Message: "CountTokens not implemented"}
+}
+
+func (e *countingCacheExecutor) HttpRequest(context.Context, *coreauth.Auth, *http.Request) (*http.Response, error) {
+	return nil, &coreauth.Error{Code: "not_implemented", Message: "HttpRequest not implemented", HTTPStatus: http.StatusNotImplemented}
+}
+
+func newResponseCacheTestHandler(t *testing.T) (*BaseAPIHandler, *countingCacheExecutor) {
+	t.Helper()
+	responsecache.Default().Apply(config.ResponseCacheConfig{Enable: true})
+	t.Cleanup(func() { responsecache.Default().Apply(config.ResponseCacheConfig{}) })
+
+	executor := &countingCacheExecutor{}
+	manager := coreauth.NewManager(nil, nil, nil)
+	manager.RegisterExecutor(executor)
+	auth := &coreauth.Auth{ID: "cache-auth", Provider: executor.Identifier(), Status: coreauth.StatusActive}
+	if _, err := manager.Register(context.Background(), auth); err != nil {
+		t.Fatalf("manager.Register(): %v", err)
+	}
+	registry.GetGlobalRegistry().RegisterClient(auth.ID, auth.Provider, []*registry.ModelInfo{{ID: "cache-model"}})
+	t.Cleanup(func() { registry.GetGlobalRegistry().UnregisterClient(auth.ID) })
+	return NewBaseAPIHandlers(&sdkconfig.SDKConfig{}, manager), executor
+}
+
+func TestExecuteWithAuthManager_ResponseCacheReplaysIdenticalRequest(t *testing.T) {
+	handler, executor := newResponseCacheTestHandler(t)
+
+	first, _, errMsg := handler.ExecuteWithAuthManager(context.Background(), "openai", "cache-model", []byte(`{"model":"cache-model","temperature":0}`), "")
+	if errMsg != nil {
+		t.Fatalf("first request error = %v", errMsg.Error)
+	}
+	second, _, errMsg := handler.ExecuteWithAuthManager(context.Background(), "openai", "cache-model", []byte(`{ "temperature": 0, "model": "cache-model" }`), "")
+	if errMsg != nil {
+		t.Fatalf("second request error = %v", errMsg.Error)
+	}
+	if string(second) != string(first) || executor.Calls() != 1 {
+		t.Fatalf("second = %s after %d upstream calls, want cached %s after 1", second, executor.Calls(), first)
+	}
+
+	recorder := httptest.NewRecorder()
+	ginCtx, _ := gin.CreateTestContext(recorder)
+	ginCtx.Request = httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+	ginCtx.Request.Header.Set("Cache-Control", "no-cache")
+	bypassCtx := context.WithValue(context.Background(), "gin", ginCtx)
+	if _, _, errMsg = handler.ExecuteWithAuthManager(bypassCtx, "openai", "cache-model", []byte(`{"model":"cache-model","temperature":0}`), ""); errMsg != nil {
+		t.Fatalf("bypass request error = %v", errMsg.Error)
+	}
+	if executor.Calls() != 2 {
+		t.Fatalf("upstream calls = %d, want bypass to reach upstream", executor.Calls())
+	}
+}
+
+func TestExecuteStreamWithAuthManager_ResponseCacheReplaysChunks(t *testing.T) {
+	handler, executor := newResponseCacheTestHandler(t)
+	payload := []byte(`{"model":"cache-model","stream":true}`)
+
+	collect := func() []string {
+		t.Helper()
+		dataChan, _, errChan := handler.ExecuteStreamWithAuthManager(context.Background(), "openai", "cache-model", payload, "")
+		var chunks []string
+		for chunk := range dataChan {
+			chunks = append(chunks, string(chunk))
+		}
+		for errMsg := range errChan {
+			if errMsg != nil {
+				t.Fatalf("stream error = %v", errMsg.Error)
+			}
+		}
+		return chunks
+	}
+
+	first := collect()
+	second := collect()
+	if len(first) != 2 || len(second) != 2 || first[0] != second[0] || first[1] != second[1] {
+		t.Fatalf("replayed chunks = %q, want %q", second, first)
+	}
+	if executor.Calls() != 1 {
+		t.Fatalf("upstream calls = %d, want 1", executor.Calls())
+	}
+	if _, _, errMsg := handler.ExecuteWithAuthManager(context.Background(), "openai", "cache-model", payload, ""); errMsg != nil || executor.Calls() != 2 {
+		t.Fatalf("non-stream request served from stream cache (calls=%d, err=%v)", executor.Calls(), errMsg)
+	}
+}
+
+func TestExecuteWithAuthManager_ResponseCacheIsScopedToClientKey(t *testing.T) {
+	handler, executor := newResponseCacheTestHandler(t)
+	payload := []byte(`{"model":"cache-model","temperature":0}`)
+
+	clientCtx := func(apiKey string) context.Context {
+		ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ginCtx.Request = httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+		ginCtx.Set("userApiKey", apiKey)
+		return context.WithValue(context.Background(), "gin", ginCtx)
+	}
+
+	for _, apiKey := range []string{"tenant-a", "tenant-b", "tenant-a"} {
+		if _, _, errMsg := handler.ExecuteWithAuthManager(clientCtx(apiKey), "openai", "cache-model", payload, ""); errMsg != nil {
+			t.Fatalf("request for %s error = %v", apiKey, errMsg.Error)
+		}
+	}
+	if executor.Calls() != 2 {
+		t.Fatalf("upstream calls = %d, want one per client key", executor.Calls())
+	}
+}
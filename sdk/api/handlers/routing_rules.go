@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	internalconfig "github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/runtime/executor/helps"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/thinking"
+	coreexecutor "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/executor"
+	log "github.com/sirupsen/logrus"
+)
+
+// applyRoutingRules evaluates the configured routing rules in order and turns
+// the first match into a route decision.
+func (h *BaseAPIHandler) applyRoutingRules(ctx context.Context, handlerType, modelName string, rawJSON []byte, execOptions modelExecutionOptions) modelRouteDecision {
+	var decision modelRouteDecision
+	if h == nil || h.Cfg == nil || len(h.Cfg.RoutingRules) == 0 {
+		return decision
+	}
+	headers := modelExecutionHeaders(ctx, execOptions.Headers)
+	clientKeys := []string{helps.APIKeyNameFromContext(ctx), helps.APIKeyFromContext(ctx)}
+	for i := range h.Cfg.RoutingRules {
+		rule := &h.Cfg.RoutingRules[i]
+		if !routingRuleMatches(rule, handlerType, modelName, clientKeys, headers, rawJSON) {
+			continue
+		}
+		decision.Providers = append([]string(nil), rule.Providers...)
+		decision.AuthPrefix = rule.Prefix
+		decision.AuthIndexes = append([]string(nil), rule.AuthIndexes...)
+		if rule.Model != "" {
+			decision.Model = routingRuleTargetModel(rule.Model, modelName)
+		}
+		log.Debugf("routing rule %q matched model %s", routingRuleName(rule, i), modelName)
+		return decision
+	}
+	return decision
+}
+
+func routingRuleMatches(rule *internalconfig.RoutingRule, handlerType, modelName string, clientKeys []string, headers http.Header, rawJSON []byte) bool {
+	if rule == nil || !rule.HasAction() {
+		return false
+	}
+	if len(rule.Models) > 0 {
+		baseModel := thinking.ParseSuffix(modelName).ModelName
+		if !internalconfig.MatchClientAPIKeyPatterns(rule.Models, modelName) && !internalconfig.MatchClientAPIKeyPatterns(rule.Models, baseModel) {
+			return false
+		}
+	}
+	if len(rule.Protocols) > 0 {
+		matched := false
+		for _, protocol := range rule.Protocols {
+			if strings.EqualFold(protocol, strings.TrimSpace(handlerType)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(rule.ClientKeys) > 0 {
+		matched := false
+		for _, key := range clientKeys {
+			if strings.TrimSpace(key) != "" && internalconfig.MatchClientAPIKeyPatterns(rule.ClientKeys, key) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if !helps.PayloadHeadersMatch(headers, rule.Headers) {
+		return false
+	}
+	return helps.PayloadConditionsMatch(rawJSON, internalconfig.PayloadModelRule{
+		Match:    rule.Match,
+		NotMatch: rule.NotMatch,
+		Exist:    rule.Exist,
+		NotExist: rule.NotExist,
+	})
+}
+
+// routingRuleTargetModel keeps the client's thinking suffix when the rewrite
+// target does not declare its own.
+func routingRuleTargetModel(target, requested string) string {
+	parsed := thinking.ParseSuffix(requested)
+	if !parsed.HasSuffix || thinking.ParseSuffix(target).HasSuffix {
+		return target
+	}
+	return fmt.Sprintf("%s(%s)", target, parsed.RawSuffix)
+}
+
+func routingRuleName(rule *internalconfig.RoutingRule, index int) string {
+	if rule.Name != "" {
+		return rule.Name
+	}
+	return fmt.Sprintf("#%d", index+1)
+}
+
+// setRouteDecisionMetadata forwards credential restrictions from a route decision to auth selection.
+func setRouteDecisionMetadata(meta map[string]any, decision modelRouteDecision) {
+	if meta == nil {
+		return
+	}
+	if decision.AuthPrefix != "" {
+		meta[coreexecutor.AllowedAuthPrefixMetadataKey] = decision.AuthPrefix
+	}
+	if len(decision.AuthIndexes) > 0 {
+		meta[coreexecutor.AllowedAuthIndexesMetadataKey] = append([]string(nil), decision.AuthIndexes...)
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	coreexecutor "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/executor"
+	sdkconfig "github.com/router-for-me/CLIProxyAPI/v7/sdk/config"
+)
+
+func routingRuleTestContext(keyName string, header http.Header) context.Context {
+	gin.SetMode(gin.TestMode)
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginCtx.Request = httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+	for key, values := range header {
+		for _, value := range values {
+			ginCtx.Request.Header.Add(key, value)
+		}
+	}
+	ginCtx.Set("accessMetadata", map[string]string{"name": keyName})
+	return context.WithValue(context.Background(), "gin", ginCtx)
+}
+
+func TestApplyRoutingRulesFirstMatchWins(t *testing.T) {
+	handler := NewBaseAPIHandlers(&sdkconfig.SDKConfig{RoutingRules: []sdkconfig.RoutingRule{
+		{
+			Name:       "team-b",
+			ClientKeys: []string{"team-b"},
+			Prefix:     "teamB",
+		},
+		{
+			Name:        "team-a-batch",
+			ClientKeys:  []string{"team-a*"},
+			Models:      []string{"claude-*"},
+			Protocols:   []string{"openai"},
+			Headers:     map[string]string{"X-Workload": "batch"},
+			Match:       []map[string]any{{"stream": false}},
+			Providers:   []string{"claude"},
+			AuthIndexes: []string{"abc123"},
+			Model:       "claude-haiku-4-5",
+		},
+		{
+			Name:       "team-a-default",
+			ClientKeys: []string{"team-a*"},
+			Prefix:     "teamA",
+		},
+	}}, nil)
+	ctx := routingRuleTestContext("team-a-ci", http.Header{"X-Workload": {"batch"}})
+
+	got := handler.applyModelRouter(ctx, "openai", "claude-sonnet-4(high)", []byte(`{"stream":false}`), false, modelExecutionOptions{})
+	if fmt.Sprint(got.Providers) != "[claude]" || fmt.Sprint(got.AuthIndexes) != "[abc123]" {
+		t.Fatalf("decision = %#v, want team-a-batch rule", got)
+	}
+	if got.Model != "claude-haiku-4-5(high)" {
+		t.Fatalf("model = %q, want rewrite keeping the thinking suffix", got.Model)
+	}
+
+	got = handler.applyModelRouter(ctx, "openai", "claude-sonnet-4", []byte(`{"stream":true}`), false, modelExecutionOptions{})
+	if got.AuthPrefix != "teamA" || len(got.Providers) != 0 || got.Model != "" {
+		t.Fatalf("decision = %#v, want team-a-default rule", got)
+	}
+
+	got = handler.applyModelRouter(routingRuleTestContext("ops", nil), "openai", "claude-sonnet-4", nil, false, modelExecutionOptions{})
+	if got.AuthPrefix != "" || len(got.Providers) != 0 {
+		t.Fatalf("decision = %#v, want no rule for unmatched key", got)
+	}
+}
+
+func TestProvidersForExecutionUsesRoutingRuleProviders(t *testing.T) {
+	handler := NewBaseAPIHandlers(&sdkconfig.SDKConfig{}, nil)
+	decision := modelRouteDecision{Providers: []string{"gemini", "vertex"}, Model: "gemini-2.5-flash", AuthPrefix: "teamA"}
+	providers, normalizedModel, errMsg := handler.providersForExecution("claude-sonnet-4", "claude-sonnet-4", false, decision)
+	if errMsg != nil {
+		t.Fatalf("providersForExecution() error = %+v", errMsg)
+	}
+	if fmt.Sprint(providers) != "[gemini vertex]" || normalizedModel != "gemini-2.5-flash" {
+		t.Fatalf("providers = %v model = %q", providers, normalizedModel)
+	}
+
+	meta := map[string]any{}
+	setRouteDecisionMetadata(meta, decision)
+	if meta[coreexecutor.AllowedAuthPrefixMetadataKey] != "teamA" {
+		t.Fatalf("metadata = %v, want allowed auth prefix", meta)
+	}
+	if _, ok := meta[coreexecutor.AllowedAuthIndexesMetadataKey]; ok {
+		t.Fatalf("metadata = %v, want no auth index restriction", meta)
+	}
+}
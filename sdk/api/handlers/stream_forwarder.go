@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/interfaces"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/metrics"
 )
 
 type StreamForwardOptions struct {
@@ -37,6 +38,14 @@ func (h *BaseAPIHandler) ForwardStream(c *gin.Context, flusher http.Flusher, can
 		return
 	}
 
+	frontend := "unknown"
+	if c.Request != nil && c.Request.URL != nil {
+		frontend = metrics.Frontend(c.Request.URL.Path)
+	}
+	activeStreams := metrics.ActiveStreams.WithLabelValues(frontend)
+	activeStreams.Inc()
+	defer activeStreams.Dec()
+
 	writeChunk := opts.WriteChunk
 	if writeChunk == nil {
 		writeChunk = func([]byte) {}
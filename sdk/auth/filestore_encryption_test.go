@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/authcrypt"
+	cliproxyauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
+)
+
+func TestFileTokenStore_EncryptsAuthFiles(t *testing.T) {
+	ctx := context.Background()
+	baseDir := t.TempDir()
+	legacyPath := filepath.Join(baseDir, "legacy.json")
+	if err := os.WriteFile(legacyPath, []byte(`{"type":"test","email":"legacy@example.com"}`), 0o600); err != nil {
+		t.Fatalf("seed auth file: %v", err)
+	}
+
+	key, err := authcrypt.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	keyring, err := authcrypt.LoadKeyring(key, "", "")
+	if err != nil {
+		t.Fatalf("LoadKeyring: %v", err)
+	}
+	authcrypt.SetKeyring(keyring)
+	t.Cleanup(func() { authcrypt.SetKeyring(nil) })
+
+	store := NewFileTokenStore()
+	store.SetBaseDir(baseDir)
+	_, err = store.Save(ctx, &cliproxyauth.Auth{
+		ID:       "storage.json",
+		Provider: "test",
+		FileName: "storage.json",
+		Storage:  &testTokenStorage{},
+		Metadata: map[string]any{"type": "test", "access_token": "secret-token"},
+	})
+	if err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	raw, err := os.ReadFile(filepath.Join(baseDir, "storage.json"))
+	if err != nil {
+		t.Fatalf("read auth file: %v", err)
+	}
+	if !authcrypt.IsSealed(raw) {
+		t.Fatalf("saved auth file is not sealed: %s", raw)
+	}
+
+	auths, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	byID := make(map[string]*cliproxyauth.Auth, len(auths))
+	for _, auth := range auths {
+		byID[auth.ID] = auth
+	}
+	if got := byID["storage.json"]; got == nil || got.Metadata["access_token"] != "secret-token" {
+		t.Fatalf("sealed auth not decrypted by List: %+v", got)
+	}
+	legacy := byID["legacy.json"]
+	if legacy == nil {
+		t.Fatal("plaintext auth file not listed")
+	}
+
+	if _, err = store.Save(ctx, legacy); err != nil {
+		t.Fatalf("Save() legacy error: %v", err)
+	}
+	raw, err = os.ReadFile(legacyPath)
+	if err != nil {
+		t.Fatalf("read legacy auth file: %v", err)
+	}
+	if !authcrypt.IsSealed(raw) {
+		t.Fatalf("re-saved plaintext auth file is not sealed: %s", raw)
+	}
+}
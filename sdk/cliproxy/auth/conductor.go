@@ -19,6 +19,7 @@ import (
 	internalconfig "github.com/router-for-me/CLIProxyAPI/v7/internal/config"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/home"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/logging"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/metrics"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/registry"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/thinking"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/util"
@@ -203,6 +204,13 @@ type Manager struct {
 	refreshCancel context.CancelFunc
 	refreshLoop   *authAutoRefreshLoop
 
+	// Quota polling state
+	quotaPollCancel context.CancelFunc
+
+	// Shared cooldown state: the sync loop and cooldowns mirrored from other replicas.
+	sharedCooldownCancel context.CancelFunc
+	sharedCooldowns      map[string]appliedCooldown
+
 	requestPrepareLocks sync.Map
 }
 
@@ -709,7 +717,7 @@ func (m *Manager) availableAuthsForRouteModel(auths []*Auth, provider, routeMode
 		checkModel := m.selectionModelForAuth(candidate, routeModel)
 		blocked, reason, next := isAuthBlockedForModel(candidate, checkModel, now)
 		if !blocked {
-			priority := authPriority(candidate)
+			priority := authModelPriority(candidate, checkModel)
 			availableByPriority[priority] = append(availableByPriority[priority], candidate)
 			continue
 		}
@@ -903,6 +911,7 @@ func (m *Manager) pickViaBuiltinScheduler(ctx context.Context, strategy schedule
 	}
 	providerKey := strings.ToLower(strings.TrimSpace(provider))
 	disallowFreeAuth := disallowFreeAuthFromMetadata(opts.Metadata)
+	routeFilter := authRouteFilterFromMetadata(opts.Metadata)
 	for {
 		var selected *Auth
 		var errPick error
@@ -925,7 +934,7 @@ func (m *Manager) pickViaBuiltinScheduler(ctx context.Context, strategy schedule
 		if selected == nil {
 			return nil, true, &Error{Code: "auth_not_found", Message: "selector returned no auth"}
 		}
-		if disallowFreeAuth && isFreeCodexAuth(selected) {
+		if (disallowFreeAuth && isFreeCodexAuth(selected)) || !routeFilter.allows(selected) {
 			if tried == nil {
 				tried = make(map[string]struct{})
 			}
@@ -1081,10 +1090,11 @@ func readStreamBootstrap(ctx context.Context, ch <-chan cliproxyexecutor.StreamC
 	}
 }
 
-func (m *Manager) wrapStreamResult(ctx context.Context, auth *Auth, provider, resultModel string, headers http.Header, buffered []cliproxyexecutor.StreamChunk, remaining <-chan cliproxyexecutor.StreamChunk) *cliproxyexecutor.StreamResult {
+func (m *Manager) wrapStreamResult(ctx context.Context, auth *Auth, provider, resultModel string, headers http.Header, buffered []cliproxyexecutor.StreamChunk, remaining <-chan cliproxyexecutor.StreamChunk, release func()) *cliproxyexecutor.StreamResult {
 	out := make(chan cliproxyexecutor.StreamChunk)
 	go func() {
 		defer close(out)
+		defer release()
 		var failed bool
 		forward := true
 		emit := func(chunk cliproxyexecutor.StreamChunk) bool {
@@ -1142,8 +1152,21 @@ func (m *Manager) executeStreamWithModelPool(ctx context.Context, executor Provi
 		execReq.Model = execModel
 		execOpts := opts
 		execReq, execOpts = applyRequestAfterAuthInterceptor(ctx, executor, provider, execReq, execOpts, requestedModelAliasFromOptions(execOpts, routeModel))
-		streamResult, errStream := executor.ExecuteStream(ctx, auth, execReq, execOpts)
+		if lastErr != nil {
+			metrics.Retries.WithLabelValues(provider).Inc()
+		}
+		attemptCtx, attemptSpan := startAttemptSpan(ctx, executor, auth, provider, execReq, execOpts)
+		// One span covers the translation of the whole stream; it ends with the load.
+		attemptCtx, streamSpan := sdktranslator.StartStream(attemptCtx, requestToFormat(provider, executor, execReq, execOpts), execOpts.SourceFormat)
+		endLoad := defaultAuthLoad.begin(auth.ID)
+		releaseLoad := func() {
+			endLoad()
+			streamSpan.End()
+		}
+		streamResult, errStream := executor.ExecuteStream(attemptCtx, auth, execReq, execOpts)
 		if errStream != nil {
+			releaseLoad()
+			endSpan(attemptSpan, errStream)
 			if errCtx := ctx.Err(); errCtx != nil {
 				return nil, errCtx
 			}
@@ -1162,7 +1185,9 @@ func (m *Manager) executeStreamWithModelPool(ctx context.Context, executor Provi
 		}
 
 		buffered, closed, bootstrapErr := readStreamBootstrap(ctx, streamResult.Chunks)
+		endSpan(attemptSpan, bootstrapErr)
 		if bootstrapErr != nil {
+			releaseLoad()
 			if errCtx := ctx.Err(); errCtx != nil {
 				discardStreamChunks(streamResult.Chunks)
 				return nil, errCtx
@@ -1202,6 +1227,7 @@ func (m *Manager) executeStreamWithModelPool(ctx context.Context, executor Provi
 		}
 
 		if closed && len(buffered) == 0 {
+			releaseLoad()
 			emptyErr := &Error{Code: "empty_stream", Message: "upstream stream closed before first payload", Retryable: true}
 			result := Result{AuthID: auth.ID, Provider: provider, Model: resultModel, Success: false, Error: emptyErr}
 			m.MarkResult(ctx, result)
@@ -1218,7 +1244,7 @@ func (m *Manager) executeStreamWithModelPool(ctx context.Context, executor Provi
 			close(closedCh)
 			remaining = closedCh
 		}
-		return m.wrapStreamResult(ctx, auth.Clone(), provider, resultModel, streamResult.Headers, buffered, remaining), nil
+		return m.wrapStreamResult(ctx, auth.Clone(), provider, resultModel, streamResult.Headers, buffered, remaining, releaseLoad), nil
 	}
 	if lastErr == nil {
 		lastErr = &Error{Code: "auth_not_found", Message: "no upstream model available"}
@@ -1279,6 +1305,10 @@ func (m *Manager) rebuildAPIKeyModelAliasLocked(cfg *internalconfig.Config) {
 			if entry := resolveVertexAPIKeyConfig(cfg, auth); entry != nil {
 				compileAPIKeyModelAliasForModels(byAlias, entry.Models)
 			}
+		case "bedrock":
+			if entry := resolveBedrockAPIKeyConfig(cfg, auth); entry != nil {
+				compileAPIKeyModelAliasForModels(byAlias, entry.Models)
+			}
 		default:
 			// OpenAI-compat uses config selection from auth.Attributes.
 			providerKey := ""
@@ -1448,6 +1478,12 @@ func (m *Manager) Update(ctx context.Context, auth *Auth) (*Auth, error) {
 			auth.ModelStates = existing.ModelStates
 		}
 	}
+	if auth.QuotaSnapshot == nil {
+		auth.QuotaSnapshot = existing.QuotaSnapshot.Clone()
+	}
+	if auth.Health == nil {
+		auth.Health = existing.Health.Clone()
+	}
 	auth.EnsureIndex()
 	authClone := auth.Clone()
 	m.auths[auth.ID] = authClone
@@ -1502,6 +1538,7 @@ func (m *Manager) Remove(ctx context.Context, id string) {
 	}
 	m.queueRefreshUnschedule(id)
 	m.invalidateSessionAffinity(id)
+	defaultAuthLoad.forget(id)
 
 	if provider != "" {
 		if exec, ok := m.Executor(provider); ok && exec != nil {
@@ -1553,105 +1590,157 @@ func (m *Manager) Load(ctx context.Context) error {
 
 // Execute performs a non-streaming execution using the configured selector and executor.
 // It supports multiple providers for the same model and round-robins the starting provider per model.
+// When every credential for the model fails, the configured model fallback chain is walked.
 func (m *Manager) Execute(ctx context.Context, providers []string, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (cliproxyexecutor.Response, error) {
 	normalized := m.normalizeProviders(providers)
-	if len(normalized) == 0 {
+	if len(normalized) == 0 && len(m.ModelFallbackChain(req.Model)) == 0 {
 		return cliproxyexecutor.Response{}, &Error{Code: "provider_not_found", Message: "no provider supplied"}
 	}
 
-	_, maxRetryCredentials, maxWait := m.retrySettings()
+	ctx, span := startExecuteSpan(ctx, "conductor.execute", normalized, req)
+	defer span.End()
 
-	var lastErr error
-	for attempt := 0; ; attempt++ {
-		resp, errExec := m.executeMixedOnce(ctx, normalized, req, opts, maxRetryCredentials)
+	lastErr := error(&Error{Code: "provider_not_found", Message: "no provider supplied"})
+	if len(normalized) > 0 {
+		resp, errExec, errWait := m.executeWithRetry(ctx, normalized, req, opts)
+		if errWait != nil {
+			return cliproxyexecutor.Response{}, errWait
+		}
 		if errExec == nil {
 			return resp, nil
 		}
 		lastErr = errExec
-		wait, shouldRetry := m.shouldRetryAfterError(errExec, attempt, normalized, req.Model, maxWait)
-		if !shouldRetry {
-			break
-		}
-		if errWait := waitForCooldown(ctx, wait); errWait != nil {
-			return cliproxyexecutor.Response{}, errWait
-		}
 	}
 	if lastErr != nil {
 		if hasAntigravityProvider(normalized) && shouldAttemptAntigravityCreditsFallback(m, lastErr, normalized) {
 			if resp, ok, errCredits := m.tryAntigravityCreditsExecute(ctx, req, opts); errCredits != nil {
+				span.RecordError(errCredits)
 				return cliproxyexecutor.Response{}, errCredits
 			} else if ok {
 				return resp, nil
 			}
 		}
+		if shouldAttemptModelFallback(ctx, lastErr) {
+			if resp, ok := m.tryModelFallbacksExecute(ctx, req, opts); ok {
+				return resp, nil
+			}
+		}
+		span.RecordError(lastErr)
 		return cliproxyexecutor.Response{}, lastErr
 	}
 	return cliproxyexecutor.Response{}, &Error{Code: "auth_not_found", Message: "no auth available"}
 }
 
+// executeWithRetry runs executeMixedOnce until it succeeds or the retry budget is spent.
+// errWait is set when the context ended while waiting for a cooldown.
+func (m *Manager) executeWithRetry(ctx context.Context, providers []string, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (resp cliproxyexecutor.Response, lastErr error, errWait error) {
+	_, maxRetryCredentials, maxWait := m.retrySettings()
+	for attempt := 0; ; attempt++ {
+		resp, errExec := m.executeMixedOnce(ctx, providers, req, opts, maxRetryCredentials)
+		if errExec == nil {
+			return resp, nil, nil
+		}
+		lastErr = errExec
+		wait, shouldRetry := m.shouldRetryAfterError(errExec, attempt, providers, req.Model, maxWait)
+		if !shouldRetry {
+			return cliproxyexecutor.Response{}, lastErr, nil
+		}
+		if errWait = waitForCooldown(ctx, wait); errWait != nil {
+			return cliproxyexecutor.Response{}, lastErr, errWait
+		}
+	}
+}
+
+// ExecuteCount performs a token count using the configured selector and executor.
 // It supports multiple providers for the same model and round-robins the starting provider per model.
+// When every credential for the model fails, the configured model fallback chain is walked.
 func (m *Manager) ExecuteCount(ctx context.Context, providers []string, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (cliproxyexecutor.Response, error) {
 	normalized := m.normalizeProviders(providers)
-	if len(normalized) == 0 {
+	if len(normalized) == 0 && len(m.ModelFallbackChain(req.Model)) == 0 {
 		return cliproxyexecutor.Response{}, &Error{Code: "provider_not_found", Message: "no provider supplied"}
 	}
 
-	_, maxRetryCredentials, maxWait := m.retrySettings()
+	ctx, span := startExecuteSpan(ctx, "conductor.count_tokens", normalized, req)
+	defer span.End()
 
-	var lastErr error
-	for attempt := 0; ; attempt++ {
-		resp, errExec := m.executeCountMixedOnce(ctx, normalized, req, opts, maxRetryCredentials)
+	lastErr := error(&Error{Code: "provider_not_found", Message: "no provider supplied"})
+	if len(normalized) > 0 {
+		resp, errExec, errWait := m.executeCountWithRetry(ctx, normalized, req, opts)
+		if errWait != nil {
+			return cliproxyexecutor.Response{}, errWait
+		}
 		if errExec == nil {
 			return resp, nil
 		}
 		lastErr = errExec
-		wait, shouldRetry := m.shouldRetryAfterError(errExec, attempt, normalized, req.Model, maxWait)
+	}
+	if shouldAttemptModelFallback(ctx, lastErr) {
+		if resp, ok := m.tryModelFallbacksExecuteCount(ctx, req, opts); ok {
+			return resp, nil
+		}
+	}
+	span.RecordError(lastErr)
+	return cliproxyexecutor.Response{}, lastErr
+}
+
+// executeCountWithRetry runs executeCountMixedOnce until it succeeds or the retry budget is spent.
+// errWait is set when the context ended while waiting for a cooldown.
+func (m *Manager) executeCountWithRetry(ctx context.Context, providers []string, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (resp cliproxyexecutor.Response, lastErr error, errWait error) {
+	_, maxRetryCredentials, maxWait := m.retrySettings()
+	for attempt := 0; ; attempt++ {
+		resp, errExec := m.executeCountMixedOnce(ctx, providers, req, opts, maxRetryCredentials)
+		if errExec == nil {
+			return resp, nil, nil
+		}
+		lastErr = errExec
+		wait, shouldRetry := m.shouldRetryAfterError(errExec, attempt, providers, req.Model, maxWait)
 		if !shouldRetry {
-			break
+			return cliproxyexecutor.Response{}, lastErr, nil
 		}
-		if errWait := waitForCooldown(ctx, wait); errWait != nil {
-			return cliproxyexecutor.Response{}, errWait
+		if errWait = waitForCooldown(ctx, wait); errWait != nil {
+			return cliproxyexecutor.Response{}, lastErr, errWait
 		}
 	}
-	if lastErr != nil {
-		return cliproxyexecutor.Response{}, lastErr
-	}
-	return cliproxyexecutor.Response{}, &Error{Code: "auth_not_found", Message: "no auth available"}
 }
 
 // ExecuteStream performs a streaming execution using the configured selector and executor.
 // It supports multiple providers for the same model and round-robins the starting provider per model.
+// When every credential for the model fails, the configured model fallback chain is walked.
 func (m *Manager) ExecuteStream(ctx context.Context, providers []string, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (*cliproxyexecutor.StreamResult, error) {
 	normalized := m.normalizeProviders(providers)
-	if len(normalized) == 0 {
+	if len(normalized) == 0 && len(m.ModelFallbackChain(req.Model)) == 0 {
 		return nil, &Error{Code: "provider_not_found", Message: "no provider supplied"}
 	}
 
-	_, maxRetryCredentials, maxWait := m.retrySettings()
+	ctx, span := startExecuteSpan(ctx, "conductor.execute_stream", normalized, req)
+	defer span.End()
 
-	var lastErr error
-	for attempt := 0; ; attempt++ {
-		result, errStream := m.executeStreamMixedOnce(ctx, normalized, req, opts, maxRetryCredentials)
+	lastErr := error(&Error{Code: "provider_not_found", Message: "no provider supplied"})
+	if len(normalized) > 0 {
+		result, errStream, errWait := m.executeStreamWithRetry(ctx, normalized, req, opts)
+		if errWait != nil {
+			return nil, errWait
+		}
 		if errStream == nil {
 			return result, nil
 		}
 		lastErr = errStream
-		wait, shouldRetry := m.shouldRetryAfterError(errStream, attempt, normalized, req.Model, maxWait)
-		if !shouldRetry {
-			break
-		}
-		if errWait := waitForCooldown(ctx, wait); errWait != nil {
-			return nil, errWait
-		}
 	}
 	if lastErr != nil {
 		if hasAntigravityProvider(normalized) && shouldAttemptAntigravityCreditsFallback(m, lastErr, normalized) {
 			if result, ok, errCredits := m.tryAntigravityCreditsExecuteStream(ctx, req, opts); errCredits != nil {
+				span.RecordError(errCredits)
 				return nil, errCredits
 			} else if ok {
 				return result, nil
 			}
 		}
+		if shouldAttemptModelFallback(ctx, lastErr) {
+			if result, ok := m.tryModelFallbacksExecuteStream(ctx, req, opts); ok {
+				return result, nil
+			}
+		}
+		span.RecordError(lastErr)
 		var bootstrapErr *streamBootstrapError
 		if errors.As(lastErr, &bootstrapErr) && bootstrapErr != nil {
 			return streamErrorResult(bootstrapErr.Headers(), bootstrapErr.cause), nil
@@ -1661,6 +1750,26 @@ func (m *Manager) ExecuteStream(ctx context.Context, providers []string, req cli
 	return nil, &Error{Code: "auth_not_found", Message: "no auth available"}
 }
 
+// executeStreamWithRetry runs executeStreamMixedOnce until it succeeds or the retry budget is spent.
+// errWait is set when the context ended while waiting for a cooldown.
+func (m *Manager) executeStreamWithRetry(ctx context.Context, providers []string, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (result *cliproxyexecutor.StreamResult, lastErr error, errWait error) {
+	_, maxRetryCredentials, maxWait := m.retrySettings()
+	for attempt := 0; ; attempt++ {
+		result, errStream := m.executeStreamMixedOnce(ctx, providers, req, opts, maxRetryCredentials)
+		if errStream == nil {
+			return result, nil, nil
+		}
+		lastErr = errStream
+		wait, shouldRetry := m.shouldRetryAfterError(errStream, attempt, providers, req.Model, maxWait)
+		if !shouldRetry {
+			return nil, lastErr, nil
+		}
+		if errWait = waitForCooldown(ctx, wait); errWait != nil {
+			return nil, lastErr, errWait
+		}
+	}
+}
+
 type requestToFormatResolver interface {
 	RequestToFormat(req cliproxyexecutor.Request, opts cliproxyexecutor.Options) sdktranslator.Format
 }
@@ -1697,7 +1806,7 @@ func requestToFormat(provider string, executor ProviderExecutor, req cliproxyexe
 		}
 	}
 	source := opts.SourceFormat.String()
-	if source == "openai-image" || source == "openai-video" {
+	if source == "openai-image" || source == "openai-video" || source == "openai-embedding" || source == "openai-audio" {
 		return opts.SourceFormat
 	}
 	if opts.Alt == "responses/compact" && !opts.Stream {
@@ -1788,6 +1897,9 @@ func (m *Manager) executeMixedOnce(ctx context.Context, providers []string, req
 		debugLogAuthSelection(entry, auth, provider, req.Model)
 		publishSelectedAuthMetadata(opts.Metadata, auth.ID)
 
+		if lastErr != nil {
+			metrics.CredentialSwitches.WithLabelValues(provider).Inc()
+		}
 		tried[auth.ID] = struct{}{}
 		execCtx := ctx
 		if rt := m.roundTripperFor(auth); rt != nil {
@@ -1819,7 +1931,14 @@ func (m *Manager) executeMixedOnce(ctx context.Context, providers []string, req
 			execReq.Model = upstreamModel
 			execOpts := opts
 			execReq, execOpts = applyRequestAfterAuthInterceptor(execCtx, executor, provider, execReq, execOpts, requestedModelAliasFromOptions(execOpts, routeModel))
-			resp, errExec := executor.Execute(execCtx, auth, execReq, execOpts)
+			if lastErr != nil || authErr != nil {
+				metrics.Retries.WithLabelValues(provider).Inc()
+			}
+			attemptCtx, attemptSpan := startAttemptSpan(execCtx, executor, auth, provider, execReq, execOpts)
+			releaseLoad := defaultAuthLoad.begin(auth.ID)
+			resp, errExec := executor.Execute(attemptCtx, auth, execReq, execOpts)
+			releaseLoad()
+			endSpan(attemptSpan, errExec)
 			result := Result{AuthID: auth.ID, Provider: provider, Model: resultModel, Success: errExec == nil}
 			if errExec != nil {
 				if errCtx := execCtx.Err(); errCtx != nil {
@@ -1889,6 +2008,9 @@ func (m *Manager) executeCountMixedOnce(ctx context.Context, providers []string,
 		debugLogAuthSelection(entry, auth, provider, req.Model)
 		publishSelectedAuthMetadata(opts.Metadata, auth.ID)
 
+		if lastErr != nil {
+			metrics.CredentialSwitches.WithLabelValues(provider).Inc()
+		}
 		tried[auth.ID] = struct{}{}
 		execCtx := ctx
 		if rt := m.roundTripperFor(auth); rt != nil {
@@ -1920,7 +2042,12 @@ func (m *Manager) executeCountMixedOnce(ctx context.Context, providers []string,
 			execReq.Model = upstreamModel
 			execOpts := opts
 			execReq, execOpts = applyRequestAfterAuthInterceptor(execCtx, executor, provider, execReq, execOpts, requestedModelAliasFromOptions(execOpts, routeModel))
-			resp, errExec := executor.CountTokens(execCtx, auth, execReq, execOpts)
+			if lastErr != nil || authErr != nil {
+				metrics.Retries.WithLabelValues(provider).Inc()
+			}
+			attemptCtx, attemptSpan := startAttemptSpan(execCtx, executor, auth, provider, execReq, execOpts)
+			resp, errExec := executor.CountTokens(attemptCtx, auth, execReq, execOpts)
+			endSpan(attemptSpan, errExec)
 			result := Result{AuthID: auth.ID, Provider: provider, Model: resultModel, Success: errExec == nil}
 			if errExec != nil {
 				if errCtx := execCtx.Err(); errCtx != nil {
@@ -1990,6 +2117,9 @@ func (m *Manager) executeStreamMixedOnce(ctx context.Context, providers []string
 		debugLogAuthSelection(entry, auth, provider, req.Model)
 		publishSelectedAuthMetadata(opts.Metadata, auth.ID)
 
+		if lastErr != nil {
+			metrics.CredentialSwitches.WithLabelValues(provider).Inc()
+		}
 		tried[auth.ID] = struct{}{}
 		execCtx := ctx
 		if rt := m.roundTripperFor(auth); rt != nil {
@@ -2013,6 +2143,9 @@ func (m *Manager) executeStreamMixedOnce(ctx context.Context, providers []string
 			continue
 		}
 		execReq := sanitizeDownstreamWebsocketFallbackRequest(execCtx, auth, req)
+		if lastErr != nil {
+			metrics.Retries.WithLabelValues(provider).Inc()
+		}
 		streamResult, errStream := m.executeStreamWithModelPool(execCtx, executor, auth, provider, execReq, opts, routeModel, models, pooled)
 		if errStream != nil {
 			if errCtx := execCtx.Err(); errCtx != nil {
@@ -2364,6 +2497,8 @@ func (m *Manager) applyAPIKeyModelAlias(auth *Auth, requestedModel string) strin
 		upstreamModel = resolveUpstreamModelForCodexAPIKey(cfg, auth, requestedModel)
 	case "vertex":
 		upstreamModel = resolveUpstreamModelForVertexAPIKey(cfg, auth, requestedModel)
+	case "bedrock":
+		upstreamModel = resolveUpstreamModelForBedrockAPIKey(cfg, auth, requestedModel)
 	default:
 		upstreamModel = resolveUpstreamModelForOpenAICompatAPIKey(cfg, auth, requestedModel)
 	}
@@ -2448,6 +2583,13 @@ func resolveVertexAPIKeyConfig(cfg *internalconfig.Config, auth *Auth) *internal
 	return resolveAPIKeyConfig(cfg.VertexCompatAPIKey, auth)
 }
 
+func resolveBedrockAPIKeyConfig(cfg *internalconfig.Config, auth *Auth) *internalconfig.BedrockKey {
+	if cfg == nil {
+		return nil
+	}
+	return resolveAPIKeyConfig(cfg.BedrockKey, auth)
+}
+
 func resolveUpstreamModelForGeminiAPIKey(cfg *internalconfig.Config, auth *Auth, requestedModel string) string {
 	entry := resolveGeminiAPIKeyConfig(cfg, auth)
 	if entry == nil {
@@ -2480,6 +2622,14 @@ func resolveUpstreamModelForVertexAPIKey(cfg *internalconfig.Config, auth *Auth,
 	return resolveModelAliasFromConfigModels(requestedModel, asModelAliasEntries(entry.Models))
 }
 
+func resolveUpstreamModelForBedrockAPIKey(cfg *internalconfig.Config, auth *Auth, requestedModel string) string {
+	entry := resolveBedrockAPIKeyConfig(cfg, auth)
+	if entry == nil {
+		return ""
+	}
+	return resolveModelAliasFromConfigModels(requestedModel, asModelAliasEntries(entry.Models))
+}
+
 func resolveUpstreamModelForOpenAICompatAPIKey(cfg *internalconfig.Config, auth *Auth, requestedModel string) string {
 	providerKey := ""
 	compatName := ""
@@ -2788,6 +2938,8 @@ func (m *Manager) MarkResult(ctx context.Context, result Result) {
 	suspendReason := ""
 	clearModelQuota := false
 	setModelQuota := false
+	clearCooldown := false
+	var publishCooldown *sharedCooldown
 	var authSnapshot *Auth
 
 	m.mu.Lock()
@@ -2803,6 +2955,10 @@ func (m *Manager) MarkResult(ctx context.Context, result Result) {
 		if result.Success {
 			if result.Model != "" {
 				state := ensureModelState(auth, result.Model)
+				if !state.NextRetryAfter.IsZero() {
+					clearCooldown = true
+					delete(m.sharedCooldowns, sharedCooldownKey(result.AuthID, result.Model))
+				}
 				resetModelState(state, now)
 				updateAggregatedAvailability(auth, now)
 				if !hasModelError(auth, now) {
@@ -2920,6 +3076,16 @@ func (m *Manager) MarkResult(ctx context.Context, result Result) {
 					auth.Status = StatusError
 					auth.UpdatedAt = now
 					updateAggregatedAvailability(auth, now)
+					if state.NextRetryAfter.After(now) {
+						publishCooldown = &sharedCooldown{
+							AuthID:        result.AuthID,
+							Model:         result.Model,
+							Until:         state.NextRetryAfter,
+							StatusMessage: state.StatusMessage,
+							Quota:         state.Quota,
+							SuspendReason: suspendReason,
+						}
+					}
 				}
 			} else {
 				applyAuthFailureState(auth, result.Error, result.RetryAfter, now)
@@ -2945,6 +3111,12 @@ func (m *Manager) MarkResult(ctx context.Context, result Result) {
 	} else if shouldSuspendModel {
 		registry.GetGlobalRegistry().SuspendClientModel(result.AuthID, result.Model, suspendReason)
 	}
+	if clearCooldown {
+		clearSharedCooldown(result.AuthID, result.Model)
+	}
+	if publishCooldown != nil {
+		publishSharedCooldown(*publishCooldown)
+	}
 
 	m.hook.OnResult(ctx, result)
 	m.publishErrorEvent(result, authSnapshot)
@@ -3573,6 +3745,7 @@ func (m *Manager) pickNextLegacy(ctx context.Context, provider, model string, op
 
 	pinnedAuthID := pinnedAuthIDFromMetadata(opts.Metadata)
 	disallowFreeAuth := disallowFreeAuthFromMetadata(opts.Metadata)
+	routeFilter := authRouteFilterFromMetadata(opts.Metadata)
 
 	m.mu.RLock()
 	selector := m.selector
@@ -3599,7 +3772,7 @@ func (m *Manager) pickNextLegacy(ctx context.Context, provider, model string, op
 		if pinnedAuthID != "" && candidate.ID != pinnedAuthID {
 			continue
 		}
-		if disallowFreeAuth && isFreeCodexAuth(candidate) {
+		if (disallowFreeAuth && isFreeCodexAuth(candidate)) || !routeFilter.allows(candidate) {
 			continue
 		}
 		if _, used := tried[candidate.ID]; used {
@@ -3677,6 +3850,7 @@ func (m *Manager) pickNext(ctx context.Context, provider, model string, opts cli
 		return nil, nil, &Error{Code: "executor_not_found", Message: "executor not registered"}
 	}
 	disallowFreeAuth := disallowFreeAuthFromMetadata(opts.Metadata)
+	routeFilter := authRouteFilterFromMetadata(opts.Metadata)
 	for {
 		selected, errPick := m.scheduler.pickSingle(ctx, provider, model, opts, tried)
 		if errPick != nil && model != "" && shouldRetrySchedulerPick(errPick) {
@@ -3689,7 +3863,7 @@ func (m *Manager) pickNext(ctx context.Context, provider, model string, opts cli
 		if selected == nil {
 			return nil, nil, &Error{Code: "auth_not_found", Message: "selector returned no auth"}
 		}
-		if disallowFreeAuth && isFreeCodexAuth(selected) {
+		if (disallowFreeAuth && isFreeCodexAuth(selected)) || !routeFilter.allows(selected) {
 			if tried == nil {
 				tried = make(map[string]struct{})
 			}
@@ -3716,6 +3890,7 @@ func (m *Manager) pickNextMixedLegacy(ctx context.Context, providers []string, m
 
 	pinnedAuthID := pinnedAuthIDFromMetadata(opts.Metadata)
 	disallowFreeAuth := disallowFreeAuthFromMetadata(opts.Metadata)
+	routeFilter := authRouteFilterFromMetadata(opts.Metadata)
 
 	providerSet := make(map[string]struct{}, len(providers))
 	for _, provider := range providers {
@@ -3749,7 +3924,7 @@ func (m *Manager) pickNextMixedLegacy(ctx context.Context, providers []string, m
 		if pinnedAuthID != "" && candidate.ID != pinnedAuthID {
 			continue
 		}
-		if disallowFreeAuth && isFreeCodexAuth(candidate) {
+		if (disallowFreeAuth && isFreeCodexAuth(candidate)) || !routeFilter.allows(candidate) {
 			continue
 		}
 		providerKey := strings.TrimSpace(strings.ToLower(candidate.Provider))
@@ -3865,6 +4040,7 @@ func (m *Manager) pickNextMixed(ctx context.Context, providers []string, model s
 	}
 
 	disallowFreeAuth := disallowFreeAuthFromMetadata(opts.Metadata)
+	routeFilter := authRouteFilterFromMetadata(opts.Metadata)
 	for {
 		selected, providerKey, errPick := m.scheduler.pickMixed(ctx, eligibleProviders, model, opts, tried)
 		if errPick != nil && model != "" && shouldRetrySchedulerPick(errPick) {
@@ -3877,7 +4053,7 @@ func (m *Manager) pickNextMixed(ctx context.Context, providers []string, model s
 		if selected == nil {
 			return nil, nil, "", &Error{Code: "auth_not_found", Message: "selector returned no auth"}
 		}
-		if disallowFreeAuth && isFreeCodexAuth(selected) {
+		if (disallowFreeAuth && isFreeCodexAuth(selected)) || !routeFilter.allows(selected) {
 			if tried == nil {
 				tried = make(map[string]struct{})
 			}
@@ -4283,6 +4459,7 @@ func (m *Manager) findAllAntigravityCreditsCandidateAuths(ctx context.Context, r
 		return nil, nil
 	}
 	pinnedAuthID := pinnedAuthIDFromMetadata(opts.Metadata)
+	routeFilter := authRouteFilterFromMetadata(opts.Metadata)
 	var candidates []creditsCandidateEntry
 	m.mu.RLock()
 	for _, auth := range m.auths {
@@ -4292,6 +4469,9 @@ func (m *Manager) findAllAntigravityCreditsCandidateAuths(ctx context.Context, r
 		if pinnedAuthID != "" && auth.ID != pinnedAuthID {
 			continue
 		}
+		if !routeFilter.allows(auth) {
+			continue
+		}
 		if !strings.EqualFold(strings.TrimSpace(auth.Provider), "antigravity") {
 			continue
 		}
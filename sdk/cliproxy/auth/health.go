@@ -0,0 +1,229 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxProbeHistory bounds the probe results kept per credential.
+const maxProbeHistory = 20
+
+// HealthChecker is implemented by executors that can verify a credential with a
+// cheap upstream call.
+type HealthChecker interface {
+	CheckHealth(ctx context.Context, auth *Auth) error
+}
+
+// ProbeResult is the outcome of one synthetic health probe.
+type ProbeResult struct {
+	Time       time.Time `json:"time"`
+	Success    bool      `json:"success"`
+	StatusCode int       `json:"status_code,omitempty"`
+	LatencyMs  int64     `json:"latency_ms"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// HealthState tracks synthetic probe results for a credential.
+type HealthState struct {
+	// ConsecutiveFailures counts failed probes since the last success.
+	ConsecutiveFailures int `json:"consecutive_failures"`
+	// Quarantined keeps the credential out of selection until a probe succeeds.
+	Quarantined bool `json:"quarantined"`
+	// QuarantinedAt is when the credential was quarantined.
+	QuarantinedAt time.Time `json:"quarantined_at,omitempty"`
+	// History holds the most recent probe results, oldest first.
+	History []ProbeResult `json:"history,omitempty"`
+	// NotProbeable reports that no health probe applies to the credential, so its
+	// health is unknown rather than healthy.
+	NotProbeable bool `json:"not_probeable,omitempty"`
+}
+
+// Clone returns a deep copy of the health state.
+func (h *HealthState) Clone() *HealthState {
+	if h == nil {
+		return nil
+	}
+	copyState := *h
+	copyState.History = append([]ProbeResult(nil), h.History...)
+	return &copyState
+}
+
+func authQuarantined(auth *Auth) bool {
+	return auth != nil && auth.Health != nil && auth.Health.Quarantined
+}
+
+// ProbeAuth runs one synthetic health probe against auth. Executors implementing
+// HealthChecker are asked directly; otherwise a quota lookup through QuotaPoller
+// or a models-list call against the credential's base_url is used. ok is false
+// when no probe applies to the credential.
+func (m *Manager) ProbeAuth(ctx context.Context, auth *Auth) (result ProbeResult, ok bool) {
+	if m == nil || auth == nil {
+		return ProbeResult{}, false
+	}
+	executor := m.executorFor(executorKeyFromAuth(auth))
+	if executor == nil {
+		return ProbeResult{}, false
+	}
+	if rt := m.roundTripperFor(auth); rt != nil {
+		ctx = context.WithValue(ctx, roundTripperContextKey{}, rt)
+		ctx = context.WithValue(ctx, "cliproxy.roundtripper", rt)
+	}
+
+	start := time.Now()
+	var errProbe error
+	statusCode := 0
+	if checker, isChecker := executor.(HealthChecker); isChecker {
+		errProbe = checker.CheckHealth(ctx, auth)
+		ok = true
+	} else if poller, isPoller := executor.(QuotaPoller); isPoller {
+		snapshot, errPoll := poller.PollQuota(ctx, auth)
+		errProbe = errPoll
+		ok = errPoll != nil || snapshot != nil
+	}
+	if !ok {
+		target := healthProbeModelsURL(auth)
+		if target == "" {
+			return ProbeResult{}, false
+		}
+		statusCode, errProbe = m.probeModelsEndpoint(ctx, auth, target)
+		ok = true
+	}
+	if statusCode == 0 {
+		statusCode = statusCodeFromError(errProbe)
+	}
+
+	result = ProbeResult{
+		Time:       start,
+		StatusCode: statusCode,
+		LatencyMs:  time.Since(start).Milliseconds(),
+	}
+	// A rate limited credential is reachable and authenticated.
+	result.Success = errProbe == nil || statusCode == http.StatusTooManyRequests
+	if errProbe != nil && !result.Success {
+		result.Error = errProbe.Error()
+	}
+	return result, true
+}
+
+// healthProbeModelsURL derives the models-list endpoint from the credential's base_url.
+func healthProbeModelsURL(auth *Auth) string {
+	if auth == nil || auth.Attributes == nil {
+		return ""
+	}
+	baseURL := strings.TrimRight(strings.TrimSpace(auth.Attributes["base_url"]), "/")
+	if baseURL == "" {
+		return ""
+	}
+	if strings.HasSuffix(baseURL, "/v1") {
+		return baseURL + "/models"
+	}
+	return baseURL + "/v1/models"
+}
+
+func (m *Manager) probeModelsEndpoint(ctx context.Context, auth *Auth, target string) (int, error) {
+	req, errReq := m.NewHttpRequest(ctx, auth, http.MethodGet, target, nil, http.Header{"Accept": []string{"application/json"}})
+	if errReq != nil {
+		return 0, errReq
+	}
+	resp, errDo := m.HttpRequest(ctx, auth, req)
+	if errDo != nil {
+		return 0, errDo
+	}
+	defer func() {
+		if errClose := resp.Body.Close(); errClose != nil {
+			log.Debugf("health check: close response body error: %v", errClose)
+		}
+	}()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, fmt.Errorf("models endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp.StatusCode, nil
+}
+
+// RecordProbeResult stores a health probe outcome for authID. The probe counts
+// towards the recent request buckets, and failureThreshold consecutive failures
+// quarantine the credential until a later probe succeeds. A threshold of zero
+// never quarantines.
+func (m *Manager) RecordProbeResult(ctx context.Context, authID string, result ProbeResult, failureThreshold int) {
+	_ = ctx
+	if m == nil || authID == "" {
+		return
+	}
+	if result.Time.IsZero() {
+		result.Time = time.Now()
+	}
+
+	var authSnapshot *Auth
+	m.mu.Lock()
+	auth := m.auths[authID]
+	if auth == nil {
+		m.mu.Unlock()
+		return
+	}
+	auth.recordRecentRequest(result.Time, result.Success)
+	health := auth.Health.Clone()
+	if health == nil {
+		health = &HealthState{}
+	}
+	health.NotProbeable = false
+	health.History = append(health.History, result)
+	if len(health.History) > maxProbeHistory {
+		health.History = health.History[len(health.History)-maxProbeHistory:]
+	}
+	if result.Success {
+		health.ConsecutiveFailures = 0
+		if health.Quarantined {
+			health.Quarantined = false
+			health.QuarantinedAt = time.Time{}
+			auth.Status = StatusActive
+			auth.StatusMessage = ""
+			auth.UpdatedAt = result.Time
+			log.Infof("health check: auth %s recovered, quarantine lifted", authID)
+		}
+	} else {
+		health.ConsecutiveFailures++
+		if failureThreshold > 0 && health.ConsecutiveFailures >= failureThreshold && !health.Quarantined {
+			health.Quarantined = true
+			health.QuarantinedAt = result.Time
+			auth.Status = StatusError
+			auth.StatusMessage = "quarantined: " + result.Error
+			auth.UpdatedAt = result.Time
+			log.Warnf("health check: auth %s quarantined after %d failed probes: %s", authID, health.ConsecutiveFailures, result.Error)
+		}
+	}
+	auth.Health = health
+	authSnapshot = auth.Clone()
+	m.mu.Unlock()
+
+	if m.scheduler != nil {
+		m.scheduler.upsertAuth(authSnapshot)
+	}
+}
+
+// RecordNotProbeable marks authID as having no applicable health probe, so its
+// health is reported as unknown instead of healthy.
+func (m *Manager) RecordNotProbeable(ctx context.Context, authID string) {
+	_ = ctx
+	if m == nil || authID == "" {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	auth := m.auths[authID]
+	if auth == nil || (auth.Health != nil && auth.Health.NotProbeable) {
+		return
+	}
+	health := auth.Health.Clone()
+	if health == nil {
+		health = &HealthState{}
+	}
+	health.NotProbeable = true
+	auth.Health = health
+}
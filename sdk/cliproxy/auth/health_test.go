@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type healthCheckingExecutor struct {
+	modelFallbackExecutor
+	err error
+}
+
+func (e *healthCheckingExecutor) CheckHealth(context.Context, *Auth) error {
+	return e.err
+}
+
+func TestManagerProbeAuth_QuarantinesAfterConsecutiveFailures(t *testing.T) {
+	executor := &healthCheckingExecutor{
+		modelFallbackExecutor: modelFallbackExecutor{id: "health-probe"},
+		err:                   &Error{Message: "invalid api key", HTTPStatus: http.StatusUnauthorized},
+	}
+	m := NewManager(nil, nil, nil)
+	m.RegisterExecutor(executor)
+	auth := &Auth{ID: "health-auth", Provider: executor.id, Status: StatusActive}
+	if _, err := m.Register(context.Background(), auth); err != nil {
+		t.Fatalf("register auth: %v", err)
+	}
+
+	probe := func() {
+		t.Helper()
+		current, _ := m.GetByID(auth.ID)
+		result, ok := m.ProbeAuth(context.Background(), current)
+		if !ok {
+			t.Fatal("ProbeAuth() ok = false, want probe through HealthChecker")
+		}
+		m.RecordProbeResult(context.Background(), auth.ID, result, 2)
+	}
+
+	probe()
+	got, _ := m.GetByID(auth.ID)
+	if got.Health == nil || got.Health.ConsecutiveFailures != 1 || got.Health.Quarantined {
+		t.Fatalf("health after one failure = %+v, want 1 failure without quarantine", got.Health)
+	}
+	if last := got.Health.History[0]; last.Success || last.StatusCode != http.StatusUnauthorized || last.Error == "" {
+		t.Fatalf("probe result = %+v, want failed 401", last)
+	}
+
+	probe()
+	got, _ = m.GetByID(auth.ID)
+	if !got.Health.Quarantined || got.Status != StatusError {
+		t.Fatalf("health after two failures = %+v status %q, want quarantined", got.Health, got.Status)
+	}
+	if blocked, _, _ := isAuthBlockedForModel(got, "any-model", time.Now()); !blocked {
+		t.Fatal("quarantined auth not blocked")
+	}
+	var failed int64
+	for _, bucket := range got.RecentRequestsSnapshot(time.Now()) {
+		failed += bucket.Failed
+	}
+	if failed != 2 {
+		t.Fatalf("recent failed requests = %d, want 2", failed)
+	}
+
+	// Rate limited probes prove the credential works.
+	executor.err = &Error{Message: "slow down", HTTPStatus: http.StatusTooManyRequests}
+	probe()
+	got, _ = m.GetByID(auth.ID)
+	if got.Health.Quarantined || got.Health.ConsecutiveFailures != 0 || got.Status != StatusActive || len(got.Health.History) != 3 {
+		t.Fatalf("health after success = %+v status %q, want quarantine lifted", got.Health, got.Status)
+	}
+	if blocked, _, _ := isAuthBlockedForModel(got, "any-model", time.Now()); blocked {
+		t.Fatal("recovered auth still blocked")
+	}
+}
+
+func TestManagerProbeAuth_SkipsWithoutProbe(t *testing.T) {
+	m := NewManager(nil, nil, nil)
+	executor := &modelFallbackExecutor{id: "health-none"}
+	m.RegisterExecutor(executor)
+	auth := &Auth{ID: "a", Provider: executor.id, Status: StatusActive}
+	if _, err := m.Register(context.Background(), auth); err != nil {
+		t.Fatalf("register auth: %v", err)
+	}
+	if _, ok := m.ProbeAuth(context.Background(), auth); ok {
+		t.Fatal("ProbeAuth() ok = true for executor without probe and no base_url")
+	}
+	m.RecordNotProbeable(context.Background(), auth.ID)
+	if got, _ := m.GetByID(auth.ID); got.Health == nil || !got.Health.NotProbeable {
+		t.Fatalf("health = %+v, want not probeable", got.Health)
+	}
+	m.RecordProbeResult(context.Background(), auth.ID, ProbeResult{Success: true}, 1)
+	if got, _ := m.GetByID(auth.ID); got.Health.NotProbeable || len(got.Health.History) != 1 {
+		t.Fatalf("health after a probe = %+v, want probe recorded", got.Health)
+	}
+	if got := healthProbeModelsURL(&Auth{Attributes: map[string]string{"base_url": "https://example.com/v1/"}}); got != "https://example.com/v1/models" {
+		t.Fatalf("healthProbeModelsURL() = %q", got)
+	}
+	if got := healthProbeModelsURL(&Auth{Attributes: map[string]string{"base_url": "https://api.anthropic.com"}}); got != "https://api.anthropic.com/v1/models" {
+		t.Fatalf("healthProbeModelsURL() = %q", got)
+	}
+}
@@ -0,0 +1,181 @@
+package auth
+
+import (
+	"context"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	cliproxyexecutor "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/executor"
+)
+
+// LeastInFlightSelector selects the available credential with the fewest requests
+// currently in flight. Ties are broken round-robin.
+type LeastInFlightSelector struct {
+	stats *authLoadStats
+	tie   RoundRobinSelector
+}
+
+// LatencySelector spreads requests across available credentials in proportion to
+// the inverse of their moving average latency, discounted by in-flight requests.
+// Credentials without latency samples are treated like the fastest known one so
+// they get explored.
+type LatencySelector struct {
+	stats     *authLoadStats
+	randFloat func() float64
+}
+
+// PriorityShareSelector treats the configured priority as a traffic weight
+// instead of a strict tier: available credentials with a positive priority share
+// requests in proportion to it using smooth weighted round-robin. When none is
+// available, the highest remaining tier is shared evenly.
+type PriorityShareSelector struct {
+	mu      sync.Mutex
+	current map[string]map[string]int
+	maxKeys int
+}
+
+func selectorLoadStats(stats *authLoadStats) *authLoadStats {
+	if stats != nil {
+		return stats
+	}
+	return defaultAuthLoad
+}
+
+// Pick selects the least loaded available auth for the provider.
+func (s *LeastInFlightSelector) Pick(ctx context.Context, provider, model string, opts cliproxyexecutor.Options, auths []*Auth) (*Auth, error) {
+	available, err := getAvailableAuths(auths, provider, model, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	available = preferCodexWebsocketAuths(ctx, provider, available)
+	stats := selectorLoadStats(s.stats)
+	least := -1
+	tied := make([]*Auth, 0, len(available))
+	for _, candidate := range available {
+		inFlight, _ := stats.snapshot(candidate.ID)
+		switch {
+		case least < 0 || inFlight < least:
+			least = inFlight
+			tied = append(tied[:0], candidate)
+		case inFlight == least:
+			tied = append(tied, candidate)
+		}
+	}
+	if len(tied) == 1 {
+		return tied[0], nil
+	}
+	return s.tie.Pick(ctx, provider, model, opts, tied)
+}
+
+// Pick selects an available auth at random, weighted towards fast and idle credentials.
+func (s *LatencySelector) Pick(ctx context.Context, provider, model string, opts cliproxyexecutor.Options, auths []*Auth) (*Auth, error) {
+	_ = opts
+	available, err := getAvailableAuths(auths, provider, model, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	available = preferCodexWebsocketAuths(ctx, provider, available)
+	if len(available) == 1 {
+		return available[0], nil
+	}
+
+	stats := selectorLoadStats(s.stats)
+	inFlight := make([]int, len(available))
+	latencies := make([]time.Duration, len(available))
+	var fastest time.Duration
+	for i, candidate := range available {
+		inFlight[i], latencies[i] = stats.snapshot(candidate.ID)
+		if latencies[i] > 0 && (fastest <= 0 || latencies[i] < fastest) {
+			fastest = latencies[i]
+		}
+	}
+	if fastest <= 0 {
+		fastest = time.Second
+	}
+	weights := make([]float64, len(available))
+	var total float64
+	for i := range available {
+		latency := latencies[i]
+		if latency <= 0 {
+			latency = fastest
+		}
+		weights[i] = 1 / (latency.Seconds() * float64(inFlight[i]+1))
+		total += weights[i]
+	}
+
+	randFloat := s.randFloat
+	if randFloat == nil {
+		randFloat = rand.Float64
+	}
+	target := randFloat() * total
+	for i, weight := range weights {
+		if target < weight {
+			return available[i], nil
+		}
+		target -= weight
+	}
+	return available[len(available)-1], nil
+}
+
+// Pick selects the next available auth according to its priority share.
+func (s *PriorityShareSelector) Pick(ctx context.Context, provider, model string, opts cliproxyexecutor.Options, auths []*Auth) (*Auth, error) {
+	_ = opts
+	now := time.Now()
+	availableByPriority, cooldownCount, earliest := collectAvailableByPriority(auths, model, now)
+	if len(availableByPriority) == 0 {
+		if len(auths) == 0 {
+			return nil, &Error{Code: "auth_not_found", Message: "no auth candidates"}
+		}
+		return nil, noAvailableAuthError(len(auths), cooldownCount, earliest, provider, model, now)
+	}
+
+	var available []*Auth
+	for priority, candidates := range availableByPriority {
+		if priority > 0 {
+			available = append(available, candidates...)
+		}
+	}
+	weighted := len(available) > 0
+	if !weighted {
+		var err error
+		if available, err = getAvailableAuths(auths, provider, model, now); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(available, func(i, j int) bool { return available[i].ID < available[j].ID })
+	available = preferCodexWebsocketAuths(ctx, provider, available)
+	if len(available) == 1 {
+		return available[0], nil
+	}
+
+	key := provider + ":" + canonicalModelKey(model)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	limit := s.maxKeys
+	if limit <= 0 {
+		limit = 4096
+	}
+	if s.current == nil || (s.current[key] == nil && len(s.current) >= limit) {
+		s.current = make(map[string]map[string]int)
+	}
+	previous := s.current[key]
+	current := make(map[string]int, len(available))
+	var best *Auth
+	total := 0
+	for _, candidate := range available {
+		weight := 1
+		if weighted {
+			weight = authModelPriority(candidate, model)
+		}
+		current[candidate.ID] = previous[candidate.ID] + weight
+		total += weight
+		if best == nil || current[candidate.ID] > current[best.ID] {
+			best = candidate
+		}
+	}
+	current[best.ID] -= total
+	s.current[key] = current
+	return best, nil
+}
@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cliproxyexecutor "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/executor"
+	coreusage "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/usage"
+)
+
+func TestAuthLoadStats_BeginReleaseAndEWMA(t *testing.T) {
+	t.Parallel()
+
+	stats := newAuthLoadStats()
+	release := stats.begin("a")
+	stats.begin("a")
+	if inFlight, _ := stats.snapshot("a"); inFlight != 2 {
+		t.Fatalf("inFlight = %d, want 2", inFlight)
+	}
+	release()
+	release()
+	if inFlight, _ := stats.snapshot("a"); inFlight != 1 {
+		t.Fatalf("inFlight after double release = %d, want 1", inFlight)
+	}
+
+	stats.observe("a", time.Second)
+	stats.observe("a", 2*time.Second)
+	if _, latency := stats.snapshot("a"); latency != 1300*time.Millisecond {
+		t.Fatalf("latency = %v, want 1.3s", latency)
+	}
+
+	plugin := &loadUsagePlugin{stats: stats}
+	plugin.HandleUsage(context.Background(), coreusage.Record{AuthID: "b", Latency: 5 * time.Second, TTFT: 200 * time.Millisecond})
+	plugin.HandleUsage(context.Background(), coreusage.Record{AuthID: "c", Latency: time.Second, Failed: true})
+	if _, latency := stats.snapshot("b"); latency != 200*time.Millisecond {
+		t.Fatalf("latency(b) = %v, want TTFT", latency)
+	}
+	if _, latency := stats.snapshot("c"); latency != 0 {
+		t.Fatalf("latency(c) = %v, want failed record ignored", latency)
+	}
+}
+
+func TestLeastInFlightSelectorPick_PrefersIdleAuth(t *testing.T) {
+	t.Parallel()
+
+	stats := newAuthLoadStats()
+	selector := &LeastInFlightSelector{stats: stats}
+	auths := []*Auth{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	stats.begin("a")
+	stats.begin("b")
+	stats.begin("b")
+
+	got, err := selector.Pick(context.Background(), "gemini", "", cliproxyexecutor.Options{}, auths)
+	if err != nil {
+		t.Fatalf("Pick() error = %v", err)
+	}
+	if got.ID != "c" {
+		t.Fatalf("Pick() auth.ID = %q, want %q", got.ID, "c")
+	}
+
+	stats.begin("c")
+	seen := make(map[string]int)
+	for i := 0; i < 4; i++ {
+		got, err = selector.Pick(context.Background(), "gemini", "", cliproxyexecutor.Options{}, auths)
+		if err != nil {
+			t.Fatalf("Pick() #%d error = %v", i, err)
+		}
+		seen[got.ID]++
+	}
+	if seen["a"] != 2 || seen["c"] != 2 {
+		t.Fatalf("tied picks = %v, want a and c alternating", seen)
+	}
+}
+
+func TestLatencySelectorPick_WeightsByInverseLatency(t *testing.T) {
+	t.Parallel()
+
+	stats := newAuthLoadStats()
+	stats.observe("fast", 100*time.Millisecond)
+	stats.observe("slow", 300*time.Millisecond)
+	auths := []*Auth{{ID: "fast"}, {ID: "slow"}}
+
+	// Weights are 10 and 3.33, so the fast auth owns the first 75% of the range.
+	for _, tc := range []struct {
+		roll float64
+		want string
+	}{
+		{0.1, "fast"},
+		{0.74, "fast"},
+		{0.76, "slow"},
+	} {
+		roll := tc.roll
+		selector := &LatencySelector{stats: stats, randFloat: func() float64 { return roll }}
+		got, err := selector.Pick(context.Background(), "gemini", "", cliproxyexecutor.Options{}, auths)
+		if err != nil {
+			t.Fatalf("Pick() error = %v", err)
+		}
+		if got.ID != tc.want {
+			t.Fatalf("Pick() roll %.2f auth.ID = %q, want %q", tc.roll, got.ID, tc.want)
+		}
+	}
+
+	// Three requests in flight quarter the fast auth's weight: 2.5 vs 3.33.
+	for i := 0; i < 3; i++ {
+		stats.begin("fast")
+	}
+	selector := &LatencySelector{stats: stats, randFloat: func() float64 { return 0.5 }}
+	got, err := selector.Pick(context.Background(), "gemini", "", cliproxyexecutor.Options{}, auths)
+	if err != nil {
+		t.Fatalf("Pick() error = %v", err)
+	}
+	if got.ID != "slow" {
+		t.Fatalf("Pick() with busy fast auth = %q, want %q", got.ID, "slow")
+	}
+}
+
+func TestPriorityShareSelectorPick_SplitsByPriority(t *testing.T) {
+	t.Parallel()
+
+	selector := &PriorityShareSelector{}
+	auths := []*Auth{
+		{ID: "a", Attributes: map[string]string{"priority": "3"}},
+		{ID: "b", Attributes: map[string]string{"priority": "1"}},
+		{ID: "z"},
+	}
+
+	want := []string{"a", "a", "b", "a", "a", "a", "b", "a"}
+	for i, id := range want {
+		got, err := selector.Pick(context.Background(), "gemini", "", cliproxyexecutor.Options{}, auths)
+		if err != nil {
+			t.Fatalf("Pick() #%d error = %v", i, err)
+		}
+		if got.ID != id {
+			t.Fatalf("Pick() #%d auth.ID = %q, want %q", i, got.ID, id)
+		}
+	}
+
+	unweighted := []*Auth{{ID: "x"}, {ID: "y"}}
+	first, _ := selector.Pick(context.Background(), "gemini", "m", cliproxyexecutor.Options{}, unweighted)
+	second, _ := selector.Pick(context.Background(), "gemini", "m", cliproxyexecutor.Options{}, unweighted)
+	if first == nil || second == nil || first.ID == second.ID {
+		t.Fatalf("unweighted picks = %v, %v, want even rotation", first, second)
+	}
+}
+
+func TestManagerExecuteStream_ReleasesInFlightWhenDrained(t *testing.T) {
+	m := NewManager(nil, &LeastInFlightSelector{}, nil)
+	executor := &modelFallbackExecutor{id: "load-stream"}
+	registerModelFallbackAuth(t, m, executor, "load-stream-model")
+	authID := executor.id + "-auth-" + t.Name()
+
+	result, err := m.ExecuteStream(context.Background(), []string{executor.id}, cliproxyexecutor.Request{Model: "load-stream-model"}, cliproxyexecutor.Options{})
+	if err != nil {
+		t.Fatalf("ExecuteStream() error = %v", err)
+	}
+	for range result.Chunks {
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		inFlight, _ := defaultAuthLoad.snapshot(authID)
+		if inFlight == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("inFlight = %d after stream drained, want 0", inFlight)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	coreusage "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/usage"
+)
+
+// authLatencyEWMAAlpha weights the newest latency sample in the moving average.
+const authLatencyEWMAAlpha = 0.3
+
+func init() {
+	coreusage.RegisterPlugin(&loadUsagePlugin{stats: defaultAuthLoad})
+}
+
+// defaultAuthLoad is shared by the conductor, which maintains in-flight counters,
+// and the load-aware selectors, which read them.
+var defaultAuthLoad = newAuthLoadStats()
+
+// authLoadStats tracks per-auth in-flight requests and an exponentially weighted
+// moving average of upstream latency.
+type authLoadStats struct {
+	mu      sync.Mutex
+	entries map[string]*authLoad
+}
+
+type authLoad struct {
+	inFlight int
+	latency  time.Duration
+}
+
+func newAuthLoadStats() *authLoadStats {
+	return &authLoadStats{entries: make(map[string]*authLoad)}
+}
+
+// begin counts a request against authID and returns the function that releases it.
+// The returned function is safe to call more than once.
+func (s *authLoadStats) begin(authID string) func() {
+	if s == nil || authID == "" {
+		return func() {}
+	}
+	s.mu.Lock()
+	s.entryLocked(authID).inFlight++
+	s.mu.Unlock()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			if entry := s.entries[authID]; entry != nil && entry.inFlight > 0 {
+				entry.inFlight--
+			}
+			s.mu.Unlock()
+		})
+	}
+}
+
+// observe folds a latency sample for authID into its moving average.
+func (s *authLoadStats) observe(authID string, latency time.Duration) {
+	if s == nil || authID == "" || latency <= 0 {
+		return
+	}
+	s.mu.Lock()
+	entry := s.entryLocked(authID)
+	if entry.latency <= 0 {
+		entry.latency = latency
+	} else {
+		entry.latency = time.Duration(authLatencyEWMAAlpha*float64(latency) + (1-authLatencyEWMAAlpha)*float64(entry.latency))
+	}
+	s.mu.Unlock()
+}
+
+// snapshot returns the in-flight count and latency average for authID. The
+// latency is zero when no sample has been observed yet.
+func (s *authLoadStats) snapshot(authID string) (int, time.Duration) {
+	if s == nil {
+		return 0, 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := s.entries[authID]
+	if entry == nil {
+		return 0, 0
+	}
+	return entry.inFlight, entry.latency
+}
+
+// forget drops the stats of a removed auth.
+func (s *authLoadStats) forget(authID string) {
+	if s == nil || authID == "" {
+		return
+	}
+	s.mu.Lock()
+	if entry := s.entries[authID]; entry != nil && entry.inFlight == 0 {
+		delete(s.entries, authID)
+	}
+	s.mu.Unlock()
+}
+
+func (s *authLoadStats) entryLocked(authID string) *authLoad {
+	entry := s.entries[authID]
+	if entry == nil {
+		entry = &authLoad{}
+		s.entries[authID] = entry
+	}
+	return entry
+}
+
+// loadUsagePlugin feeds successful request latencies into the load stats. The
+// time to first token is preferred since it does not grow with response length.
+type loadUsagePlugin struct {
+	stats *authLoadStats
+}
+
+func (p *loadUsagePlugin) HandleUsage(_ context.Context, record coreusage.Record) {
+	if p == nil || p.stats == nil || record.Failed {
+		return
+	}
+	latency := record.TTFT
+	if latency <= 0 {
+		latency = record.Latency
+	}
+	p.stats.observe(strings.TrimSpace(record.AuthID), latency)
+}
@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	internalconfig "github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/metrics"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/thinking"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/util"
+	cliproxyexecutor "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/executor"
+	coreusage "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/usage"
+	log "github.com/sirupsen/logrus"
+)
+
+// ModelFallbackChain returns the configured fallback models for model. The
+// thinking suffix of model is ignored when looking up the chain.
+func (m *Manager) ModelFallbackChain(model string) []string {
+	if m == nil {
+		return nil
+	}
+	cfg, _ := m.runtimeConfig.Load().(*internalconfig.Config)
+	if cfg == nil || len(cfg.ModelFallbacks) == 0 {
+		return nil
+	}
+	base := strings.TrimSpace(thinking.ParseSuffix(model).ModelName)
+	if base == "" {
+		base = strings.TrimSpace(model)
+	}
+	return cfg.ModelFallbackChain(base)
+}
+
+// shouldAttemptModelFallback reports whether lastErr means the requested model
+// had no usable credential. Errors caused by the request itself are returned
+// to the client as-is since another model would most likely reject it too.
+func shouldAttemptModelFallback(ctx context.Context, lastErr error) bool {
+	if lastErr == nil || ctx.Err() != nil {
+		return false
+	}
+	if isRequestInvalidError(lastErr) {
+		return false
+	}
+	switch status := statusCodeFromError(lastErr); status {
+	case 0, http.StatusUnauthorized, http.StatusPaymentRequired, http.StatusForbidden,
+		http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return true
+	case http.StatusBadRequest, http.StatusNotFound:
+		return isModelSupportError(lastErr)
+	default:
+		return status >= http.StatusInternalServerError
+	}
+}
+
+// modelFallbackTarget is one resolved step of a fallback chain.
+type modelFallbackTarget struct {
+	model     string
+	providers []string
+}
+
+// modelFallbackTargets resolves the fallback chain for requestedModel into
+// models with at least one provider. A thinking suffix on the requested model
+// is carried over to fallbacks that do not declare their own.
+func (m *Manager) modelFallbackTargets(requestedModel string) []modelFallbackTarget {
+	chain := m.ModelFallbackChain(requestedModel)
+	if len(chain) == 0 {
+		return nil
+	}
+	requested := thinking.ParseSuffix(requestedModel)
+	targets := make([]modelFallbackTarget, 0, len(chain))
+	for _, fallback := range chain {
+		parsed := thinking.ParseSuffix(fallback)
+		model := fallback
+		if requested.HasSuffix && !parsed.HasSuffix {
+			model = fmt.Sprintf("%s(%s)", fallback, requested.RawSuffix)
+		}
+		var providers []string
+		if m.HomeEnabled() {
+			providers = []string{"home"}
+		} else {
+			providers = m.normalizeProviders(util.GetProviderName(parsed.ModelName))
+		}
+		if len(providers) == 0 {
+			log.Debugf("model fallback: skipping %s for %s, no provider serves it", fallback, requestedModel)
+			continue
+		}
+		targets = append(targets, modelFallbackTarget{model: model, providers: providers})
+	}
+	return targets
+}
+
+// prepareModelFallback rewrites the request for a fallback model. The fallback
+// becomes the requested model for alias and payload resolution while the
+// usage record keeps the original model in FallbackFrom.
+func prepareModelFallback(ctx context.Context, req cliproxyexecutor.Request, opts cliproxyexecutor.Options, originalModel, fallbackModel string) (context.Context, cliproxyexecutor.Request, cliproxyexecutor.Options) {
+	fallbackFrom := requestedModelAliasFromOptions(opts, originalModel)
+	meta := make(map[string]any, len(opts.Metadata)+1)
+	for k, v := range opts.Metadata {
+		meta[k] = v
+	}
+	meta[cliproxyexecutor.RequestedModelMetadataKey] = fallbackModel
+	opts.Metadata = meta
+	req.Model = fallbackModel
+	return coreusage.WithFallbackFrom(ctx, fallbackFrom), req, opts
+}
+
+func (m *Manager) tryModelFallbacksExecute(ctx context.Context, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (cliproxyexecutor.Response, bool) {
+	originalModel := req.Model
+	for _, target := range m.modelFallbackTargets(originalModel) {
+		if ctx.Err() != nil {
+			return cliproxyexecutor.Response{}, false
+		}
+		fallbackCtx, fallbackReq, fallbackOpts := prepareModelFallback(ctx, req, opts, originalModel, target.model)
+		resp, errExec, errWait := m.executeWithRetry(fallbackCtx, target.providers, fallbackReq, fallbackOpts)
+		if errWait != nil {
+			return cliproxyexecutor.Response{}, false
+		}
+		if errExec != nil {
+			log.Debugf("model fallback: %s -> %s failed: %v", originalModel, target.model, errExec)
+			continue
+		}
+		log.Infof("model fallback: served %s with %s", originalModel, target.model)
+		metrics.ModelFallbacks.WithLabelValues(originalModel, target.model).Inc()
+		return resp, true
+	}
+	return cliproxyexecutor.Response{}, false
+}
+
+func (m *Manager) tryModelFallbacksExecuteCount(ctx context.Context, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (cliproxyexecutor.Response, bool) {
+	originalModel := req.Model
+	for _, target := range m.modelFallbackTargets(originalModel) {
+		if ctx.Err() != nil {
+			return cliproxyexecutor.Response{}, false
+		}
+		fallbackCtx, fallbackReq, fallbackOpts := prepareModelFallback(ctx, req, opts, originalModel, target.model)
+		resp, errExec, errWait := m.executeCountWithRetry(fallbackCtx, target.providers, fallbackReq, fallbackOpts)
+		if errWait != nil {
+			return cliproxyexecutor.Response{}, false
+		}
+		if errExec != nil {
+			log.Debugf("model fallback: %s -> %s count failed: %v", originalModel, target.model, errExec)
+			continue
+		}
+		return resp, true
+	}
+	return cliproxyexecutor.Response{}, false
+}
+
+func (m *Manager) tryModelFallbacksExecuteStream(ctx context.Context, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (*cliproxyexecutor.StreamResult, bool) {
+	originalModel := req.Model
+	for _, target := range m.modelFallbackTargets(originalModel) {
+		if ctx.Err() != nil {
+			return nil, false
+		}
+		fallbackCtx, fallbackReq, fallbackOpts := prepareModelFallback(ctx, req, opts, originalModel, target.model)
+		result, errStream, errWait := m.executeStreamWithRetry(fallbackCtx, target.providers, fallbackReq, fallbackOpts)
+		if errWait != nil {
+			return nil, false
+		}
+		if errStream != nil {
+			log.Debugf("model fallback: %s -> %s failed: %v", originalModel, target.model, errStream)
+			continue
+		}
+		log.Infof("model fallback: served %s with %s", originalModel, target.model)
+		metrics.ModelFallbacks.WithLabelValues(originalModel, target.model).Inc()
+		return result, true
+	}
+	return nil, false
+}
@@ -0,0 +1,198 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	internalconfig "github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/registry"
+	cliproxyexecutor "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/executor"
+	coreusage "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/usage"
+)
+
+type modelFallbackCall struct {
+	model          string
+	requestedModel string
+	fallbackFrom   string
+}
+
+type modelFallbackExecutor struct {
+	id  string
+	err error
+
+	mu    sync.Mutex
+	calls []modelFallbackCall
+}
+
+func (e *modelFallbackExecutor) Identifier() string { return e.id }
+
+func (e *modelFallbackExecutor) record(ctx context.Context, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) {
+	requested, _ := opts.Metadata[cliproxyexecutor.RequestedModelMetadataKey].(string)
+	e.mu.Lock()
+	e.calls = append(e.calls, modelFallbackCall{
+		model:          req.Model,
+		requestedModel: requested,
+		fallbackFrom:   coreusage.FallbackFromContext(ctx),
+	})
+	e.mu.Unlock()
+}
+
+func (e *modelFallbackExecutor) Calls() []modelFallbackCall {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]modelFallbackCall(nil), e.calls...)
+}
+
+func (e *modelFallbackExecutor) Execute(ctx context.Context, _ *Auth, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (cliproxyexecutor.Response, error) {
+	e.record(ctx, req, opts)
+	if e.err != nil {
+		return cliproxyexecutor.Response{}, e.err
+	}
+	return cliproxyexecutor.Response{Payload: []byte(e.id + ":" + req.Model)}, nil
+}
+
+func (e *modelFallbackExecutor) ExecuteStream(ctx context.Context, _ *Auth, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (*cliproxyexecutor.StreamResult, error) {
+	e.record(ctx, req, opts)
+	if e.err != nil {
+		return nil, e.err
+	}
+	ch := make(chan cliproxyexecutor.StreamChunk, 1)
+	ch <- cliproxyexecutor.StreamChunk{Payload: []byte(e.id + ":" + req.Model)}
+	close(ch)
+	return &cliproxyexecutor.StreamResult{Chunks: ch}, nil
+}
+
+func (e *modelFallbackExecutor) Refresh(_ context.Context, auth *Auth) (*Auth, error) {
+	return auth, nil
+}
+
+func (e *modelFallbackExecutor) CountTokens(ctx context.Context, auth *Auth, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (cliproxyexecutor.Response, error) {
+	return e.Execute(ctx, auth, req, opts)
+}
+
+func (e *modelFallbackExecutor) HttpRequest(context.Context, *Auth, *http.Request) (*http.Response, error) {
+	return nil, &Error{HTTPStatus: http.StatusNotImplemented, Message: "HttpRequest not implemented"}
+}
+
+func registerModelFallbackAuth(t *testing.T, m *Manager, executor *modelFallbackExecutor, model string) {
+	t.Helper()
+	m.RegisterExecutor(executor)
+	auth := &Auth{ID: executor.id + "-auth-" + t.Name(), Provider: executor.id, Status: StatusActive}
+	if _, err := m.Register(context.Background(), auth); err != nil {
+		t.Fatalf("register auth: %v", err)
+	}
+	reg := registry.GetGlobalRegistry()
+	reg.RegisterClient(auth.ID, executor.id, []*registry.ModelInfo{{ID: model}})
+	t.Cleanup(func() {
+		reg.UnregisterClient(auth.ID)
+	})
+}
+
+func newModelFallbackTestManager(t *testing.T, primaryErr error) (*Manager, *modelFallbackExecutor, *modelFallbackExecutor) {
+	t.Helper()
+	m := NewManager(nil, nil, nil)
+	m.SetConfig(&internalconfig.Config{
+		ModelFallbacks: []internalconfig.ModelFallback{{
+			Model:     "fb-primary-model",
+			Fallbacks: []string{"fb-unserved-model", "fb-backup-model"},
+		}},
+	})
+	primary := &modelFallbackExecutor{id: "fb-primary", err: primaryErr}
+	backup := &modelFallbackExecutor{id: "fb-backup"}
+	registerModelFallbackAuth(t, m, primary, "fb-primary-model")
+	registerModelFallbackAuth(t, m, backup, "fb-backup-model")
+	return m, primary, backup
+}
+
+func TestManagerExecute_ModelFallbackServesFromNextProvider(t *testing.T) {
+	m, primary, backup := newModelFallbackTestManager(t, &Error{HTTPStatus: http.StatusTooManyRequests, Message: "quota exhausted"})
+
+	opts := cliproxyexecutor.Options{Metadata: map[string]any{cliproxyexecutor.RequestedModelMetadataKey: "fb-primary-model"}}
+	resp, err := m.Execute(context.Background(), []string{"fb-primary"}, cliproxyexecutor.Request{Model: "fb-primary-model"}, opts)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got := string(resp.Payload); got != "fb-backup:fb-backup-model" {
+		t.Fatalf("payload = %q, want fallback response", got)
+	}
+	if calls := primary.Calls(); len(calls) != 1 || calls[0].fallbackFrom != "" {
+		t.Fatalf("primary calls = %+v, want one non-fallback attempt", calls)
+	}
+	calls := backup.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("backup calls = %+v, want 1", calls)
+	}
+	if calls[0].fallbackFrom != "fb-primary-model" || calls[0].requestedModel != "fb-backup-model" {
+		t.Fatalf("backup call = %+v, want fallback from fb-primary-model", calls[0])
+	}
+	if got := opts.Metadata[cliproxyexecutor.RequestedModelMetadataKey]; got != "fb-primary-model" {
+		t.Fatalf("caller metadata mutated to %v", got)
+	}
+}
+
+func TestManagerExecuteStream_ModelFallbackKeepsThinkingSuffix(t *testing.T) {
+	m, _, backup := newModelFallbackTestManager(t, &Error{HTTPStatus: http.StatusServiceUnavailable, Message: "overloaded"})
+
+	result, err := m.ExecuteStream(context.Background(), []string{"fb-primary"}, cliproxyexecutor.Request{Model: "fb-primary-model(high)"}, cliproxyexecutor.Options{})
+	if err != nil {
+		t.Fatalf("ExecuteStream() error = %v", err)
+	}
+	var payload string
+	for chunk := range result.Chunks {
+		if chunk.Err != nil {
+			t.Fatalf("stream chunk error = %v", chunk.Err)
+		}
+		payload += string(chunk.Payload)
+	}
+	if payload != "fb-backup:fb-backup-model(high)" {
+		t.Fatalf("payload = %q, want fallback stream with suffix", payload)
+	}
+	if calls := backup.Calls(); len(calls) != 1 || calls[0].fallbackFrom != "fb-primary-model(high)" {
+		t.Fatalf("backup calls = %+v", calls)
+	}
+}
+
+func TestManagerExecute_ModelFallbackWithoutOwnProviders(t *testing.T) {
+	m, primary, backup := newModelFallbackTestManager(t, nil)
+
+	resp, err := m.Execute(context.Background(), nil, cliproxyexecutor.Request{Model: "fb-primary-model"}, cliproxyexecutor.Options{})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got := string(resp.Payload); got != "fb-backup:fb-backup-model" {
+		t.Fatalf("payload = %q, want fallback response", got)
+	}
+	if len(primary.Calls()) != 0 || len(backup.Calls()) != 1 {
+		t.Fatalf("calls primary=%d backup=%d, want 0 and 1", len(primary.Calls()), len(backup.Calls()))
+	}
+}
+
+func TestManagerExecuteCount_ModelFallbackWithoutOwnProviders(t *testing.T) {
+	m, primary, backup := newModelFallbackTestManager(t, nil)
+
+	resp, err := m.ExecuteCount(context.Background(), nil, cliproxyexecutor.Request{Model: "fb-primary-model"}, cliproxyexecutor.Options{})
+	if err != nil {
+		t.Fatalf("ExecuteCount() error = %v", err)
+	}
+	if got := string(resp.Payload); got != "fb-backup:fb-backup-model" {
+		t.Fatalf("payload = %q, want count from the fallback model", got)
+	}
+	if len(primary.Calls()) != 0 || len(backup.Calls()) != 1 {
+		t.Fatalf("calls primary=%d backup=%d, want 0 and 1", len(primary.Calls()), len(backup.Calls()))
+	}
+}
+
+func TestManagerExecute_ModelFallbackSkipsInvalidRequest(t *testing.T) {
+	invalidErr := &Error{HTTPStatus: http.StatusUnprocessableEntity, Message: "unprocessable entity"}
+	m, _, backup := newModelFallbackTestManager(t, invalidErr)
+
+	_, err := m.Execute(context.Background(), []string{"fb-primary"}, cliproxyexecutor.Request{Model: "fb-primary-model"}, cliproxyexecutor.Options{})
+	if err == nil || err.Error() != invalidErr.Error() {
+		t.Fatalf("Execute() error = %v, want %v", err, invalidErr)
+	}
+	if calls := backup.Calls(); len(calls) != 0 {
+		t.Fatalf("backup calls = %+v, want none", calls)
+	}
+}
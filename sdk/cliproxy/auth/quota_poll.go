@@ -0,0 +1,260 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	internalconfig "github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultQuotaPollInterval     = 5 * time.Minute
+	defaultQuotaLowThreshold     = 0.1
+	quotaPollTimeout             = 30 * time.Second
+	quotaPollMaxConcurrency      = 4
+	quotaLowPriorityPenalty  int = 1000
+)
+
+// QuotaPoller is implemented by executors that can query the remaining upstream
+// quota of a credential without spending it.
+type QuotaPoller interface {
+	// PollQuota returns the current quota windows of auth. A nil snapshot with a
+	// nil error means the credential type does not expose quota information.
+	PollQuota(ctx context.Context, auth *Auth) (*QuotaSnapshot, error)
+}
+
+// QuotaWindow is one upstream usage limit reported by a provider.
+type QuotaWindow struct {
+	// Name identifies the limit, e.g. "primary", "weekly" or a token type.
+	Name string `json:"name"`
+	// Model scopes the limit to one model. Empty means the whole credential.
+	Model string `json:"model,omitempty"`
+	// RemainingFraction is the unused share of the limit between 0 and 1.
+	RemainingFraction float64 `json:"remaining_fraction"`
+	// ResetAt is when the limit refills, if known.
+	ResetAt time.Time `json:"reset_at,omitempty"`
+}
+
+// QuotaSnapshot is the most recent result of polling a credential's quota.
+type QuotaSnapshot struct {
+	Windows []QuotaWindow `json:"windows,omitempty"`
+	// Low reports that a credential-wide window is at or below the configured threshold.
+	Low bool `json:"low,omitempty"`
+	// PolledAt is when the snapshot was taken.
+	PolledAt time.Time `json:"polled_at"`
+	// Error holds the last polling failure; Windows keeps the previous values then.
+	Error string `json:"error,omitempty"`
+}
+
+// Clone returns a deep copy of the snapshot.
+func (s *QuotaSnapshot) Clone() *QuotaSnapshot {
+	if s == nil {
+		return nil
+	}
+	copySnapshot := *s
+	copySnapshot.Windows = append([]QuotaWindow(nil), s.Windows...)
+	return &copySnapshot
+}
+
+// quotaLow reports whether the snapshot marks its credential as nearly exhausted.
+func quotaLow(auth *Auth) bool {
+	return auth != nil && auth.QuotaSnapshot != nil && auth.QuotaSnapshot.Low
+}
+
+// modelQuotaLow reports whether polling found the per-model quota of model low.
+func modelQuotaLow(auth *Auth, model string) bool {
+	if auth == nil || model == "" || len(auth.ModelStates) == 0 {
+		return false
+	}
+	state := auth.ModelStates[model]
+	if state == nil {
+		if baseModel := canonicalModelKey(model); baseModel != "" && baseModel != model {
+			state = auth.ModelStates[baseModel]
+		}
+	}
+	return state != nil && state.QuotaLow
+}
+
+// StartQuotaPolling launches a background loop that polls the remaining quota
+// of every credential whose executor implements QuotaPoller. Starting a new
+// loop cancels the previous one.
+func (m *Manager) StartQuotaPolling(parent context.Context, interval time.Duration) {
+	if m == nil {
+		return
+	}
+	if interval <= 0 {
+		interval = defaultQuotaPollInterval
+	}
+	ctx, cancel := context.WithCancel(parent)
+	m.mu.Lock()
+	cancelPrev := m.quotaPollCancel
+	m.quotaPollCancel = cancel
+	m.mu.Unlock()
+	if cancelPrev != nil {
+		cancelPrev()
+	}
+	go m.runQuotaPolling(ctx, interval)
+}
+
+// StopQuotaPolling cancels the background quota poller, if running.
+func (m *Manager) StopQuotaPolling() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	cancel := m.quotaPollCancel
+	m.quotaPollCancel = nil
+	m.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+func (m *Manager) runQuotaPolling(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		m.PollQuotas(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PollQuotas polls every enabled credential once and records the results.
+func (m *Manager) PollQuotas(ctx context.Context) {
+	if m == nil {
+		return
+	}
+	type pollTarget struct {
+		auth   *Auth
+		poller QuotaPoller
+	}
+	var targets []pollTarget
+	m.mu.RLock()
+	for _, auth := range m.auths {
+		if auth == nil || auth.Disabled || auth.Status == StatusDisabled {
+			continue
+		}
+		poller, ok := m.executors[executorKeyFromAuth(auth)].(QuotaPoller)
+		if !ok || poller == nil {
+			continue
+		}
+		targets = append(targets, pollTarget{auth: auth.Clone(), poller: poller})
+	}
+	m.mu.RUnlock()
+
+	sem := make(chan struct{}, quotaPollMaxConcurrency)
+	var wg sync.WaitGroup
+	for _, target := range targets {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(target pollTarget) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			pollCtx, cancel := context.WithTimeout(ctx, quotaPollTimeout)
+			defer cancel()
+			if rt := m.roundTripperFor(target.auth); rt != nil {
+				pollCtx = context.WithValue(pollCtx, roundTripperContextKey{}, rt)
+				pollCtx = context.WithValue(pollCtx, "cliproxy.roundtripper", rt)
+			}
+			snapshot, err := target.poller.PollQuota(pollCtx, target.auth)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Debugf("quota poll: auth %s failed: %v", target.auth.ID, err)
+				m.recordQuotaSnapshot(target.auth.ID, nil, err)
+				return
+			}
+			if snapshot != nil {
+				m.recordQuotaSnapshot(target.auth.ID, snapshot, nil)
+			}
+		}(target)
+	}
+	wg.Wait()
+}
+
+// recordQuotaSnapshot stores a polling result on the auth. Models reported as
+// exhausted enter the same cooldown a 429 would cause, lasting until their reset.
+func (m *Manager) recordQuotaSnapshot(authID string, snapshot *QuotaSnapshot, errPoll error) {
+	threshold := defaultQuotaLowThreshold
+	if cfg, ok := m.runtimeConfig.Load().(*internalconfig.Config); ok && cfg != nil && cfg.QuotaPolling.LowThreshold > 0 {
+		threshold = cfg.QuotaPolling.LowThreshold
+	}
+	now := time.Now()
+
+	var authSnapshot *Auth
+	m.mu.Lock()
+	auth := m.auths[authID]
+	if auth == nil {
+		m.mu.Unlock()
+		return
+	}
+	if errPoll != nil {
+		next := auth.QuotaSnapshot.Clone()
+		if next == nil {
+			next = &QuotaSnapshot{}
+		}
+		next.PolledAt = now
+		next.Error = errPoll.Error()
+		auth.QuotaSnapshot = next
+		m.mu.Unlock()
+		return
+	}
+	next := snapshot.Clone()
+	next.PolledAt = now
+	next.Low = false
+	lowModels := make(map[string]bool)
+	for _, window := range next.Windows {
+		model := strings.TrimSpace(window.Model)
+		if window.RemainingFraction <= threshold {
+			if model == "" {
+				next.Low = true
+			} else {
+				lowModels[model] = true
+			}
+		}
+		if model == "" || window.RemainingFraction > 0 || !window.ResetAt.After(now) || quotaCooldownDisabledForAuth(auth) {
+			continue
+		}
+		state := ensureModelState(auth, model)
+		state.Unavailable = true
+		state.Status = StatusError
+		state.StatusMessage = "quota exhausted"
+		state.NextRetryAfter = window.ResetAt
+		state.Quota = QuotaState{
+			Exceeded:      true,
+			Reason:        "quota",
+			NextRecoverAt: window.ResetAt,
+			BackoffLevel:  state.Quota.BackoffLevel,
+		}
+		state.UpdatedAt = now
+		updateAggregatedAvailability(auth, now)
+	}
+	for model, state := range auth.ModelStates {
+		if state != nil {
+			state.QuotaLow = lowModels[model]
+		}
+	}
+	for model := range lowModels {
+		ensureModelState(auth, model).QuotaLow = true
+	}
+	auth.QuotaSnapshot = next
+	authSnapshot = auth.Clone()
+	m.mu.Unlock()
+
+	if m.scheduler != nil {
+		m.scheduler.upsertAuth(authSnapshot)
+	}
+}
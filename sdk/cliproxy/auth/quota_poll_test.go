@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	internalconfig "github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+)
+
+type quotaPollingExecutor struct {
+	modelFallbackExecutor
+	snapshot *QuotaSnapshot
+	err      error
+}
+
+func (e *quotaPollingExecutor) PollQuota(context.Context, *Auth) (*QuotaSnapshot, error) {
+	return e.snapshot, e.err
+}
+
+func TestManagerPollQuotas_RecordsSnapshotAndCoolsExhaustedModel(t *testing.T) {
+	resetAt := time.Now().Add(time.Hour)
+	executor := &quotaPollingExecutor{
+		modelFallbackExecutor: modelFallbackExecutor{id: "quota-poll"},
+		snapshot: &QuotaSnapshot{Windows: []QuotaWindow{
+			{Name: "primary", RemainingFraction: 0.05},
+			{Name: "requests", Model: "quota-model", ResetAt: resetAt},
+		}},
+	}
+	m := NewManager(nil, nil, nil)
+	m.SetConfig(&internalconfig.Config{})
+	m.RegisterExecutor(executor)
+	auth := &Auth{ID: "quota-auth", Provider: executor.id, Status: StatusActive, Attributes: map[string]string{"priority": "2"}}
+	if _, err := m.Register(context.Background(), auth); err != nil {
+		t.Fatalf("register auth: %v", err)
+	}
+
+	m.PollQuotas(context.Background())
+
+	got, ok := m.GetByID(auth.ID)
+	if !ok || got.QuotaSnapshot == nil {
+		t.Fatalf("auth quota snapshot missing: %+v", got)
+	}
+	if !got.QuotaSnapshot.Low || got.QuotaSnapshot.PolledAt.IsZero() || len(got.QuotaSnapshot.Windows) != 2 {
+		t.Fatalf("snapshot = %+v, want low with 2 windows", got.QuotaSnapshot)
+	}
+	if priority := authPriority(got); priority != 2-quotaLowPriorityPenalty {
+		t.Fatalf("authPriority = %d, want deprioritized", priority)
+	}
+	blocked, reason, next := isAuthBlockedForModel(got, "quota-model", time.Now())
+	if !blocked || reason != blockReasonCooldown || !next.Equal(resetAt) {
+		t.Fatalf("quota-model blocked=%v reason=%v next=%v, want cooldown until reset", blocked, reason, next)
+	}
+	if blocked, _, _ = isAuthBlockedForModel(got, "other-model", time.Now()); blocked {
+		t.Fatal("other-model blocked, want only the exhausted model cooled down")
+	}
+
+	executor.snapshot, executor.err = nil, errors.New("upstream unavailable")
+	m.PollQuotas(context.Background())
+	got, _ = m.GetByID(auth.ID)
+	if got.QuotaSnapshot.Error != "upstream unavailable" || len(got.QuotaSnapshot.Windows) != 2 {
+		t.Fatalf("snapshot after failure = %+v, want error with previous windows", got.QuotaSnapshot)
+	}
+}
+
+func TestManagerPollQuotas_DeprioritizesLowModelOnly(t *testing.T) {
+	executor := &quotaPollingExecutor{
+		modelFallbackExecutor: modelFallbackExecutor{id: "quota-poll-model"},
+		snapshot: &QuotaSnapshot{Windows: []QuotaWindow{
+			{Name: "requests", Model: "low-model", RemainingFraction: 0.05},
+			{Name: "requests", Model: "healthy-model", RemainingFraction: 0.8},
+		}},
+	}
+	m := NewManager(nil, nil, nil)
+	m.SetConfig(&internalconfig.Config{})
+	m.RegisterExecutor(executor)
+	low := &Auth{ID: "quota-low-auth", Provider: executor.id, Status: StatusActive, Attributes: map[string]string{"priority": "2"}}
+	other := &Auth{ID: "quota-other-auth", Provider: executor.id, Status: StatusActive, Attributes: map[string]string{"priority": "1"}}
+	if _, err := m.Register(context.Background(), low); err != nil {
+		t.Fatalf("register auth: %v", err)
+	}
+	m.PollQuotas(context.Background())
+	if _, err := m.Register(context.Background(), other); err != nil {
+		t.Fatalf("register auth: %v", err)
+	}
+
+	got, _ := m.GetByID(low.ID)
+	if got.QuotaSnapshot == nil || got.QuotaSnapshot.Low {
+		t.Fatalf("snapshot = %+v, want per-model windows not to flag the whole auth", got.QuotaSnapshot)
+	}
+	if priority := authModelPriority(got, "low-model"); priority != 2-quotaLowPriorityPenalty {
+		t.Fatalf("authModelPriority(low-model) = %d, want deprioritized", priority)
+	}
+	if priority := authModelPriority(got, "healthy-model"); priority != 2 {
+		t.Fatalf("authModelPriority(healthy-model) = %d, want 2", priority)
+	}
+	if blocked, _, _ := isAuthBlockedForModel(got, "low-model", time.Now()); blocked {
+		t.Fatal("low-model blocked, want it usable until exhausted")
+	}
+
+	gotOther, _ := m.GetByID(other.ID)
+	available, _, _ := collectAvailableByPriority([]*Auth{got, gotOther}, "low-model", time.Now())
+	best := -1 << 31
+	for priority := range available {
+		best = max(best, priority)
+	}
+	if len(available[best]) != 1 || available[best][0].ID != other.ID {
+		t.Fatalf("highest priority candidates = %v, want %s ahead of the low auth", available[best], other.ID)
+	}
+
+	executor.snapshot = &QuotaSnapshot{Windows: []QuotaWindow{{Name: "requests", Model: "low-model", RemainingFraction: 0.9}}}
+	m.PollQuotas(context.Background())
+	got, _ = m.GetByID(low.ID)
+	if priority := authModelPriority(got, "low-model"); priority != 2 {
+		t.Fatalf("authModelPriority after recovery = %d, want 2", priority)
+	}
+}
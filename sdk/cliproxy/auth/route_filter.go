@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"strings"
+
+	cliproxyexecutor "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/executor"
+)
+
+// authRouteFilter restricts credential selection to the prefix or auth indexes
+// chosen by a routing rule. The zero value allows every credential.
+type authRouteFilter struct {
+	prefix  string
+	indexes map[string]struct{}
+}
+
+func authRouteFilterFromMetadata(meta map[string]any) authRouteFilter {
+	var filter authRouteFilter
+	if len(meta) == 0 {
+		return filter
+	}
+	if raw, ok := meta[cliproxyexecutor.AllowedAuthPrefixMetadataKey].(string); ok {
+		filter.prefix = strings.Trim(strings.TrimSpace(raw), "/")
+	}
+	var indexes []string
+	switch raw := meta[cliproxyexecutor.AllowedAuthIndexesMetadataKey].(type) {
+	case []string:
+		indexes = raw
+	case []any:
+		for _, item := range raw {
+			if value, ok := item.(string); ok {
+				indexes = append(indexes, value)
+			}
+		}
+	case string:
+		indexes = strings.Split(raw, ",")
+	}
+	for _, index := range indexes {
+		index = strings.TrimSpace(index)
+		if index == "" {
+			continue
+		}
+		if filter.indexes == nil {
+			filter.indexes = make(map[string]struct{}, len(indexes))
+		}
+		filter.indexes[index] = struct{}{}
+	}
+	return filter
+}
+
+// allows reports whether auth satisfies the filter. Auth indexes are derived
+// without mutating auth so the check is safe under the manager read lock.
+func (f authRouteFilter) allows(auth *Auth) bool {
+	if f.prefix == "" && len(f.indexes) == 0 {
+		return true
+	}
+	if auth == nil {
+		return false
+	}
+	if f.prefix != "" && !strings.EqualFold(strings.Trim(strings.TrimSpace(auth.Prefix), "/"), f.prefix) {
+		return false
+	}
+	if len(f.indexes) > 0 {
+		index := auth.Index
+		if !auth.indexAssigned || index == "" {
+			index = stableAuthIndex(auth.indexSeed())
+		}
+		if _, ok := f.indexes[index]; !ok {
+			return false
+		}
+	}
+	return true
+}
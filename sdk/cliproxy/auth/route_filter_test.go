@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	cliproxyexecutor "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/executor"
+)
+
+func TestAuthRouteFilterAllows(t *testing.T) {
+	teamA := &Auth{ID: "a", Provider: "claude", Prefix: "teamA"}
+	teamB := &Auth{ID: "b", Provider: "claude", Prefix: "teamB"}
+
+	if filter := authRouteFilterFromMetadata(nil); !filter.allows(teamA) || !filter.allows(teamB) {
+		t.Fatal("empty filter should allow every auth")
+	}
+
+	filter := authRouteFilterFromMetadata(map[string]any{cliproxyexecutor.AllowedAuthPrefixMetadataKey: "teama"})
+	if !filter.allows(teamA) || filter.allows(teamB) {
+		t.Fatal("prefix filter should only allow teamA")
+	}
+
+	indexB := teamB.Clone().EnsureIndex()
+	filter = authRouteFilterFromMetadata(map[string]any{cliproxyexecutor.AllowedAuthIndexesMetadataKey: []string{indexB}})
+	if filter.allows(teamA) || !filter.allows(teamB) {
+		t.Fatal("index filter should only allow teamB")
+	}
+	if teamB.indexAssigned {
+		t.Fatal("filter must not assign indexes on the shared auth")
+	}
+}
+
+func TestManagerExecute_RouteFilterRestrictsCredentials(t *testing.T) {
+	m, primary, _ := newModelFallbackTestManager(t, nil)
+	other := &Auth{ID: "fb-primary-other-" + t.Name(), Provider: "fb-primary", Prefix: "teamA", Status: StatusActive}
+	if _, err := m.Register(context.Background(), other); err != nil {
+		t.Fatalf("register auth: %v", err)
+	}
+	opts := cliproxyexecutor.Options{Metadata: map[string]any{cliproxyexecutor.AllowedAuthPrefixMetadataKey: "teamB"}}
+
+	_, err := m.Execute(context.Background(), []string{"fb-primary"}, cliproxyexecutor.Request{Model: "fb-primary-model"}, opts)
+	if err == nil {
+		t.Fatal("Execute() error = nil, want no credential for prefix teamB")
+	}
+	if calls := primary.Calls(); len(calls) != 0 {
+		t.Fatalf("primary calls = %+v, want none", calls)
+	}
+}
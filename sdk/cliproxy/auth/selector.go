@@ -113,7 +113,23 @@ func (e *modelCooldownError) Headers() http.Header {
 	return headers
 }
 
+// authPriority returns the configured priority of auth. Credentials the quota
+// poller reports as nearly exhausted drop behind every healthy tier.
 func authPriority(auth *Auth) int {
+	return authModelPriority(auth, "")
+}
+
+// authModelPriority returns the scheduling priority of an auth for model,
+// deprioritizing it while the auth or that model is low on quota.
+func authModelPriority(auth *Auth, model string) int {
+	priority := configuredAuthPriority(auth)
+	if quotaLow(auth) || modelQuotaLow(auth, model) {
+		priority -= quotaLowPriorityPenalty
+	}
+	return priority
+}
+
+func configuredAuthPriority(auth *Auth) int {
 	if auth == nil || auth.Attributes == nil {
 		return 0
 	}
@@ -203,7 +219,7 @@ func collectAvailableByPriority(auths []*Auth, model string, now time.Time) (ava
 		candidate := auths[i]
 		blocked, reason, next := isAuthBlockedForModel(candidate, model, now)
 		if !blocked {
-			priority := authPriority(candidate)
+			priority := authModelPriority(candidate, model)
 			available[priority] = append(available[priority], candidate)
 			continue
 		}
@@ -224,18 +240,7 @@ func getAvailableAuths(auths []*Auth, provider, model string, now time.Time) ([]
 
 	availableByPriority, cooldownCount, earliest := collectAvailableByPriority(auths, model, now)
 	if len(availableByPriority) == 0 {
-		if cooldownCount == len(auths) && !earliest.IsZero() {
-			providerForError := provider
-			if providerForError == "mixed" {
-				providerForError = ""
-			}
-			resetIn := earliest.Sub(now)
-			if resetIn < 0 {
-				resetIn = 0
-			}
-			return nil, newModelCooldownError(model, providerForError, resetIn)
-		}
-		return nil, &Error{Code: "auth_unavailable", Message: "no auth available"}
+		return nil, noAvailableAuthError(len(auths), cooldownCount, earliest, provider, model, now)
 	}
 
 	bestPriority := 0
@@ -254,6 +259,23 @@ func getAvailableAuths(auths []*Auth, provider, model string, now time.Time) ([]
 	return available, nil
 }
 
+// noAvailableAuthError reports a cooldown error when every candidate is cooling
+// down and a generic unavailable error otherwise.
+func noAvailableAuthError(total, cooldownCount int, earliest time.Time, provider, model string, now time.Time) error {
+	if cooldownCount == total && !earliest.IsZero() {
+		providerForError := provider
+		if providerForError == "mixed" {
+			providerForError = ""
+		}
+		resetIn := earliest.Sub(now)
+		if resetIn < 0 {
+			resetIn = 0
+		}
+		return newModelCooldownError(model, providerForError, resetIn)
+	}
+	return &Error{Code: "auth_unavailable", Message: "no auth available"}
+}
+
 // Pick selects the next available auth for the provider in a round-robin manner.
 // For gemini-cli virtual auths (identified by the gemini_virtual_parent attribute),
 // a two-level round-robin is used: first cycling across credential groups (parent
@@ -375,6 +397,9 @@ func isAuthBlockedForModel(auth *Auth, model string, now time.Time) (bool, block
 	if auth.Disabled || auth.Status == StatusDisabled {
 		return true, blockReasonDisabled, time.Time{}
 	}
+	if authQuarantined(auth) {
+		return true, blockReasonOther, time.Time{}
+	}
 	if model != "" {
 		if len(auth.ModelStates) > 0 {
 			state, ok := auth.ModelStates[model]
@@ -1,17 +1,38 @@
 package auth
 
 import (
+	"context"
 	"sync"
 	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/home"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/sharedstate"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// sessionKVKeyPrefix namespaces session bindings in the shared state store.
+	sessionKVKeyPrefix = "cpa:affinity:"
+	// sessionKVLookupTimeout bounds the shared store lookup made on the selection
+	// path when a session is not cached locally.
+	sessionKVLookupTimeout = 250 * time.Millisecond
+	// sessionKVWriteTimeout bounds the asynchronous shared store writes.
+	sessionKVWriteTimeout = 2 * time.Second
 )
 
 // sessionEntry stores auth binding with expiration.
 type sessionEntry struct {
 	authID    string
 	expiresAt time.Time
+	// sharedRefreshedAt is when the shared store TTL was last pushed.
+	sharedRefreshedAt time.Time
 }
 
 // SessionCache provides TTL-based session to auth mapping with automatic cleanup.
+// When a shared state store is configured, the local map stays the primary cache:
+// bindings are written through to the store asynchronously, and the store is only
+// consulted when a session is not cached locally, so a session that moves to
+// another replica keeps its auth without putting the store on every request.
 type SessionCache struct {
 	mu      sync.RWMutex
 	entries map[string]sessionEntry
@@ -43,16 +64,16 @@ func (c *SessionCache) Get(sessionID string) (string, bool) {
 	c.mu.RLock()
 	entry, ok := c.entries[sessionID]
 	c.mu.RUnlock()
-	if !ok {
-		return "", false
-	}
-	if time.Now().After(entry.expiresAt) {
+	if ok && time.Now().After(entry.expiresAt) {
 		c.mu.Lock()
 		delete(c.entries, sessionID)
 		c.mu.Unlock()
-		return "", false
+		ok = false
 	}
-	return entry.authID, true
+	if ok {
+		return entry.authID, true
+	}
+	return c.sharedGet(sessionID)
 }
 
 // GetAndRefresh retrieves the auth ID bound to a session and refreshes TTL on hit.
@@ -64,19 +85,28 @@ func (c *SessionCache) GetAndRefresh(sessionID string) (string, bool) {
 	now := time.Now()
 	c.mu.Lock()
 	entry, ok := c.entries[sessionID]
-	if !ok {
-		c.mu.Unlock()
-		return "", false
-	}
-	if now.After(entry.expiresAt) {
+	if ok && now.After(entry.expiresAt) {
 		delete(c.entries, sessionID)
+		ok = false
+	}
+	if !ok {
 		c.mu.Unlock()
-		return "", false
+		return c.sharedGet(sessionID)
 	}
 	// Refresh TTL on successful access
 	entry.expiresAt = now.Add(c.ttl)
+	refreshShared := now.Sub(entry.sharedRefreshedAt) >= c.ttl/4
+	if refreshShared {
+		entry.sharedRefreshedAt = now
+	}
 	c.entries[sessionID] = entry
 	c.mu.Unlock()
+	if refreshShared {
+		c.sharedWrite(func(ctx context.Context, store sharedstate.Store) error {
+			_, errExpire := store.KVExpire(ctx, sessionKVKey(sessionID), c.ttl)
+			return errExpire
+		})
+	}
 	return entry.authID, true
 }
 
@@ -85,15 +115,22 @@ func (c *SessionCache) Set(sessionID, authID string) {
 	if sessionID == "" || authID == "" {
 		return
 	}
+	now := time.Now()
 	c.mu.Lock()
 	c.entries[sessionID] = sessionEntry{
-		authID:    authID,
-		expiresAt: time.Now().Add(c.ttl),
+		authID:            authID,
+		expiresAt:         now.Add(c.ttl),
+		sharedRefreshedAt: now,
 	}
 	c.mu.Unlock()
+	c.sharedWrite(func(ctx context.Context, store sharedstate.Store) error {
+		_, errSet := store.KVSet(ctx, sessionKVKey(sessionID), []byte(authID), home.KVSetOptions{EX: c.ttl})
+		return errSet
+	})
 }
 
-// Invalidate removes a specific session binding.
+// Invalidate removes a specific session binding. Replicas that already cached
+// the binding locally keep it until it expires or they rebind the session.
 func (c *SessionCache) Invalidate(sessionID string) {
 	if sessionID == "" {
 		return
@@ -101,10 +138,15 @@ func (c *SessionCache) Invalidate(sessionID string) {
 	c.mu.Lock()
 	delete(c.entries, sessionID)
 	c.mu.Unlock()
+	c.sharedWrite(func(ctx context.Context, store sharedstate.Store) error {
+		_, errDel := store.KVDel(ctx, sessionKVKey(sessionID))
+		return errDel
+	})
 }
 
 // InvalidateAuth removes all sessions bound to a specific auth ID.
-// Used when an auth becomes unavailable.
+// Used when an auth becomes unavailable. Shared bindings are left to expire:
+// the selector rebinds a session whose bound auth is no longer available.
 func (c *SessionCache) InvalidateAuth(authID string) {
 	if authID == "" {
 		return
@@ -150,3 +192,48 @@ func (c *SessionCache) cleanup() {
 	}
 	c.mu.Unlock()
 }
+
+// sharedGet looks up a session missing from the local map in the shared state
+// store and caches a hit locally. Store errors are treated as a miss.
+func (c *SessionCache) sharedGet(sessionID string) (string, bool) {
+	store := sharedstate.Current()
+	if store == nil {
+		return "", false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sessionKVLookupTimeout)
+	defer cancel()
+	raw, found, errGet := store.KVGet(ctx, sessionKVKey(sessionID))
+	if errGet != nil {
+		log.Warnf("session-affinity: shared binding lookup failed: %v", errGet)
+		return "", false
+	}
+	if !found || len(raw) == 0 {
+		return "", false
+	}
+	authID := string(raw)
+	now := time.Now()
+	c.mu.Lock()
+	c.entries[sessionID] = sessionEntry{authID: authID, expiresAt: now.Add(c.ttl), sharedRefreshedAt: now}
+	c.mu.Unlock()
+	return authID, true
+}
+
+// sharedWrite runs a shared state store update in the background so store
+// latency never reaches the request path.
+func (c *SessionCache) sharedWrite(write func(ctx context.Context, store sharedstate.Store) error) {
+	store := sharedstate.Current()
+	if store == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionKVWriteTimeout)
+		defer cancel()
+		if errWrite := write(ctx, store); errWrite != nil {
+			log.Warnf("session-affinity: shared binding update failed: %v", errWrite)
+		}
+	}()
+}
+
+func sessionKVKey(sessionID string) string {
+	return sessionKVKeyPrefix + home.HashKeyPart(sessionID)
+}
@@ -0,0 +1,307 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/home"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/registry"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/sharedstate"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// sharedCooldownKeyPrefix namespaces model cooldowns in the shared state store.
+	sharedCooldownKeyPrefix = "cpa:cooldown:"
+	// sharedCooldownTimeout bounds a single publish, clear or sync against the store.
+	sharedCooldownTimeout = 5 * time.Second
+	// defaultSharedCooldownSyncInterval is used when StartSharedCooldownSync gets no interval.
+	defaultSharedCooldownSyncInterval = 5 * time.Second
+)
+
+// sharedCooldown is published when a model of an auth enters cooldown so other
+// replicas skip it instead of rediscovering the same error.
+type sharedCooldown struct {
+	AuthID        string     `json:"auth_id"`
+	Model         string     `json:"model"`
+	Until         time.Time  `json:"until"`
+	StatusMessage string     `json:"status_message,omitempty"`
+	Quota         QuotaState `json:"quota"`
+	SuspendReason string     `json:"suspend_reason,omitempty"`
+}
+
+// appliedCooldown remembers a cooldown mirrored from another replica so its
+// removal from the store can resume the model locally.
+type appliedCooldown struct {
+	authID string
+	model  string
+	until  time.Time
+}
+
+func sharedCooldownKey(authID, model string) string {
+	return sharedCooldownKeyPrefix + home.HashKeyPart(authID+"\x00"+model)
+}
+
+// sharedCooldownWrite is a pending publish or, with a nil value, clear of one
+// shared cooldown key.
+type sharedCooldownWrite struct {
+	authID string
+	model  string
+	value  []byte
+	ttl    time.Duration
+}
+
+// sharedCooldownWrites serializes writes to the shared store on one goroutine so
+// a clear queued after a publish of the same key can never overtake it. Only the
+// latest write per key is kept; keys are written in the order they were queued.
+var sharedCooldownWrites struct {
+	mu      sync.Mutex
+	pending map[string]sharedCooldownWrite
+	order   []string
+	wake    chan struct{}
+	once    sync.Once
+}
+
+// publishSharedCooldown stores a local cooldown in the shared state store, if any.
+func publishSharedCooldown(cooldown sharedCooldown) {
+	if sharedstate.Current() == nil {
+		return
+	}
+	ttl := time.Until(cooldown.Until)
+	if ttl <= 0 {
+		return
+	}
+	raw, errMarshal := json.Marshal(cooldown)
+	if errMarshal != nil {
+		return
+	}
+	queueSharedCooldownWrite(sharedCooldownWrite{authID: cooldown.AuthID, model: cooldown.Model, value: raw, ttl: ttl})
+}
+
+// clearSharedCooldown removes a cooldown from the shared state store after the
+// model succeeded again on this replica.
+func clearSharedCooldown(authID, model string) {
+	if sharedstate.Current() == nil {
+		return
+	}
+	queueSharedCooldownWrite(sharedCooldownWrite{authID: authID, model: model})
+}
+
+func queueSharedCooldownWrite(write sharedCooldownWrite) {
+	queue := &sharedCooldownWrites
+	queue.once.Do(func() {
+		queue.pending = make(map[string]sharedCooldownWrite)
+		queue.wake = make(chan struct{}, 1)
+		go runSharedCooldownWriter()
+	})
+	key := sharedCooldownKey(write.authID, write.model)
+	queue.mu.Lock()
+	if _, queued := queue.pending[key]; !queued {
+		queue.order = append(queue.order, key)
+	}
+	queue.pending[key] = write
+	queue.mu.Unlock()
+	select {
+	case queue.wake <- struct{}{}:
+	default:
+	}
+}
+
+func runSharedCooldownWriter() {
+	queue := &sharedCooldownWrites
+	for range queue.wake {
+		for {
+			queue.mu.Lock()
+			if len(queue.order) == 0 {
+				queue.mu.Unlock()
+				break
+			}
+			key := queue.order[0]
+			queue.order = queue.order[1:]
+			write := queue.pending[key]
+			delete(queue.pending, key)
+			queue.mu.Unlock()
+			writeSharedCooldown(key, write)
+		}
+	}
+}
+
+func writeSharedCooldown(key string, write sharedCooldownWrite) {
+	store := sharedstate.Current()
+	if store == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sharedCooldownTimeout)
+	defer cancel()
+	if write.value == nil {
+		if _, errDel := store.KVDel(ctx, key); errDel != nil {
+			log.Warnf("shared cooldown clear failed auth=%s model=%s: %v", write.authID, write.model, errDel)
+		}
+		return
+	}
+	if _, errSet := store.KVSet(ctx, key, write.value, home.KVSetOptions{EX: write.ttl}); errSet != nil {
+		log.Warnf("shared cooldown publish failed auth=%s model=%s: %v", write.authID, write.model, errSet)
+	}
+}
+
+// StartSharedCooldownSync launches a background loop that mirrors cooldowns
+// published by other replicas into the local model states. Starting a new loop
+// cancels the previous one.
+func (m *Manager) StartSharedCooldownSync(parent context.Context, interval time.Duration) {
+	if m == nil {
+		return
+	}
+	if interval <= 0 {
+		interval = defaultSharedCooldownSyncInterval
+	}
+	ctx, cancel := context.WithCancel(parent)
+	m.mu.Lock()
+	cancelPrev := m.sharedCooldownCancel
+	m.sharedCooldownCancel = cancel
+	m.mu.Unlock()
+	if cancelPrev != nil {
+		cancelPrev()
+	}
+	go m.runSharedCooldownSync(ctx, interval)
+}
+
+// StopSharedCooldownSync cancels the shared cooldown loop, if running.
+func (m *Manager) StopSharedCooldownSync() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	cancel := m.sharedCooldownCancel
+	m.sharedCooldownCancel = nil
+	m.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+func (m *Manager) runSharedCooldownSync(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		syncCtx, cancel := context.WithTimeout(ctx, sharedCooldownTimeout)
+		m.SyncSharedCooldowns(syncCtx)
+		cancel()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SyncSharedCooldowns applies cooldowns other replicas published to the shared
+// state store. A model is only suspended when the shared cooldown outlasts the
+// local one, and models suspended this way resume once the shared entry is
+// cleared by a success elsewhere.
+func (m *Manager) SyncSharedCooldowns(ctx context.Context) {
+	store := sharedstate.Current()
+	if m == nil || store == nil {
+		return
+	}
+	entries, errScan := store.KVScan(ctx, sharedCooldownKeyPrefix)
+	if errScan != nil {
+		log.Warnf("shared cooldown sync failed: %v", errScan)
+		return
+	}
+	now := time.Now()
+	remote := make(map[string]sharedCooldown, len(entries))
+	for key, raw := range entries {
+		var cooldown sharedCooldown
+		if errUnmarshal := json.Unmarshal(raw, &cooldown); errUnmarshal != nil || cooldown.AuthID == "" || cooldown.Model == "" {
+			continue
+		}
+		if cooldown.Until.After(now) {
+			remote[key] = cooldown
+		}
+	}
+
+	var (
+		suspended []sharedCooldown
+		resumed   []appliedCooldown
+		snapshots []*Auth
+	)
+	m.mu.Lock()
+	if m.sharedCooldowns == nil {
+		m.sharedCooldowns = make(map[string]appliedCooldown)
+	}
+	for key, cooldown := range remote {
+		auth, ok := m.auths[cooldown.AuthID]
+		if !ok || auth == nil || auth.Disabled {
+			continue
+		}
+		state := ensureModelState(auth, cooldown.Model)
+		if !state.NextRetryAfter.Before(cooldown.Until) {
+			continue
+		}
+		state.Unavailable = true
+		state.Status = StatusError
+		state.StatusMessage = cooldown.StatusMessage
+		state.NextRetryAfter = cooldown.Until
+		state.Quota = cooldown.Quota
+		state.UpdatedAt = now
+		auth.Status = StatusError
+		auth.UpdatedAt = now
+		updateAggregatedAvailability(auth, now)
+		m.sharedCooldowns[key] = appliedCooldown{authID: cooldown.AuthID, model: cooldown.Model, until: cooldown.Until}
+		suspended = append(suspended, cooldown)
+		snapshots = append(snapshots, auth.Clone())
+	}
+	for key, applied := range m.sharedCooldowns {
+		if _, ok := remote[key]; ok {
+			continue
+		}
+		delete(m.sharedCooldowns, key)
+		if !applied.until.After(now) {
+			continue
+		}
+		auth, ok := m.auths[applied.authID]
+		if !ok || auth == nil {
+			continue
+		}
+		state := auth.ModelStates[applied.model]
+		if state == nil || !state.NextRetryAfter.Equal(applied.until) {
+			continue
+		}
+		resetModelState(state, now)
+		updateAggregatedAvailability(auth, now)
+		if !hasModelError(auth, now) {
+			auth.LastError = nil
+			auth.StatusMessage = ""
+			auth.Status = StatusActive
+		}
+		auth.UpdatedAt = now
+		resumed = append(resumed, applied)
+		snapshots = append(snapshots, auth.Clone())
+	}
+	m.mu.Unlock()
+
+	if m.scheduler != nil {
+		for _, snapshot := range snapshots {
+			m.scheduler.upsertAuth(snapshot)
+		}
+	}
+	reg := registry.GetGlobalRegistry()
+	for _, cooldown := range suspended {
+		if cooldown.Quota.Exceeded {
+			reg.SetModelQuotaExceeded(cooldown.AuthID, cooldown.Model)
+		}
+		reason := cooldown.SuspendReason
+		if reason == "" {
+			reason = "shared_cooldown"
+		}
+		reg.SuspendClientModel(cooldown.AuthID, cooldown.Model, reason)
+		log.Debugf("shared cooldown applied auth=%s model=%s until=%s", cooldown.AuthID, cooldown.Model, cooldown.Until.Format(time.RFC3339))
+	}
+	for _, applied := range resumed {
+		reg.ClearModelQuotaExceeded(applied.authID, applied.model)
+		reg.ResumeClientModel(applied.authID, applied.model)
+		log.Debugf("shared cooldown cleared auth=%s model=%s", applied.authID, applied.model)
+	}
+}
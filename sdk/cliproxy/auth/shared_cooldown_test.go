@@ -0,0 +1,219 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/home"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/sharedstate"
+)
+
+// memSharedStore is an in-memory sharedstate.Store standing in for Redis or Postgres.
+type memSharedStore struct {
+	mu     sync.Mutex
+	values map[string][]byte
+	gets   int
+}
+
+func installMemSharedStore(t *testing.T) *memSharedStore {
+	t.Helper()
+	store := &memSharedStore{values: make(map[string][]byte)}
+	sharedstate.SetCurrent(store)
+	t.Cleanup(func() { sharedstate.SetCurrent(nil) })
+	return store
+}
+
+func (s *memSharedStore) KVGet(_ context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gets++
+	value, ok := s.values[key]
+	return value, ok, nil
+}
+
+func (s *memSharedStore) KVSet(_ context.Context, key string, value []byte, opts home.KVSetOptions) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, exists := s.values[key]
+	if (opts.NX && exists) || (opts.XX && !exists) {
+		return false, nil
+	}
+	s.values[key] = value
+	return true, nil
+}
+
+func (s *memSharedStore) KVSetNX(ctx context.Context, key string, value []byte, _ time.Duration) (bool, error) {
+	return s.KVSet(ctx, key, value, home.KVSetOptions{NX: true})
+}
+
+func (s *memSharedStore) KVDel(_ context.Context, keys ...string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var deleted int64
+	for _, key := range keys {
+		if _, ok := s.values[key]; ok {
+			delete(s.values, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (s *memSharedStore) KVExpire(_ context.Context, key string, _ time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.values[key]
+	return ok, nil
+}
+
+func (s *memSharedStore) KVScan(_ context.Context, prefix string) (map[string][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string][]byte)
+	for key, value := range s.values {
+		if strings.HasPrefix(key, prefix) {
+			out[key] = value
+		}
+	}
+	return out, nil
+}
+
+func (s *memSharedStore) Close() error { return nil }
+
+func (s *memSharedStore) waitFor(t *testing.T, key string, present bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		_, ok := s.values[key]
+		s.mu.Unlock()
+		if ok == present {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("key %s present=%v never observed", key, !present)
+}
+
+func TestSyncSharedCooldowns_AppliesAndResumesRemoteCooldown(t *testing.T) {
+	store := installMemSharedStore(t)
+	ctx := context.Background()
+	m := NewManager(nil, nil, nil)
+	if _, err := m.Register(ctx, &Auth{ID: "shared-auth", Provider: "claude", Status: StatusActive}); err != nil {
+		t.Fatalf("register auth: %v", err)
+	}
+
+	until := time.Now().Add(time.Hour).Truncate(time.Second)
+	raw, _ := json.Marshal(sharedCooldown{AuthID: "shared-auth", Model: "shared-model", Until: until, StatusMessage: "quota exhausted", Quota: QuotaState{Exceeded: true, NextRecoverAt: until}})
+	key := sharedCooldownKey("shared-auth", "shared-model")
+	if _, err := store.KVSet(ctx, key, raw, home.KVSetOptions{}); err != nil {
+		t.Fatalf("seed cooldown: %v", err)
+	}
+
+	m.SyncSharedCooldowns(ctx)
+	got, _ := m.GetByID("shared-auth")
+	blocked, reason, next := isAuthBlockedForModel(got, "shared-model", time.Now())
+	if !blocked || reason != blockReasonCooldown || !next.Equal(until) {
+		t.Fatalf("blocked=%v reason=%v next=%v, want cooldown until %v", blocked, reason, next, until)
+	}
+	if blocked, _, _ = isAuthBlockedForModel(got, "other-model", time.Now()); blocked {
+		t.Fatal("other-model blocked, want only the shared model cooled down")
+	}
+
+	if _, err := store.KVDel(ctx, key); err != nil {
+		t.Fatalf("clear cooldown: %v", err)
+	}
+	m.SyncSharedCooldowns(ctx)
+	got, _ = m.GetByID("shared-auth")
+	if blocked, _, _ = isAuthBlockedForModel(got, "shared-model", time.Now()); blocked {
+		t.Fatal("shared-model still blocked after the shared cooldown was cleared")
+	}
+}
+
+func TestMarkResult_PublishesAndClearsSharedCooldown(t *testing.T) {
+	store := installMemSharedStore(t)
+	ctx := context.Background()
+	m := NewManager(nil, nil, nil)
+	if _, err := m.Register(ctx, &Auth{ID: "publish-auth", Provider: "claude", Status: StatusActive}); err != nil {
+		t.Fatalf("register auth: %v", err)
+	}
+	key := sharedCooldownKey("publish-auth", "publish-model")
+
+	m.MarkResult(ctx, Result{AuthID: "publish-auth", Provider: "claude", Model: "publish-model", Error: &Error{HTTPStatus: 429, Message: "rate limited"}})
+	store.waitFor(t, key, true)
+	raw, _, _ := store.KVGet(ctx, key)
+	var published sharedCooldown
+	if err := json.Unmarshal(raw, &published); err != nil || published.AuthID != "publish-auth" || !published.Until.After(time.Now()) {
+		t.Fatalf("published cooldown = %+v, %v", published, err)
+	}
+
+	m.MarkResult(ctx, Result{AuthID: "publish-auth", Provider: "claude", Model: "publish-model", Success: true})
+	store.waitFor(t, key, false)
+}
+
+func TestSharedCooldownWrites_ClearNeverOvertakesPublish(t *testing.T) {
+	store := installMemSharedStore(t)
+	key := sharedCooldownKey("ordered-auth", "ordered-model")
+	marker := sharedCooldownKey("ordered-auth", "ordered-marker")
+
+	for i := 0; i < 50; i++ {
+		publishSharedCooldown(sharedCooldown{AuthID: "ordered-auth", Model: "ordered-model", Until: time.Now().Add(time.Hour)})
+		clearSharedCooldown("ordered-auth", "ordered-model")
+	}
+	// Writes are applied in queue order, so the marker lands after the last clear.
+	publishSharedCooldown(sharedCooldown{AuthID: "ordered-auth", Model: "ordered-marker", Until: time.Now().Add(time.Hour)})
+	store.waitFor(t, marker, true)
+
+	store.mu.Lock()
+	_, stale := store.values[key]
+	store.mu.Unlock()
+	if stale {
+		t.Fatal("cooldown still published after it was cleared")
+	}
+}
+
+func TestSessionCache_SharesBindingsAcrossInstances(t *testing.T) {
+	store := installMemSharedStore(t)
+	first := NewSessionCache(time.Hour)
+	defer first.Stop()
+	second := NewSessionCache(time.Hour)
+	defer second.Stop()
+	key := sessionKVKey("session-1")
+
+	first.Set("session-1", "auth-a")
+	store.waitFor(t, key, true)
+	if authID, ok := first.GetAndRefresh("session-1"); !ok || authID != "auth-a" {
+		t.Fatalf("first.GetAndRefresh = %q, %v; want local auth-a", authID, ok)
+	}
+	store.mu.Lock()
+	localGets := store.gets
+	store.mu.Unlock()
+	if localGets != 0 {
+		t.Fatalf("store lookups = %d, want local hits served without the store", localGets)
+	}
+
+	if authID, ok := second.Get("session-1"); !ok || authID != "auth-a" {
+		t.Fatalf("second.Get = %q, %v; want auth-a from the shared store", authID, ok)
+	}
+	if authID, ok := second.Get("session-1"); !ok || authID != "auth-a" {
+		t.Fatalf("second.Get cached = %q, %v", authID, ok)
+	}
+	store.mu.Lock()
+	sharedGets := store.gets
+	store.mu.Unlock()
+	if sharedGets != 1 {
+		t.Fatalf("store lookups = %d, want the shared hit cached locally", sharedGets)
+	}
+
+	second.Invalidate("session-1")
+	store.waitFor(t, key, false)
+	third := NewSessionCache(time.Hour)
+	defer third.Stop()
+	if _, ok := third.Get("session-1"); ok {
+		t.Fatal("binding still in the shared store after invalidation")
+	}
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/tracing"
+	cliproxyexecutor "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/executor"
+)
+
+// attemptCounterKey carries the number of executor calls made for one client request.
+type attemptCounterKey struct{}
+
+// startExecuteSpan opens the span covering credential selection and retries
+// for one conductor call. It also installs the attempt counter used to number
+// the executor calls below it.
+func startExecuteSpan(ctx context.Context, name string, providers []string, req cliproxyexecutor.Request) (context.Context, *tracing.Span) {
+	if !tracing.Enabled() {
+		return ctx, nil
+	}
+	if _, ok := ctx.Value(attemptCounterKey{}).(*atomic.Int32); !ok {
+		ctx = context.WithValue(ctx, attemptCounterKey{}, new(atomic.Int32))
+	}
+	return tracing.Start(ctx, name,
+		tracing.String("request.model", req.Model),
+		tracing.Int("providers", len(providers)),
+	)
+}
+
+// startAttemptSpan opens the span for one executor call against a picked credential.
+func startAttemptSpan(ctx context.Context, executor ProviderExecutor, auth *Auth, provider string, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (context.Context, *tracing.Span) {
+	if !tracing.Enabled() {
+		return ctx, nil
+	}
+	attempt := 0
+	if counter, ok := ctx.Value(attemptCounterKey{}).(*atomic.Int32); ok {
+		attempt = int(counter.Add(1)) - 1
+	}
+	index := auth.Index
+	if index == "" {
+		// Avoid EnsureIndex here: the picked auth may be shared with other requests.
+		index = stableAuthIndex(auth.indexSeed())
+	}
+	return tracing.Start(ctx, "conductor.attempt",
+		tracing.String("auth.index", index),
+		tracing.String("auth.provider", provider),
+		tracing.String("upstream.model", req.Model),
+		tracing.Int("retry.attempt", attempt),
+		tracing.String("translator.from", opts.SourceFormat.String()),
+		tracing.String("translator.to", requestToFormat(provider, executor, req, opts).String()),
+		tracing.Bool("stream", opts.Stream),
+	)
+}
+
+// endSpan records err, if any, and ends span.
+func endSpan(span *tracing.Span, err error) {
+	span.RecordError(err)
+	span.End()
+}
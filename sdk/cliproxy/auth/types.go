@@ -89,6 +89,10 @@ type Auth struct {
 	NextRetryAfter time.Time `json:"next_retry_after"`
 	// ModelStates tracks per-model runtime availability data.
 	ModelStates map[string]*ModelState `json:"model_states,omitempty"`
+	// QuotaSnapshot holds the remaining quota last reported by the quota poller.
+	QuotaSnapshot *QuotaSnapshot `json:"quota_snapshot,omitempty"`
+	// Health holds synthetic health probe results and quarantine state.
+	Health *HealthState `json:"health,omitempty"`
 
 	// Runtime carries non-serialisable data used during execution (in-memory only).
 	Runtime any `json:"-"`
@@ -147,6 +151,9 @@ type ModelState struct {
 	LastError *Error `json:"last_error,omitempty"`
 	// Quota retains quota information if this model hit rate limits.
 	Quota QuotaState `json:"quota"`
+	// QuotaLow is set by quota polling while this model's remaining quota is
+	// at or below the configured low threshold.
+	QuotaLow bool `json:"quota_low,omitempty"`
 	// UpdatedAt tracks the last update timestamp for this model state.
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -239,6 +246,8 @@ func (a *Auth) Clone() *Auth {
 			copyAuth.ModelStates[key] = state.Clone()
 		}
 	}
+	copyAuth.QuotaSnapshot = a.QuotaSnapshot.Clone()
+	copyAuth.Health = a.Health.Clone()
 	copyAuth.Runtime = a.Runtime
 	return &copyAuth
 }
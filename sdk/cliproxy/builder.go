@@ -248,6 +248,12 @@ func (b *Builder) Build() (*Service, error) {
 		switch strategy {
 		case "fill-first", "fillfirst", "ff":
 			selector = &coreauth.FillFirstSelector{}
+		case "least-in-flight", "leastinflight", "lif":
+			selector = &coreauth.LeastInFlightSelector{}
+		case "latency", "ewma-latency", "ewma":
+			selector = &coreauth.LatencySelector{}
+		case "weighted", "priority-share":
+			selector = &coreauth.PriorityShareSelector{}
 		default:
 			selector = &coreauth.RoundRobinSelector{}
 		}
@@ -292,6 +298,7 @@ func (b *Builder) Build() (*Service, error) {
 		api.WithConfigReloadHook(func(ctx context.Context, cfg *config.Config) {
 			service.applyConfigUpdate(cfg)
 		}),
+		api.WithConfigModelPreview(service.previewConfigModels),
 	)
 	return service, nil
 }
@@ -0,0 +1,85 @@
+package cliproxy
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/configcheck"
+	coreauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
+)
+
+// PreviewConfigModels returns the client-visible models, including aliases and
+// prefixes, that auths would expose under cfg. Nothing is registered and no
+// plugin or upstream provider is called.
+func PreviewConfigModels(ctx context.Context, cfg *config.Config, auths []*coreauth.Auth) []configcheck.Model {
+	return (&Service{cfg: cfg}).previewModels(ctx, auths)
+}
+
+// previewConfigModels is PreviewConfigModels with the models contributed by
+// the running plugins.
+func (s *Service) previewConfigModels(ctx context.Context, cfg *config.Config, auths []*coreauth.Auth) []configcheck.Model {
+	preview := &Service{cfg: cfg}
+	if s != nil {
+		preview.pluginHost = s.pluginHost
+	}
+	return preview.previewModels(ctx, auths)
+}
+
+func (s *Service) previewModels(ctx context.Context, auths []*coreauth.Auth) []configcheck.Model {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	type previewEntry struct {
+		providers map[string]struct{}
+		auths     int
+	}
+	entries := make(map[string]*previewEntry)
+	for _, a := range auths {
+		if a == nil || a.ID == "" || a.Disabled {
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(a.Attributes["gemini_virtual_primary"]), "true") {
+			continue
+		}
+		providerKey, models, _ := s.resolveModelsForAuth(ctx, a, false)
+		providerKey = strings.ToLower(strings.TrimSpace(providerKey))
+		if providerKey == "" {
+			continue
+		}
+		seen := make(map[string]struct{}, len(models))
+		for _, model := range models {
+			if model == nil {
+				continue
+			}
+			id := strings.TrimSpace(model.ID)
+			if id == "" {
+				continue
+			}
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			entry := entries[id]
+			if entry == nil {
+				entry = &previewEntry{providers: make(map[string]struct{})}
+				entries[id] = entry
+			}
+			entry.providers[providerKey] = struct{}{}
+			entry.auths++
+		}
+	}
+
+	out := make([]configcheck.Model, 0, len(entries))
+	for id, entry := range entries {
+		providers := make([]string, 0, len(entry.providers))
+		for provider := range entry.providers {
+			providers = append(providers, provider)
+		}
+		sort.Strings(providers)
+		out = append(out, configcheck.Model{ID: id, Providers: providers, Auths: entry.auths})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
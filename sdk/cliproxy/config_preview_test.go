@@ -0,0 +1,61 @@
+package cliproxy
+
+import (
+	"context"
+	"testing"
+
+	internalregistry "github.com/router-for-me/CLIProxyAPI/v7/internal/registry"
+	coreauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
+	"github.com/router-for-me/CLIProxyAPI/v7/sdk/config"
+)
+
+func TestPreviewConfigModels_AppliesAliasesAndPrefixesWithoutRegistering(t *testing.T) {
+	cfg := &config.Config{
+		OpenAICompatibility: []config.OpenAICompatibility{
+			{
+				Name:    "team",
+				BaseURL: "https://example.com/v1",
+				Models: []config.OpenAICompatibilityModel{
+					{Name: "upstream-chat", Alias: "compat-chat"},
+				},
+			},
+		},
+	}
+	auths := []*coreauth.Auth{
+		{
+			ID:       "auth-preview-compat",
+			Provider: "openai-compatibility",
+			Prefix:   "team",
+			Attributes: map[string]string{
+				"auth_kind":    "api_key",
+				"compat_name":  "team",
+				"provider_key": "team",
+			},
+		},
+		{
+			ID:       "auth-preview-disabled",
+			Provider: "openai-compatibility",
+			Disabled: true,
+			Attributes: map[string]string{
+				"compat_name":  "team",
+				"provider_key": "team",
+			},
+		},
+	}
+
+	models := PreviewConfigModels(context.Background(), cfg, auths)
+
+	got := make(map[string]int, len(models))
+	for _, model := range models {
+		got[model.ID] = model.Auths
+		if len(model.Providers) != 1 || model.Providers[0] != "team" {
+			t.Fatalf("model %s providers = %v, want [team]", model.ID, model.Providers)
+		}
+	}
+	if len(got) != 2 || got["compat-chat"] != 1 || got["team/compat-chat"] != 1 {
+		t.Fatalf("models = %+v, want compat-chat and team/compat-chat from one auth", models)
+	}
+	if registered := internalregistry.GetGlobalRegistry().GetModelsForClient("auth-preview-compat"); len(registered) != 0 {
+		t.Fatalf("preview registered %d models", len(registered))
+	}
+}
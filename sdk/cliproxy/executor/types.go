@@ -33,6 +33,10 @@ const (
 	SelectedAuthCallbackMetadataKey = "selected_auth_callback"
 	// ExecutionSessionMetadataKey identifies a long-lived downstream execution session.
 	ExecutionSessionMetadataKey = "execution_session_id"
+	// AllowedAuthPrefixMetadataKey restricts auth selection to credentials with this prefix.
+	AllowedAuthPrefixMetadataKey = "allowed_auth_prefix"
+	// AllowedAuthIndexesMetadataKey restricts auth selection to these auth indexes ([]string).
+	AllowedAuthIndexesMetadataKey = "allowed_auth_indexes"
 )
 
 // Request encapsulates the translated payload that will be sent to a provider executor.
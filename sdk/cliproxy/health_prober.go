@@ -0,0 +1,154 @@
+package cliproxy
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	coreauth "github.com/router-for-me/CLIProxyAPI/v7/sdk/cliproxy/auth"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultHealthCheckInterval         = 10 * time.Minute
+	defaultHealthCheckTimeout          = 20 * time.Second
+	defaultHealthCheckFailureThreshold = 3
+	healthCheckMaxConcurrency          = 4
+)
+
+// healthProberSettings is the effective configuration of a running prober.
+type healthProberSettings struct {
+	interval         time.Duration
+	timeout          time.Duration
+	failureThreshold int
+}
+
+// healthProber periodically probes every enabled credential and records the
+// results on the core manager, which quarantines credentials that keep failing.
+type healthProber struct {
+	manager  *coreauth.Manager
+	settings healthProberSettings
+	cancel   context.CancelFunc
+}
+
+func healthCheckSettings(cfg config.HealthCheckConfig) healthProberSettings {
+	settings := healthProberSettings{
+		interval:         defaultHealthCheckInterval,
+		timeout:          defaultHealthCheckTimeout,
+		failureThreshold: defaultHealthCheckFailureThreshold,
+	}
+	if raw := strings.TrimSpace(cfg.Interval); raw != "" {
+		if parsed, errParse := time.ParseDuration(raw); errParse != nil || parsed <= 0 {
+			log.Warnf("invalid health-check.interval %q, using %s", raw, defaultHealthCheckInterval)
+		} else {
+			settings.interval = parsed
+		}
+	}
+	if raw := strings.TrimSpace(cfg.Timeout); raw != "" {
+		if parsed, errParse := time.ParseDuration(raw); errParse != nil || parsed <= 0 {
+			log.Warnf("invalid health-check.timeout %q, using %s", raw, defaultHealthCheckTimeout)
+		} else {
+			settings.timeout = parsed
+		}
+	}
+	if cfg.FailureThreshold > 0 {
+		settings.failureThreshold = cfg.FailureThreshold
+	}
+	return settings
+}
+
+// applyHealthCheckConfig starts, restarts or stops the health prober so it
+// matches cfg. A running prober is left alone when its settings are unchanged.
+func (s *Service) applyHealthCheckConfig(cfg *config.Config) {
+	if s == nil || cfg == nil || s.coreManager == nil {
+		return
+	}
+	if !cfg.HealthCheck.Enable {
+		if s.healthProber != nil {
+			s.healthProber.stop()
+			s.healthProber = nil
+			log.Info("health check stopped")
+		}
+		return
+	}
+	settings := healthCheckSettings(cfg.HealthCheck)
+	if s.healthProber != nil && s.healthProber.settings == settings {
+		return
+	}
+	if s.healthProber != nil {
+		s.healthProber.stop()
+	}
+	s.healthProber = newHealthProber(s.coreManager, settings)
+	s.healthProber.start(context.Background())
+	log.Infof("health check started (interval=%s, timeout=%s, failure-threshold=%d)", settings.interval, settings.timeout, settings.failureThreshold)
+}
+
+func newHealthProber(manager *coreauth.Manager, settings healthProberSettings) *healthProber {
+	return &healthProber{manager: manager, settings: settings}
+}
+
+func (p *healthProber) start(parent context.Context) {
+	ctx, cancel := context.WithCancel(parent)
+	p.cancel = cancel
+	go func() {
+		ticker := time.NewTicker(p.settings.interval)
+		defer ticker.Stop()
+		for {
+			p.probeAll(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (p *healthProber) stop() {
+	if p != nil && p.cancel != nil {
+		p.cancel()
+	}
+}
+
+// probeAll probes every enabled credential once.
+func (p *healthProber) probeAll(ctx context.Context) {
+	sem := make(chan struct{}, healthCheckMaxConcurrency)
+	var wg sync.WaitGroup
+	for _, auth := range p.manager.List() {
+		if auth == nil || auth.Disabled || auth.Status == coreauth.StatusDisabled {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(auth *coreauth.Auth) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			p.probe(ctx, auth)
+		}(auth)
+	}
+	wg.Wait()
+}
+
+func (p *healthProber) probe(ctx context.Context, auth *coreauth.Auth) {
+	probeCtx, cancel := context.WithTimeout(ctx, p.settings.timeout)
+	defer cancel()
+	result, ok := p.manager.ProbeAuth(probeCtx, auth)
+	if ctx.Err() != nil {
+		return
+	}
+	if !ok {
+		p.manager.RecordNotProbeable(ctx, auth.ID)
+		return
+	}
+	if !result.Success {
+		log.Debugf("health check: auth %s probe failed: %s", auth.ID, result.Error)
+	}
+	p.manager.RecordProbeResult(ctx, auth.ID, result, p.settings.failureThreshold)
+}
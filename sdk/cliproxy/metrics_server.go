@@ -0,0 +1,148 @@
+package cliproxy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/metrics"
+	log "github.com/sirupsen/logrus"
+)
+
+type metricsServer struct {
+	mu      sync.Mutex
+	server  *http.Server
+	addr    string
+	enabled bool
+}
+
+func newMetricsServer() *metricsServer {
+	return &metricsServer{}
+}
+
+func (s *Service) applyMetricsConfig(cfg *config.Config) {
+	if s == nil || cfg == nil {
+		return
+	}
+	if s.metricsServer == nil {
+		s.metricsServer = newMetricsServer()
+	}
+	s.metricsServer.Apply(cfg)
+}
+
+func (s *Service) shutdownMetrics(ctx context.Context) error {
+	if s == nil || s.metricsServer == nil {
+		return nil
+	}
+	return s.metricsServer.Shutdown(ctx)
+}
+
+func (m *metricsServer) Apply(cfg *config.Config) {
+	if m == nil || cfg == nil {
+		return
+	}
+	addr := strings.TrimSpace(cfg.Metrics.Addr)
+	if addr == "" {
+		addr = config.DefaultMetricsAddr
+	}
+	enabled := cfg.Metrics.Enable
+
+	m.mu.Lock()
+	currentServer := m.server
+	currentAddr := m.addr
+	m.addr = addr
+	m.enabled = enabled
+	if !enabled {
+		m.server = nil
+		m.mu.Unlock()
+		if currentServer != nil {
+			m.stopServer(currentServer, currentAddr, "disabled")
+		}
+		return
+	}
+	if currentServer != nil && currentAddr == addr {
+		m.mu.Unlock()
+		return
+	}
+	m.server = nil
+	m.mu.Unlock()
+
+	if currentServer != nil {
+		m.stopServer(currentServer, currentAddr, "restarted")
+	}
+
+	m.startServer(addr)
+}
+
+func (m *metricsServer) Shutdown(ctx context.Context) error {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	currentServer := m.server
+	currentAddr := m.addr
+	m.server = nil
+	m.enabled = false
+	m.mu.Unlock()
+
+	if currentServer == nil {
+		return nil
+	}
+	return m.stopServerWithContext(ctx, currentServer, currentAddr, "shutdown")
+}
+
+func (m *metricsServer) startServer(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	m.mu.Lock()
+	if !m.enabled || m.addr != addr || m.server != nil {
+		m.mu.Unlock()
+		return
+	}
+	m.server = server
+	m.mu.Unlock()
+
+	log.Infof("metrics server starting on %s", addr)
+	go func() {
+		if errServe := server.ListenAndServe(); errServe != nil && !errors.Is(errServe, http.ErrServerClosed) {
+			log.Errorf("metrics server failed on %s: %v", addr, errServe)
+			m.mu.Lock()
+			if m.server == server {
+				m.server = nil
+			}
+			m.mu.Unlock()
+		}
+	}()
+}
+
+func (m *metricsServer) stopServer(server *http.Server, addr string, reason string) {
+	_ = m.stopServerWithContext(context.Background(), server, addr, reason)
+}
+
+func (m *metricsServer) stopServerWithContext(ctx context.Context, server *http.Server, addr string, reason string) error {
+	if server == nil {
+		return nil
+	}
+	stopCtx := ctx
+	if stopCtx == nil {
+		stopCtx = context.Background()
+	}
+	stopCtx, cancel := context.WithTimeout(stopCtx, 5*time.Second)
+	defer cancel()
+	if errStop := server.Shutdown(stopCtx); errStop != nil {
+		log.Errorf("metrics server stop failed on %s: %v", addr, errStop)
+		return errStop
+	}
+	log.Infof("metrics server stopped on %s (%s)", addr, reason)
+	return nil
+}
@@ -0,0 +1,43 @@
+package cliproxy
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultQuotaPollInterval = 5 * time.Minute
+
+// applyQuotaPollingConfig starts, restarts or stops the core quota poller so it
+// matches cfg. The poller is left alone when its interval is unchanged.
+func (s *Service) applyQuotaPollingConfig(cfg *config.Config) {
+	if s == nil || cfg == nil || s.coreManager == nil {
+		return
+	}
+	if !cfg.QuotaPolling.Enable {
+		if s.quotaPollInterval > 0 {
+			s.coreManager.StopQuotaPolling()
+			s.quotaPollInterval = 0
+			log.Info("quota polling stopped")
+		}
+		return
+	}
+	interval := defaultQuotaPollInterval
+	if raw := strings.TrimSpace(cfg.QuotaPolling.Interval); raw != "" {
+		parsed, errParse := time.ParseDuration(raw)
+		if errParse != nil || parsed <= 0 {
+			log.Warnf("invalid quota-polling.interval %q, using %s", raw, defaultQuotaPollInterval)
+		} else {
+			interval = parsed
+		}
+	}
+	if interval == s.quotaPollInterval {
+		return
+	}
+	s.coreManager.StartQuotaPolling(context.Background(), interval)
+	s.quotaPollInterval = interval
+	log.Infof("quota polling started (interval=%s)", interval)
+}
@@ -0,0 +1,13 @@
+package cliproxy
+
+import (
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/responsecache"
+)
+
+func (s *Service) applyResponseCacheConfig(cfg *config.Config) {
+	if s == nil || cfg == nil {
+		return
+	}
+	responsecache.Default().Apply(cfg.ResponseCache)
+}
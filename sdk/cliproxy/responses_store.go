@@ -0,0 +1,23 @@
+package cliproxy
+
+import (
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/responsestore"
+	log "github.com/sirupsen/logrus"
+)
+
+func (s *Service) applyResponsesStoreConfig(cfg *config.Config) {
+	if s == nil || cfg == nil {
+		return
+	}
+	if err := responsestore.Default().Apply(cfg.ResponsesStore); err != nil {
+		log.Errorf("failed to configure responses store: %v", err)
+	}
+}
+
+func (s *Service) shutdownResponsesStore() {
+	if s == nil {
+		return
+	}
+	responsestore.Default().Close()
+}
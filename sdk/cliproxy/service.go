@@ -70,6 +70,18 @@ type Service struct {
 	// pprofServer manages the optional pprof HTTP debug server.
 	pprofServer *pprofServer
 
+	// metricsServer manages the optional Prometheus metrics listener.
+	metricsServer *metricsServer
+
+	// quotaPollInterval is the interval of the running quota poller, zero when stopped.
+	quotaPollInterval time.Duration
+
+	// sharedCooldownSyncInterval is the interval of the running shared cooldown sync, zero when stopped.
+	sharedCooldownSyncInterval time.Duration
+
+	// healthProber runs synthetic credential health checks, nil when disabled.
+	healthProber *healthProber
+
 	// serverErr channel for server startup/shutdown errors.
 	serverErr chan error
 
@@ -855,6 +867,7 @@ func baselineExecutorAuths() []*coreauth.Auth {
 		"claude",
 		"gemini",
 		"vertex",
+		"bedrock",
 		"gemini-cli",
 		"aistudio",
 		"antigravity",
@@ -938,6 +951,8 @@ func (s *Service) registerExecutorForAuth(a *coreauth.Auth, forceReplace bool) {
 		s.coreManager.RegisterExecutor(executor.NewAntigravityExecutor(s.cfg))
 	case "claude":
 		s.coreManager.RegisterExecutor(executor.NewClaudeExecutor(s.cfg))
+	case "bedrock":
+		s.coreManager.RegisterExecutor(executor.NewBedrockExecutor(s.cfg))
 	case "kimi":
 		s.coreManager.RegisterExecutor(executor.NewKimiExecutor(s.cfg))
 	case "xai":
@@ -1129,6 +1144,12 @@ func (s *Service) applyConfigUpdate(newCfg *config.Config) {
 		switch strategy {
 		case "fill-first", "fillfirst", "ff":
 			return "fill-first"
+		case "least-in-flight", "leastinflight", "lif":
+			return "least-in-flight"
+		case "latency", "ewma-latency", "ewma":
+			return "latency"
+		case "weighted", "priority-share":
+			return "weighted"
 		default:
 			return "round-robin"
 		}
@@ -1148,6 +1169,12 @@ func (s *Service) applyConfigUpdate(newCfg *config.Config) {
 		switch nextStrategy {
 		case "fill-first":
 			selector = &coreauth.FillFirstSelector{}
+		case "least-in-flight":
+			selector = &coreauth.LeastInFlightSelector{}
+		case "latency":
+			selector = &coreauth.LatencySelector{}
+		case "weighted":
+			selector = &coreauth.PriorityShareSelector{}
 		default:
 			selector = &coreauth.RoundRobinSelector{}
 		}
@@ -1170,6 +1197,14 @@ func (s *Service) applyConfigUpdate(newCfg *config.Config) {
 
 	s.applyRetryConfig(newCfg)
 	s.applyPprofConfig(newCfg)
+	s.applyMetricsConfig(newCfg)
+	s.applyTracingConfig(newCfg)
+	s.applyUsageLedgerConfig(newCfg)
+	s.applyResponsesStoreConfig(newCfg)
+	s.applyResponseCacheConfig(newCfg)
+	s.applyQuotaPollingConfig(newCfg)
+	s.applySharedStateConfig(newCfg)
+	s.applyHealthCheckConfig(newCfg)
 	if s.server != nil {
 		s.server.UpdateClients(newCfg)
 	}
@@ -1525,6 +1560,14 @@ func (s *Service) Run(ctx context.Context) error {
 	fmt.Printf("API server started successfully on: %s:%d\n", s.cfg.Host, s.cfg.Port)
 
 	s.applyPprofConfig(s.cfg)
+	s.applyMetricsConfig(s.cfg)
+	s.applyTracingConfig(s.cfg)
+	s.applyUsageLedgerConfig(s.cfg)
+	s.applyResponsesStoreConfig(s.cfg)
+	s.applyResponseCacheConfig(s.cfg)
+	s.applyQuotaPollingConfig(s.cfg)
+	s.applySharedStateConfig(s.cfg)
+	s.applyHealthCheckConfig(s.cfg)
 
 	if s.hooks.OnAfterStart != nil {
 		s.hooks.OnAfterStart(s)
@@ -1613,6 +1656,11 @@ func (s *Service) Shutdown(ctx context.Context) error {
 		}
 		if s.coreManager != nil {
 			s.coreManager.StopAutoRefresh()
+			s.coreManager.StopQuotaPolling()
+		}
+		if s.healthProber != nil {
+			s.healthProber.stop()
+			s.healthProber = nil
 		}
 		if s.watcher != nil {
 			if err := s.watcher.Stop(); err != nil {
@@ -1639,6 +1687,21 @@ func (s *Service) Shutdown(ctx context.Context) error {
 				shutdownErr = errShutdownPprof
 			}
 		}
+		if errShutdownMetrics := s.shutdownMetrics(ctx); errShutdownMetrics != nil {
+			log.Errorf("failed to stop metrics server: %v", errShutdownMetrics)
+			if shutdownErr == nil {
+				shutdownErr = errShutdownMetrics
+			}
+		}
+		if errShutdownTracing := s.shutdownTracing(ctx); errShutdownTracing != nil {
+			log.Errorf("failed to flush traces: %v", errShutdownTracing)
+			if shutdownErr == nil {
+				shutdownErr = errShutdownTracing
+			}
+		}
+		s.shutdownUsageLedger()
+		s.shutdownResponsesStore()
+		s.shutdownSharedState()
 
 		// no legacy clients to persist
 
@@ -1706,12 +1769,6 @@ func (s *Service) registerModelsForAuth(ctx context.Context, a *coreauth.Auth) {
 		GlobalModelRegistry().UnregisterClient(a.ID)
 		return
 	}
-	authKind := strings.ToLower(strings.TrimSpace(a.Attributes["auth_kind"]))
-	if authKind == "" {
-		if kind, _ := a.AccountInfo(); strings.EqualFold(kind, "api_key") {
-			authKind = "apikey"
-		}
-	}
 	if a.Attributes != nil {
 		if v := strings.TrimSpace(a.Attributes["gemini_virtual_primary"]); strings.EqualFold(v, "true") {
 			GlobalModelRegistry().UnregisterClient(a.ID)
@@ -1726,6 +1783,28 @@ func (s *Service) registerModelsForAuth(ctx context.Context, a *coreauth.Auth) {
 			}
 		}
 	}
+	providerKey, models, handled := s.resolveModelsForAuth(ctx, a, true)
+	if handled {
+		return
+	}
+	if len(models) > 0 {
+		s.registerResolvedModelsForAuth(a, providerKey, models)
+		return
+	}
+	GlobalModelRegistry().UnregisterClient(a.ID)
+}
+
+// resolveModelsForAuth computes the provider key and client-visible models of
+// an auth, including aliases and prefixes, without touching the model registry.
+// handled reports that a plugin registered the models itself. When live is
+// false, plugin model hooks and network-backed capability hints are skipped.
+func (s *Service) resolveModelsForAuth(ctx context.Context, a *coreauth.Auth, live bool) (providerKey string, models []*ModelInfo, handled bool) {
+	authKind := strings.ToLower(strings.TrimSpace(a.Attributes["auth_kind"]))
+	if authKind == "" {
+		if kind, _ := a.AccountInfo(); strings.EqualFold(kind, "api_key") {
+			authKind = "apikey"
+		}
+	}
 	provider := strings.ToLower(strings.TrimSpace(a.Provider))
 	compatProviderKey, compatDisplayName, compatDetected := openAICompatInfoFromAuth(a)
 	if compatDetected {
@@ -1739,10 +1818,9 @@ func (s *Service) registerModelsForAuth(ctx context.Context, a *coreauth.Auth) {
 			excluded = strings.Split(val, ",")
 		}
 	}
-	if s.tryRegisterPluginModelsForAuth(ctx, a, provider, authKind, excluded) {
-		return
+	if live && s.tryRegisterPluginModelsForAuth(ctx, a, provider, authKind, excluded) {
+		return "", nil, true
 	}
-	var models []*ModelInfo
 	switch provider {
 	case "gemini":
 		models = registry.GetGeminiModels()
@@ -1775,7 +1853,18 @@ func (s *Service) registerModelsForAuth(ctx context.Context, a *coreauth.Auth) {
 		models = applyExcludedModels(models, excluded)
 	case "antigravity":
 		models = registry.GetAntigravityModels()
-		models = applyAntigravityFetchedModelCapabilities(models, s.fetchAntigravityModelCapabilityHintsForAuth(ctx, a))
+		if live {
+			models = applyAntigravityFetchedModelCapabilities(models, s.fetchAntigravityModelCapabilityHintsForAuth(ctx, a))
+		}
+		models = applyExcludedModels(models, excluded)
+	case "bedrock":
+		// Bedrock model IDs are account/region specific, so only configured models are exposed.
+		if entry := s.resolveConfigBedrockKey(a); entry != nil {
+			models = buildBedrockConfigModels(entry)
+			if authKind == "apikey" {
+				excluded = entry.ExcludedModels
+			}
+		}
 		models = applyExcludedModels(models, excluded)
 	case "claude":
 		models = registry.GetClaudeModels()
@@ -1823,7 +1912,7 @@ func (s *Service) registerModelsForAuth(ctx context.Context, a *coreauth.Auth) {
 	default:
 		// Handle OpenAI-compatibility providers by name using config
 		if s.cfg != nil {
-			providerKey := provider
+			providerKey = provider
 			compatName := strings.TrimSpace(a.Provider)
 			isCompatAuth := false
 			if compatDetected {
@@ -1867,34 +1956,18 @@ func (s *Service) registerModelsForAuth(ctx context.Context, a *coreauth.Auth) {
 				if strings.EqualFold(compat.Name, compatName) {
 					isCompatAuth = true
 					ms := buildOpenAICompatibilityConfigModels(compat)
-					// Register and return
-					if len(ms) > 0 {
-						if providerKey == "" {
-							providerKey = "openai-compatibility"
-						}
-						ms = s.appendPluginModels(providerKey, ms)
-						s.registerResolvedModelsForAuth(a, providerKey, applyModelPrefixes(ms, a.Prefix, s.cfg.ForceModelPrefix))
-					} else {
-						// Ensure stale registrations are cleared when model list becomes empty.
-						ms = s.appendPluginModels(providerKey, nil)
-						if len(ms) > 0 {
-							s.registerResolvedModelsForAuth(a, providerKey, applyModelPrefixes(ms, a.Prefix, s.cfg.ForceModelPrefix))
-						} else {
-							GlobalModelRegistry().UnregisterClient(a.ID)
-						}
+					if len(ms) > 0 && providerKey == "" {
+						providerKey = "openai-compatibility"
 					}
-					return
+					// An empty list clears stale registrations when the models are removed.
+					ms = s.appendPluginModels(providerKey, ms)
+					return providerKey, applyModelPrefixes(ms, a.Prefix, s.cfg.ForceModelPrefix), false
 				}
 			}
 			if isCompatAuth {
+				// No matching provider found or models removed entirely; only plugin models remain.
 				models = s.appendPluginModels(providerKey, nil)
-				if len(models) > 0 {
-					s.registerResolvedModelsForAuth(a, providerKey, applyModelPrefixes(models, a.Prefix, s.cfg != nil && s.cfg.ForceModelPrefix))
-				} else {
-					// No matching provider found or models removed entirely; drop any prior registration.
-					GlobalModelRegistry().UnregisterClient(a.ID)
-				}
-				return
+				return providerKey, applyModelPrefixes(models, a.Prefix, s.cfg.ForceModelPrefix), false
 			}
 		}
 	}
@@ -1904,12 +1977,7 @@ func (s *Service) registerModelsForAuth(ctx context.Context, a *coreauth.Auth) {
 		key = strings.ToLower(strings.TrimSpace(a.Provider))
 	}
 	models = s.appendPluginModels(key, models)
-	if len(models) > 0 {
-		s.registerResolvedModelsForAuth(a, key, applyModelPrefixes(models, a.Prefix, s.cfg != nil && s.cfg.ForceModelPrefix))
-		return
-	}
-
-	GlobalModelRegistry().UnregisterClient(a.ID)
+	return key, applyModelPrefixes(models, a.Prefix, s.cfg != nil && s.cfg.ForceModelPrefix), false
 }
 
 // refreshModelRegistrationForAuth re-applies the latest model registration for
@@ -2061,6 +2129,35 @@ func (s *Service) resolveConfigVertexCompatKey(auth *coreauth.Auth) *config.Vert
 	return nil
 }
 
+func (s *Service) resolveConfigBedrockKey(auth *coreauth.Auth) *config.BedrockKey {
+	if auth == nil || s.cfg == nil {
+		return nil
+	}
+	var attrKey, attrBase, attrRegion string
+	if auth.Attributes != nil {
+		attrKey = strings.TrimSpace(auth.Attributes["api_key"])
+		attrBase = strings.TrimSpace(auth.Attributes["base_url"])
+		attrRegion = strings.TrimSpace(auth.Attributes["region"])
+	}
+	if attrKey == "" {
+		return nil
+	}
+	var fallback *config.BedrockKey
+	for i := range s.cfg.BedrockKey {
+		entry := &s.cfg.BedrockKey[i]
+		if strings.TrimSpace(entry.AccessKeyID) != attrKey {
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(entry.BaseURL), attrBase) && (attrRegion == "" || strings.EqualFold(strings.TrimSpace(entry.Region), attrRegion)) {
+			return entry
+		}
+		if fallback == nil {
+			fallback = entry
+		}
+	}
+	return fallback
+}
+
 func (s *Service) resolveConfigCodexKey(auth *coreauth.Auth) *config.CodexKey {
 	if auth == nil || s.cfg == nil {
 		return nil
@@ -2224,6 +2321,7 @@ func matchWildcard(pattern, value string) bool {
 type modelEntry interface {
 	GetName() string
 	GetAlias() string
+	GetPricing() *registry.ModelPricing
 }
 
 func buildOpenAICompatibilityConfigModels(compat *config.OpenAICompatibility) []*ModelInfo {
@@ -2258,6 +2356,7 @@ func buildOpenAICompatibilityConfigModels(compat *config.OpenAICompatibility) []
 			DisplayName: modelID,
 			UserDefined: false,
 			Thinking:    thinking,
+			Pricing:     model.Pricing,
 		})
 	}
 	return models
@@ -2299,10 +2398,16 @@ func buildConfigModels[T modelEntry](models []T, ownedBy, modelType string) []*M
 			UserDefined: true,
 		}
 		if name != "" {
-			if upstream := registry.LookupStaticModelInfo(name); upstream != nil && upstream.Thinking != nil {
-				info.Thinking = upstream.Thinking
+			if upstream := registry.LookupStaticModelInfo(name); upstream != nil {
+				if upstream.Thinking != nil {
+					info.Thinking = upstream.Thinking
+				}
+				info.Pricing = upstream.Pricing
 			}
 		}
+		if pricing := model.GetPricing(); pricing != nil {
+			info.Pricing = pricing
+		}
 		out = append(out, info)
 	}
 	return out
@@ -2315,6 +2420,31 @@ func buildVertexCompatConfigModels(entry *config.VertexCompatKey) []*ModelInfo {
 	return buildConfigModels(entry.Models, "google", "vertex")
 }
 
+func buildBedrockConfigModels(entry *config.BedrockKey) []*ModelInfo {
+	if entry == nil {
+		return nil
+	}
+	models := buildConfigModels(entry.Models, "anthropic", "bedrock")
+	// Bedrock IDs such as "us.anthropic.claude-sonnet-4-20250514-v1:0" are not in the
+	// static catalog; fall back to the Anthropic model they wrap for thinking and pricing.
+	for _, info := range models {
+		if info == nil || info.Thinking != nil {
+			continue
+		}
+		catalogID := executor.BedrockCatalogModelID(info.DisplayName)
+		if catalogID == "" || catalogID == info.DisplayName {
+			continue
+		}
+		if upstream := registry.LookupStaticModelInfo(catalogID); upstream != nil {
+			info.Thinking = upstream.Thinking
+			if info.Pricing == nil {
+				info.Pricing = upstream.Pricing
+			}
+		}
+	}
+	return models
+}
+
 func buildGeminiConfigModels(entry *config.GeminiKey) []*ModelInfo {
 	if entry == nil {
 		return nil
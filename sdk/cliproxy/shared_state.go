@@ -0,0 +1,61 @@
+package cliproxy
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/sharedstate"
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultSharedCooldownSyncInterval = 5 * time.Second
+
+// applySharedStateConfig opens or closes the shared state store and starts,
+// restarts or stops the core shared cooldown sync so both match cfg.
+func (s *Service) applySharedStateConfig(cfg *config.Config) {
+	if s == nil || cfg == nil {
+		return
+	}
+	if err := sharedstate.Apply(cfg.SharedState); err != nil {
+		log.Errorf("failed to configure shared state: %v", err)
+	}
+	if s.coreManager == nil {
+		return
+	}
+	if sharedstate.Current() == nil {
+		if s.sharedCooldownSyncInterval > 0 {
+			s.coreManager.StopSharedCooldownSync()
+			s.sharedCooldownSyncInterval = 0
+			log.Info("shared cooldown sync stopped")
+		}
+		return
+	}
+	interval := defaultSharedCooldownSyncInterval
+	if raw := strings.TrimSpace(cfg.SharedState.SyncInterval); raw != "" {
+		parsed, errParse := time.ParseDuration(raw)
+		if errParse != nil || parsed <= 0 {
+			log.Warnf("invalid shared-state.sync-interval %q, using %s", raw, defaultSharedCooldownSyncInterval)
+		} else {
+			interval = parsed
+		}
+	}
+	if interval == s.sharedCooldownSyncInterval {
+		return
+	}
+	s.coreManager.StartSharedCooldownSync(context.Background(), interval)
+	s.sharedCooldownSyncInterval = interval
+	log.Infof("shared cooldown sync started (interval=%s)", interval)
+}
+
+func (s *Service) shutdownSharedState() {
+	if s == nil {
+		return
+	}
+	if s.coreManager != nil {
+		s.coreManager.StopSharedCooldownSync()
+	}
+	s.sharedCooldownSyncInterval = 0
+	sharedstate.Close()
+}
@@ -0,0 +1,25 @@
+package cliproxy
+
+import (
+	"context"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/tracing"
+	log "github.com/sirupsen/logrus"
+)
+
+func (s *Service) applyTracingConfig(cfg *config.Config) {
+	if s == nil || cfg == nil {
+		return
+	}
+	if err := tracing.Default().Apply(cfg.Tracing); err != nil {
+		log.Errorf("failed to configure tracing: %v", err)
+	}
+}
+
+func (s *Service) shutdownTracing(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	return tracing.Default().Shutdown(ctx)
+}
@@ -37,8 +37,26 @@ type Record struct {
 	Detail      Detail
 	// ResponseHeaders stores a snapshot of upstream response headers for usage sinks.
 	ResponseHeaders http.Header
+	// APIKeyName stores the label of the structured client key that authenticated the request.
+	APIKeyName string
+	// CostUSD is the estimated request cost derived from the model pricing catalog.
+	// It is zero when no pricing is known for the model.
+	CostUSD float64
+	// FallbackFrom stores the originally requested model when the request was
+	// served by a configured model fallback. It is empty otherwise.
+	FallbackFrom string
+	// CacheStatus is CacheStatusHit when the response cache answered the request
+	// and CacheStatusMiss when it was consulted without a match. It is empty when
+	// the cache is disabled or bypassed.
+	CacheStatus string
 }
 
+// Response cache outcomes recorded in Record.CacheStatus.
+const (
+	CacheStatusHit  = "hit"
+	CacheStatusMiss = "miss"
+)
+
 // Failure holds HTTP failure metadata for an upstream request attempt.
 type Failure struct {
 	StatusCode int
@@ -59,6 +77,8 @@ type Detail struct {
 type requestedModelAliasContextKey struct{}
 type reasoningEffortContextKey struct{}
 type serviceTierContextKey struct{}
+type fallbackFromContextKey struct{}
+type cacheStatusContextKey struct{}
 
 // WithRequestedModelAlias stores the client-requested model name for usage sinks.
 func WithRequestedModelAlias(ctx context.Context, alias string) context.Context {
@@ -88,6 +108,51 @@ func RequestedModelAliasFromContext(ctx context.Context) string {
 	}
 }
 
+// WithFallbackFrom marks ctx as serving a model fallback for the given original model.
+func WithFallbackFrom(ctx context.Context, model string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	model = strings.TrimSpace(model)
+	if model == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, fallbackFromContextKey{}, model)
+}
+
+// FallbackFromContext returns the original model stored by WithFallbackFrom, if any.
+func FallbackFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if value, ok := ctx.Value(fallbackFromContextKey{}).(string); ok {
+		return value
+	}
+	return ""
+}
+
+// WithCacheStatus records the response cache outcome of the request for usage sinks.
+func WithCacheStatus(ctx context.Context, status string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if status == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, cacheStatusContextKey{}, status)
+}
+
+// CacheStatusFromContext returns the response cache outcome stored by WithCacheStatus, if any.
+func CacheStatusFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if value, ok := ctx.Value(cacheStatusContextKey{}).(string); ok {
+		return value
+	}
+	return ""
+}
+
 // WithReasoningEffort stores the client-requested reasoning effort for usage sinks.
 func WithReasoningEffort(ctx context.Context, effort string) context.Context {
 	if ctx == nil {
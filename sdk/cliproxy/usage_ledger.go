@@ -0,0 +1,23 @@
+package cliproxy
+
+import (
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/usageledger"
+	log "github.com/sirupsen/logrus"
+)
+
+func (s *Service) applyUsageLedgerConfig(cfg *config.Config) {
+	if s == nil || cfg == nil {
+		return
+	}
+	if err := usageledger.Default().Apply(cfg.UsageLedger); err != nil {
+		log.Errorf("failed to configure usage ledger: %v", err)
+	}
+}
+
+func (s *Service) shutdownUsageLedger() {
+	if s == nil {
+		return
+	}
+	usageledger.Default().Close()
+}
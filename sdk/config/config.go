@@ -24,9 +24,14 @@ type CodexKey = internalconfig.CodexKey
 type ClaudeKey = internalconfig.ClaudeKey
 type VertexCompatKey = internalconfig.VertexCompatKey
 type VertexCompatModel = internalconfig.VertexCompatModel
+type BedrockKey = internalconfig.BedrockKey
+type BedrockModel = internalconfig.BedrockModel
+type ModelFallback = internalconfig.ModelFallback
+type RoutingRule = internalconfig.RoutingRule
 type OpenAICompatibility = internalconfig.OpenAICompatibility
 type OpenAICompatibilityAPIKey = internalconfig.OpenAICompatibilityAPIKey
 type OpenAICompatibilityModel = internalconfig.OpenAICompatibilityModel
+type ClientAPIKey = internalconfig.ClientAPIKey
 
 type TLS = internalconfig.TLSConfig
 
@@ -53,3 +58,5 @@ func SaveConfigPreserveCommentsUpdateNestedScalar(configFile string, path []stri
 func NormalizeCommentIndentation(data []byte) []byte {
 	return internalconfig.NormalizeCommentIndentation(data)
 }
+
+func NormalizeClientAPIKey(entry *ClientAPIKey) { internalconfig.NormalizeClientAPIKey(entry) }
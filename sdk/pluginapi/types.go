@@ -1260,6 +1260,8 @@ type UsageRecord struct {
 	Alias string
 	// APIKey is the client API key identifier when available.
 	APIKey string
+	// APIKeyName is the label of the structured client key when available.
+	APIKeyName string
 	// AuthID identifies the selected credential.
 	AuthID string
 	// AuthIndex identifies the credential index when applicable.
@@ -1286,6 +1288,8 @@ type UsageRecord struct {
 	Detail UsageDetail
 	// ResponseHeaders contains selected upstream response headers.
 	ResponseHeaders http.Header
+	// CostUSD is the estimated request cost from the model pricing catalog.
+	CostUSD float64
 }
 
 // UsageFailure describes an upstream or executor failure.
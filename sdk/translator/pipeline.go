@@ -63,7 +63,7 @@ func (p *Pipeline) UseResponse(mw ResponseMiddleware) {
 // TranslateRequest applies middleware and registry transformations.
 func (p *Pipeline) TranslateRequest(ctx context.Context, from, to Format, req RequestEnvelope) (RequestEnvelope, error) {
 	terminal := func(ctx context.Context, input RequestEnvelope) (RequestEnvelope, error) {
-		translated := p.registry.TranslateRequest(from, to, input.Model, input.Body, input.Stream)
+		translated := p.registry.TranslateRequestContext(ctx, from, to, input.Model, input.Body, input.Stream)
 		input.Body = translated
 		input.Format = to
 		return input, nil
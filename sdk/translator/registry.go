@@ -4,6 +4,7 @@ import (
 	"context"
 	"sync"
 
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/tracing"
 	log "github.com/sirupsen/logrus"
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
@@ -56,6 +57,21 @@ func (r *Registry) SetPluginHooks(hooks PluginHooks) {
 // "model" field is still updated to match the resolved model name so that
 // client-side prefixes (e.g. "copilot/gpt-5-mini") are not leaked upstream.
 func (r *Registry) TranslateRequest(from, to Format, model string, rawJSON []byte, stream bool) []byte {
+	return r.translateRequest(context.Background(), from, to, model, rawJSON, stream)
+}
+
+// TranslateRequestContext behaves like TranslateRequest and records the
+// translation as a span of the request trace carried by ctx.
+func (r *Registry) TranslateRequestContext(ctx context.Context, from, to Format, model string, rawJSON []byte, stream bool) []byte {
+	ctx, span := tracing.Start(ctx, "translator.request",
+		tracing.String("translator.from", from.String()),
+		tracing.String("translator.to", to.String()),
+	)
+	defer span.End()
+	return r.translateRequest(ctx, from, to, model, rawJSON, stream)
+}
+
+func (r *Registry) translateRequest(ctx context.Context, from, to Format, model string, rawJSON []byte, stream bool) []byte {
 	r.mu.RLock()
 	var fn RequestTransform
 	if byTarget, ok := r.requests[from]; ok {
@@ -78,9 +94,9 @@ func (r *Registry) TranslateRequest(from, to Format, model string, rawJSON []byt
 	}
 
 	if hooks != nil {
-		body = hooks.NormalizeRequest(context.Background(), from, to, model, body, stream)
+		body = hooks.NormalizeRequest(ctx, from, to, model, body, stream)
 		if fn == nil {
-			if translated, ok := hooks.TranslateRequest(context.Background(), from, to, model, body, stream); ok {
+			if translated, ok := hooks.TranslateRequest(ctx, from, to, model, body, stream); ok {
 				body = translated
 			}
 		}
@@ -141,6 +157,7 @@ func (r *Registry) HasNonStreamResponseTransformer(from, to Format) bool {
 }
 
 // TranslateStream applies the registered streaming response translator.
+// Chunks are counted on the StreamSpan carried by ctx, if the caller opened one.
 func (r *Registry) TranslateStream(ctx context.Context, from, to Format, model string, originalRequestRawJSON, requestRawJSON, rawJSON []byte, param *any) [][]byte {
 	r.mu.RLock()
 	var stream ResponseStreamTransform
@@ -173,11 +190,17 @@ func (r *Registry) TranslateStream(ctx context.Context, from, to Format, model s
 			outputs[i] = hooks.NormalizeResponseAfter(ctx, from, to, model, originalRequestRawJSON, requestRawJSON, output, true)
 		}
 	}
+	streamSpanFromContext(ctx).record(len(outputs))
 	return outputs
 }
 
 // TranslateNonStream applies the registered non-stream response translator.
 func (r *Registry) TranslateNonStream(ctx context.Context, from, to Format, model string, originalRequestRawJSON, requestRawJSON, rawJSON []byte, param *any) []byte {
+	ctx, span := tracing.Start(ctx, "translator.response",
+		tracing.String("translator.from", from.String()),
+		tracing.String("translator.to", to.String()),
+	)
+	defer span.End()
 	r.mu.RLock()
 	var fn ResponseTransform
 	if byTarget, ok := r.responses[to]; ok {
@@ -238,6 +261,11 @@ func TranslateRequest(from, to Format, model string, rawJSON []byte, stream bool
 	return defaultRegistry.TranslateRequest(from, to, model, rawJSON, stream)
 }
 
+// TranslateRequestContext is a helper on the default registry that traces the translation.
+func TranslateRequestContext(ctx context.Context, from, to Format, model string, rawJSON []byte, stream bool) []byte {
+	return defaultRegistry.TranslateRequestContext(ctx, from, to, model, rawJSON, stream)
+}
+
 // HasRequestTransformer inspects the default registry.
 func HasRequestTransformer(from, to Format) bool {
 	return defaultRegistry.HasRequestTransformer(from, to)
@@ -0,0 +1,82 @@
+package translator
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/tracing"
+)
+
+type streamSpanKey struct{}
+
+// StreamSpan records the translation of one streamed response as a single span.
+// A nil *StreamSpan is valid and ignores every call.
+type StreamSpan struct {
+	span *tracing.Span
+
+	mu      sync.Mutex
+	chunks  int
+	outputs int
+	last    time.Time
+}
+
+// StartStream opens the "translator.stream" span of a streamed response. The caller
+// passes the returned context to the executor and ends the span once the stream is
+// drained; TranslateStream only counts the chunks it translates on it.
+func StartStream(ctx context.Context, from, to Format) (context.Context, *StreamSpan) {
+	if !tracing.Enabled() {
+		return ctx, nil
+	}
+	ctx, span := tracing.Start(ctx, "translator.stream",
+		tracing.String("translator.from", from.String()),
+		tracing.String("translator.to", to.String()),
+	)
+	if !span.IsRecording() {
+		return ctx, nil
+	}
+	stream := &StreamSpan{span: span}
+	return context.WithValue(ctx, streamSpanKey{}, stream), stream
+}
+
+// End sets the chunk counts and last chunk time and finishes the span.
+func (s *StreamSpan) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	attrs := []tracing.Attribute{
+		tracing.Int("translator.chunks", s.chunks),
+		tracing.Int("translator.output_chunks", s.outputs),
+	}
+	if !s.last.IsZero() {
+		attrs = append(attrs, tracing.String("translator.last_chunk_time", s.last.UTC().Format(time.RFC3339Nano)))
+	}
+	s.mu.Unlock()
+	s.span.SetAttributes(attrs...)
+	s.span.End()
+}
+
+// record counts one translated upstream chunk and marks the first with an event.
+func (s *StreamSpan) record(outputs int) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.chunks++
+	s.outputs += outputs
+	s.last = time.Now()
+	first := s.chunks == 1
+	s.mu.Unlock()
+	if first {
+		s.span.AddEvent("translator.first_chunk")
+	}
+}
+
+func streamSpanFromContext(ctx context.Context) *StreamSpan {
+	if ctx == nil {
+		return nil
+	}
+	stream, _ := ctx.Value(streamSpanKey{}).(*StreamSpan)
+	return stream
+}
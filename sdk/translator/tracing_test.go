@@ -0,0 +1,58 @@
+package translator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/tracing"
+)
+
+func TestTranslationsRecordSpansInRequestTrace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+	if err := tracing.Default().Apply(config.TracingConfig{Enable: true, Exporter: "file", File: path}); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	t.Cleanup(func() { _ = tracing.Default().Apply(config.TracingConfig{}) })
+
+	reg := NewRegistry()
+	from, to := Format("trace-from"), Format("trace-to")
+	reg.Register(from, to, func(_ string, rawJSON []byte, _ bool) []byte { return rawJSON }, ResponseTransform{
+		Stream: func(_ context.Context, _ string, _, _, rawJSON []byte, _ *any) [][]byte { return [][]byte{rawJSON} },
+	})
+
+	ctx, parent := tracing.Start(context.Background(), "parent")
+	reg.TranslateRequestContext(ctx, from, to, "m", []byte(`{"model":"m"}`), true)
+	// Chunks translated outside a stream span record nothing; inside it they share one span.
+	reg.TranslateStream(ctx, to, from, "m", nil, nil, []byte(`{}`), nil)
+	streamCtx, stream := StartStream(ctx, to, from)
+	for i := 0; i < 3; i++ {
+		reg.TranslateStream(streamCtx, to, from, "m", nil, nil, []byte(`{}`), nil)
+	}
+	stream.End()
+	reg.TranslateRequest(from, to, "m", []byte(`{"model":"m"}`), false)
+	parent.End()
+	if err := tracing.Default().Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read trace file: %v", err)
+	}
+	output := string(data)
+	if spans := strings.Count(output, `"SpanContext"`); spans != 3 {
+		t.Fatalf("recorded %d spans, want parent, request and stream: %s", spans, output)
+	}
+	if strings.Count(output, `"SpanContext":{"TraceID":"`+parent.SpanContext().TraceID().String()) != 3 {
+		t.Fatalf("translation spans not recorded in the request trace: %s", output)
+	}
+	for _, name := range []string{`"Name":"translator.request"`, `"Name":"translator.stream"`, `"Name":"translator.first_chunk"`, `{"Key":"translator.chunks","Value":{"Type":"INT64","Value":3}}`} {
+		if !strings.Contains(output, name) {
+			t.Fatalf("span %s missing: %s", name, output)
+		}
+	}
+}